@@ -96,7 +96,8 @@ const ExtractionPrompt = `You are a financial data extractor. You will receive e
 	- Skip the e-mandate email.
 	- Output field names must match the schema exactly. Do not rename, add, or omit any fields.
 	- date is always required if present in the email. Date formats like "3 Apr, 2023" must be parsed as 2023-04-03.
-	SCHEMA: {"merchant": "string", "amount": float, "date": "string (formatted as YYYY-MM-DD)", "time": string | null, "account_card": "string (Bank name and last 4 digits only, no extra words)", "reasoning": "string (Brief 1 sentence explanation of why this classification is chosen)"}
+	- When the charge is in a foreign currency, amount is the equivalent INR amount, original_amount is the foreign amount with the same sign and original_currency is its ISO 4217 code. Otherwise both are null.
+	SCHEMA: {"merchant": "string", "amount": float, "original_amount": float | null, "original_currency": "string (ISO 4217 code like USD)" | null, "date": "string (formatted as YYYY-MM-DD)", "time": string | null, "account_card": "string (Bank name and last 4 digits only, no extra words)", "reasoning": "string (Brief 1 sentence explanation of why this classification is chosen)"}
 
 	EXAMPLES: 
   Input: "Dear Customer, Rs.500.00 has been debited from account 4567 to VPA 9876543210@ybl JOHN DOE S O JAMES DOE on 14-07-25. Your UPI transaction reference number is 123456789012. If you did not authorize this transaction, please report it immediately by calling 18002586161 Or SMS BLOCK UPI to 7308080808. Warm Regards, HDFC BankFor more details on Service charges and Fees, click here.. © HDFC Bank"
@@ -108,6 +109,9 @@ const ExtractionPrompt = `You are a financial data extractor. You will receive e
 	Input: "Dear Customer, Rs.1200.00 has been debited from your HDFC Bank Credit Card ending 9876 towards NETFLIX on 05 Jan, 2025 at 10:22:11."
 	Output: {"merchant": "NETFLIX", "amount": -1200.0, "date": "2025-01-05", "time": "10:22:11", "account_card": "HDFC 9876", "reasoning": "Debit from HDFC credit card ending 9876 to Netflix."}
 
+	Input: "Dear Customer, USD 12.00 has been debited from your HDFC Bank Credit Card ending 9876 towards OPENAI *CHATGPT on 02 Feb, 2025 at 08:15:40. The equivalent INR 1024.50 will be billed to your card."
+	Output: {"merchant": "OPENAI *CHATGPT", "amount": -1024.5, "original_amount": -12.0, "original_currency": "USD", "date": "2025-02-02", "time": "08:15:40", "account_card": "HDFC 9876", "reasoning": "Forex debit of USD 12.00, billed as INR 1024.50, from HDFC credit card ending 9876 to OpenAI."}
+
 	Input: "Dear Customer, Greetings from HDFC Bank! Your Canva Pty Ltd bill, set up through E-mandate (Auto payment), has been successfully paid using your HDFC Bank Credit Card ending 1234. Transaction Details: Amount: INR 500.00 Date: 10/06/2026 SI Hub ID: Y8Inwhnbjn To manage your e-Mandates, please visit: https://www.sihub.in/managesi/hdfcbank Thank you for banking with us. Warm regards, HDFC BankFor more details on Service charges and Fees, click here.. © HDFC Bank"
 	Output: {"merchant": "", "amount": 0.0, "date": "", "time": "", "account_card": "", "reasoning": "Skipping e-mandate payment from HDFC credit card ending 1234"}
 
@@ -117,6 +121,7 @@ const ExtractionPrompt = `You are a financial data extractor. You will receive e
 // ExtractEmailData implements Phase 1 of the classification pipeline:
 // sends raw email text to a local SLM (Gemma via Ollama) in JSON mode
 // to extract structured {merchant, amount, account_card} from chaotic bank alerts.
+// Forex charges also carry the foreign amount and currency.
 func (c *OllamaClient) ExtractEmailData(
 	ctx context.Context,
 	rawText string,
@@ -86,6 +86,9 @@ func (a *PredictionActivity) Predict(
 			Source:          prediction.Source,
 			Reasoning:       prediction.Reasoning,
 			Metadata:        prediction.Metadata,
+			// the foreign amount of forex charges, Amount is in the card currency
+			OriginalAmount:   email.OriginalAmount,
+			OriginalCurrency: email.OriginalCurrency,
 		})
 	}
 
@@ -158,6 +161,8 @@ func (a *PredictionActivity) ParseEmailData(
 			Date:              date.Format("2006-01-02"),
 			TransactionType:   transactionType,
 			Account:           "",
+			OriginalAmount:    extracted.OriginalAmount,
+			OriginalCurrency:  extracted.OriginalCurrency,
 		})
 	}
 
@@ -9,6 +9,7 @@ import (
 	"syscall"
 	"time"
 
+	"github.com/Rishabh-Kapri/pennywise/backend/go-pennywise-api/internal/blobstore"
 	"github.com/Rishabh-Kapri/pennywise/backend/go-pennywise-api/internal/config"
 	"github.com/Rishabh-Kapri/pennywise/backend/go-pennywise-api/internal/db"
 	"github.com/Rishabh-Kapri/pennywise/backend/go-pennywise-api/internal/handler"
@@ -96,8 +97,9 @@ func main() {
 			utils.HeaderOriginService,
 			utils.HeaderInternalToken,
 			utils.HeaderInternalService,
+			utils.HeaderIdempotencyKey,
 		},
-		ExposeHeaders:    []string{"Content-Length"},
+		ExposeHeaders:    []string{"Content-Length", utils.HeaderIdempotentReplayed},
 		AllowCredentials: true,
 	}))
 
@@ -118,24 +120,27 @@ func main() {
 	googleProviderRepo := repository.NewGoogleProviderRepository(dbConn)
 	apiKeyRepo := repository.NewAPIKeyRepository(dbConn)
 	agentRepo := repository.NewAgentRepository(dbConn)
+	auditLogRepo := repository.NewAuditLogRepository(dbConn)
+	operationJournalRepo := repository.NewOperationJournalRepository(dbConn)
+	transactionDuplicateRepo := repository.NewTransactionDuplicateRepository(dbConn)
+
+	auditService := service.NewAuditService(auditLogRepo)
+	auditHandler := handler.NewAuditHandler(auditService)
 
 	budgetService := service.NewBudgetService(budgetRepo, payeeRepo, categoryRepo, categoryGroupRepo)
 	budgetHandler := handler.NewBudgetHandler(budgetService)
 
-	accountService := service.NewAccountService(accountRepo, payeeRepo)
-	accountHandler := handler.NewAccountHandler(accountService)
-
 	userService := service.NewUserService(userRepo)
 	userHandler := handler.NewUserHandler(userService)
 
-	payeeService := service.NewPayeeService(payeeRepo, payeeRuleRepo)
+	payeeService := service.NewPayeeService(payeeRepo, payeeRuleRepo, transactionRepo, auditService)
 	payeeHandler := handler.NewPayeeHandler(payeeService)
 
 	categoryGroupService := service.NewCategoryGroupService(categoryGroupRepo)
 	categoryGroupHandler := handler.NewCategoryGroupHandler(categoryGroupService)
 
 	monthlyBudgetRepo := repository.NewMonthlyBudgetRepository(dbConn)
-	monthlyBudgetService := service.NewMonthlyBudgetService(monthlyBudgetRepo)
+	monthlyBudgetService := service.NewMonthlyBudgetService(monthlyBudgetRepo, categoryRepo)
 
 	predictionService := service.NewPredictionService(predictionRepo, cipherPredictionRepo)
 	predictionHandler := handler.NewPredictionHandler(predictionService)
@@ -167,10 +172,25 @@ func main() {
 		payeeRepo,
 		categoryRepo,
 		monthlyBudgetService,
+		auditService,
+		operationJournalRepo,
+		transactionDuplicateRepo,
 	)
 	transactionHandler := handler.NewTransactionHandler(transactionService)
+	undoHandler := handler.NewUndoHandler(transactionService)
+
+	accountService := service.NewAccountService(
+		accountRepo,
+		payeeRepo,
+		transactionRepo,
+		budgetRepo,
+		categoryRepo,
+		transactionService,
+		auditService,
+	)
+	accountHandler := handler.NewAccountHandler(accountService)
 
-	categoryService := service.NewCategoryService(categoryRepo, monthlyBudgetRepo, transactionRepo)
+	categoryService := service.NewCategoryService(categoryRepo, monthlyBudgetRepo, transactionRepo, auditService)
 	categoryHandler := handler.NewCategoryHandler(categoryService)
 
 	embeddingService := service.NewEmbeddingService(embeddingRepo)
@@ -191,9 +211,69 @@ func main() {
 	loanMetadataService := service.NewLoanMetadataService(loanMetadataRepo)
 	loanMetadataHandler := handler.NewLoanMetadataHandler(loanMetadataService)
 
+	scheduledTransactionRepo := repository.NewScheduledTransactionRepository(dbConn)
+	scheduledTransactionService := service.NewScheduledTransactionService(scheduledTransactionRepo, transactionService)
+	scheduledTransactionHandler := handler.NewScheduledTransactionHandler(scheduledTransactionService)
+
+	transactionTemplateRepo := repository.NewTransactionTemplateRepository(dbConn)
+	transactionTemplateService := service.NewTransactionTemplateService(
+		transactionTemplateRepo,
+		transactionRepo,
+		transactionService,
+	)
+	transactionTemplateHandler := handler.NewTransactionTemplateHandler(transactionTemplateService)
+
+	importMappingRepo := repository.NewImportMappingRepository(dbConn)
+	importService := service.NewImportService(
+		importMappingRepo,
+		accountRepo,
+		transactionService,
+		payeeService,
+		cipherClient,
+	)
+	importHandler := handler.NewImportHandler(importService)
+
+	fxRateRepo := repository.NewFxRateRepository(dbConn)
+	fxRateService := service.NewFxRateService(fxRateRepo)
+	fxRateHandler := handler.NewFxRateHandler(fxRateService)
+
+	attachmentStore, err := blobstore.NewLocalStore(config.AttachmentsDir)
+	if err != nil {
+		logger.Logger(ctx).Error("failed to open attachment store", "error", err)
+		panic(err)
+	}
+	attachmentRepo := repository.NewAttachmentRepository(dbConn)
+	attachmentService := service.NewAttachmentService(attachmentRepo, transactionRepo, attachmentStore)
+	attachmentHandler := handler.NewAttachmentHandler(attachmentService)
+
+	trashRepo := repository.NewTrashRepository(dbConn)
+	trashService := service.NewTrashService(trashRepo, transactionService, attachmentStore, config.TrashRetentionDays)
+	trashHandler := handler.NewTrashHandler(trashService)
+
+	exportService := service.NewExportService(
+		transactionRepo,
+		monthlyBudgetRepo,
+		accountRepo,
+		categoryGroupRepo,
+		tagRepo,
+		fxRateRepo,
+	)
+	exportHandler := handler.NewExportHandler(exportService)
+
 	websocketHub := websocket.NewConnectionHub()
 	websocketService := service.NewWebsocketService(websocketHub)
 	websocketHandler := handler.NewWebsocketHandler(websocketService)
+
+	budgetMoveRepo := repository.NewBudgetMoveRepository(dbConn)
+	budgetMoveService := service.NewBudgetMoveService(
+		budgetMoveRepo,
+		categoryRepo,
+		monthlyBudgetRepo,
+		categoryService,
+		websocketService,
+	)
+	budgetMoveHandler := handler.NewBudgetMoveHandler(budgetMoveService)
+	monthlyBudgetHandler := handler.NewMonthlyBudgetHandler(monthlyBudgetService)
 	// go websocketHub.HandleBroadcastMessages() // run once
 	go websocket.NewRedisStreamListener(redisClient, websocketHub).Listen(appCtx)
 
@@ -201,6 +281,7 @@ func main() {
 	authMiddleware := middleware.AuthMiddleware(authService, apiKeyService)
 	rateLimitMiddleware := middleware.RateLimitMiddleware(service.NewRateLimitService(redisClient))
 	budgetMiddleware := sharedMiddleware.BudgetIdMiddleware(budgetRepo)
+	idempotencyMiddleware := middleware.IdempotencyMiddleware(service.NewIdempotencyService(redisClient))
 
 	// Websocket routes
 	{
@@ -238,7 +319,7 @@ func main() {
 		}
 		{
 			agentGroup := router.Group("/api/agent")
-			agentGroup.Use(authMiddleware, rateLimitMiddleware, budgetMiddleware)
+			agentGroup.Use(authMiddleware, rateLimitMiddleware, budgetMiddleware, idempotencyMiddleware)
 			agentGroup.GET(
 				"/conversations",
 				middleware.RouteAuthMiddleware(sharedModel.ScopeRead),
@@ -278,6 +359,7 @@ func main() {
 			)
 		}
 		{
+			// no idempotency here, a stored response would keep the plaintext key around
 			apiKeyGroup := router.Group("/api/keys")
 			apiKeyGroup.Use(authMiddleware, rateLimitMiddleware)
 			apiKeyGroup.GET("", middleware.RouteAuthMiddleware(sharedModel.ScopeRead), apiKeyHandler.GetByKeyID)
@@ -285,10 +367,17 @@ func main() {
 		}
 		{
 			budgetGroup := router.Group("/api/budgets")
-			budgetGroup.Use(authMiddleware, rateLimitMiddleware)
+			budgetGroup.Use(authMiddleware, rateLimitMiddleware, idempotencyMiddleware)
 			budgetGroup.GET("", middleware.RouteAuthMiddleware(sharedModel.ScopeRead), budgetHandler.List)
 			budgetGroup.POST("", middleware.RouteAuthMiddleware(sharedModel.ScopeWrite), budgetHandler.Create)
 			budgetGroup.PATCH("/:id", middleware.RouteAuthMiddleware(sharedModel.ScopeWrite), budgetHandler.UpdateById)
+			// the budget comes from the X-Budget-ID header like every other budget scoped route
+			budgetGroup.POST(
+				"/:month/auto-assign",
+				budgetMiddleware,
+				middleware.RouteAuthMiddleware(sharedModel.ScopeWrite),
+				categoryHandler.AutoAssign,
+			)
 		}
 		// Auth-only provider user routes (no budget middleware) — used by internal services
 		{
@@ -307,20 +396,41 @@ func main() {
 		}
 		{
 			accountGroup := router.Group("/api/accounts")
-			accountGroup.Use(authMiddleware, rateLimitMiddleware, budgetMiddleware)
+			accountGroup.Use(authMiddleware, rateLimitMiddleware, budgetMiddleware, idempotencyMiddleware)
 			accountGroup.GET("/search", middleware.RouteAuthMiddleware(sharedModel.ScopeRead), accountHandler.Search)
 			accountGroup.GET("", middleware.RouteAuthMiddleware(sharedModel.ScopeRead), accountHandler.List)
 			accountGroup.POST("", middleware.RouteAuthMiddleware(sharedModel.ScopeWrite), accountHandler.Create)
+			accountGroup.POST(
+				"/:id/reconcile",
+				middleware.RouteAuthMiddleware(sharedModel.ScopeWrite),
+				accountHandler.Reconcile,
+			)
+			accountGroup.PATCH(":id", middleware.RouteAuthMiddleware(sharedModel.ScopeWrite), accountHandler.Update)
+			accountGroup.POST(
+				"/:id/close",
+				middleware.RouteAuthMiddleware(sharedModel.ScopeWrite),
+				accountHandler.Close,
+			)
+			accountGroup.POST(
+				"/:id/reopen",
+				middleware.RouteAuthMiddleware(sharedModel.ScopeWrite),
+				accountHandler.Reopen,
+			)
+			accountGroup.DELETE(
+				":id",
+				middleware.RouteAuthMiddleware(sharedModel.ScopeDelete),
+				accountHandler.DeleteById,
+			)
 		}
 		{
 			userGroup := router.Group("/api/users")
-			userGroup.Use(authMiddleware, rateLimitMiddleware, budgetMiddleware)
+			userGroup.Use(authMiddleware, rateLimitMiddleware, budgetMiddleware, idempotencyMiddleware)
 			userGroup.GET("/search", middleware.RouteAuthMiddleware(sharedModel.ScopeRead), userHandler.Search)
 			userGroup.PATCH("", middleware.RouteAuthMiddleware(sharedModel.ScopeWrite), userHandler.Update)
 		}
 		{
 			groupGroup := router.Group("/api/category-groups")
-			groupGroup.Use(authMiddleware, rateLimitMiddleware, budgetMiddleware)
+			groupGroup.Use(authMiddleware, rateLimitMiddleware, budgetMiddleware, idempotencyMiddleware)
 			groupGroup.GET("", middleware.RouteAuthMiddleware(sharedModel.ScopeRead), categoryGroupHandler.List)
 			groupGroup.POST("", middleware.RouteAuthMiddleware(sharedModel.ScopeWrite), categoryGroupHandler.Create)
 			groupGroup.PUT(":id", middleware.RouteAuthMiddleware(sharedModel.ScopeWrite), categoryGroupHandler.Update)
@@ -332,7 +442,7 @@ func main() {
 		}
 		{
 			categoryGroup := router.Group("/api/categories")
-			categoryGroup.Use(authMiddleware, rateLimitMiddleware, budgetMiddleware)
+			categoryGroup.Use(authMiddleware, rateLimitMiddleware, budgetMiddleware, idempotencyMiddleware)
 			categoryGroup.POST("", middleware.RouteAuthMiddleware(sharedModel.ScopeWrite), categoryHandler.Create)
 			categoryGroup.GET("", middleware.RouteAuthMiddleware(sharedModel.ScopeRead), categoryHandler.List)
 			categoryGroup.GET(
@@ -353,10 +463,33 @@ func main() {
 				middleware.RouteAuthMiddleware(sharedModel.ScopeDelete),
 				categoryHandler.DeleteById,
 			)
+			categoryGroup.GET("/:id/goal", middleware.RouteAuthMiddleware(sharedModel.ScopeRead), categoryHandler.GetGoal)
+			categoryGroup.PUT("/:id/goal", middleware.RouteAuthMiddleware(sharedModel.ScopeWrite), categoryHandler.SetGoal)
+			categoryGroup.DELETE(
+				"/:id/goal",
+				middleware.RouteAuthMiddleware(sharedModel.ScopeDelete),
+				categoryHandler.DeleteGoal,
+			)
+		}
+		{
+			monthlyBudgetGroup := router.Group("/api/monthly-budgets")
+			monthlyBudgetGroup.Use(authMiddleware, rateLimitMiddleware, budgetMiddleware, idempotencyMiddleware)
+			monthlyBudgetGroup.GET("/moves", middleware.RouteAuthMiddleware(sharedModel.ScopeRead), budgetMoveHandler.List)
+			monthlyBudgetGroup.POST("/move", middleware.RouteAuthMiddleware(sharedModel.ScopeWrite), budgetMoveHandler.Move)
+			monthlyBudgetGroup.GET(
+				"/:month",
+				middleware.RouteAuthMiddleware(sharedModel.ScopeRead),
+				monthlyBudgetHandler.GetMonth,
+			)
+			monthlyBudgetGroup.POST(
+				"/rebuild",
+				middleware.RouteAuthMiddleware(sharedModel.ScopeAdmin),
+				monthlyBudgetHandler.Rebuild,
+			)
 		}
 		{
 			transactionGroup := router.Group("/api/transactions")
-			transactionGroup.Use(authMiddleware, rateLimitMiddleware, budgetMiddleware)
+			transactionGroup.Use(authMiddleware, rateLimitMiddleware, budgetMiddleware, idempotencyMiddleware)
 			transactionGroup.GET("", middleware.RouteAuthMiddleware(sharedModel.ScopeRead), transactionHandler.List)
 			transactionGroup.GET(
 				"/normalized",
@@ -364,6 +497,43 @@ func main() {
 				transactionHandler.ListNormalized,
 			)
 			transactionGroup.POST("", middleware.RouteAuthMiddleware(sharedModel.ScopeWrite), transactionHandler.Create)
+			// bulk operations can delete transactions as well
+			transactionGroup.POST(
+				"/bulk",
+				middleware.RouteAuthMiddleware(sharedModel.ScopeWrite, sharedModel.ScopeDelete),
+				transactionHandler.Bulk,
+			)
+			transactionGroup.GET(
+				"/transfer-matches",
+				middleware.RouteAuthMiddleware(sharedModel.ScopeRead),
+				transactionHandler.TransferMatches,
+			)
+			transactionGroup.POST(
+				"/transfer-matches/accept",
+				middleware.RouteAuthMiddleware(sharedModel.ScopeWrite),
+				transactionHandler.AcceptTransferMatch,
+			)
+			transactionGroup.GET(
+				"/duplicates",
+				middleware.RouteAuthMiddleware(sharedModel.ScopeRead),
+				transactionHandler.Duplicates,
+			)
+			// merging deletes one side of the duplicate
+			transactionGroup.POST(
+				"/duplicates/:duplicateId/merge",
+				middleware.RouteAuthMiddleware(sharedModel.ScopeWrite, sharedModel.ScopeDelete),
+				transactionHandler.MergeDuplicate,
+			)
+			transactionGroup.POST(
+				"/duplicates/:duplicateId/dismiss",
+				middleware.RouteAuthMiddleware(sharedModel.ScopeWrite),
+				transactionHandler.DismissDuplicate,
+			)
+			transactionGroup.GET(
+				":id/history",
+				middleware.RouteAuthMiddleware(sharedModel.ScopeRead),
+				auditHandler.TransactionHistory,
+			)
 			transactionGroup.PATCH(
 				":id",
 				middleware.RouteAuthMiddleware(sharedModel.ScopeWrite),
@@ -379,10 +549,59 @@ func main() {
 				middleware.RouteAuthMiddleware(sharedModel.ScopeDelete),
 				transactionHandler.DeleteById,
 			)
+			transactionGroup.GET(
+				":id/attachments",
+				middleware.RouteAuthMiddleware(sharedModel.ScopeRead),
+				attachmentHandler.List,
+			)
+			transactionGroup.POST(
+				":id/attachments",
+				middleware.RouteAuthMiddleware(sharedModel.ScopeWrite),
+				attachmentHandler.Upload,
+			)
+			transactionGroup.GET(
+				":id/attachments/:attachmentId",
+				middleware.RouteAuthMiddleware(sharedModel.ScopeRead),
+				attachmentHandler.Download,
+			)
+			transactionGroup.DELETE(
+				":id/attachments/:attachmentId",
+				middleware.RouteAuthMiddleware(sharedModel.ScopeDelete),
+				attachmentHandler.DeleteById,
+			)
+		}
+		{
+			// undo and redo can revert creates into deletes and the other way around
+			undoGroup := router.Group("/api")
+			undoGroup.Use(authMiddleware, rateLimitMiddleware, budgetMiddleware, idempotencyMiddleware)
+			undoGroup.POST(
+				"/undo",
+				middleware.RouteAuthMiddleware(sharedModel.ScopeWrite, sharedModel.ScopeDelete),
+				undoHandler.Undo,
+			)
+			undoGroup.POST(
+				"/redo",
+				middleware.RouteAuthMiddleware(sharedModel.ScopeWrite, sharedModel.ScopeDelete),
+				undoHandler.Redo,
+			)
+		}
+		{
+			trashGroup := router.Group("/api/trash")
+			trashGroup.Use(authMiddleware, rateLimitMiddleware, budgetMiddleware, idempotencyMiddleware)
+			trashGroup.GET(
+				"",
+				middleware.RouteAuthMiddleware(sharedModel.ScopeRead),
+				trashHandler.List,
+			)
+			trashGroup.POST(
+				":type/:id/restore",
+				middleware.RouteAuthMiddleware(sharedModel.ScopeWrite),
+				trashHandler.Restore,
+			)
 		}
 		{
 			payeeGroup := router.Group("/api/payees")
-			payeeGroup.Use(authMiddleware, rateLimitMiddleware, budgetMiddleware)
+			payeeGroup.Use(authMiddleware, rateLimitMiddleware, budgetMiddleware, idempotencyMiddleware)
 			payeeGroup.GET("", middleware.RouteAuthMiddleware(sharedModel.ScopeRead), payeeHandler.List)
 			payeeGroup.GET("/search", middleware.RouteAuthMiddleware(sharedModel.ScopeRead), payeeHandler.Search)
 			payeeGroup.GET("/:id", middleware.RouteAuthMiddleware(sharedModel.ScopeRead), payeeHandler.GetById)
@@ -405,10 +624,15 @@ func main() {
 			payeeGroup.POST("", middleware.RouteAuthMiddleware(sharedModel.ScopeWrite), payeeHandler.Create)
 			payeeGroup.PATCH(":id", middleware.RouteAuthMiddleware(sharedModel.ScopeWrite), payeeHandler.Update)
 			payeeGroup.DELETE(":id", middleware.RouteAuthMiddleware(sharedModel.ScopeDelete), payeeHandler.DeleteById)
+			payeeGroup.POST(
+				"/:id/merge",
+				middleware.RouteAuthMiddleware(sharedModel.ScopeWrite),
+				payeeHandler.Merge,
+			)
 		}
 		{
 			tagGroup := router.Group("/api/tags")
-			tagGroup.Use(authMiddleware, rateLimitMiddleware, budgetMiddleware)
+			tagGroup.Use(authMiddleware, rateLimitMiddleware, budgetMiddleware, idempotencyMiddleware)
 			tagGroup.GET("", middleware.RouteAuthMiddleware(sharedModel.ScopeRead), tagHandler.List)
 			tagGroup.GET("/search", middleware.RouteAuthMiddleware(sharedModel.ScopeRead), tagHandler.Search)
 			tagGroup.POST("", middleware.RouteAuthMiddleware(sharedModel.ScopeWrite), tagHandler.Create)
@@ -417,7 +641,7 @@ func main() {
 		}
 		{
 			predictionGroup := router.Group("/api/predictions")
-			predictionGroup.Use(authMiddleware, rateLimitMiddleware, budgetMiddleware)
+			predictionGroup.Use(authMiddleware, rateLimitMiddleware, budgetMiddleware, idempotencyMiddleware)
 			predictionGroup.GET("", middleware.RouteAuthMiddleware(sharedModel.ScopeRead), predictionHandler.List)
 			predictionGroup.GET(
 				"/transactions/:transactionId",
@@ -438,7 +662,7 @@ func main() {
 		}
 		{
 			embeddingGroup := router.Group("/api/embeddings")
-			embeddingGroup.Use(authMiddleware, rateLimitMiddleware)
+			embeddingGroup.Use(authMiddleware, rateLimitMiddleware, idempotencyMiddleware)
 			embeddingGroup.POST("", middleware.RouteAuthMiddleware(sharedModel.ScopeWrite), embeddingHandler.Create)
 			embeddingGroup.GET(
 				"/search",
@@ -448,7 +672,7 @@ func main() {
 		}
 		{
 			loanMetadataGroup := router.Group("/api/loan-metadata")
-			loanMetadataGroup.Use(authMiddleware, rateLimitMiddleware, budgetMiddleware)
+			loanMetadataGroup.Use(authMiddleware, rateLimitMiddleware, budgetMiddleware, idempotencyMiddleware)
 			loanMetadataGroup.GET("", middleware.RouteAuthMiddleware(sharedModel.ScopeRead), loanMetadataHandler.List)
 			loanMetadataGroup.GET(
 				":accountId",
@@ -471,6 +695,136 @@ func main() {
 				loanMetadataHandler.Delete,
 			)
 		}
+		{
+			scheduledTxnGroup := router.Group("/api/scheduled-transactions")
+			scheduledTxnGroup.Use(authMiddleware, rateLimitMiddleware, budgetMiddleware, idempotencyMiddleware)
+			scheduledTxnGroup.GET(
+				"",
+				middleware.RouteAuthMiddleware(sharedModel.ScopeRead),
+				scheduledTransactionHandler.List,
+			)
+			scheduledTxnGroup.GET(
+				":id",
+				middleware.RouteAuthMiddleware(sharedModel.ScopeRead),
+				scheduledTransactionHandler.GetById,
+			)
+			scheduledTxnGroup.POST(
+				"",
+				middleware.RouteAuthMiddleware(sharedModel.ScopeWrite),
+				scheduledTransactionHandler.Create,
+			)
+			scheduledTxnGroup.PATCH(
+				":id",
+				middleware.RouteAuthMiddleware(sharedModel.ScopeWrite),
+				scheduledTransactionHandler.Update,
+			)
+			scheduledTxnGroup.DELETE(
+				":id",
+				middleware.RouteAuthMiddleware(sharedModel.ScopeDelete),
+				scheduledTransactionHandler.DeleteById,
+			)
+		}
+		{
+			templateGroup := router.Group("/api/transaction-templates")
+			templateGroup.Use(authMiddleware, rateLimitMiddleware, budgetMiddleware, idempotencyMiddleware)
+			templateGroup.GET(
+				"",
+				middleware.RouteAuthMiddleware(sharedModel.ScopeRead),
+				transactionTemplateHandler.List,
+			)
+			templateGroup.GET(
+				":id",
+				middleware.RouteAuthMiddleware(sharedModel.ScopeRead),
+				transactionTemplateHandler.GetById,
+			)
+			templateGroup.POST(
+				"",
+				middleware.RouteAuthMiddleware(sharedModel.ScopeWrite),
+				transactionTemplateHandler.Create,
+			)
+			templateGroup.POST(
+				"from-transaction",
+				middleware.RouteAuthMiddleware(sharedModel.ScopeWrite),
+				transactionTemplateHandler.CreateFromTransaction,
+			)
+			templateGroup.POST(
+				":id/instantiate",
+				middleware.RouteAuthMiddleware(sharedModel.ScopeWrite),
+				transactionTemplateHandler.Instantiate,
+			)
+			templateGroup.PATCH(
+				":id",
+				middleware.RouteAuthMiddleware(sharedModel.ScopeWrite),
+				transactionTemplateHandler.Update,
+			)
+			templateGroup.DELETE(
+				":id",
+				middleware.RouteAuthMiddleware(sharedModel.ScopeDelete),
+				transactionTemplateHandler.DeleteById,
+			)
+		}
+		{
+			importGroup := router.Group("/api/imports")
+			importGroup.Use(authMiddleware, rateLimitMiddleware, budgetMiddleware, idempotencyMiddleware)
+			importGroup.POST(
+				"",
+				middleware.RouteAuthMiddleware(sharedModel.ScopeWrite),
+				importHandler.Import,
+			)
+			importGroup.GET(
+				"mappings",
+				middleware.RouteAuthMiddleware(sharedModel.ScopeRead),
+				importHandler.ListMappings,
+			)
+			importGroup.GET(
+				"mappings/:accountId",
+				middleware.RouteAuthMiddleware(sharedModel.ScopeRead),
+				importHandler.GetMapping,
+			)
+			importGroup.PUT(
+				"mappings/:accountId",
+				middleware.RouteAuthMiddleware(sharedModel.ScopeWrite),
+				importHandler.SaveMapping,
+			)
+			importGroup.DELETE(
+				"mappings/:accountId",
+				middleware.RouteAuthMiddleware(sharedModel.ScopeDelete),
+				importHandler.DeleteMapping,
+			)
+		}
+		{
+			exportGroup := router.Group("/api/exports")
+			exportGroup.Use(authMiddleware, rateLimitMiddleware, budgetMiddleware, idempotencyMiddleware)
+			exportGroup.GET(
+				"",
+				middleware.RouteAuthMiddleware(sharedModel.ScopeRead),
+				exportHandler.Export,
+			)
+		}
+		{
+			fxRateGroup := router.Group("/api/fx-rates")
+			fxRateGroup.Use(authMiddleware, rateLimitMiddleware, budgetMiddleware, idempotencyMiddleware)
+			fxRateGroup.GET(
+				"",
+				middleware.RouteAuthMiddleware(sharedModel.ScopeRead),
+				fxRateHandler.List,
+			)
+			fxRateGroup.POST(
+				"",
+				middleware.RouteAuthMiddleware(sharedModel.ScopeWrite),
+				fxRateHandler.Save,
+			)
+			fxRateGroup.POST(
+				"import",
+				middleware.RouteAuthMiddleware(sharedModel.ScopeWrite),
+				fxRateHandler.Import,
+			)
+			fxRateGroup.DELETE(
+				":id",
+				middleware.RouteAuthMiddleware(sharedModel.ScopeDelete),
+				fxRateHandler.DeleteById,
+			)
+		}
 	}
 	quit := make(chan os.Signal, 1)
 	signal.Notify(quit, os.Interrupt, syscall.SIGTERM)
@@ -500,6 +854,34 @@ func main() {
 		if err != nil {
 			logger.Logger(ctx).Warn("failed to create gmail watch schedule", "error", err)
 		}
+		_, err = temporalClient.ScheduleClient().Create(ctx, client.ScheduleOptions{
+			ID: "materialize-scheduled-transactions-workflow-schedule",
+			Spec: client.ScheduleSpec{
+				CronExpressions: []string{"0 1 * * *"}, // every day at 01:00 AM
+			},
+			Action: &client.ScheduleWorkflowAction{
+				ID:        "",
+				Workflow:  sharedModel.MaterializeScheduledTransactionsWorkflowName,
+				TaskQueue: sharedModel.PennywiseTaskQueue,
+			},
+		})
+		if err != nil {
+			logger.Logger(ctx).Warn("failed to create scheduled transactions schedule", "error", err)
+		}
+		_, err = temporalClient.ScheduleClient().Create(ctx, client.ScheduleOptions{
+			ID: "purge-trash-workflow-schedule",
+			Spec: client.ScheduleSpec{
+				CronExpressions: []string{"0 3 * * *"}, // every day at 03:00 AM
+			},
+			Action: &client.ScheduleWorkflowAction{
+				ID:        "",
+				Workflow:  sharedModel.PurgeTrashWorkflowName,
+				TaskQueue: sharedModel.PennywiseTaskQueue,
+			},
+		})
+		if err != nil {
+			logger.Logger(ctx).Warn("failed to create purge trash schedule", "error", err)
+		}
 
 		w := worker.New(temporalClient, sharedModel.PennywiseActivitiesTaskQueue, worker.Options{
 			BackgroundActivityContext: utils.WithInternalAuthToken(
@@ -520,6 +902,13 @@ func main() {
 		w.RegisterActivity(&temporalActivities.FetchGoogleUsersActivity{
 			AuthService: authService,
 		})
+		w.RegisterActivity(&temporalActivities.ScheduledTransactionActivity{
+			ScheduledTransactionService: scheduledTransactionService,
+			WebsocketService:            websocketService,
+		})
+		w.RegisterActivity(&temporalActivities.PurgeTrashActivity{
+			TrashService: trashService,
+		})
 
 		if err := w.Start(); err != nil {
 			logger.Logger(ctx).Error("failed to start temporal worker", "error", err)
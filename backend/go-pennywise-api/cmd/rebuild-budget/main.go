@@ -0,0 +1,93 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+
+	"github.com/Rishabh-Kapri/pennywise/backend/go-pennywise-api/internal/db"
+	"github.com/Rishabh-Kapri/pennywise/backend/go-pennywise-api/internal/service"
+
+	repository "github.com/Rishabh-Kapri/pennywise/backend/shared/db"
+	"github.com/Rishabh-Kapri/pennywise/backend/shared/model"
+	"github.com/Rishabh-Kapri/pennywise/backend/shared/utils"
+
+	"github.com/google/uuid"
+)
+
+// rebuild-budget recomputes the carryover balances of budgets from their transactions and budgeted amounts
+// and reports every category month stored wrong. With -repair the balances of a budget are overwritten in
+// a single db transaction. It exits with 1 when a rebuild failed or discrepancies were left alone.
+func main() {
+	os.Exit(run())
+}
+
+// run does the work of main and returns the exit code, so the db connection is closed before exiting
+func run() int {
+	var budgets string
+	var repair bool
+	flag.StringVar(&budgets, "budget", "", "comma separated ids of the budgets to rebuild")
+	flag.BoolVar(&repair, "repair", false, "overwrite the wrong carryover balances")
+	flag.Parse()
+
+	if budgets == "" {
+		flag.Usage()
+		return 2
+	}
+	var budgetIds []uuid.UUID
+	for _, value := range strings.Split(budgets, ",") {
+		budgetId, err := uuid.Parse(strings.TrimSpace(value))
+		if err != nil {
+			log.Printf("invalid budget id %q: %v", value, err)
+			return 2
+		}
+		budgetIds = append(budgetIds, budgetId)
+	}
+
+	ctx := context.Background()
+	dbConn := db.Connect(ctx)
+	defer dbConn.Close()
+
+	monthlyBudgetService := service.NewMonthlyBudgetService(
+		repository.NewMonthlyBudgetRepository(dbConn),
+		repository.NewCategoryRepository(dbConn),
+	)
+
+	unrepaired := false
+	for _, budgetId := range budgetIds {
+		result, err := monthlyBudgetService.Rebuild(utils.WithBudgetID(ctx, budgetId), repair)
+		if err != nil {
+			log.Printf("error rebuilding budget %v: %v", budgetId, err)
+			return 1
+		}
+		report(result)
+		unrepaired = unrepaired || (len(result.Discrepancies) > 0 && !result.Repaired)
+	}
+	if unrepaired {
+		return 1
+	}
+	return 0
+}
+
+func report(result *model.BudgetRebuildResult) {
+	log.Printf(
+		"budget %v: checked %d category months, %d discrepancies",
+		result.BudgetID, result.Checked, len(result.Discrepancies),
+	)
+	for _, d := range result.Discrepancies {
+		stored := "missing"
+		if d.Stored != nil {
+			stored = fmt.Sprintf("%.2f", *d.Stored)
+		}
+		log.Printf(
+			"  category %v %s: stored %s, expected %.2f, difference %.2f",
+			d.CategoryID, d.Month, stored, d.Expected, d.Difference,
+		)
+	}
+	if result.Repaired {
+		log.Printf("budget %v: repaired", result.BudgetID)
+	}
+}
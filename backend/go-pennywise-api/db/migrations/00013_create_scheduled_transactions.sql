@@ -0,0 +1,32 @@
+-- +goose Up
+-- +goose StatementBegin
+
+CREATE TABLE IF NOT EXISTS scheduled_transactions (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    budget_id UUID NOT NULL REFERENCES budgets(id) ON DELETE CASCADE,
+    account_id UUID NOT NULL REFERENCES accounts(id) ON DELETE CASCADE,
+    payee_id UUID NOT NULL REFERENCES payees(id) ON DELETE CASCADE,
+    category_id UUID REFERENCES categories(id) ON DELETE SET NULL,
+    amount NUMERIC(12, 2) NOT NULL,
+    note TEXT NOT NULL DEFAULT '',
+    tag_ids UUID[] NOT NULL DEFAULT '{}',
+    frequency TEXT NOT NULL CHECK (frequency IN ('MONTHLY_ON_DAY', 'EVERY_N_WEEKS', 'LAST_BUSINESS_DAY')),
+    interval_count INT NOT NULL DEFAULT 1 CHECK (interval_count > 0),
+    day_of_month INT CHECK (day_of_month BETWEEN 1 AND 31),
+    start_date TEXT NOT NULL,
+    end_date TEXT,
+    next_date TEXT,
+    deleted BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_scheduled_transactions_next_date
+    ON scheduled_transactions (next_date)
+    WHERE deleted = FALSE AND next_date IS NOT NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS scheduled_transactions;
+-- +goose StatementEnd
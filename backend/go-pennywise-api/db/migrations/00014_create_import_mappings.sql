@@ -0,0 +1,32 @@
+-- +goose Up
+-- +goose StatementBegin
+
+CREATE TABLE IF NOT EXISTS import_mappings (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    budget_id UUID NOT NULL REFERENCES budgets(id) ON DELETE CASCADE,
+    account_id UUID NOT NULL REFERENCES accounts(id) ON DELETE CASCADE,
+    delimiter TEXT NOT NULL DEFAULT ',',
+    skip_rows INT NOT NULL DEFAULT 0 CHECK (skip_rows >= 0),
+    date_column TEXT NOT NULL,
+    date_format TEXT NOT NULL DEFAULT 'YYYY-MM-DD',
+    amount_column TEXT,
+    inflow_column TEXT,
+    outflow_column TEXT,
+    payee_column TEXT,
+    memo_column TEXT,
+    invert_amount BOOLEAN NOT NULL DEFAULT FALSE,
+    deleted BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- one active mapping per account
+CREATE UNIQUE INDEX IF NOT EXISTS idx_import_mappings_account
+    ON import_mappings (budget_id, account_id)
+    WHERE deleted = FALSE;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS import_mappings;
+-- +goose StatementEnd
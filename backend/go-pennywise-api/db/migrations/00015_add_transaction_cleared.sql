@@ -0,0 +1,22 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TYPE cleared_status AS ENUM ('UNCLEARED', 'CLEARED', 'RECONCILED');
+
+ALTER TABLE transactions
+    ADD COLUMN IF NOT EXISTS cleared cleared_status NOT NULL DEFAULT 'UNCLEARED';
+
+ALTER TABLE accounts
+    ADD COLUMN IF NOT EXISTS last_reconciled_at TIMESTAMPTZ;
+
+CREATE INDEX IF NOT EXISTS idx_transactions_account_cleared
+    ON transactions (account_id, cleared)
+    WHERE deleted = FALSE;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_transactions_account_cleared;
+ALTER TABLE accounts DROP COLUMN IF EXISTS last_reconciled_at;
+ALTER TABLE transactions DROP COLUMN IF EXISTS cleared;
+DROP TYPE IF EXISTS cleared_status;
+-- +goose StatementEnd
@@ -0,0 +1,84 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+-- search_text is the plain text that is matched by trigram and highlighted in snippets,
+-- search_vector weights the payee and note above the summary, category and raw bank text
+ALTER TABLE transactions
+    ADD COLUMN IF NOT EXISTS search_text TEXT NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS search_vector tsvector NOT NULL DEFAULT ''::tsvector;
+
+CREATE OR REPLACE FUNCTION transactions_search_refresh() RETURNS TRIGGER AS $$
+DECLARE
+    payee_name TEXT;
+    category_name TEXT;
+BEGIN
+    SELECT name INTO payee_name FROM payees WHERE id = NEW.payee_id;
+    SELECT name INTO category_name FROM categories WHERE id = NEW.category_id;
+
+    NEW.search_text := concat_ws(' ', payee_name, NEW.note, NEW.summary, category_name, NEW.raw_bank_text);
+    NEW.search_vector :=
+        setweight(to_tsvector('simple', coalesce(payee_name, '')), 'A') ||
+        setweight(to_tsvector('simple', coalesce(NEW.note, '')), 'A') ||
+        setweight(to_tsvector('simple', coalesce(NEW.summary, '')), 'B') ||
+        setweight(to_tsvector('simple', coalesce(category_name, '')), 'C') ||
+        setweight(to_tsvector('simple', coalesce(NEW.raw_bank_text, '')), 'D');
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER transactions_search_refresh
+    BEFORE INSERT OR UPDATE OF payee_id, category_id, note, summary, raw_bank_text ON transactions
+    FOR EACH ROW EXECUTE FUNCTION transactions_search_refresh();
+
+-- renaming a payee or category touches the referencing transactions so the trigger above reindexes them
+CREATE OR REPLACE FUNCTION payees_search_rename() RETURNS TRIGGER AS $$
+BEGIN
+    UPDATE transactions SET payee_id = payee_id WHERE payee_id = NEW.id;
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER payees_search_rename
+    AFTER UPDATE OF name ON payees
+    FOR EACH ROW WHEN (OLD.name IS DISTINCT FROM NEW.name)
+    EXECUTE FUNCTION payees_search_rename();
+
+CREATE OR REPLACE FUNCTION categories_search_rename() RETURNS TRIGGER AS $$
+BEGIN
+    UPDATE transactions SET category_id = category_id WHERE category_id = NEW.id;
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER categories_search_rename
+    AFTER UPDATE OF name ON categories
+    FOR EACH ROW WHEN (OLD.name IS DISTINCT FROM NEW.name)
+    EXECUTE FUNCTION categories_search_rename();
+
+-- backfill existing transactions through the trigger
+UPDATE transactions SET payee_id = payee_id;
+
+CREATE INDEX IF NOT EXISTS idx_transactions_search_vector
+    ON transactions USING GIN (search_vector)
+    WHERE deleted = FALSE;
+
+CREATE INDEX IF NOT EXISTS idx_transactions_search_text_trgm
+    ON transactions USING GIN (search_text gin_trgm_ops)
+    WHERE deleted = FALSE;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TRIGGER IF EXISTS categories_search_rename ON categories;
+DROP TRIGGER IF EXISTS payees_search_rename ON payees;
+DROP TRIGGER IF EXISTS transactions_search_refresh ON transactions;
+DROP FUNCTION IF EXISTS categories_search_rename();
+DROP FUNCTION IF EXISTS payees_search_rename();
+DROP FUNCTION IF EXISTS transactions_search_refresh();
+DROP INDEX IF EXISTS idx_transactions_search_text_trgm;
+DROP INDEX IF EXISTS idx_transactions_search_vector;
+ALTER TABLE transactions
+    DROP COLUMN IF EXISTS search_vector,
+    DROP COLUMN IF EXISTS search_text;
+-- +goose StatementEnd
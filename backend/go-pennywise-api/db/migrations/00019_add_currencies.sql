@@ -0,0 +1,41 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE budgets
+    ADD COLUMN IF NOT EXISTS currency TEXT NOT NULL DEFAULT 'INR' CHECK (currency ~ '^[A-Z]{3}$');
+
+ALTER TABLE accounts
+    ADD COLUMN IF NOT EXISTS currency TEXT NOT NULL DEFAULT 'INR' CHECK (currency ~ '^[A-Z]{3}$');
+
+-- amount stays in the account currency, the original columns keep what the merchant charged
+ALTER TABLE transactions
+    ADD COLUMN IF NOT EXISTS original_amount NUMERIC(12, 2),
+    ADD COLUMN IF NOT EXISTS original_currency TEXT CHECK (original_currency ~ '^[A-Z]{3}$'),
+    ADD COLUMN IF NOT EXISTS fx_rate NUMERIC(18, 6);
+
+-- 1 unit of base_currency is worth rate units of quote_currency on date
+CREATE TABLE IF NOT EXISTS fx_rates (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    budget_id UUID NOT NULL REFERENCES budgets(id) ON DELETE CASCADE,
+    date TEXT NOT NULL,
+    base_currency TEXT NOT NULL CHECK (base_currency ~ '^[A-Z]{3}$'),
+    quote_currency TEXT NOT NULL CHECK (quote_currency ~ '^[A-Z]{3}$'),
+    rate NUMERIC(18, 6) NOT NULL CHECK (rate > 0),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    UNIQUE (budget_id, base_currency, quote_currency, date)
+);
+
+CREATE INDEX IF NOT EXISTS idx_fx_rates_lookup
+    ON fx_rates (budget_id, base_currency, quote_currency, date DESC);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS fx_rates;
+ALTER TABLE transactions
+    DROP COLUMN IF EXISTS fx_rate,
+    DROP COLUMN IF EXISTS original_currency,
+    DROP COLUMN IF EXISTS original_amount;
+ALTER TABLE accounts DROP COLUMN IF EXISTS currency;
+ALTER TABLE budgets DROP COLUMN IF EXISTS currency;
+-- +goose StatementEnd
@@ -0,0 +1,24 @@
+-- +goose Up
+-- +goose StatementBegin
+-- suspected duplicates flagged on creation, transaction_id is the newer transaction
+CREATE TABLE IF NOT EXISTS transaction_duplicates (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    budget_id UUID NOT NULL REFERENCES budgets(id) ON DELETE CASCADE,
+    transaction_id UUID NOT NULL REFERENCES transactions(id) ON DELETE CASCADE,
+    duplicate_of_id UUID NOT NULL REFERENCES transactions(id) ON DELETE CASCADE,
+    score NUMERIC(4, 3) NOT NULL,
+    status TEXT NOT NULL DEFAULT 'PENDING' CHECK (status IN ('PENDING', 'MERGED', 'DISMISSED')),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    UNIQUE (budget_id, transaction_id, duplicate_of_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_transaction_duplicates_pending
+    ON transaction_duplicates (budget_id, created_at)
+    WHERE status = 'PENDING';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS transaction_duplicates;
+-- +goose StatementEnd
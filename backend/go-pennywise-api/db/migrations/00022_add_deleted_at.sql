@@ -0,0 +1,114 @@
+-- +goose Up
+-- +goose StatementBegin
+-- deleted_at tracks when an entity went to the trash, it drives the trash listing and the purge
+ALTER TABLE transactions ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMPTZ;
+ALTER TABLE payees ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMPTZ;
+ALTER TABLE categories ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMPTZ;
+ALTER TABLE accounts ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMPTZ;
+ALTER TABLE payee_rules ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMPTZ;
+
+-- rows deleted before this migration start their retention now
+UPDATE transactions SET deleted_at = NOW() WHERE deleted = TRUE;
+UPDATE payees SET deleted_at = NOW() WHERE deleted = TRUE;
+UPDATE categories SET deleted_at = NOW() WHERE deleted = TRUE;
+UPDATE accounts SET deleted_at = NOW() WHERE deleted = TRUE;
+UPDATE payee_rules SET deleted_at = NOW() WHERE deleted = TRUE;
+
+CREATE OR REPLACE FUNCTION set_deleted_at() RETURNS TRIGGER AS $$
+BEGIN
+    IF COALESCE(NEW.deleted, FALSE) AND NOT COALESCE(OLD.deleted, FALSE) THEN
+        NEW.deleted_at := NOW();
+    ELSIF NOT COALESCE(NEW.deleted, FALSE) THEN
+        NEW.deleted_at := NULL;
+    END IF;
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER transactions_set_deleted_at
+    BEFORE UPDATE OF deleted ON transactions
+    FOR EACH ROW EXECUTE FUNCTION set_deleted_at();
+
+CREATE TRIGGER payees_set_deleted_at
+    BEFORE UPDATE OF deleted ON payees
+    FOR EACH ROW EXECUTE FUNCTION set_deleted_at();
+
+CREATE TRIGGER categories_set_deleted_at
+    BEFORE UPDATE OF deleted ON categories
+    FOR EACH ROW EXECUTE FUNCTION set_deleted_at();
+
+CREATE TRIGGER accounts_set_deleted_at
+    BEFORE UPDATE OF deleted ON accounts
+    FOR EACH ROW EXECUTE FUNCTION set_deleted_at();
+
+CREATE TRIGGER payee_rules_set_deleted_at
+    BEFORE UPDATE OF deleted ON payee_rules
+    FOR EACH ROW EXECUTE FUNCTION set_deleted_at();
+
+-- the rules of a payee go to the trash with it, restoring the payee brings back
+-- the rules deleted along with it but not the ones deleted on their own before
+CREATE OR REPLACE FUNCTION payees_cascade_deleted() RETURNS TRIGGER AS $$
+BEGIN
+    IF COALESCE(NEW.deleted, FALSE) THEN
+        UPDATE payee_rules SET deleted = TRUE, updated_at = NOW()
+        WHERE payee_id = NEW.id AND COALESCE(deleted, FALSE) = FALSE;
+    ELSE
+        UPDATE payee_rules SET deleted = FALSE, updated_at = NOW()
+        WHERE payee_id = NEW.id AND deleted = TRUE AND deleted_at = OLD.deleted_at;
+    END IF;
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER payees_cascade_deleted
+    AFTER UPDATE OF deleted ON payees
+    FOR EACH ROW WHEN (OLD.deleted IS DISTINCT FROM NEW.deleted)
+    EXECUTE FUNCTION payees_cascade_deleted();
+
+-- an account takes its transfer payee along the same way
+CREATE OR REPLACE FUNCTION accounts_cascade_deleted() RETURNS TRIGGER AS $$
+BEGIN
+    IF COALESCE(NEW.deleted, FALSE) THEN
+        UPDATE payees SET deleted = TRUE, updated_at = NOW()
+        WHERE id = NEW.transfer_payee_id AND COALESCE(deleted, FALSE) = FALSE;
+    ELSE
+        UPDATE payees SET deleted = FALSE, updated_at = NOW()
+        WHERE id = NEW.transfer_payee_id AND deleted = TRUE AND deleted_at = OLD.deleted_at;
+    END IF;
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER accounts_cascade_deleted
+    AFTER UPDATE OF deleted ON accounts
+    FOR EACH ROW WHEN (OLD.deleted IS DISTINCT FROM NEW.deleted)
+    EXECUTE FUNCTION accounts_cascade_deleted();
+
+CREATE INDEX IF NOT EXISTS idx_transactions_trash ON transactions(budget_id, deleted_at) WHERE deleted = TRUE;
+CREATE INDEX IF NOT EXISTS idx_payees_trash ON payees(budget_id, deleted_at) WHERE deleted = TRUE;
+CREATE INDEX IF NOT EXISTS idx_categories_trash ON categories(budget_id, deleted_at) WHERE deleted = TRUE;
+CREATE INDEX IF NOT EXISTS idx_accounts_trash ON accounts(budget_id, deleted_at) WHERE deleted = TRUE;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_accounts_trash;
+DROP INDEX IF EXISTS idx_categories_trash;
+DROP INDEX IF EXISTS idx_payees_trash;
+DROP INDEX IF EXISTS idx_transactions_trash;
+DROP TRIGGER IF EXISTS accounts_cascade_deleted ON accounts;
+DROP TRIGGER IF EXISTS payees_cascade_deleted ON payees;
+DROP TRIGGER IF EXISTS payee_rules_set_deleted_at ON payee_rules;
+DROP TRIGGER IF EXISTS accounts_set_deleted_at ON accounts;
+DROP TRIGGER IF EXISTS categories_set_deleted_at ON categories;
+DROP TRIGGER IF EXISTS payees_set_deleted_at ON payees;
+DROP TRIGGER IF EXISTS transactions_set_deleted_at ON transactions;
+DROP FUNCTION IF EXISTS accounts_cascade_deleted();
+DROP FUNCTION IF EXISTS payees_cascade_deleted();
+DROP FUNCTION IF EXISTS set_deleted_at();
+ALTER TABLE payee_rules DROP COLUMN IF EXISTS deleted_at;
+ALTER TABLE accounts DROP COLUMN IF EXISTS deleted_at;
+ALTER TABLE categories DROP COLUMN IF EXISTS deleted_at;
+ALTER TABLE payees DROP COLUMN IF EXISTS deleted_at;
+ALTER TABLE transactions DROP COLUMN IF EXISTS deleted_at;
+-- +goose StatementEnd
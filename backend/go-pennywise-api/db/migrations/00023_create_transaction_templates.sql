@@ -0,0 +1,28 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS transaction_templates (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    budget_id UUID NOT NULL REFERENCES budgets(id) ON DELETE CASCADE,
+    name TEXT NOT NULL,
+    account_id UUID REFERENCES accounts(id) ON DELETE SET NULL,
+    payee_id UUID REFERENCES payees(id) ON DELETE SET NULL,
+    category_id UUID REFERENCES categories(id) ON DELETE SET NULL,
+    amount NUMERIC(12, 2) NOT NULL DEFAULT 0,
+    note TEXT NOT NULL DEFAULT '',
+    tag_ids UUID[] NOT NULL DEFAULT '{}',
+    use_count INT NOT NULL DEFAULT 0,
+    last_used_at TIMESTAMPTZ,
+    deleted BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS uniq_transaction_templates_budget_name
+    ON transaction_templates (budget_id, LOWER(name))
+    WHERE deleted = FALSE;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS transaction_templates;
+-- +goose StatementEnd
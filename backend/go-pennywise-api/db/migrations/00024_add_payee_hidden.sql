@@ -0,0 +1,15 @@
+-- +goose Up
+-- +goose StatementBegin
+-- hidden payees are left out of payee lists but still resolve on existing transactions,
+-- the transfer payee of a closed account is hidden
+ALTER TABLE payees ADD COLUMN IF NOT EXISTS hidden BOOLEAN NOT NULL DEFAULT FALSE;
+
+UPDATE payees SET hidden = TRUE
+FROM accounts
+WHERE accounts.transfer_payee_id = payees.id AND accounts.closed = TRUE;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE payees DROP COLUMN IF EXISTS hidden;
+-- +goose StatementEnd
@@ -0,0 +1,19 @@
+-- +goose Up
+-- +goose StatementBegin
+-- a category has at most one goal, target_month (YYYY-MM) is only used by TARGET_BALANCE goals
+CREATE TABLE IF NOT EXISTS category_goals (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    budget_id UUID NOT NULL REFERENCES budgets(id) ON DELETE CASCADE,
+    category_id UUID NOT NULL UNIQUE REFERENCES categories(id) ON DELETE CASCADE,
+    type TEXT NOT NULL CHECK (type IN ('MONTHLY_FUNDING', 'TARGET_BALANCE', 'NEEDED_FOR_SPENDING')),
+    amount NUMERIC(12, 2) NOT NULL CHECK (amount > 0),
+    target_month TEXT,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS category_goals;
+-- +goose StatementEnd
@@ -0,0 +1,23 @@
+-- +goose Up
+-- +goose StatementBegin
+-- a move of budgeted money between two categories in a month, a NULL category is Ready to Assign
+CREATE TABLE IF NOT EXISTS budget_moves (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    budget_id UUID NOT NULL REFERENCES budgets(id) ON DELETE CASCADE,
+    month TEXT NOT NULL,
+    from_category_id UUID REFERENCES categories(id) ON DELETE CASCADE,
+    to_category_id UUID REFERENCES categories(id) ON DELETE CASCADE,
+    amount NUMERIC(12, 2) NOT NULL CHECK (amount > 0),
+    note TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CHECK (from_category_id IS NOT NULL OR to_category_id IS NOT NULL)
+);
+
+CREATE INDEX IF NOT EXISTS idx_budget_moves_budget_month
+    ON budget_moves (budget_id, month);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS budget_moves;
+-- +goose StatementEnd
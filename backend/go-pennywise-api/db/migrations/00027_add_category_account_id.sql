@@ -0,0 +1,96 @@
+-- +goose Up
+-- +goose StatementBegin
+-- the credit card account a payment category belongs to, NULL for every other category
+ALTER TABLE categories ADD COLUMN IF NOT EXISTS account_id UUID REFERENCES accounts(id);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_categories_account_id
+    ON categories (account_id) WHERE account_id IS NOT NULL AND deleted = FALSE;
+
+-- every existing credit card gets its payment category in the budget's credit card payments group
+INSERT INTO categories (budget_id, name, category_group_id, note, is_system, hidden, deleted, account_id, created_at, updated_at)
+SELECT a.budget_id, a.name, (b.metadata->>'ccGroupId')::uuid, '', TRUE, a.closed, FALSE, a.id, NOW(), NOW()
+FROM accounts a
+JOIN budgets b ON b.id = a.budget_id
+WHERE a.type = 'creditCard' AND a.deleted = FALSE AND b.metadata->>'ccGroupId' IS NOT NULL;
+
+-- the new payment categories start with the card activity so far, kept the same way as when transactions
+-- are saved: spending on the card moves into the payment category and payments into the card take it out
+WITH payment_categories AS (
+    SELECT c.id, c.budget_id, c.account_id, (b.metadata->>'inflowCategoryId')::uuid AS inflow_category_id
+    FROM categories c
+    JOIN budgets b ON b.id = c.budget_id
+    WHERE c.account_id IS NOT NULL AND c.deleted = FALSE
+), card_transactions AS (
+    SELECT p.id AS payment_category_id, p.budget_id, p.inflow_category_id,
+        t.id, t.date, t.amount, t.category_id, t.transfer_account_id,
+        EXISTS (
+            SELECT 1 FROM transaction_splits s WHERE s.transaction_id = t.id AND s.deleted = FALSE
+        ) AS is_split
+    FROM transactions t
+    JOIN payment_categories p ON p.account_id = t.account_id
+    WHERE t.deleted = FALSE
+), lines AS (
+    SELECT payment_category_id, budget_id, LEFT(date, 7) AS month, -amount AS amount
+    FROM card_transactions
+    WHERE NOT is_split AND category_id IS NOT NULL AND category_id IS DISTINCT FROM inflow_category_id
+    UNION ALL
+    SELECT t.payment_category_id, t.budget_id, LEFT(t.date, 7), -s.amount
+    FROM card_transactions t
+    JOIN transaction_splits s ON s.transaction_id = t.id
+    WHERE s.deleted = FALSE AND s.category_id IS NOT NULL AND s.category_id IS DISTINCT FROM t.inflow_category_id
+    UNION ALL
+    SELECT payment_category_id, budget_id, LEFT(date, 7), -amount
+    FROM card_transactions
+    WHERE NOT is_split AND category_id IS NULL AND transfer_account_id IS NOT NULL AND amount > 0
+), activity AS (
+    SELECT payment_category_id, budget_id, month, SUM(amount) AS amount
+    FROM lines
+    GROUP BY payment_category_id, budget_id, month
+)
+INSERT INTO monthly_budgets (budget_id, category_id, month, budgeted, carryover_balance, created_at, updated_at)
+SELECT budget_id, payment_category_id, month, 0, carryover, NOW(), NOW()
+FROM (
+    SELECT *, SUM(amount) OVER (PARTITION BY payment_category_id ORDER BY month) AS carryover
+    FROM activity
+) running
+WHERE ROUND(amount, 2) <> 0;
+
+-- an account takes its payment category along with its transfer payee
+CREATE OR REPLACE FUNCTION accounts_cascade_deleted() RETURNS TRIGGER AS $$
+BEGIN
+    IF COALESCE(NEW.deleted, FALSE) THEN
+        UPDATE payees SET deleted = TRUE, updated_at = NOW()
+        WHERE id = NEW.transfer_payee_id AND COALESCE(deleted, FALSE) = FALSE;
+        UPDATE categories SET deleted = TRUE, updated_at = NOW()
+        WHERE account_id = NEW.id AND COALESCE(deleted, FALSE) = FALSE;
+    ELSE
+        UPDATE payees SET deleted = FALSE, updated_at = NOW()
+        WHERE id = NEW.transfer_payee_id AND deleted = TRUE AND deleted_at = OLD.deleted_at;
+        UPDATE categories SET deleted = FALSE, updated_at = NOW()
+        WHERE account_id = NEW.id AND deleted = TRUE AND deleted_at = OLD.deleted_at;
+    END IF;
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+CREATE OR REPLACE FUNCTION accounts_cascade_deleted() RETURNS TRIGGER AS $$
+BEGIN
+    IF COALESCE(NEW.deleted, FALSE) THEN
+        UPDATE payees SET deleted = TRUE, updated_at = NOW()
+        WHERE id = NEW.transfer_payee_id AND COALESCE(deleted, FALSE) = FALSE;
+    ELSE
+        UPDATE payees SET deleted = FALSE, updated_at = NOW()
+        WHERE id = NEW.transfer_payee_id AND deleted = TRUE AND deleted_at = OLD.deleted_at;
+    END IF;
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+DELETE FROM monthly_budgets WHERE category_id IN (SELECT id FROM categories WHERE account_id IS NOT NULL);
+DELETE FROM categories WHERE account_id IS NOT NULL;
+DROP INDEX IF EXISTS idx_categories_account_id;
+ALTER TABLE categories DROP COLUMN IF EXISTS account_id;
+-- +goose StatementEnd
@@ -0,0 +1,10 @@
+-- +goose Up
+-- +goose StatementBegin
+-- an overspent category starts the next month at zero unless it rolls its negative balance over
+ALTER TABLE categories ADD COLUMN IF NOT EXISTS rollover_negative BOOLEAN NOT NULL DEFAULT FALSE;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE categories DROP COLUMN IF EXISTS rollover_negative;
+-- +goose StatementEnd
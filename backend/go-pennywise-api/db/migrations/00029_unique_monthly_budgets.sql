@@ -0,0 +1,31 @@
+-- +goose Up
+-- +goose StatementBegin
+-- a category has a single monthly budget per month. Duplicates left by concurrent creates are folded
+-- into the latest one, keeping everything budgeted; their carryovers can be fixed with rebuild-budget.
+WITH duplicates AS (
+    SELECT id, budget_id, category_id, month,
+        ROW_NUMBER() OVER (PARTITION BY budget_id, category_id, month ORDER BY updated_at DESC, id) AS position,
+        SUM(budgeted) OVER (PARTITION BY budget_id, category_id, month) AS total_budgeted
+    FROM monthly_budgets
+), kept AS (
+    UPDATE monthly_budgets SET budgeted = duplicates.total_budgeted, updated_at = NOW()
+    FROM duplicates
+    WHERE monthly_budgets.id = duplicates.id AND duplicates.position = 1
+        AND EXISTS (
+            SELECT 1 FROM duplicates d
+            WHERE d.budget_id = duplicates.budget_id AND d.category_id = duplicates.category_id
+                AND d.month = duplicates.month AND d.position > 1
+        )
+)
+DELETE FROM monthly_budgets
+WHERE id IN (SELECT id FROM duplicates WHERE position > 1);
+
+DROP INDEX IF EXISTS idx_monthly_budgets_lookup;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_monthly_budgets_lookup ON monthly_budgets(budget_id, category_id, month);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_monthly_budgets_lookup;
+CREATE INDEX IF NOT EXISTS idx_monthly_budgets_lookup ON monthly_budgets(budget_id, category_id, month);
+-- +goose StatementEnd
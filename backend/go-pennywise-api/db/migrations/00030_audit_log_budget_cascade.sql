@@ -0,0 +1,22 @@
+-- +goose Up
+-- +goose StatementBegin
+-- the audit log stays append only, but its entries go with their budget when the budget is deleted,
+-- otherwise the ON DELETE CASCADE of budget_id would make every budget delete fail
+CREATE OR REPLACE FUNCTION audit_log_append_only() RETURNS TRIGGER AS $$
+BEGIN
+    IF TG_OP = 'DELETE' AND NOT EXISTS (SELECT 1 FROM budgets WHERE id = OLD.budget_id) THEN
+        RETURN OLD;
+    END IF;
+    RAISE EXCEPTION 'audit_log is append only';
+END;
+$$ LANGUAGE plpgsql;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+CREATE OR REPLACE FUNCTION audit_log_append_only() RETURNS TRIGGER AS $$
+BEGIN
+    RAISE EXCEPTION 'audit_log is append only';
+END;
+$$ LANGUAGE plpgsql;
+-- +goose StatementEnd
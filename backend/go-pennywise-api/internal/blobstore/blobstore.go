@@ -0,0 +1,19 @@
+package blobstore
+
+import (
+	"context"
+	"errors"
+	"io"
+)
+
+// ErrNotFound is returned when no blob is stored under a key
+var ErrNotFound = errors.New("blob not found")
+
+// BlobStore stores file contents by key. Keys are slash separated paths such as
+// "<budgetId>/<transactionId>/<attachmentId>", backends map them onto their own layout.
+type BlobStore interface {
+	Put(ctx context.Context, key string, r io.Reader) error
+	// Get returns the contents of the blob, the caller has to close it
+	Get(ctx context.Context, key string) (io.ReadCloser, error)
+	Delete(ctx context.Context, key string) error
+}
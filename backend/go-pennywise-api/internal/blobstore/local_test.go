@@ -0,0 +1,39 @@
+package blobstore
+
+import (
+	"context"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLocalStore(t *testing.T) {
+	ctx := context.Background()
+	store, err := NewLocalStore(t.TempDir())
+	require.NoError(t, err)
+
+	t.Run("round_trip", func(t *testing.T) {
+		require.NoError(t, store.Put(ctx, "budget/txn/file", strings.NewReader("receipt")))
+		reader, err := store.Get(ctx, "budget/txn/file")
+		require.NoError(t, err)
+		data, err := io.ReadAll(reader)
+		require.NoError(t, reader.Close())
+		require.NoError(t, err)
+		assert.Equal(t, "receipt", string(data))
+
+		require.NoError(t, store.Delete(ctx, "budget/txn/file"))
+		_, err = store.Get(ctx, "budget/txn/file")
+		assert.ErrorIs(t, err, ErrNotFound)
+		// deleting twice is not an error
+		assert.NoError(t, store.Delete(ctx, "budget/txn/file"))
+	})
+
+	t.Run("rejects_keys_outside_root", func(t *testing.T) {
+		for _, key := range []string{"", "../escape", "a/../../escape", "/etc/passwd"} {
+			assert.Error(t, store.Put(ctx, key, strings.NewReader("x")), key)
+		}
+	})
+}
@@ -2,6 +2,7 @@ package config
 
 import (
 	"os"
+	"strconv"
 
 	"github.com/joho/godotenv"
 )
@@ -24,6 +25,8 @@ type Config struct {
 	InternalAuthToken     string
 	TemporalServerHost    string
 	TemporalServerPort    string
+	AttachmentsDir        string
+	TrashRetentionDays    int
 }
 
 func Load() Config {
@@ -32,6 +35,12 @@ func Load() Config {
 	if env == "" {
 		env = "local"
 	}
+	attachmentsDir := os.Getenv("ATTACHMENTS_DIR")
+	if attachmentsDir == "" {
+		attachmentsDir = "data/attachments"
+	}
+	// zero falls back to model.DefaultTrashRetentionDays
+	trashRetentionDays, _ := strconv.Atoi(os.Getenv("TRASH_RETENTION_DAYS"))
 	return Config{
 		Environment:           env,
 		ServiceName:           "pennywise-api",
@@ -53,5 +62,8 @@ func Load() Config {
 
 		TemporalServerHost: os.Getenv("TEMPORAL_SERVER_HOST"),
 		TemporalServerPort: os.Getenv("TEMPORAL_SERVER_PORT"),
+
+		AttachmentsDir:     attachmentsDir,
+		TrashRetentionDays: trashRetentionDays,
 	}
 }
@@ -1,19 +1,27 @@
 package handler
 
 import (
+	stderrors "errors"
 	"net/http"
 	"strings"
 
 	"github.com/Rishabh-Kapri/pennywise/backend/go-pennywise-api/internal/service"
+	errs "github.com/Rishabh-Kapri/pennywise/backend/shared/errors"
 	"github.com/Rishabh-Kapri/pennywise/backend/shared/model"
 
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
 )
 
 type AccountHandler interface {
 	List(c *gin.Context)
 	Search(c *gin.Context)
 	Create(c *gin.Context)
+	Reconcile(c *gin.Context)
+	Update(c *gin.Context)
+	Close(c *gin.Context)
+	Reopen(c *gin.Context)
+	DeleteById(c *gin.Context)
 }
 
 type accountHandler struct {
@@ -61,3 +69,115 @@ func (h *accountHandler) Create(c *gin.Context) {
 	}
 	c.JSON(http.StatusCreated, createdAcc)
 }
+
+func (h *accountHandler) Reconcile(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Error while parsing id"})
+		return
+	}
+	var body model.ReconcileRequest
+	if err := c.ShouldBindJSON(&body); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	result, err := h.service.Reconcile(ctx, id, body)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, result)
+}
+
+func (h *accountHandler) Update(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Error while parsing id"})
+		return
+	}
+	var body model.UpdateAccountRequest
+	if err := c.ShouldBindJSON(&body); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	account, err := h.service.Update(ctx, id, body)
+	if err != nil {
+		c.JSON(accountErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, account)
+}
+
+func (h *accountHandler) Close(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Error while parsing id"})
+		return
+	}
+	// an empty body closes an account that has no balance
+	var body model.CloseAccountRequest
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&body); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+	result, err := h.service.Close(ctx, id, body)
+	if err != nil {
+		c.JSON(accountErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, result)
+}
+
+func (h *accountHandler) Reopen(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Error while parsing id"})
+		return
+	}
+	account, err := h.service.Reopen(ctx, id)
+	if err != nil {
+		c.JSON(accountErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, account)
+}
+
+func (h *accountHandler) DeleteById(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Error while parsing id"})
+		return
+	}
+	if err := h.service.DeleteById(ctx, id); err != nil {
+		c.JSON(accountErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "account deleted"})
+}
+
+func accountErrorStatus(err error) int {
+	var apiErr *errs.Error
+	if stderrors.As(err, &apiErr) {
+		switch apiErr.Code {
+		case errs.CodeInvalidArgument:
+			return http.StatusBadRequest
+		case errs.CodeAccountNotFound:
+			return http.StatusNotFound
+		case errs.CodeAccountClosed, errs.CodeAccountHasBalance, errs.CodeAccountHasTransactions, errs.CodeTransactionLocked:
+			return http.StatusConflict
+		}
+	}
+	return http.StatusInternalServerError
+}
@@ -0,0 +1,166 @@
+package handler
+
+import (
+	stderrors "errors"
+	"mime"
+	"net/http"
+
+	"github.com/Rishabh-Kapri/pennywise/backend/go-pennywise-api/internal/service"
+	errs "github.com/Rishabh-Kapri/pennywise/backend/shared/errors"
+	"github.com/Rishabh-Kapri/pennywise/backend/shared/model"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type AttachmentHandler interface {
+	List(c *gin.Context)
+	Upload(c *gin.Context)
+	Download(c *gin.Context)
+	DeleteById(c *gin.Context)
+}
+
+type attachmentHandler struct {
+	service service.AttachmentService
+}
+
+func NewAttachmentHandler(service service.AttachmentService) AttachmentHandler {
+	return &attachmentHandler{service: service}
+}
+
+func (h *attachmentHandler) List(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	transactionId, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Error while parsing id"})
+		return
+	}
+	attachments, err := h.service.GetAll(ctx, transactionId)
+	if err != nil {
+		c.JSON(attachmentErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, attachments)
+}
+
+// Upload handles multipart uploads, the file is sent as "file"
+func (h *attachmentHandler) Upload(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	transactionId, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Error while parsing id"})
+		return
+	}
+	limitUpload(c, model.MaxAttachmentSize)
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		c.JSON(formFileError(err))
+		return
+	}
+	if fileHeader.Size > model.MaxAttachmentSize {
+		c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": "file is too large"})
+		return
+	}
+	file, err := fileHeader.Open()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	defer file.Close()
+
+	attachment, err := h.service.Upload(ctx, transactionId, fileHeader.Filename, file)
+	if err != nil {
+		c.JSON(attachmentErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, attachment)
+}
+
+func (h *attachmentHandler) Download(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	transactionId, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Error while parsing id"})
+		return
+	}
+	id, err := uuid.Parse(c.Param("attachmentId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Error while parsing attachmentId"})
+		return
+	}
+	attachment, reader, err := h.service.Open(ctx, transactionId, id)
+	if err != nil {
+		c.JSON(attachmentErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	defer reader.Close()
+
+	c.DataFromReader(
+		http.StatusOK,
+		attachment.Size,
+		attachment.ContentType,
+		reader,
+		map[string]string{
+			"Content-Disposition":    mime.FormatMediaType("attachment", map[string]string{"filename": attachment.FileName}),
+			"X-Content-Type-Options": "nosniff",
+		},
+	)
+}
+
+func (h *attachmentHandler) DeleteById(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	transactionId, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Error while parsing id"})
+		return
+	}
+	id, err := uuid.Parse(c.Param("attachmentId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Error while parsing attachmentId"})
+		return
+	}
+	if err := h.service.DeleteById(ctx, transactionId, id); err != nil {
+		c.JSON(attachmentErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "attachment deleted"})
+}
+
+// maxMultipartOverhead leaves room for the boundaries and the other fields of an upload form
+const maxMultipartOverhead = 1 << 20
+
+// limitUpload caps the body of an upload taking a file of up to maxFileSize, so an oversized request
+// is cut off while the form is parsed instead of being spooled to disk first
+func limitUpload(c *gin.Context, maxFileSize int64) {
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxFileSize+maxMultipartOverhead)
+}
+
+// formFileError returns the status and body for an upload whose file couldn't be read
+func formFileError(err error) (int, gin.H) {
+	var tooLarge *http.MaxBytesError
+	if stderrors.As(err, &tooLarge) {
+		return http.StatusRequestEntityTooLarge, gin.H{"error": "file is too large"}
+	}
+	return http.StatusBadRequest, gin.H{"error": "file is required"}
+}
+
+func attachmentErrorStatus(err error) int {
+	var apiErr *errs.Error
+	if stderrors.As(err, &apiErr) {
+		switch apiErr.Code {
+		case errs.CodeInvalidArgument:
+			return http.StatusBadRequest
+		case errs.CodeTransactionNotFound, errs.CodeAttachmentNotFound:
+			return http.StatusNotFound
+		case errs.CodeAttachmentTooLarge:
+			return http.StatusRequestEntityTooLarge
+		case errs.CodeAttachmentUnsupported:
+			return http.StatusUnsupportedMediaType
+		}
+	}
+	return http.StatusInternalServerError
+}
@@ -0,0 +1,40 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/Rishabh-Kapri/pennywise/backend/go-pennywise-api/internal/service"
+	"github.com/Rishabh-Kapri/pennywise/backend/shared/model"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type AuditHandler interface {
+	TransactionHistory(c *gin.Context)
+}
+
+type auditHandler struct {
+	service service.AuditService
+}
+
+func NewAuditHandler(service service.AuditService) AuditHandler {
+	return &auditHandler{service: service}
+}
+
+// TransactionHistory lists the audit trail of a transaction, oldest change first
+func (h *auditHandler) TransactionHistory(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Error while parsing id"})
+		return
+	}
+	history, err := h.service.GetHistory(ctx, model.AuditEntityTransaction, id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, history)
+}
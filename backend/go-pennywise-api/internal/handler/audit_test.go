@@ -0,0 +1,69 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/Rishabh-Kapri/pennywise/backend/go-pennywise-api/internal/service"
+	"github.com/Rishabh-Kapri/pennywise/backend/shared/model"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type mockAuditService struct{ mock.Mock }
+
+func (m *mockAuditService) Record(ctx context.Context, tx pgx.Tx, record service.AuditRecord) error {
+	return m.Called(ctx, tx, record).Error(0)
+}
+
+func (m *mockAuditService) GetHistory(
+	ctx context.Context,
+	entityType model.AuditEntityType,
+	entityId uuid.UUID,
+) ([]model.AuditLogEntry, error) {
+	args := m.Called(ctx, entityType, entityId)
+	if v := args.Get(0); v != nil {
+		return v.([]model.AuditLogEntry), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func TestAuditHandler_TransactionHistory(t *testing.T) {
+	txnId := uuid.New()
+
+	t.Run("returns_history", func(t *testing.T) {
+		svc := &mockAuditService{}
+		svc.On("GetHistory", mock.Anything, model.AuditEntityTransaction, txnId).Return([]model.AuditLogEntry{{
+			EntityID:  txnId,
+			Action:    model.AuditActionUpdate,
+			ActorType: model.AuditActorUser,
+			Changes:   []model.AuditChange{{Field: "amount", Before: -10.0, After: -12.0}},
+		}}, nil)
+		w, c := makeReq(http.MethodGet, "/api/transactions/"+txnId.String()+"/history", nil)
+		c.Params = gin.Params{{Key: "id", Value: txnId.String()}}
+		NewAuditHandler(svc).TransactionHistory(c)
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), `"field":"amount"`)
+	})
+
+	t.Run("invalid_id", func(t *testing.T) {
+		w, c := makeReq(http.MethodGet, "/api/transactions/bad/history", nil)
+		c.Params = gin.Params{{Key: "id", Value: "bad"}}
+		NewAuditHandler(&mockAuditService{}).TransactionHistory(c)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("service_error", func(t *testing.T) {
+		svc := &mockAuditService{}
+		svc.On("GetHistory", mock.Anything, model.AuditEntityTransaction, txnId).Return(nil, assert.AnError)
+		w, c := makeReq(http.MethodGet, "/api/transactions/"+txnId.String()+"/history", nil)
+		c.Params = gin.Params{{Key: "id", Value: txnId.String()}}
+		NewAuditHandler(svc).TransactionHistory(c)
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+	})
+}
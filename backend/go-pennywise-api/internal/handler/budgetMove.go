@@ -0,0 +1,69 @@
+package handler
+
+import (
+	stderrors "errors"
+	"net/http"
+	"strings"
+
+	"github.com/Rishabh-Kapri/pennywise/backend/go-pennywise-api/internal/service"
+	errs "github.com/Rishabh-Kapri/pennywise/backend/shared/errors"
+	"github.com/Rishabh-Kapri/pennywise/backend/shared/model"
+
+	"github.com/gin-gonic/gin"
+)
+
+type BudgetMoveHandler interface {
+	List(c *gin.Context)
+	// Move moves budgeted money between categories, or to and from Ready to Assign, for a month
+	Move(c *gin.Context)
+}
+
+type budgetMoveHandler struct {
+	service service.BudgetMoveService
+}
+
+func NewBudgetMoveHandler(service service.BudgetMoveService) BudgetMoveHandler {
+	return &budgetMoveHandler{service: service}
+}
+
+func (h *budgetMoveHandler) List(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	moves, err := h.service.GetAll(ctx, strings.TrimSpace(c.Query("month")))
+	if err != nil {
+		c.JSON(budgetMoveErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, moves)
+}
+
+func (h *budgetMoveHandler) Move(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	var body model.MoveMoneyRequest
+	if err := c.ShouldBindJSON(&body); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	move, err := h.service.Move(ctx, body)
+	if err != nil {
+		c.JSON(budgetMoveErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, move)
+}
+
+func budgetMoveErrorStatus(err error) int {
+	var apiErr *errs.Error
+	if stderrors.As(err, &apiErr) {
+		switch apiErr.Code {
+		case errs.CodeInvalidArgument:
+			return http.StatusBadRequest
+		case errs.CodeCategoryNotFound:
+			return http.StatusNotFound
+		case errs.CodeMonthlyBudgetOverAssigned:
+			return http.StatusConflict
+		}
+	}
+	return http.StatusInternalServerError
+}
@@ -0,0 +1,73 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	errs "github.com/Rishabh-Kapri/pennywise/backend/shared/errors"
+	"github.com/Rishabh-Kapri/pennywise/backend/shared/model"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type mockBudgetMoveService struct{ mock.Mock }
+
+func (m *mockBudgetMoveService) GetAll(ctx context.Context, month string) ([]model.BudgetMove, error) {
+	args := m.Called(ctx, month)
+	if v := args.Get(0); v != nil {
+		return v.([]model.BudgetMove), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+func (m *mockBudgetMoveService) Move(ctx context.Context, req model.MoveMoneyRequest) (*model.BudgetMove, error) {
+	args := m.Called(ctx, req)
+	if v := args.Get(0); v != nil {
+		return v.(*model.BudgetMove), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func TestBudgetMoveHandler(t *testing.T) {
+	from, to := uuid.New(), uuid.New()
+	req := model.MoveMoneyRequest{Month: "2025-04", FromCategoryID: &from, ToCategoryID: &to, Amount: 25, Note: "oops"}
+
+	t.Run("move", func(t *testing.T) {
+		svc := &mockBudgetMoveService{}
+		svc.On("Move", mock.Anything, req).Return(&model.BudgetMove{ID: uuid.New(), Amount: 25}, nil)
+		w, c := makeReq(http.MethodPost, "/api/monthly-budgets/move", req)
+		NewBudgetMoveHandler(svc).Move(c)
+		assert.Equal(t, http.StatusCreated, w.Code)
+		assert.Contains(t, w.Body.String(), `"amount":25`)
+	})
+
+	t.Run("over_assigned", func(t *testing.T) {
+		svc := &mockBudgetMoveService{}
+		svc.On("Move", mock.Anything, mock.Anything).
+			Return(nil, errs.New(errs.CodeMonthlyBudgetOverAssigned, "not enough ready to assign"))
+		w, c := makeReq(http.MethodPost, "/api/monthly-budgets/move", model.MoveMoneyRequest{
+			Month:        "2025-04",
+			ToCategoryID: &to,
+			Amount:       25,
+		})
+		NewBudgetMoveHandler(svc).Move(c)
+		assert.Equal(t, http.StatusConflict, w.Code)
+	})
+
+	t.Run("missing_month", func(t *testing.T) {
+		w, c := makeReq(http.MethodPost, "/api/monthly-budgets/move", map[string]any{"amount": 25})
+		NewBudgetMoveHandler(&mockBudgetMoveService{}).Move(c)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("list_by_month", func(t *testing.T) {
+		svc := &mockBudgetMoveService{}
+		svc.On("GetAll", mock.Anything, "2025-04").Return([]model.BudgetMove{}, nil)
+		w, c := makeReq(http.MethodGet, "/api/monthly-budgets/moves?month=2025-04", nil)
+		NewBudgetMoveHandler(svc).List(c)
+		assert.Equal(t, http.StatusOK, w.Code)
+		svc.AssertExpectations(t)
+	})
+}
@@ -1,10 +1,12 @@
 package handler
 
 import (
+	stderrors "errors"
 	"net/http"
 	"strings"
 
 	"github.com/Rishabh-Kapri/pennywise/backend/go-pennywise-api/internal/service"
+	errs "github.com/Rishabh-Kapri/pennywise/backend/shared/errors"
 	"github.com/Rishabh-Kapri/pennywise/backend/shared/logger"
 	"github.com/Rishabh-Kapri/pennywise/backend/shared/model"
 
@@ -21,6 +23,11 @@ type CategoryHandler interface {
 	UpdateBudget(c *gin.Context)
 	GetById(c *gin.Context)
 	DeleteById(c *gin.Context)
+	GetGoal(c *gin.Context)
+	SetGoal(c *gin.Context)
+	DeleteGoal(c *gin.Context)
+	// AutoAssign fills the budgeted amounts of a month using a strategy, or previews them
+	AutoAssign(c *gin.Context)
 }
 
 type categoryHandler struct {
@@ -117,7 +124,7 @@ func (h *categoryHandler) Update(c *gin.Context) {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error while parsing id"})
 		return
 	}
-	var body model.Category
+	var body model.CategoryUpdate
 	if err := c.ShouldBindJSON(&body); err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
@@ -167,12 +174,105 @@ func (h *categoryHandler) DeleteById(c *gin.Context) {
 	}
 	parsedId, err := uuid.Parse(id)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error while parsing id"})
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Error while parsing id"})
+		return
+	}
+	var replacementId *uuid.UUID
+	if replacement := strings.TrimSpace(c.Query("replacementId")); replacement != "" {
+		parsed, err := uuid.Parse(replacement)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Error while parsing replacementId"})
+			return
+		}
+		replacementId = &parsed
 	}
-	err = h.service.DeleteById(ctx, parsedId)
+	result, err := h.service.DeleteById(ctx, parsedId, replacementId)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		c.JSON(categoryErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, result)
+}
+
+func (h *categoryHandler) GetGoal(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Error while parsing id"})
+		return
+	}
+	goal, err := h.service.GetGoal(ctx, id)
+	if err != nil {
+		c.JSON(categoryErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, goal)
+}
+
+func (h *categoryHandler) SetGoal(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Error while parsing id"})
+		return
+	}
+	var body model.CategoryGoal
+	if err := c.ShouldBindJSON(&body); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
-	c.JSON(http.StatusOK, nil)
+	goal, err := h.service.SetGoal(ctx, id, body)
+	if err != nil {
+		c.JSON(categoryErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, goal)
+}
+
+func (h *categoryHandler) DeleteGoal(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Error while parsing id"})
+		return
+	}
+	if err := h.service.DeleteGoal(ctx, id); err != nil {
+		c.JSON(categoryErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "category goal deleted"})
+}
+
+func (h *categoryHandler) AutoAssign(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	var body model.AutoAssignRequest
+	if err := c.ShouldBindJSON(&body); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	result, err := h.service.AutoAssign(ctx, c.Param("month"), body)
+	if err != nil {
+		c.JSON(categoryErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, result)
+}
+
+func categoryErrorStatus(err error) int {
+	var apiErr *errs.Error
+	if stderrors.As(err, &apiErr) {
+		switch apiErr.Code {
+		case errs.CodeInvalidArgument:
+			return http.StatusBadRequest
+		case errs.CodeCategoryNotFound, errs.CodeCategoryGoalNotFound:
+			return http.StatusNotFound
+		case errs.CodeCategoryInUse, errs.CodeMonthlyBudgetOverAssigned:
+			return http.StatusConflict
+		}
+	}
+	return http.StatusInternalServerError
 }
@@ -0,0 +1,108 @@
+package handler
+
+import (
+	stderrors "errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/Rishabh-Kapri/pennywise/backend/go-pennywise-api/internal/service"
+	errs "github.com/Rishabh-Kapri/pennywise/backend/shared/errors"
+	"github.com/Rishabh-Kapri/pennywise/backend/shared/logger"
+	"github.com/Rishabh-Kapri/pennywise/backend/shared/model"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ExportHandler interface {
+	Export(c *gin.Context)
+}
+
+type exportHandler struct {
+	service service.ExportService
+}
+
+func NewExportHandler(service service.ExportService) ExportHandler {
+	return &exportHandler{service: service}
+}
+
+// attachmentWriter sends the download headers on the first write, so errors
+// raised before anything is streamed can still be returned as JSON
+type attachmentWriter struct {
+	c           *gin.Context
+	filename    string
+	contentType string
+	started     bool
+}
+
+func (w *attachmentWriter) Write(p []byte) (int, error) {
+	if !w.started {
+		w.started = true
+		w.c.Header("Content-Type", w.contentType)
+		w.c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", w.filename))
+		w.c.Status(http.StatusOK)
+	}
+	return w.c.Writer.Write(p)
+}
+
+// Export streams transactions or monthly budgets. It takes "type" (transactions or budgets),
+// "format" (csv, ofx or ynab), an optional "currency" to convert transaction amounts into
+// and the same filter params as the transaction list.
+func (h *exportHandler) Export(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	filter, err := parseTransactionFilter(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	req := model.ExportRequest{
+		Type:   model.ExportType(c.DefaultQuery("type", string(model.ExportTypeTransactions))),
+		Format: model.ExportFormat(c.DefaultQuery("format", string(model.ExportFormatCSV))),
+		Filter: filter,
+		// amounts are converted using the fx rate table
+		Currency: c.Query("currency"),
+	}
+	req.Normalize()
+	if err := req.Valid(); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	extension, contentType := "csv", "text/csv; charset=utf-8"
+	if req.Format == model.ExportFormatOFX {
+		extension, contentType = "ofx", "application/x-ofx"
+	}
+	writer := &attachmentWriter{
+		c: c,
+		filename: fmt.Sprintf(
+			"pennywise-%s-%s.%s",
+			strings.ToLower(string(req.Type)),
+			time.Now().Format("20060102"),
+			extension,
+		),
+		contentType: contentType,
+	}
+
+	if err := h.service.Export(ctx, req, writer); err != nil {
+		if !writer.started {
+			c.JSON(exportErrorStatus(err), gin.H{"error": err.Error()})
+			return
+		}
+		// the status is already sent, all we can do is stop the stream
+		logger.Logger(ctx).Error("export failed mid stream", "error", err)
+		c.Abort()
+	}
+}
+
+func exportErrorStatus(err error) int {
+	var apiErr *errs.Error
+	if stderrors.As(err, &apiErr) {
+		switch apiErr.Code {
+		case errs.CodeInvalidArgument, errs.CodeFxRateNotFound:
+			return http.StatusBadRequest
+		}
+	}
+	return http.StatusInternalServerError
+}
@@ -0,0 +1,74 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"testing"
+
+	errs "github.com/Rishabh-Kapri/pennywise/backend/shared/errors"
+	"github.com/Rishabh-Kapri/pennywise/backend/shared/model"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type mockExportService struct{ mock.Mock }
+
+func (m *mockExportService) Export(ctx context.Context, req model.ExportRequest, w io.Writer) error {
+	args := m.Called(ctx, req, w)
+	if body := args.String(0); body != "" {
+		_, _ = io.WriteString(w, body)
+	}
+	return args.Error(1)
+}
+
+func TestExportHandler_Export(t *testing.T) {
+	t.Run("streams_attachment", func(t *testing.T) {
+		svc := &mockExportService{}
+		svc.On("Export", mock.Anything, mock.MatchedBy(func(req model.ExportRequest) bool {
+			return req.Type == model.ExportTypeTransactions && req.Format == model.ExportFormatOFX &&
+				req.Filter.StartDate != nil && *req.Filter.StartDate == "2024-01-01"
+		}), mock.Anything).Return("<OFX></OFX>", nil)
+
+		w, c := makeReq(http.MethodGet, "/api/exports?format=ofx&startDate=2024-01-01", nil)
+		NewExportHandler(svc).Export(c)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "application/x-ofx", w.Header().Get("Content-Type"))
+		assert.Contains(t, w.Header().Get("Content-Disposition"), "pennywise-transactions-")
+		assert.Contains(t, w.Header().Get("Content-Disposition"), ".ofx")
+		assert.Equal(t, "<OFX></OFX>", w.Body.String())
+	})
+
+	t.Run("bad_format", func(t *testing.T) {
+		w, c := makeReq(http.MethodGet, "/api/exports?type=budgets&format=ofx", nil)
+		NewExportHandler(&mockExportService{}).Export(c)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("service_error_before_write", func(t *testing.T) {
+		svc := &mockExportService{}
+		svc.On("Export", mock.Anything, mock.Anything, mock.Anything).Return("", errors.New("db down"))
+
+		w, c := makeReq(http.MethodGet, "/api/exports?type=budgets", nil)
+		NewExportHandler(svc).Export(c)
+
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+		assert.Empty(t, w.Header().Get("Content-Disposition"))
+		assert.Contains(t, w.Body.String(), "db down")
+	})
+
+	t.Run("missing_fx_rate_before_write", func(t *testing.T) {
+		svc := &mockExportService{}
+		svc.On("Export", mock.Anything, mock.Anything, mock.Anything).
+			Return("", errs.New(errs.CodeFxRateNotFound, "no USD to EUR rate on or before 2024-01-01"))
+
+		w, c := makeReq(http.MethodGet, "/api/exports?currency=EUR", nil)
+		NewExportHandler(svc).Export(c)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Empty(t, w.Header().Get("Content-Disposition"))
+	})
+}
@@ -0,0 +1,133 @@
+package handler
+
+import (
+	stderrors "errors"
+	"io"
+	"net/http"
+
+	"github.com/Rishabh-Kapri/pennywise/backend/go-pennywise-api/internal/service"
+	errs "github.com/Rishabh-Kapri/pennywise/backend/shared/errors"
+	"github.com/Rishabh-Kapri/pennywise/backend/shared/model"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// maxFxRateFileSize caps the size of an uploaded rate table
+const maxFxRateFileSize = 5 << 20
+
+type FxRateHandler interface {
+	List(c *gin.Context)
+	Save(c *gin.Context)
+	Import(c *gin.Context)
+	DeleteById(c *gin.Context)
+}
+
+type fxRateHandler struct {
+	service service.FxRateService
+}
+
+func NewFxRateHandler(service service.FxRateService) FxRateHandler {
+	return &fxRateHandler{service: service}
+}
+
+// List returns the rates of the budget, newest first. It takes optional "base", "quote",
+// "startDate" and "endDate" query params.
+func (h *fxRateHandler) List(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	var filter model.FxRateFilter
+	for param, dest := range map[string]**string{
+		"base":      &filter.BaseCurrency,
+		"quote":     &filter.QuoteCurrency,
+		"startDate": &filter.StartDate,
+		"endDate":   &filter.EndDate,
+	} {
+		if value := c.Query(param); value != "" {
+			*dest = &value
+		}
+	}
+
+	rates, err := h.service.GetAll(ctx, filter)
+	if err != nil {
+		c.JSON(fxRateErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, rates)
+}
+
+func (h *fxRateHandler) Save(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	var body model.FxRate
+	if err := c.ShouldBindJSON(&body); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	rate, err := h.service.Save(ctx, body)
+	if err != nil {
+		c.JSON(fxRateErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, rate)
+}
+
+// Import handles multipart uploads of a rate table. The CSV is sent as "file" and needs
+// date, base, quote and rate columns.
+func (h *fxRateHandler) Import(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "file is required"})
+		return
+	}
+	if fileHeader.Size > maxFxRateFileSize {
+		c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": "file is too large"})
+		return
+	}
+	file, err := fileHeader.Open()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	defer file.Close()
+	data, err := io.ReadAll(file)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	result, err := h.service.Import(ctx, data)
+	if err != nil {
+		c.JSON(fxRateErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, result)
+}
+
+func (h *fxRateHandler) DeleteById(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Error while parsing id"})
+		return
+	}
+	if err := h.service.DeleteById(ctx, id); err != nil {
+		c.JSON(fxRateErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "fx rate deleted"})
+}
+
+func fxRateErrorStatus(err error) int {
+	var apiErr *errs.Error
+	if stderrors.As(err, &apiErr) {
+		switch apiErr.Code {
+		case errs.CodeInvalidArgument, errs.CodeImportParseFailed:
+			return http.StatusBadRequest
+		}
+	}
+	return http.StatusInternalServerError
+}
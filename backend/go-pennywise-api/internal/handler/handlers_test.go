@@ -6,10 +6,12 @@ import (
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/Rishabh-Kapri/pennywise/backend/go-pennywise-api/internal/service"
+	errs "github.com/Rishabh-Kapri/pennywise/backend/shared/errors"
 	"github.com/Rishabh-Kapri/pennywise/backend/shared/model"
 	"github.com/Rishabh-Kapri/pennywise/backend/shared/utils"
 
@@ -78,6 +80,50 @@ func (m *mockAccountService) Create(ctx context.Context, account model.Account)
 	}
 	return nil, args.Error(1)
 }
+func (m *mockAccountService) Reconcile(
+	ctx context.Context,
+	id uuid.UUID,
+	req model.ReconcileRequest,
+) (*model.ReconcileResult, error) {
+	args := m.Called(ctx, id, req)
+	if v := args.Get(0); v != nil {
+		return v.(*model.ReconcileResult), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *mockAccountService) Update(
+	ctx context.Context,
+	id uuid.UUID,
+	req model.UpdateAccountRequest,
+) (*model.Account, error) {
+	args := m.Called(ctx, id, req)
+	if v := args.Get(0); v != nil {
+		return v.(*model.Account), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+func (m *mockAccountService) Close(
+	ctx context.Context,
+	id uuid.UUID,
+	req model.CloseAccountRequest,
+) (*model.CloseAccountResult, error) {
+	args := m.Called(ctx, id, req)
+	if v := args.Get(0); v != nil {
+		return v.(*model.CloseAccountResult), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+func (m *mockAccountService) Reopen(ctx context.Context, id uuid.UUID) (*model.Account, error) {
+	args := m.Called(ctx, id)
+	if v := args.Get(0); v != nil {
+		return v.(*model.Account), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+func (m *mockAccountService) DeleteById(ctx context.Context, id uuid.UUID) error {
+	return m.Called(ctx, id).Error(0)
+}
 
 func TestAccountHandler_List(t *testing.T) {
 	t.Run("returns_accounts", func(t *testing.T) {
@@ -141,6 +187,36 @@ func TestAccountHandler_Create(t *testing.T) {
 	})
 }
 
+func TestAccountHandler_Reconcile(t *testing.T) {
+	accountID := uuid.New()
+	t.Run("reconciles_account", func(t *testing.T) {
+		svc := &mockAccountService{}
+		req := model.ReconcileRequest{StatementBalance: 120.5, Date: "2024-03-31"}
+		svc.On("Reconcile", mock.Anything, accountID, req).
+			Return(&model.ReconcileResult{StatementBalance: 120.5, Reconciled: 3}, nil)
+		w, c := makeReq("POST", "/accounts/"+accountID.String()+"/reconcile", req)
+		c.Params = gin.Params{{Key: "id", Value: accountID.String()}}
+		NewAccountHandler(svc).Reconcile(c)
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), `"reconciled":3`)
+	})
+	t.Run("invalid_id_returns_400", func(t *testing.T) {
+		svc := &mockAccountService{}
+		w, c := makeReq("POST", "/accounts/bad/reconcile", model.ReconcileRequest{})
+		c.Params = gin.Params{{Key: "id", Value: "bad"}}
+		NewAccountHandler(svc).Reconcile(c)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+	t.Run("service_error_returns_400", func(t *testing.T) {
+		svc := &mockAccountService{}
+		svc.On("Reconcile", mock.Anything, accountID, mock.Anything).Return(nil, assert.AnError)
+		w, c := makeReq("POST", "/accounts/"+accountID.String()+"/reconcile", model.ReconcileRequest{})
+		c.Params = gin.Params{{Key: "id", Value: accountID.String()}}
+		NewAccountHandler(svc).Reconcile(c)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
+
 // ─────────────────────────────────────────────────────────────────────────────
 // APIKeyHandler
 // ─────────────────────────────────────────────────────────────────────────────
@@ -513,12 +589,61 @@ func (m *mockCategoryService) Create(ctx context.Context, cat model.Category) (*
 	}
 	return nil, args.Error(1)
 }
-func (m *mockCategoryService) DeleteById(ctx context.Context, id uuid.UUID) error {
-	return m.Called(ctx, id).Error(0)
+func (m *mockCategoryService) DeleteById(
+	ctx context.Context,
+	id uuid.UUID,
+	replacementId *uuid.UUID,
+) (*model.CategoryDeleteResult, error) {
+	args := m.Called(ctx, id, replacementId)
+	if v := args.Get(0); v != nil {
+		return v.(*model.CategoryDeleteResult), args.Error(1)
+	}
+	return nil, args.Error(1)
 }
-func (m *mockCategoryService) Update(ctx context.Context, id uuid.UUID, cat model.Category) error {
+func (m *mockCategoryService) Update(ctx context.Context, id uuid.UUID, cat model.CategoryUpdate) error {
 	return m.Called(ctx, id, cat).Error(0)
 }
+func (m *mockCategoryService) GetGoal(ctx context.Context, categoryId uuid.UUID) (*model.CategoryGoal, error) {
+	args := m.Called(ctx, categoryId)
+	if v := args.Get(0); v != nil {
+		return v.(*model.CategoryGoal), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+func (m *mockCategoryService) SetGoal(
+	ctx context.Context,
+	categoryId uuid.UUID,
+	goal model.CategoryGoal,
+) (*model.CategoryGoal, error) {
+	args := m.Called(ctx, categoryId, goal)
+	if v := args.Get(0); v != nil {
+		return v.(*model.CategoryGoal), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+func (m *mockCategoryService) DeleteGoal(ctx context.Context, categoryId uuid.UUID) error {
+	return m.Called(ctx, categoryId).Error(0)
+}
+func (m *mockCategoryService) AutoAssign(
+	ctx context.Context,
+	month string,
+	req model.AutoAssignRequest,
+) (*model.AutoAssignResult, error) {
+	args := m.Called(ctx, month, req)
+	if v := args.Get(0); v != nil {
+		return v.(*model.AutoAssignResult), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+func (m *mockCategoryService) SetBudgetedWithTx(
+	ctx context.Context,
+	tx pgx.Tx,
+	categoryId uuid.UUID,
+	budgeted float64,
+	month string,
+) error {
+	return m.Called(ctx, tx, categoryId, budgeted, month).Error(0)
+}
 func (m *mockCategoryService) UpdateMonthlyBudget(ctx context.Context, categoryId uuid.UUID, newBudgeted float64, month string) error {
 	return m.Called(ctx, categoryId, newBudgeted, month).Error(0)
 }
@@ -607,21 +732,50 @@ func TestCategoryHandler_DeleteById(t *testing.T) {
 	id := uuid.New()
 	t.Run("deletes_category", func(t *testing.T) {
 		svc := &mockCategoryService{}
-		svc.On("DeleteById", mock.Anything, id).Return(nil)
+		svc.On("DeleteById", mock.Anything, id, (*uuid.UUID)(nil)).Return(&model.CategoryDeleteResult{}, nil)
 		w, c := makeReq("DELETE", "/categories/"+id.String(), nil)
 		c.Params = gin.Params{{Key: "id", Value: id.String()}}
 		NewCategoryHandler(svc).DeleteById(c)
 		assert.Equal(t, http.StatusOK, w.Code)
 	})
+	t.Run("passes_replacement", func(t *testing.T) {
+		replacementId := uuid.New()
+		svc := &mockCategoryService{}
+		svc.On("DeleteById", mock.Anything, id, &replacementId).
+			Return(&model.CategoryDeleteResult{ReplacementID: &replacementId, Transactions: 3}, nil)
+		w, c := makeReq("DELETE", "/categories/"+id.String()+"?replacementId="+replacementId.String(), nil)
+		c.Params = gin.Params{{Key: "id", Value: id.String()}}
+		NewCategoryHandler(svc).DeleteById(c)
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), `"transactions":3`)
+		svc.AssertExpectations(t)
+	})
+	t.Run("invalid_replacement_returns_400", func(t *testing.T) {
+		svc := &mockCategoryService{}
+		w, c := makeReq("DELETE", "/categories/"+id.String()+"?replacementId=nope", nil)
+		c.Params = gin.Params{{Key: "id", Value: id.String()}}
+		NewCategoryHandler(svc).DeleteById(c)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		svc.AssertNotCalled(t, "DeleteById", mock.Anything, mock.Anything, mock.Anything)
+	})
 	t.Run("missing_id_returns_400", func(t *testing.T) {
 		svc := &mockCategoryService{}
 		w, c := makeReq("DELETE", "/categories/", nil)
 		NewCategoryHandler(svc).DeleteById(c)
 		assert.Equal(t, http.StatusBadRequest, w.Code)
 	})
+	t.Run("in_use_returns_409", func(t *testing.T) {
+		svc := &mockCategoryService{}
+		svc.On("DeleteById", mock.Anything, id, (*uuid.UUID)(nil)).
+			Return(nil, errs.New(errs.CodeCategoryInUse, "category is in use"))
+		w, c := makeReq("DELETE", "/categories/"+id.String(), nil)
+		c.Params = gin.Params{{Key: "id", Value: id.String()}}
+		NewCategoryHandler(svc).DeleteById(c)
+		assert.Equal(t, http.StatusConflict, w.Code)
+	})
 	t.Run("service_error_returns_500", func(t *testing.T) {
 		svc := &mockCategoryService{}
-		svc.On("DeleteById", mock.Anything, id).Return(assert.AnError)
+		svc.On("DeleteById", mock.Anything, id, (*uuid.UUID)(nil)).Return(nil, assert.AnError)
 		w, c := makeReq("DELETE", "/categories/"+id.String(), nil)
 		c.Params = gin.Params{{Key: "id", Value: id.String()}}
 		NewCategoryHandler(svc).DeleteById(c)
@@ -629,6 +783,74 @@ func TestCategoryHandler_DeleteById(t *testing.T) {
 	})
 }
 
+func TestCategoryHandler_Goal(t *testing.T) {
+	id := uuid.New()
+	t.Run("set_goal", func(t *testing.T) {
+		goal := model.CategoryGoal{Type: model.CategoryGoalMonthlyFunding, Amount: 150}
+		svc := &mockCategoryService{}
+		svc.On("SetGoal", mock.Anything, id, goal).Return(&model.CategoryGoal{CategoryID: id, Amount: 150}, nil).Once()
+		w, c := makeReq("PUT", "/categories/"+id.String()+"/goal", goal)
+		c.Params = gin.Params{{Key: "id", Value: id.String()}}
+		NewCategoryHandler(svc).SetGoal(c)
+		assert.Equal(t, http.StatusOK, w.Code)
+		svc.AssertExpectations(t)
+	})
+	t.Run("invalid_goal_returns_400", func(t *testing.T) {
+		svc := &mockCategoryService{}
+		svc.On("SetGoal", mock.Anything, id, mock.Anything).
+			Return(nil, errs.New(errs.CodeInvalidArgument, "goal amount must be positive")).Once()
+		w, c := makeReq("PUT", "/categories/"+id.String()+"/goal", model.CategoryGoal{})
+		c.Params = gin.Params{{Key: "id", Value: id.String()}}
+		NewCategoryHandler(svc).SetGoal(c)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+	t.Run("missing_goal_returns_404", func(t *testing.T) {
+		svc := &mockCategoryService{}
+		svc.On("GetGoal", mock.Anything, id).Return(nil, errs.New(errs.CodeCategoryGoalNotFound, "category has no goal")).Once()
+		svc.On("DeleteGoal", mock.Anything, id).Return(errs.New(errs.CodeCategoryGoalNotFound, "category has no goal")).Once()
+
+		w, c := makeReq("GET", "/categories/"+id.String()+"/goal", nil)
+		c.Params = gin.Params{{Key: "id", Value: id.String()}}
+		NewCategoryHandler(svc).GetGoal(c)
+		assert.Equal(t, http.StatusNotFound, w.Code)
+
+		w, c = makeReq("DELETE", "/categories/"+id.String()+"/goal", nil)
+		c.Params = gin.Params{{Key: "id", Value: id.String()}}
+		NewCategoryHandler(svc).DeleteGoal(c)
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+}
+
+func TestCategoryHandler_AutoAssign(t *testing.T) {
+	req := model.AutoAssignRequest{Strategy: model.AutoAssignReset, Preview: true}
+	t.Run("returns_proposals", func(t *testing.T) {
+		svc := &mockCategoryService{}
+		svc.On("AutoAssign", mock.Anything, "2025-04", req).
+			Return(&model.AutoAssignResult{Month: "2025-04", Strategy: model.AutoAssignReset}, nil).Once()
+		w, c := makeReq("POST", "/budgets/2025-04/auto-assign", req)
+		c.Params = gin.Params{{Key: "month", Value: "2025-04"}}
+		NewCategoryHandler(svc).AutoAssign(c)
+		assert.Equal(t, http.StatusOK, w.Code)
+		svc.AssertExpectations(t)
+	})
+	t.Run("missing_strategy_returns_400", func(t *testing.T) {
+		svc := &mockCategoryService{}
+		w, c := makeReq("POST", "/budgets/2025-04/auto-assign", map[string]any{"preview": true})
+		c.Params = gin.Params{{Key: "month", Value: "2025-04"}}
+		NewCategoryHandler(svc).AutoAssign(c)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+	t.Run("over_assigned_returns_409", func(t *testing.T) {
+		svc := &mockCategoryService{}
+		svc.On("AutoAssign", mock.Anything, "2025-04", mock.Anything).
+			Return(nil, errs.New(errs.CodeMonthlyBudgetOverAssigned, "not enough ready to assign")).Once()
+		w, c := makeReq("POST", "/budgets/2025-04/auto-assign", model.AutoAssignRequest{Strategy: model.AutoAssignAverageSpent})
+		c.Params = gin.Params{{Key: "month", Value: "2025-04"}}
+		NewCategoryHandler(svc).AutoAssign(c)
+		assert.Equal(t, http.StatusConflict, w.Code)
+	})
+}
+
 // ─────────────────────────────────────────────────────────────────────────────
 // TagHandler
 // ─────────────────────────────────────────────────────────────────────────────
@@ -1048,6 +1270,17 @@ func (m *mockPayeeService) DeleteById(ctx context.Context, id uuid.UUID) error {
 func (m *mockPayeeService) Update(ctx context.Context, id uuid.UUID, payee model.Payee) error {
 	return m.Called(ctx, id, payee).Error(0)
 }
+func (m *mockPayeeService) Merge(
+	ctx context.Context,
+	targetId uuid.UUID,
+	req model.MergePayeesRequest,
+) (*model.PayeeMergeResult, error) {
+	args := m.Called(ctx, targetId, req)
+	if v := args.Get(0); v != nil {
+		return v.(*model.PayeeMergeResult), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
 
 func TestPayeeHandler_List(t *testing.T) {
 	t.Run("returns_payees", func(t *testing.T) {
@@ -1182,6 +1415,35 @@ func TestPayeeHandler_Update(t *testing.T) {
 	})
 }
 
+func TestPayeeHandler_Merge(t *testing.T) {
+	id := uuid.New()
+	body := model.MergePayeesRequest{SourceIDs: []uuid.UUID{uuid.New()}}
+	t.Run("merges_payees", func(t *testing.T) {
+		svc := &mockPayeeService{}
+		svc.On("Merge", mock.Anything, id, body).Return(&model.PayeeMergeResult{Transactions: 3}, nil)
+		w, c := makeReq("POST", "/payees/"+id.String()+"/merge", body)
+		c.Params = gin.Params{{Key: "id", Value: id.String()}}
+		NewPayeeHandler(svc).Merge(c)
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+	t.Run("missing_sources_returns_400", func(t *testing.T) {
+		svc := &mockPayeeService{}
+		w, c := makeReq("POST", "/payees/"+id.String()+"/merge", model.MergePayeesRequest{})
+		c.Params = gin.Params{{Key: "id", Value: id.String()}}
+		NewPayeeHandler(svc).Merge(c)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		svc.AssertNotCalled(t, "Merge", mock.Anything, mock.Anything, mock.Anything)
+	})
+	t.Run("unknown_payee_returns_404", func(t *testing.T) {
+		svc := &mockPayeeService{}
+		svc.On("Merge", mock.Anything, id, body).Return(nil, errs.New(errs.CodePayeeNotFound, "payee not found"))
+		w, c := makeReq("POST", "/payees/"+id.String()+"/merge", body)
+		c.Params = gin.Params{{Key: "id", Value: id.String()}}
+		NewPayeeHandler(svc).Merge(c)
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+}
+
 func TestPayeeHandler_DeleteById(t *testing.T) {
 	id := uuid.New()
 	t.Run("deletes_payee", func(t *testing.T) {
@@ -1245,6 +1507,72 @@ func (m *mockTransactionService) CreateWithTx(ctx context.Context, tx pgx.Tx, tx
 func (m *mockTransactionService) DeleteById(ctx context.Context, id uuid.UUID) error {
 	return m.Called(ctx, id).Error(0)
 }
+func (m *mockTransactionService) Bulk(ctx context.Context, ops []model.BulkTransactionOperation) (*model.BulkTransactionResponse, error) {
+	args := m.Called(ctx, ops)
+	if v := args.Get(0); v != nil {
+		return v.(*model.BulkTransactionResponse), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+func (m *mockTransactionService) Undo(ctx context.Context, count int) (*model.UndoResponse, error) {
+	args := m.Called(ctx, count)
+	if v := args.Get(0); v != nil {
+		return v.(*model.UndoResponse), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+func (m *mockTransactionService) Redo(ctx context.Context, count int) (*model.UndoResponse, error) {
+	args := m.Called(ctx, count)
+	if v := args.Get(0); v != nil {
+		return v.(*model.UndoResponse), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+func (m *mockTransactionService) FindTransferMatches(ctx context.Context, windowDays int) ([]model.TransferMatch, error) {
+	args := m.Called(ctx, windowDays)
+	if v := args.Get(0); v != nil {
+		return v.([]model.TransferMatch), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+func (m *mockTransactionService) GetDuplicates(
+	ctx context.Context,
+	status model.DuplicateStatus,
+) ([]model.TransactionDuplicate, error) {
+	args := m.Called(ctx, status)
+	if v := args.Get(0); v != nil {
+		return v.([]model.TransactionDuplicate), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+func (m *mockTransactionService) MergeDuplicate(
+	ctx context.Context,
+	id uuid.UUID,
+	keepId *uuid.UUID,
+) (*model.Transaction, error) {
+	args := m.Called(ctx, id, keepId)
+	if v := args.Get(0); v != nil {
+		return v.(*model.Transaction), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+func (m *mockTransactionService) DismissDuplicate(ctx context.Context, id uuid.UUID) error {
+	return m.Called(ctx, id).Error(0)
+}
+func (m *mockTransactionService) Restore(ctx context.Context, id uuid.UUID) error {
+	return m.Called(ctx, id).Error(0)
+}
+func (m *mockTransactionService) AcceptTransferMatch(
+	ctx context.Context,
+	outflowId uuid.UUID,
+	inflowId uuid.UUID,
+) (*model.TransferMatch, error) {
+	args := m.Called(ctx, outflowId, inflowId)
+	if v := args.Get(0); v != nil {
+		return v.(*model.TransferMatch), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
 
 func TestTransactionHandler_List(t *testing.T) {
 	t.Run("returns_transactions", func(t *testing.T) {
@@ -1745,6 +2073,39 @@ func TestTransactionHandler_ListNormalized_InvalidAccountId(t *testing.T) {
 	})
 }
 
+func TestTransactionHandler_ListNormalized_Search(t *testing.T) {
+	t.Run("q_defaults_to_relevance", func(t *testing.T) {
+		svc := &mockTransactionService{}
+		svc.On("GetAllNormalized", mock.Anything, mock.MatchedBy(func(filter *model.TransactionFilter) bool {
+			return filter.Query != nil && *filter.Query == "coffee beans" && filter.SortOrder == model.SortOrderRelevance
+		})).Return(model.PaginatedResponse[model.Transaction]{}, nil)
+		w, c := makeReq("GET", "/transactions/normalized?q=+coffee+beans+", nil)
+		NewTransactionHandler(svc).ListNormalized(c)
+		assert.Equal(t, http.StatusOK, w.Code)
+		svc.AssertExpectations(t)
+	})
+	t.Run("explicit_sort_order_is_kept", func(t *testing.T) {
+		svc := &mockTransactionService{}
+		svc.On("GetAllNormalized", mock.Anything, mock.MatchedBy(func(filter *model.TransactionFilter) bool {
+			return filter.Query != nil && filter.SortOrder == "ASC"
+		})).Return(model.PaginatedResponse[model.Transaction]{}, nil)
+		w, c := makeReq("GET", "/transactions/normalized?q=rent&sortOrder=ASC", nil)
+		NewTransactionHandler(svc).ListNormalized(c)
+		assert.Equal(t, http.StatusOK, w.Code)
+		svc.AssertExpectations(t)
+	})
+	t.Run("relevance_without_q_returns_400", func(t *testing.T) {
+		w, c := makeReq("GET", "/transactions/normalized?sortOrder=relevance", nil)
+		NewTransactionHandler(&mockTransactionService{}).ListNormalized(c)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+	t.Run("long_q_returns_400", func(t *testing.T) {
+		w, c := makeReq("GET", "/transactions/normalized?q="+strings.Repeat("a", maxSearchQueryLength+1), nil)
+		NewTransactionHandler(&mockTransactionService{}).ListNormalized(c)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
+
 func TestTransactionHandler_Create_BindError(t *testing.T) {
 	t.Run("bind_error_returns_500", func(t *testing.T) {
 		svc := &mockTransactionService{}
@@ -2130,7 +2491,7 @@ func TestCategoryHandler_UpdateBudget_ServiceError(t *testing.T) {
 func TestCategoryHandler_DeleteById_ServiceError(t *testing.T) {
 	id := uuid.New()
 	svc := &mockCategoryService{}
-	svc.On("DeleteById", mock.Anything, id).Return(assert.AnError)
+	svc.On("DeleteById", mock.Anything, id, (*uuid.UUID)(nil)).Return(nil, assert.AnError)
 	w, c := makeReq("DELETE", "/categories/"+id.String(), nil)
 	c.Params = gin.Params{{Key: "id", Value: id.String()}}
 	NewCategoryHandler(svc).DeleteById(c)
@@ -2272,6 +2633,32 @@ func TestTransactionHandler_UpdateStatus_ServiceError(t *testing.T) {
 	assert.Equal(t, http.StatusBadRequest, w.Code)
 }
 
+func TestTransactionHandler_Bulk(t *testing.T) {
+	ops := []model.BulkTransactionOperation{{TransactionID: uuid.New(), Action: model.BulkActionApprove}}
+	t.Run("applies_operations", func(t *testing.T) {
+		svc := &mockTransactionService{}
+		svc.On("Bulk", mock.Anything, ops).Return(&model.BulkTransactionResponse{Applied: true}, nil)
+		w, c := makeReq("POST", "/transactions/bulk", model.BulkTransactionRequest{Operations: ops})
+		NewTransactionHandler(svc).Bulk(c)
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+	t.Run("failed_operation_returns_400_with_results", func(t *testing.T) {
+		svc := &mockTransactionService{}
+		failed := &model.BulkTransactionResponse{Results: []model.BulkTransactionResult{{Status: model.BulkResultFailed}}}
+		svc.On("Bulk", mock.Anything, ops).Return(failed, assert.AnError)
+		w, c := makeReq("POST", "/transactions/bulk", model.BulkTransactionRequest{Operations: ops})
+		NewTransactionHandler(svc).Bulk(c)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, w.Body.String(), "results")
+	})
+	t.Run("invalid_json_returns_400", func(t *testing.T) {
+		svc := &mockTransactionService{}
+		w, c := makeReq("POST", "/transactions/bulk", "not-json")
+		NewTransactionHandler(svc).Bulk(c)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
+
 func TestTransactionHandler_DeleteById_ServiceError(t *testing.T) {
 	id := uuid.New()
 	svc := &mockTransactionService{}
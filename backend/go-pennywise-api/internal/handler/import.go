@@ -0,0 +1,162 @@
+package handler
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/Rishabh-Kapri/pennywise/backend/go-pennywise-api/internal/service"
+	"github.com/Rishabh-Kapri/pennywise/backend/shared/model"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// maxImportFileSize caps the size of an uploaded statement
+const maxImportFileSize = 10 << 20
+
+type ImportHandler interface {
+	Import(c *gin.Context)
+	ListMappings(c *gin.Context)
+	GetMapping(c *gin.Context)
+	SaveMapping(c *gin.Context)
+	DeleteMapping(c *gin.Context)
+}
+
+type importHandler struct {
+	service service.ImportService
+}
+
+func NewImportHandler(service service.ImportService) ImportHandler {
+	return &importHandler{service: service}
+}
+
+// Import handles multipart statement uploads. The form takes the statement as "file" along with
+// "accountId", an optional "format" (csv, ofx or qif, inferred from the file extension when missing),
+// "predict", "saveMapping" and a JSON encoded "mapping" for CSV files.
+func (h *importHandler) Import(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	limitUpload(c, maxImportFileSize)
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		c.JSON(formFileError(err))
+		return
+	}
+	if fileHeader.Size > maxImportFileSize {
+		c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": "file is too large"})
+		return
+	}
+
+	accountId, err := uuid.Parse(c.PostForm("accountId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Error while parsing accountId"})
+		return
+	}
+
+	req := model.ImportRequest{
+		AccountID: accountId,
+		Format:    model.ImportFormat(strings.ToUpper(c.PostForm("format"))),
+	}
+	if req.Format == "" {
+		req.Format = model.ImportFormat(strings.ToUpper(strings.TrimPrefix(filepath.Ext(fileHeader.Filename), ".")))
+	}
+	req.Predict, _ = strconv.ParseBool(c.PostForm("predict"))
+	req.SaveMapping, _ = strconv.ParseBool(c.PostForm("saveMapping"))
+	if mapping := c.PostForm("mapping"); mapping != "" {
+		req.Mapping = &model.ImportMapping{}
+		if err := json.Unmarshal([]byte(mapping), req.Mapping); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Error while parsing mapping"})
+			return
+		}
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	defer file.Close()
+	data, err := io.ReadAll(file)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	result, err := h.service.Import(ctx, req, data)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, result)
+}
+
+func (h *importHandler) ListMappings(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	mappings, err := h.service.GetMappings(ctx)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, mappings)
+}
+
+func (h *importHandler) GetMapping(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	accountId, err := uuid.Parse(c.Param("accountId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Error while parsing accountId"})
+		return
+	}
+
+	mapping, err := h.service.GetMapping(ctx, accountId)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, mapping)
+}
+
+func (h *importHandler) SaveMapping(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	accountId, err := uuid.Parse(c.Param("accountId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Error while parsing accountId"})
+		return
+	}
+
+	var body model.ImportMapping
+	if err := c.ShouldBindJSON(&body); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	mapping, err := h.service.SaveMapping(ctx, accountId, body)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, mapping)
+}
+
+func (h *importHandler) DeleteMapping(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	accountId, err := uuid.Parse(c.Param("accountId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Error while parsing accountId"})
+		return
+	}
+
+	if err := h.service.DeleteMapping(ctx, accountId); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "import mapping deleted"})
+}
@@ -0,0 +1,174 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/Rishabh-Kapri/pennywise/backend/shared/model"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type mockImportService struct{ mock.Mock }
+
+func (m *mockImportService) GetMappings(ctx context.Context) ([]model.ImportMapping, error) {
+	args := m.Called(ctx)
+	if v := args.Get(0); v != nil {
+		return v.([]model.ImportMapping), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+func (m *mockImportService) GetMapping(ctx context.Context, accountId uuid.UUID) (*model.ImportMapping, error) {
+	args := m.Called(ctx, accountId)
+	if v := args.Get(0); v != nil {
+		return v.(*model.ImportMapping), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+func (m *mockImportService) SaveMapping(
+	ctx context.Context,
+	accountId uuid.UUID,
+	mapping model.ImportMapping,
+) (*model.ImportMapping, error) {
+	args := m.Called(ctx, accountId, mapping)
+	if v := args.Get(0); v != nil {
+		return v.(*model.ImportMapping), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+func (m *mockImportService) DeleteMapping(ctx context.Context, accountId uuid.UUID) error {
+	return m.Called(ctx, accountId).Error(0)
+}
+func (m *mockImportService) Import(
+	ctx context.Context,
+	req model.ImportRequest,
+	data []byte,
+) (*model.ImportResult, error) {
+	args := m.Called(ctx, req, data)
+	if v := args.Get(0); v != nil {
+		return v.(*model.ImportResult), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func makeImportReq(t *testing.T, fields map[string]string, filename string, content string) (*httptest.ResponseRecorder, *gin.Context) {
+	t.Helper()
+	body := &bytes.Buffer{}
+	writer := multipart.NewWriter(body)
+	for key, value := range fields {
+		require.NoError(t, writer.WriteField(key, value))
+	}
+	if filename != "" {
+		part, err := writer.CreateFormFile("file", filename)
+		require.NoError(t, err)
+		_, err = part.Write([]byte(content))
+		require.NoError(t, err)
+	}
+	require.NoError(t, writer.Close())
+
+	gin.SetMode(gin.TestMode)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest("POST", "/imports", body)
+	c.Request.Header.Set("Content-Type", writer.FormDataContentType())
+	return w, c
+}
+
+func TestImportHandler_Import(t *testing.T) {
+	accountId := uuid.New()
+
+	t.Run("infers_format_from_extension", func(t *testing.T) {
+		svc := &mockImportService{}
+		svc.On("Import", mock.Anything, mock.MatchedBy(func(req model.ImportRequest) bool {
+			return req.AccountID == accountId && req.Format == model.ImportFormatOFX && req.Predict
+		}), []byte("<OFX>")).Return(&model.ImportResult{Created: 1}, nil)
+
+		w, c := makeImportReq(t, map[string]string{"accountId": accountId.String(), "predict": "true"}, "jan.ofx", "<OFX>")
+		NewImportHandler(svc).Import(c)
+		assert.Equal(t, http.StatusOK, w.Code)
+		svc.AssertExpectations(t)
+	})
+	t.Run("parses_mapping", func(t *testing.T) {
+		svc := &mockImportService{}
+		svc.On("Import", mock.Anything, mock.MatchedBy(func(req model.ImportRequest) bool {
+			return req.Format == model.ImportFormatCSV && req.SaveMapping &&
+				req.Mapping != nil && req.Mapping.DateColumn == "Date"
+		}), mock.Anything).Return(&model.ImportResult{}, nil)
+
+		w, c := makeImportReq(t, map[string]string{
+			"accountId":   accountId.String(),
+			"format":      "csv",
+			"saveMapping": "true",
+			"mapping":     `{"dateColumn":"Date","amountColumn":"Amount"}`,
+		}, "statement.txt", "Date,Amount\n")
+		NewImportHandler(svc).Import(c)
+		assert.Equal(t, http.StatusOK, w.Code)
+		svc.AssertExpectations(t)
+	})
+	t.Run("missing_file_returns_400", func(t *testing.T) {
+		w, c := makeImportReq(t, map[string]string{"accountId": accountId.String()}, "", "")
+		NewImportHandler(&mockImportService{}).Import(c)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+	t.Run("oversized_body_returns_413", func(t *testing.T) {
+		content := strings.Repeat("a", maxImportFileSize+maxMultipartOverhead)
+		w, c := makeImportReq(t, map[string]string{"accountId": accountId.String()}, "jan.csv", content)
+		NewImportHandler(&mockImportService{}).Import(c)
+		assert.Equal(t, http.StatusRequestEntityTooLarge, w.Code)
+	})
+	t.Run("invalid_account_returns_400", func(t *testing.T) {
+		w, c := makeImportReq(t, map[string]string{"accountId": "bad"}, "jan.qif", "^")
+		NewImportHandler(&mockImportService{}).Import(c)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+	t.Run("service_error_returns_400", func(t *testing.T) {
+		svc := &mockImportService{}
+		svc.On("Import", mock.Anything, mock.Anything, mock.Anything).Return(nil, assert.AnError)
+		w, c := makeImportReq(t, map[string]string{"accountId": accountId.String()}, "jan.qif", "^")
+		NewImportHandler(svc).Import(c)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
+
+func TestImportHandler_Mappings(t *testing.T) {
+	accountId := uuid.New()
+
+	t.Run("list", func(t *testing.T) {
+		svc := &mockImportService{}
+		svc.On("GetMappings", mock.Anything).Return([]model.ImportMapping{{AccountID: accountId}}, nil)
+		w, c := makeReq("GET", "/imports/mappings", nil)
+		NewImportHandler(svc).ListMappings(c)
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+	t.Run("get_not_found_returns_404", func(t *testing.T) {
+		svc := &mockImportService{}
+		svc.On("GetMapping", mock.Anything, accountId).Return(nil, assert.AnError)
+		w, c := makeReq("GET", "/imports/mappings/"+accountId.String(), nil)
+		c.Params = gin.Params{{Key: "accountId", Value: accountId.String()}}
+		NewImportHandler(svc).GetMapping(c)
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+	t.Run("save", func(t *testing.T) {
+		svc := &mockImportService{}
+		svc.On("SaveMapping", mock.Anything, accountId, mock.Anything).Return(&model.ImportMapping{AccountID: accountId}, nil)
+		w, c := makeReq("PUT", "/imports/mappings/"+accountId.String(), model.ImportMapping{DateColumn: "Date"})
+		c.Params = gin.Params{{Key: "accountId", Value: accountId.String()}}
+		NewImportHandler(svc).SaveMapping(c)
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+	t.Run("delete_invalid_uuid_returns_400", func(t *testing.T) {
+		w, c := makeReq("DELETE", "/imports/mappings/bad", nil)
+		c.Params = gin.Params{{Key: "accountId", Value: "bad"}}
+		NewImportHandler(&mockImportService{}).DeleteMapping(c)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
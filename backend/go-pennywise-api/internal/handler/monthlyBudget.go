@@ -0,0 +1,68 @@
+package handler
+
+import (
+	stderrors "errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/Rishabh-Kapri/pennywise/backend/go-pennywise-api/internal/service"
+	errs "github.com/Rishabh-Kapri/pennywise/backend/shared/errors"
+
+	"github.com/gin-gonic/gin"
+)
+
+type MonthlyBudgetHandler interface {
+	// GetMonth sums up a month of the budget with its overspent categories
+	GetMonth(c *gin.Context)
+	// Rebuild checks the stored carryover balances against transactions and budgeted amounts, and repairs
+	// them when the repair query parameter is set
+	Rebuild(c *gin.Context)
+}
+
+type monthlyBudgetHandler struct {
+	service service.MonthlyBudgetService
+}
+
+func NewMonthlyBudgetHandler(service service.MonthlyBudgetService) MonthlyBudgetHandler {
+	return &monthlyBudgetHandler{service: service}
+}
+
+func (h *monthlyBudgetHandler) GetMonth(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	month, err := h.service.GetMonth(ctx, strings.TrimSpace(c.Param("month")))
+	if err != nil {
+		c.JSON(monthlyBudgetErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, month)
+}
+
+func (h *monthlyBudgetHandler) Rebuild(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	repair := false
+	if value := c.Query("repair"); value != "" {
+		parsed, err := strconv.ParseBool(value)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "repair must be true or false"})
+			return
+		}
+		repair = parsed
+	}
+	result, err := h.service.Rebuild(ctx, repair)
+	if err != nil {
+		c.JSON(monthlyBudgetErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, result)
+}
+
+func monthlyBudgetErrorStatus(err error) int {
+	var apiErr *errs.Error
+	if stderrors.As(err, &apiErr) && apiErr.Code == errs.CodeInvalidArgument {
+		return http.StatusBadRequest
+	}
+	return http.StatusInternalServerError
+}
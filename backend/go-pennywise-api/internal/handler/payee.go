@@ -1,10 +1,12 @@
 package handler
 
 import (
+	stderrors "errors"
 	"net/http"
 	"strings"
 
 	"github.com/Rishabh-Kapri/pennywise/backend/go-pennywise-api/internal/service"
+	errs "github.com/Rishabh-Kapri/pennywise/backend/shared/errors"
 	"github.com/Rishabh-Kapri/pennywise/backend/shared/model"
 
 	"github.com/gin-gonic/gin"
@@ -22,6 +24,8 @@ type PayeeHandler interface {
 	Create(c *gin.Context)
 	Update(c *gin.Context)
 	DeleteById(c *gin.Context)
+	// Merge folds the payees in the body into the payee of the path
+	Merge(c *gin.Context)
 }
 
 type payeeHandler struct {
@@ -231,3 +235,37 @@ func (h *payeeHandler) DeleteById(c *gin.Context) {
 	}
 	c.JSON(http.StatusOK, gin.H{"message": "Payee deleted"})
 }
+
+func (h *payeeHandler) Merge(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Error while parsing id"})
+		return
+	}
+	var body model.MergePayeesRequest
+	if err := c.ShouldBindJSON(&body); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	result, err := h.service.Merge(ctx, id, body)
+	if err != nil {
+		c.JSON(payeeErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, result)
+}
+
+func payeeErrorStatus(err error) int {
+	var apiErr *errs.Error
+	if stderrors.As(err, &apiErr) {
+		switch apiErr.Code {
+		case errs.CodeInvalidArgument:
+			return http.StatusBadRequest
+		case errs.CodePayeeNotFound:
+			return http.StatusNotFound
+		}
+	}
+	return http.StatusInternalServerError
+}
@@ -0,0 +1,111 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/Rishabh-Kapri/pennywise/backend/go-pennywise-api/internal/service"
+	"github.com/Rishabh-Kapri/pennywise/backend/shared/model"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type ScheduledTransactionHandler interface {
+	List(c *gin.Context)
+	GetById(c *gin.Context)
+	Create(c *gin.Context)
+	Update(c *gin.Context)
+	DeleteById(c *gin.Context)
+}
+
+type scheduledTransactionHandler struct {
+	service service.ScheduledTransactionService
+}
+
+func NewScheduledTransactionHandler(service service.ScheduledTransactionService) ScheduledTransactionHandler {
+	return &scheduledTransactionHandler{service: service}
+}
+
+func (h *scheduledTransactionHandler) List(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	scheduled, err := h.service.GetAll(ctx)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, scheduled)
+}
+
+func (h *scheduledTransactionHandler) GetById(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Error while parsing id"})
+		return
+	}
+
+	scheduled, err := h.service.GetById(ctx, id)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, scheduled)
+}
+
+func (h *scheduledTransactionHandler) Create(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	var body model.ScheduledTransaction
+	if err := c.ShouldBindJSON(&body); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	created, err := h.service.Create(ctx, body)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, created)
+}
+
+func (h *scheduledTransactionHandler) Update(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Error while parsing id"})
+		return
+	}
+
+	var body model.ScheduledTransaction
+	if err := c.ShouldBindJSON(&body); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	updated, err := h.service.Update(ctx, id, body)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, updated)
+}
+
+func (h *scheduledTransactionHandler) DeleteById(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Error while parsing id"})
+		return
+	}
+
+	if err := h.service.DeleteById(ctx, id); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "scheduled transaction deleted"})
+}
@@ -0,0 +1,150 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/Rishabh-Kapri/pennywise/backend/shared/model"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type mockScheduledTransactionService struct{ mock.Mock }
+
+func (m *mockScheduledTransactionService) GetAll(ctx context.Context) ([]model.ScheduledTransaction, error) {
+	args := m.Called(ctx)
+	if v := args.Get(0); v != nil {
+		return v.([]model.ScheduledTransaction), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+func (m *mockScheduledTransactionService) GetById(ctx context.Context, id uuid.UUID) (*model.ScheduledTransaction, error) {
+	args := m.Called(ctx, id)
+	if v := args.Get(0); v != nil {
+		return v.(*model.ScheduledTransaction), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+func (m *mockScheduledTransactionService) Create(
+	ctx context.Context,
+	st model.ScheduledTransaction,
+) (*model.ScheduledTransaction, error) {
+	args := m.Called(ctx, st)
+	if v := args.Get(0); v != nil {
+		return v.(*model.ScheduledTransaction), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+func (m *mockScheduledTransactionService) Update(
+	ctx context.Context,
+	id uuid.UUID,
+	st model.ScheduledTransaction,
+) (*model.ScheduledTransaction, error) {
+	args := m.Called(ctx, id, st)
+	if v := args.Get(0); v != nil {
+		return v.(*model.ScheduledTransaction), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+func (m *mockScheduledTransactionService) DeleteById(ctx context.Context, id uuid.UUID) error {
+	return m.Called(ctx, id).Error(0)
+}
+func (m *mockScheduledTransactionService) MaterializeDue(
+	ctx context.Context,
+	asOf time.Time,
+) ([]model.Transaction, error) {
+	args := m.Called(ctx, asOf)
+	if v := args.Get(0); v != nil {
+		return v.([]model.Transaction), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func TestScheduledTransactionHandler_List(t *testing.T) {
+	t.Run("returns_scheduled_transactions", func(t *testing.T) {
+		svc := &mockScheduledTransactionService{}
+		svc.On("GetAll", mock.Anything).Return([]model.ScheduledTransaction{{Note: "rent"}}, nil)
+		w, c := makeReq("GET", "/scheduled-transactions", nil)
+		NewScheduledTransactionHandler(svc).List(c)
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+	t.Run("service_error_returns_500", func(t *testing.T) {
+		svc := &mockScheduledTransactionService{}
+		svc.On("GetAll", mock.Anything).Return(nil, assert.AnError)
+		w, c := makeReq("GET", "/scheduled-transactions", nil)
+		NewScheduledTransactionHandler(svc).List(c)
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+	})
+}
+
+func TestScheduledTransactionHandler_Create(t *testing.T) {
+	body := model.ScheduledTransaction{
+		Frequency: model.ScheduledFrequencyLastBusinessDay,
+		Amount:    -500,
+		StartDate: "2024-01-01",
+	}
+	t.Run("creates_scheduled_transaction", func(t *testing.T) {
+		svc := &mockScheduledTransactionService{}
+		svc.On("Create", mock.Anything, mock.Anything).Return(&model.ScheduledTransaction{ID: uuid.New()}, nil)
+		w, c := makeReq("POST", "/scheduled-transactions", body)
+		NewScheduledTransactionHandler(svc).Create(c)
+		assert.Equal(t, http.StatusCreated, w.Code)
+	})
+	t.Run("validation_error_returns_400", func(t *testing.T) {
+		svc := &mockScheduledTransactionService{}
+		svc.On("Create", mock.Anything, mock.Anything).Return(nil, assert.AnError)
+		w, c := makeReq("POST", "/scheduled-transactions", body)
+		NewScheduledTransactionHandler(svc).Create(c)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+	t.Run("invalid_json_returns_400", func(t *testing.T) {
+		svc := &mockScheduledTransactionService{}
+		w, c := makeReq("POST", "/scheduled-transactions", "not-json")
+		NewScheduledTransactionHandler(svc).Create(c)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
+
+func TestScheduledTransactionHandler_Update(t *testing.T) {
+	id := uuid.New()
+	t.Run("updates_scheduled_transaction", func(t *testing.T) {
+		svc := &mockScheduledTransactionService{}
+		svc.On("Update", mock.Anything, id, mock.Anything).Return(&model.ScheduledTransaction{ID: id}, nil)
+		w, c := makeReq("PATCH", "/scheduled-transactions/"+id.String(), model.ScheduledTransaction{Amount: -10})
+		c.Params = gin.Params{{Key: "id", Value: id.String()}}
+		NewScheduledTransactionHandler(svc).Update(c)
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+	t.Run("invalid_uuid_returns_400", func(t *testing.T) {
+		svc := &mockScheduledTransactionService{}
+		w, c := makeReq("PATCH", "/scheduled-transactions/bad", model.ScheduledTransaction{})
+		c.Params = gin.Params{{Key: "id", Value: "not-uuid"}}
+		NewScheduledTransactionHandler(svc).Update(c)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
+
+func TestScheduledTransactionHandler_DeleteById(t *testing.T) {
+	id := uuid.New()
+	t.Run("deletes_scheduled_transaction", func(t *testing.T) {
+		svc := &mockScheduledTransactionService{}
+		svc.On("DeleteById", mock.Anything, id).Return(nil)
+		w, c := makeReq("DELETE", "/scheduled-transactions/"+id.String(), nil)
+		c.Params = gin.Params{{Key: "id", Value: id.String()}}
+		NewScheduledTransactionHandler(svc).DeleteById(c)
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+	t.Run("service_error_returns_500", func(t *testing.T) {
+		svc := &mockScheduledTransactionService{}
+		svc.On("DeleteById", mock.Anything, id).Return(assert.AnError)
+		w, c := makeReq("DELETE", "/scheduled-transactions/"+id.String(), nil)
+		c.Params = gin.Params{{Key: "id", Value: id.String()}}
+		NewScheduledTransactionHandler(svc).DeleteById(c)
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+	})
+}
@@ -1,11 +1,13 @@
 package handler
 
 import (
+	"errors"
 	"net/http"
 	"strconv"
 	"strings"
 
 	"github.com/Rishabh-Kapri/pennywise/backend/go-pennywise-api/internal/service"
+	errs "github.com/Rishabh-Kapri/pennywise/backend/shared/errors"
 	"github.com/Rishabh-Kapri/pennywise/backend/shared/logger"
 	"github.com/Rishabh-Kapri/pennywise/backend/shared/model"
 
@@ -24,6 +26,16 @@ type TransactionHandler interface {
 	// parses the ID, and then calls the service to perform the deletion.
 	// Returns appropriate HTTP status and message based on the outcome.
 	DeleteById(c *gin.Context)
+	// Bulk applies a list of operations atomically and returns the per operation results.
+	Bulk(c *gin.Context)
+	// TransferMatches lists likely transfers between the budget's accounts. It takes an optional
+	// "windowDays" query param for how many days apart the two sides may be.
+	TransferMatches(c *gin.Context)
+	AcceptTransferMatch(c *gin.Context)
+	// Duplicates lists suspected duplicate transactions, it takes an optional "status" query param
+	Duplicates(c *gin.Context)
+	MergeDuplicate(c *gin.Context)
+	DismissDuplicate(c *gin.Context)
 }
 
 type transactionHandler struct {
@@ -63,25 +75,43 @@ func stringToUUIDs(ids []string) ([]uuid.UUID, error) {
 	return filterIds, nil
 }
 
-func (h *transactionHandler) ListNormalized(c *gin.Context) {
-	ctx := c.Request.Context()
+// maxSearchQueryLength caps the q param of the transaction list
+const maxSearchQueryLength = 200
 
+// parseTransactionFilter builds a TransactionFilter from the query params shared by the
+// transaction list and export endpoints. The returned error message is safe to send to the client.
+func parseTransactionFilter(c *gin.Context) (model.TransactionFilter, error) {
 	accountIdParam := strings.TrimSpace(c.Query("accountId"))
 	categoryIdParam := strings.TrimSpace(c.Query("categoryId"))
 	payeeIdParam := strings.TrimSpace(c.Query("payeeId"))
 	startDateParam := strings.TrimSpace(c.DefaultQuery("startDate", ""))
 	endDateParam := strings.TrimSpace(c.DefaultQuery("endDate", ""))
 	noteParam := strings.TrimSpace(c.DefaultQuery("note", ""))
+	queryParam := strings.TrimSpace(c.Query("q"))
+	clearedParam := strings.TrimSpace(c.Query("cleared"))
 
 	limit := c.DefaultQuery("limit", "30")
 	limitInt, err := strconv.ParseUint(limit, 10, 64)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Error while parsing limit"})
-		return
+		return model.TransactionFilter{}, errors.New("Error while parsing limit")
 	}
 
 	groupBy := c.DefaultQuery("groupBy", "month")
-	sortOrder := c.DefaultQuery("sortOrder", "DESC")
+	// search results are ranked unless a sort order is asked for
+	defaultSortOrder := "DESC"
+	if queryParam != "" {
+		defaultSortOrder = model.SortOrderRelevance
+	}
+	sortOrder := c.DefaultQuery("sortOrder", defaultSortOrder)
+	if strings.EqualFold(sortOrder, model.SortOrderRelevance) {
+		if queryParam == "" {
+			return model.TransactionFilter{}, errors.New("sortOrder RELEVANCE requires q")
+		}
+		sortOrder = model.SortOrderRelevance
+	}
+	if len(queryParam) > maxSearchQueryLength {
+		return model.TransactionFilter{}, errors.New("q is too long")
+	}
 	cursor := c.Query("cursor")
 
 	accountIds := c.QueryArray("accountId[]")
@@ -99,8 +129,6 @@ func (h *transactionHandler) ListNormalized(c *gin.Context) {
 		payeeIds = strings.Split(payeeIdParam, ",")
 	}
 
-	logger.Logger(ctx).Info("listing normalized transactions", "accountIdParam", accountIdParam)
-
 	txnFilter := model.TransactionFilter{
 		Limit:        limitInt,
 		GroupBy:      &groupBy,
@@ -111,8 +139,7 @@ func (h *transactionHandler) ListNormalized(c *gin.Context) {
 	if len(accountIds) > 0 {
 		ids, err := stringToUUIDs(accountIds)
 		if err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "Error while parsing accountId"})
-			return
+			return model.TransactionFilter{}, errors.New("Error while parsing accountId")
 		}
 		txnFilter.AccountIDs = ids
 	}
@@ -120,8 +147,7 @@ func (h *transactionHandler) ListNormalized(c *gin.Context) {
 	if len(categoryIds) > 0 {
 		ids, err := stringToUUIDs(categoryIds)
 		if err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "Error while parsing categoryId"})
-			return
+			return model.TransactionFilter{}, errors.New("Error while parsing categoryId")
 		}
 		txnFilter.CategoryIDs = ids
 	}
@@ -129,16 +155,29 @@ func (h *transactionHandler) ListNormalized(c *gin.Context) {
 	if len(payeeIds) > 0 {
 		ids, err := stringToUUIDs(payeeIds)
 		if err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "Error while parsing payeeId"})
-			return
+			return model.TransactionFilter{}, errors.New("Error while parsing payeeId")
 		}
 		txnFilter.PayeeIDs = ids
 	}
 
+	if clearedParam != "" {
+		for _, value := range strings.Split(clearedParam, ",") {
+			cleared := model.ClearedStatus(strings.ToUpper(strings.TrimSpace(value)))
+			if err := cleared.Valid(); err != nil {
+				return model.TransactionFilter{}, errors.New("Error while parsing cleared")
+			}
+			txnFilter.Cleared = append(txnFilter.Cleared, cleared)
+		}
+	}
+
 	if noteParam != "" {
 		txnFilter.Note = &noteParam
 	}
 
+	if queryParam != "" {
+		txnFilter.Query = &queryParam
+	}
+
 	if startDateParam != "" {
 		txnFilter.StartDate = &startDateParam
 	}
@@ -147,6 +186,20 @@ func (h *transactionHandler) ListNormalized(c *gin.Context) {
 		txnFilter.EndDate = &endDateParam
 	}
 
+	return txnFilter, nil
+}
+
+func (h *transactionHandler) ListNormalized(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	txnFilter, err := parseTransactionFilter(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	logger.Logger(ctx).Info("listing normalized transactions", "accountIds", txnFilter.AccountIDs)
+
 	transactions, err := h.service.GetAllNormalized(ctx, &txnFilter)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
@@ -244,3 +297,134 @@ func (h *transactionHandler) DeleteById(c *gin.Context) {
 
 	c.JSON(http.StatusOK, nil)
 }
+
+func (h *transactionHandler) Bulk(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	var body model.BulkTransactionRequest
+	if err := c.ShouldBindJSON(&body); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	response, err := h.service.Bulk(ctx, body.Operations)
+	if err != nil {
+		if response != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error(), "results": response.Results})
+			return
+		}
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+func (h *transactionHandler) TransferMatches(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	windowDays := 0
+	if value := c.Query("windowDays"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Error while parsing windowDays"})
+			return
+		}
+		windowDays = parsed
+	}
+
+	matches, err := h.service.FindTransferMatches(ctx, windowDays)
+	if err != nil {
+		c.JSON(transactionErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, matches)
+}
+
+func (h *transactionHandler) AcceptTransferMatch(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	var body model.AcceptTransferMatchRequest
+	if err := c.ShouldBindJSON(&body); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if body.OutflowID == uuid.Nil || body.InflowID == uuid.Nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "outflowId and inflowId are required"})
+		return
+	}
+
+	match, err := h.service.AcceptTransferMatch(ctx, body.OutflowID, body.InflowID)
+	if err != nil {
+		c.JSON(transactionErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, match)
+}
+
+func (h *transactionHandler) Duplicates(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	status := model.DuplicateStatus(strings.ToUpper(c.Query("status")))
+	duplicates, err := h.service.GetDuplicates(ctx, status)
+	if err != nil {
+		c.JSON(transactionErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, duplicates)
+}
+
+// MergeDuplicate keeps one side of a suspected duplicate and deletes the other.
+// The body takes an optional "keepId", the older transaction is kept by default.
+func (h *transactionHandler) MergeDuplicate(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	id, err := uuid.Parse(c.Param("duplicateId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Error while parsing duplicateId"})
+		return
+	}
+	var body model.MergeDuplicateRequest
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&body); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	txn, err := h.service.MergeDuplicate(ctx, id, body.KeepID)
+	if err != nil {
+		c.JSON(transactionErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, txn)
+}
+
+func (h *transactionHandler) DismissDuplicate(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	id, err := uuid.Parse(c.Param("duplicateId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Error while parsing duplicateId"})
+		return
+	}
+	if err := h.service.DismissDuplicate(ctx, id); err != nil {
+		c.JSON(transactionErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "duplicate dismissed"})
+}
+
+func transactionErrorStatus(err error) int {
+	var apiErr *errs.Error
+	if errors.As(err, &apiErr) {
+		switch apiErr.Code {
+		case errs.CodeInvalidArgument:
+			return http.StatusBadRequest
+		case errs.CodeTransactionNotFound, errs.CodeDuplicateNotFound:
+			return http.StatusNotFound
+		case errs.CodeTransactionLocked, errs.CodeAccountClosed:
+			return http.StatusConflict
+		}
+	}
+	return http.StatusInternalServerError
+}
@@ -0,0 +1,174 @@
+package handler
+
+import (
+	stderrors "errors"
+	"net/http"
+
+	"github.com/Rishabh-Kapri/pennywise/backend/go-pennywise-api/internal/service"
+	errs "github.com/Rishabh-Kapri/pennywise/backend/shared/errors"
+	"github.com/Rishabh-Kapri/pennywise/backend/shared/model"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type TransactionTemplateHandler interface {
+	List(c *gin.Context)
+	GetById(c *gin.Context)
+	Create(c *gin.Context)
+	CreateFromTransaction(c *gin.Context)
+	Update(c *gin.Context)
+	DeleteById(c *gin.Context)
+	// Instantiate creates a transaction from the template, the body can override the date and amount
+	Instantiate(c *gin.Context)
+}
+
+type transactionTemplateHandler struct {
+	service service.TransactionTemplateService
+}
+
+func NewTransactionTemplateHandler(service service.TransactionTemplateService) TransactionTemplateHandler {
+	return &transactionTemplateHandler{service: service}
+}
+
+func (h *transactionTemplateHandler) List(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	templates, err := h.service.GetAll(ctx)
+	if err != nil {
+		c.JSON(transactionTemplateErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, templates)
+}
+
+func (h *transactionTemplateHandler) GetById(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Error while parsing id"})
+		return
+	}
+
+	template, err := h.service.GetById(ctx, id)
+	if err != nil {
+		c.JSON(transactionTemplateErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, template)
+}
+
+func (h *transactionTemplateHandler) Create(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	var body model.TransactionTemplate
+	if err := c.ShouldBindJSON(&body); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	created, err := h.service.Create(ctx, body)
+	if err != nil {
+		c.JSON(transactionTemplateErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, created)
+}
+
+func (h *transactionTemplateHandler) CreateFromTransaction(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	var body model.CreateTemplateFromTransactionRequest
+	if err := c.ShouldBindJSON(&body); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	created, err := h.service.CreateFromTransaction(ctx, body)
+	if err != nil {
+		c.JSON(transactionTemplateErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, created)
+}
+
+func (h *transactionTemplateHandler) Update(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Error while parsing id"})
+		return
+	}
+
+	var body model.TransactionTemplate
+	if err := c.ShouldBindJSON(&body); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	updated, err := h.service.Update(ctx, id, body)
+	if err != nil {
+		c.JSON(transactionTemplateErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, updated)
+}
+
+func (h *transactionTemplateHandler) DeleteById(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Error while parsing id"})
+		return
+	}
+
+	if err := h.service.DeleteById(ctx, id); err != nil {
+		c.JSON(transactionTemplateErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "transaction template deleted"})
+}
+
+func (h *transactionTemplateHandler) Instantiate(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Error while parsing id"})
+		return
+	}
+
+	// an empty body uses the template as is
+	var body model.InstantiateTemplateRequest
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&body); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	created, err := h.service.Instantiate(ctx, id, body)
+	if err != nil {
+		c.JSON(transactionTemplateErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, created)
+}
+
+func transactionTemplateErrorStatus(err error) int {
+	var apiErr *errs.Error
+	if stderrors.As(err, &apiErr) {
+		switch apiErr.Code {
+		case errs.CodeInvalidArgument:
+			return http.StatusBadRequest
+		case errs.CodeTemplateNotFound, errs.CodeTransactionNotFound:
+			return http.StatusNotFound
+		case errs.CodeTemplateNameTaken, errs.CodeAccountClosed:
+			return http.StatusConflict
+		}
+	}
+	return http.StatusInternalServerError
+}
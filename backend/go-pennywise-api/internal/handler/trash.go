@@ -0,0 +1,69 @@
+package handler
+
+import (
+	stderrors "errors"
+	"net/http"
+
+	"github.com/Rishabh-Kapri/pennywise/backend/go-pennywise-api/internal/service"
+	errs "github.com/Rishabh-Kapri/pennywise/backend/shared/errors"
+	"github.com/Rishabh-Kapri/pennywise/backend/shared/model"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type TrashHandler interface {
+	// List returns the deleted entities that can still be restored, ?type= narrows it to one type
+	List(c *gin.Context)
+	Restore(c *gin.Context)
+}
+
+type trashHandler struct {
+	service service.TrashService
+}
+
+func NewTrashHandler(service service.TrashService) TrashHandler {
+	return &trashHandler{service: service}
+}
+
+func (h *trashHandler) List(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	items, err := h.service.GetAll(ctx, model.TrashEntityType(c.Query("type")))
+	if err != nil {
+		c.JSON(trashErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, items)
+}
+
+func (h *trashHandler) Restore(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Error while parsing id"})
+		return
+	}
+	item, err := h.service.Restore(ctx, model.TrashEntityType(c.Param("type")), id)
+	if err != nil {
+		c.JSON(trashErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, item)
+}
+
+func trashErrorStatus(err error) int {
+	var apiErr *errs.Error
+	if stderrors.As(err, &apiErr) {
+		switch apiErr.Code {
+		case errs.CodeInvalidArgument:
+			return http.StatusBadRequest
+		case errs.CodeTrashItemNotFound:
+			return http.StatusNotFound
+		case errs.CodeTransactionLocked:
+			return http.StatusConflict
+		}
+	}
+	return http.StatusInternalServerError
+}
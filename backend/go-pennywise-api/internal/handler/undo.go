@@ -0,0 +1,67 @@
+package handler
+
+import (
+	"context"
+	stderrors "errors"
+	"io"
+	"net/http"
+
+	"github.com/Rishabh-Kapri/pennywise/backend/go-pennywise-api/internal/service"
+	errs "github.com/Rishabh-Kapri/pennywise/backend/shared/errors"
+	"github.com/Rishabh-Kapri/pennywise/backend/shared/model"
+
+	"github.com/gin-gonic/gin"
+)
+
+type UndoHandler interface {
+	// Undo reverts the last mutations of the current user, the body is optional and defaults to one mutation
+	Undo(c *gin.Context)
+	// Redo reapplies the last undone mutations of the current user
+	Redo(c *gin.Context)
+}
+
+type undoHandler struct {
+	service service.TransactionService
+}
+
+func NewUndoHandler(service service.TransactionService) UndoHandler {
+	return &undoHandler{service: service}
+}
+
+func (h *undoHandler) Undo(c *gin.Context) {
+	h.replay(c, h.service.Undo)
+}
+
+func (h *undoHandler) Redo(c *gin.Context) {
+	h.replay(c, h.service.Redo)
+}
+
+func (h *undoHandler) replay(c *gin.Context, replay func(ctx context.Context, count int) (*model.UndoResponse, error)) {
+	ctx := c.Request.Context()
+
+	var body model.UndoRequest
+	if err := c.ShouldBindJSON(&body); err != nil && !stderrors.Is(err, io.EOF) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	response, err := replay(ctx, body.Count)
+	if err != nil {
+		c.JSON(undoErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+func undoErrorStatus(err error) int {
+	var apiErr *errs.Error
+	if stderrors.As(err, &apiErr) {
+		switch apiErr.Code {
+		case errs.CodeInvalidArgument:
+			return http.StatusBadRequest
+		case errs.CodeUndoConflict, errs.CodeTransactionLocked:
+			return http.StatusConflict
+		}
+	}
+	return http.StatusInternalServerError
+}
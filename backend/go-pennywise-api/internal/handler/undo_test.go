@@ -0,0 +1,57 @@
+package handler
+
+import (
+	"net/http"
+	"testing"
+
+	errs "github.com/Rishabh-Kapri/pennywise/backend/shared/errors"
+	"github.com/Rishabh-Kapri/pennywise/backend/shared/model"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestUndoHandler(t *testing.T) {
+	t.Run("undo_defaults_to_one", func(t *testing.T) {
+		svc := &mockTransactionService{}
+		svc.On("Undo", mock.Anything, 0).Return(&model.UndoResponse{
+			Entries: []model.JournalEntry{{ID: uuid.New(), Action: model.AuditActionDelete}},
+		}, nil)
+		w, c := makeReq(http.MethodPost, "/api/undo", nil)
+		NewUndoHandler(svc).Undo(c)
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), `"action":"DELETE"`)
+	})
+
+	t.Run("redo_with_count", func(t *testing.T) {
+		svc := &mockTransactionService{}
+		svc.On("Redo", mock.Anything, 3).Return(&model.UndoResponse{Entries: []model.JournalEntry{}}, nil)
+		w, c := makeReq(http.MethodPost, "/api/redo", model.UndoRequest{Count: 3})
+		NewUndoHandler(svc).Redo(c)
+		assert.Equal(t, http.StatusOK, w.Code)
+		svc.AssertExpectations(t)
+	})
+
+	t.Run("conflict", func(t *testing.T) {
+		svc := &mockTransactionService{}
+		svc.On("Undo", mock.Anything, 1).Return(nil, errs.New(errs.CodeUndoConflict, "transaction was changed since"))
+		w, c := makeReq(http.MethodPost, "/api/undo", model.UndoRequest{Count: 1})
+		NewUndoHandler(svc).Undo(c)
+		assert.Equal(t, http.StatusConflict, w.Code)
+	})
+
+	t.Run("invalid_count", func(t *testing.T) {
+		svc := &mockTransactionService{}
+		svc.On("Undo", mock.Anything, 100).Return(nil, errs.New(errs.CodeInvalidArgument, "count must be between 1 and 50"))
+		w, c := makeReq(http.MethodPost, "/api/undo", model.UndoRequest{Count: 100})
+		NewUndoHandler(svc).Undo(c)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("invalid_body", func(t *testing.T) {
+		w, c := makeReq(http.MethodPost, "/api/undo", "not an object")
+		NewUndoHandler(&mockTransactionService{}).Undo(c)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
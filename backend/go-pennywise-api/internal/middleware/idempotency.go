@@ -0,0 +1,179 @@
+package middleware
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/Rishabh-Kapri/pennywise/backend/go-pennywise-api/internal/service"
+	errs "github.com/Rishabh-Kapri/pennywise/backend/shared/errors"
+	"github.com/Rishabh-Kapri/pennywise/backend/shared/logger"
+	utils "github.com/Rishabh-Kapri/pennywise/backend/shared/utils"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	maxIdempotencyKeyLength   = 255
+	maxIdempotentRequestSize  = 1 << 20
+	maxIdempotentResponseSize = 1 << 20
+)
+
+// responseRecorder keeps a copy of the response body while writing it out, up to
+// maxIdempotentResponseSize. A larger response isn't kept at all.
+type responseRecorder struct {
+	gin.ResponseWriter
+	body     bytes.Buffer
+	tooLarge bool
+}
+
+func (w *responseRecorder) record(size int) bool {
+	if w.tooLarge || w.body.Len()+size > maxIdempotentResponseSize {
+		w.tooLarge = true
+		w.body.Reset()
+		return false
+	}
+	return true
+}
+
+func (w *responseRecorder) Write(data []byte) (int, error) {
+	if w.record(len(data)) {
+		w.body.Write(data)
+	}
+	return w.ResponseWriter.Write(data)
+}
+
+func (w *responseRecorder) WriteString(s string) (int, error) {
+	if w.record(len(s)) {
+		w.body.WriteString(s)
+	}
+	return w.ResponseWriter.WriteString(s)
+}
+
+// IdempotencyMiddleware makes POST, PATCH and DELETE requests carrying an Idempotency-Key header
+// safe to retry. The first response for a key is stored and replayed for retries of the same
+// request; server errors aren't stored so the request can be retried.
+// Uploads are left alone, their multipart bodies are too large to keep around for fingerprinting.
+// Keys are scoped to the authenticated user, or the calling service for internal requests.
+func IdempotencyMiddleware(idempotencyService service.IdempotencyService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx := c.Request.Context()
+		log := logger.Logger(ctx)
+
+		idempotencyKey := c.GetHeader(utils.HeaderIdempotencyKey)
+		if idempotencyKey == "" || !isIdempotentMethod(c.Request.Method) ||
+			strings.HasPrefix(c.ContentType(), "multipart/") {
+			c.Next()
+			return
+		}
+		if len(idempotencyKey) > maxIdempotencyKeyLength {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "idempotency key is too long"})
+			c.Abort()
+			return
+		}
+		scope := idempotencyScope(c)
+		if scope == "" {
+			c.Next()
+			return
+		}
+		key := scope + ":" + idempotencyKey
+
+		body, err := io.ReadAll(http.MaxBytesReader(c.Writer, c.Request.Body, maxIdempotentRequestSize))
+		if err != nil {
+			var tooLarge *http.MaxBytesError
+			if errors.As(err, &tooLarge) {
+				c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": "request body is too large"})
+			} else {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "error reading request body"})
+			}
+			c.Abort()
+			return
+		}
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+		fingerprint := requestFingerprint(c, body)
+		stored, err := idempotencyService.Begin(ctx, key, fingerprint)
+		if err != nil {
+			var apiErr *errs.Error
+			switch {
+			case errors.As(err, &apiErr) && apiErr.Code == errs.CodeIdempotencyKeyReused:
+				c.JSON(http.StatusUnprocessableEntity, gin.H{"error": apiErr.Message})
+			case errors.As(err, &apiErr) && apiErr.Code == errs.CodeIdempotencyInProgress:
+				c.Header("Retry-After", "1")
+				c.JSON(http.StatusConflict, gin.H{"error": apiErr.Message})
+			default:
+				log.Error("idempotency check failed", "error", err)
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "idempotency check failed"})
+			}
+			c.Abort()
+			return
+		}
+		if stored != nil {
+			log.Debug("replaying idempotent response", "status", stored.Status)
+			c.Header(utils.HeaderIdempotentReplayed, "true")
+			c.Data(stored.Status, stored.ContentType, stored.Body)
+			c.Abort()
+			return
+		}
+
+		completed := false
+		defer func() {
+			if completed {
+				return
+			}
+			// a failed or panicking request leaves the key free for a retry
+			if err := idempotencyService.Release(ctx, key); err != nil {
+				log.Error("error releasing idempotency key", "error", err)
+			}
+		}()
+
+		recorder := &responseRecorder{ResponseWriter: c.Writer}
+		c.Writer = recorder
+		c.Next()
+
+		status := c.Writer.Status()
+		if status >= http.StatusInternalServerError || recorder.tooLarge {
+			return
+		}
+		if err := idempotencyService.Complete(ctx, key, service.IdempotentResponse{
+			Fingerprint: fingerprint,
+			Status:      status,
+			ContentType: c.Writer.Header().Get("Content-Type"),
+			Body:        recorder.body.Bytes(),
+		}); err != nil {
+			log.Error("error storing idempotent response", "error", err)
+			return
+		}
+		completed = true
+	}
+}
+
+func isIdempotentMethod(method string) bool {
+	return method == http.MethodPost || method == http.MethodPatch || method == http.MethodDelete
+}
+
+// idempotencyScope returns who the key belongs to, empty for unauthenticated requests
+func idempotencyScope(c *gin.Context) string {
+	ctx := c.Request.Context()
+	if userId, err := utils.UserIDFromContext(ctx); err == nil {
+		return "user:" + userId.String()
+	}
+	if utils.VerifiedInternalFromContext(ctx) {
+		return "service:" + utils.CallerServiceFromContext(ctx)
+	}
+	return ""
+}
+
+// requestFingerprint identifies a request by its method, url, budget and body
+func requestFingerprint(c *gin.Context, body []byte) string {
+	hash := sha256.New()
+	for _, part := range []string{c.Request.Method, c.Request.URL.RequestURI(), c.GetHeader(utils.HeaderBudgetID)} {
+		hash.Write([]byte(part))
+		hash.Write([]byte{0})
+	}
+	hash.Write(body)
+	return hex.EncodeToString(hash.Sum(nil))
+}
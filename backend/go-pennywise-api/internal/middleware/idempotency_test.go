@@ -0,0 +1,268 @@
+package middleware
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/Rishabh-Kapri/pennywise/backend/go-pennywise-api/internal/service"
+	errs "github.com/Rishabh-Kapri/pennywise/backend/shared/errors"
+	sharedMiddleware "github.com/Rishabh-Kapri/pennywise/backend/shared/middleware"
+	"github.com/Rishabh-Kapri/pennywise/backend/shared/utils"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// ─────────────────────────────────────────────────────────────────────────────
+// Mock IdempotencyService
+// ─────────────────────────────────────────────────────────────────────────────
+
+type mockIdempotencyService struct{ mock.Mock }
+
+func (m *mockIdempotencyService) Begin(ctx context.Context, key string, fingerprint string) (*service.IdempotentResponse, error) {
+	args := m.Called(ctx, key, fingerprint)
+	if v := args.Get(0); v != nil {
+		return v.(*service.IdempotentResponse), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *mockIdempotencyService) Complete(ctx context.Context, key string, response service.IdempotentResponse) error {
+	return m.Called(ctx, key, response).Error(0)
+}
+
+func (m *mockIdempotencyService) Release(ctx context.Context, key string) error {
+	return m.Called(ctx, key).Error(0)
+}
+
+// ─────────────────────────────────────────────────────────────────────────────
+// Helper
+// ─────────────────────────────────────────────────────────────────────────────
+
+func setupIdempotencyRouter(is service.IdempotencyService, userId *uuid.UUID, handler gin.HandlerFunc) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(sharedMiddleware.RequestMetadata("pennywise-api"))
+	if userId != nil {
+		r.Use(func(c *gin.Context) {
+			c.Request = c.Request.WithContext(utils.WithUserID(c.Request.Context(), *userId))
+			c.Next()
+		})
+	}
+	r.Use(IdempotencyMiddleware(is))
+	r.POST("/", handler)
+	r.GET("/", handler)
+	return r
+}
+
+func newIdempotentRequest(method string, key string, body string) *http.Request {
+	req := httptest.NewRequest(method, "/", strings.NewReader(body))
+	if key != "" {
+		req.Header.Set(utils.HeaderIdempotencyKey, key)
+	}
+	return req
+}
+
+// ─────────────────────────────────────────────────────────────────────────────
+// Tests
+// ─────────────────────────────────────────────────────────────────────────────
+
+func TestIdempotencyMiddleware_WithoutKeyOrOnGet_Passes(t *testing.T) {
+	is := &mockIdempotencyService{}
+	userId := uuid.New()
+	calls := 0
+	r := setupIdempotencyRouter(is, &userId, func(c *gin.Context) {
+		calls++
+		c.Status(http.StatusOK)
+	})
+
+	for _, req := range []*http.Request{
+		newIdempotentRequest(http.MethodPost, "", "{}"),
+		newIdempotentRequest(http.MethodGet, "key-1", ""),
+	} {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusOK, w.Code)
+	}
+	assert.Equal(t, 2, calls)
+	is.AssertNotCalled(t, "Begin")
+}
+
+func TestIdempotencyMiddleware_NoUser_Passes(t *testing.T) {
+	is := &mockIdempotencyService{}
+	r := setupIdempotencyRouter(is, nil, func(c *gin.Context) { c.Status(http.StatusCreated) })
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, newIdempotentRequest(http.MethodPost, "key-1", "{}"))
+	assert.Equal(t, http.StatusCreated, w.Code)
+	is.AssertNotCalled(t, "Begin")
+}
+
+func TestIdempotencyMiddleware_StoresFirstResponse(t *testing.T) {
+	is := &mockIdempotencyService{}
+	userId := uuid.New()
+	key := "user:" + userId.String() + ":key-1"
+	is.On("Begin", mock.Anything, key, mock.Anything).Return(nil, nil).Once()
+	is.On("Complete", mock.Anything, key, mock.MatchedBy(func(response service.IdempotentResponse) bool {
+		return response.Status == http.StatusCreated && string(response.Body) == `{"id":"txn-1"}` &&
+			strings.HasPrefix(response.ContentType, "application/json") && response.Fingerprint != ""
+	})).Return(nil).Once()
+
+	r := setupIdempotencyRouter(is, &userId, func(c *gin.Context) {
+		body, _ := io.ReadAll(c.Request.Body)
+		assert.Equal(t, `{"amount":10}`, string(body))
+		c.JSON(http.StatusCreated, gin.H{"id": "txn-1"})
+	})
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, newIdempotentRequest(http.MethodPost, "key-1", `{"amount":10}`))
+	assert.Equal(t, http.StatusCreated, w.Code)
+	assert.Empty(t, w.Header().Get(utils.HeaderIdempotentReplayed))
+	is.AssertExpectations(t)
+	is.AssertNotCalled(t, "Release")
+}
+
+func TestIdempotencyMiddleware_ReplaysStoredResponse(t *testing.T) {
+	is := &mockIdempotencyService{}
+	userId := uuid.New()
+	is.On("Begin", mock.Anything, mock.Anything, mock.Anything).Return(&service.IdempotentResponse{
+		Completed:   true,
+		Status:      http.StatusCreated,
+		ContentType: "application/json; charset=utf-8",
+		Body:        []byte(`{"id":"txn-1"}`),
+	}, nil).Once()
+
+	r := setupIdempotencyRouter(is, &userId, func(c *gin.Context) {
+		t.Fatal("handler must not run for a replayed request")
+	})
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, newIdempotentRequest(http.MethodPost, "key-1", `{"amount":10}`))
+	assert.Equal(t, http.StatusCreated, w.Code)
+	assert.Equal(t, `{"id":"txn-1"}`, w.Body.String())
+	assert.Equal(t, "true", w.Header().Get(utils.HeaderIdempotentReplayed))
+	is.AssertExpectations(t)
+}
+
+func TestIdempotencyMiddleware_SameRequestSameFingerprint(t *testing.T) {
+	is := &mockIdempotencyService{}
+	userId := uuid.New()
+	var fingerprints []string
+	is.On("Begin", mock.Anything, mock.Anything, mock.MatchedBy(func(fingerprint string) bool {
+		fingerprints = append(fingerprints, fingerprint)
+		return true
+	})).Return(nil, errs.New(errs.CodeIdempotencyInProgress, "in progress"))
+
+	r := setupIdempotencyRouter(is, &userId, func(c *gin.Context) { c.Status(http.StatusOK) })
+	for _, body := range []string{`{"amount":10}`, `{"amount":10}`, `{"amount":11}`} {
+		r.ServeHTTP(httptest.NewRecorder(), newIdempotentRequest(http.MethodPost, "key-1", body))
+	}
+	assert.Len(t, fingerprints, 3)
+	assert.Equal(t, fingerprints[0], fingerprints[1])
+	assert.NotEqual(t, fingerprints[0], fingerprints[2])
+}
+
+func TestIdempotencyMiddleware_Conflicts(t *testing.T) {
+	userId := uuid.New()
+	for _, tc := range []struct {
+		name   string
+		err    error
+		status int
+	}{
+		{"reused", errs.New(errs.CodeIdempotencyKeyReused, "reused"), http.StatusUnprocessableEntity},
+		{"in_progress", errs.New(errs.CodeIdempotencyInProgress, "in progress"), http.StatusConflict},
+		{"service_error", assert.AnError, http.StatusInternalServerError},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			is := &mockIdempotencyService{}
+			is.On("Begin", mock.Anything, mock.Anything, mock.Anything).Return(nil, tc.err).Once()
+			r := setupIdempotencyRouter(is, &userId, func(c *gin.Context) {
+				t.Fatal("handler must not run")
+			})
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, newIdempotentRequest(http.MethodPost, "key-1", "{}"))
+			assert.Equal(t, tc.status, w.Code)
+			is.AssertExpectations(t)
+		})
+	}
+}
+
+func TestIdempotencyMiddleware_ServerErrorReleasesKey(t *testing.T) {
+	is := &mockIdempotencyService{}
+	userId := uuid.New()
+	key := "user:" + userId.String() + ":key-1"
+	is.On("Begin", mock.Anything, key, mock.Anything).Return(nil, nil).Once()
+	is.On("Release", mock.Anything, key).Return(nil).Once()
+
+	r := setupIdempotencyRouter(is, &userId, func(c *gin.Context) {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "boom"})
+	})
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, newIdempotentRequest(http.MethodPost, "key-1", "{}"))
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	is.AssertExpectations(t)
+	is.AssertNotCalled(t, "Complete")
+}
+
+func TestIdempotencyMiddleware_KeyTooLong(t *testing.T) {
+	is := &mockIdempotencyService{}
+	userId := uuid.New()
+	r := setupIdempotencyRouter(is, &userId, func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, newIdempotentRequest(http.MethodPost, strings.Repeat("k", maxIdempotencyKeyLength+1), "{}"))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	is.AssertNotCalled(t, "Begin")
+}
+
+func TestIdempotencyMiddleware_MultipartPasses(t *testing.T) {
+	is := &mockIdempotencyService{}
+	userId := uuid.New()
+	r := setupIdempotencyRouter(is, &userId, func(c *gin.Context) { c.Status(http.StatusCreated) })
+
+	req := newIdempotentRequest(http.MethodPost, "key-1", "--boundary--")
+	req.Header.Set("Content-Type", "multipart/form-data; boundary=boundary")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusCreated, w.Code)
+	is.AssertNotCalled(t, "Begin")
+}
+
+func TestIdempotencyMiddleware_BodyTooLarge(t *testing.T) {
+	is := &mockIdempotencyService{}
+	userId := uuid.New()
+	r := setupIdempotencyRouter(is, &userId, func(c *gin.Context) {
+		t.Fatal("handler must not run")
+	})
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, newIdempotentRequest(http.MethodPost, "key-1", strings.Repeat("a", maxIdempotentRequestSize+1)))
+	assert.Equal(t, http.StatusRequestEntityTooLarge, w.Code)
+	is.AssertNotCalled(t, "Begin")
+}
+
+func TestIdempotencyMiddleware_LargeResponseNotStored(t *testing.T) {
+	is := &mockIdempotencyService{}
+	userId := uuid.New()
+	key := "user:" + userId.String() + ":key-1"
+	is.On("Begin", mock.Anything, key, mock.Anything).Return(nil, nil).Once()
+	is.On("Release", mock.Anything, key).Return(nil).Once()
+
+	chunk := strings.Repeat("a", maxIdempotentResponseSize/2)
+	r := setupIdempotencyRouter(is, &userId, func(c *gin.Context) {
+		c.Status(http.StatusOK)
+		for range 3 {
+			_, _ = c.Writer.WriteString(chunk)
+		}
+	})
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, newIdempotentRequest(http.MethodPost, "key-1", "{}"))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, 3*len(chunk), w.Body.Len())
+	is.AssertExpectations(t)
+	is.AssertNotCalled(t, "Complete")
+}
@@ -2,6 +2,10 @@ package service
 
 import (
 	"context"
+	"errors"
+	"math"
+	"strings"
+	"time"
 
 	repository "github.com/Rishabh-Kapri/pennywise/backend/shared/db"
 	"github.com/Rishabh-Kapri/pennywise/backend/shared/model"
@@ -9,6 +13,7 @@ import (
 	errs "github.com/Rishabh-Kapri/pennywise/backend/shared/errors"
 	utils "github.com/Rishabh-Kapri/pennywise/backend/shared/utils"
 
+	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
 )
 
@@ -16,15 +21,47 @@ type AccountService interface {
 	GetAll(ctx context.Context) ([]model.Account, error)
 	Search(ctx context.Context, query string) ([]model.Account, error)
 	Create(ctx context.Context, account model.Account) (*model.Account, error)
+	Reconcile(ctx context.Context, id uuid.UUID, req model.ReconcileRequest) (*model.ReconcileResult, error)
+	Update(ctx context.Context, id uuid.UUID, req model.UpdateAccountRequest) (*model.Account, error)
+	// Close closes an account with a zero balance, or moves the balance out with a final transfer first.
+	// The transfer payee of a closed account is hidden, its transactions stay as they are.
+	Close(ctx context.Context, id uuid.UUID, req model.CloseAccountRequest) (*model.CloseAccountResult, error)
+	Reopen(ctx context.Context, id uuid.UUID) (*model.Account, error)
+	// DeleteById deletes an account without transactions, accounts with history have to be closed instead
+	DeleteById(ctx context.Context, id uuid.UUID) error
 }
 
+// reconciliationPayeeName is the payee of the balance adjustment created by a reconciliation
+const reconciliationPayeeName = "Reconciliation Balance Adjustment"
+
 type accountService struct {
-	repo      repository.AccountRepository
-	payeeRepo repository.PayeesRepository
+	repo               repository.AccountRepository
+	payeeRepo          repository.PayeesRepository
+	transactionRepo    repository.TransactionRepository
+	budgetRepo         repository.BudgetRepository
+	categoryRepo       repository.CategoryRepository
+	transactionService TransactionService
+	auditService       AuditService
 }
 
-func NewAccountService(r repository.AccountRepository, payeeRepo repository.PayeesRepository) AccountService {
-	return &accountService{repo: r, payeeRepo: payeeRepo}
+func NewAccountService(
+	r repository.AccountRepository,
+	payeeRepo repository.PayeesRepository,
+	transactionRepo repository.TransactionRepository,
+	budgetRepo repository.BudgetRepository,
+	categoryRepo repository.CategoryRepository,
+	transactionService TransactionService,
+	auditService AuditService,
+) AccountService {
+	return &accountService{
+		repo:               r,
+		payeeRepo:          payeeRepo,
+		transactionRepo:    transactionRepo,
+		budgetRepo:         budgetRepo,
+		categoryRepo:       categoryRepo,
+		transactionService: transactionService,
+		auditService:       auditService,
+	}
 }
 
 func (s *accountService) GetAll(ctx context.Context) ([]model.Account, error) {
@@ -40,9 +77,17 @@ func (s *accountService) Search(ctx context.Context, query string) ([]model.Acco
 func (s *accountService) Create(ctx context.Context, account model.Account) (*model.Account, error) {
 	budgetId := utils.MustBudgetID(ctx)
 	account.BudgetID = budgetId
+	// accounts without a currency use the currency of the budget
+	if account.Currency != "" {
+		currency, err := model.NormalizeCurrency(account.Currency)
+		if err != nil {
+			return nil, err
+		}
+		account.Currency = currency
+	}
 
 	var createdAcc *model.Account
-	err := utils.WithTx(ctx, s.repo.GetDB(), func(tx pgx.Tx) error {
+	err := withTx(ctx, s.repo.GetDB(), func(tx pgx.Tx) error {
 		// 1. create account
 		acc, err := s.repo.Create(ctx, tx, account)
 		if err != nil {
@@ -67,6 +112,10 @@ func (s *accountService) Create(ctx context.Context, account model.Account) (*mo
 			return errs.Wrap(errs.CodeAccountCreateFailed, "error updating transfer payee", err)
 		}
 
+		// 4. credit cards get a payment category
+		if createdAcc.Type == "creditCard" {
+			return s.createPaymentCategory(ctx, tx, budgetId, *createdAcc)
+		}
 		return nil
 	})
 	if err != nil {
@@ -74,3 +123,410 @@ func (s *accountService) Create(ctx context.Context, account model.Account) (*mo
 	}
 	return createdAcc, nil
 }
+
+// createPaymentCategory creates the payment category of a credit card in the credit card payments group.
+// Spending on the card moves the budgeted money into it, payments to the card take it out again.
+func (s *accountService) createPaymentCategory(
+	ctx context.Context,
+	tx pgx.Tx,
+	budgetId uuid.UUID,
+	account model.Account,
+) error {
+	budget, err := s.budgetRepo.GetById(ctx, tx, budgetId)
+	if err != nil {
+		return errs.Wrap(errs.CodeBudgetLookupFailed, "error fetching budget", err)
+	}
+	_, err = s.categoryRepo.Create(ctx, tx, model.Category{
+		Name:            account.Name,
+		BudgetID:        budgetId,
+		CategoryGroupID: budget.Metadata.CCGroupID,
+		AccountID:       &account.ID,
+		IsSystem:        true,
+	})
+	if err != nil {
+		return errs.Wrap(errs.CodeCategoryCreateFailed, "error creating credit card payment category", err)
+	}
+	return nil
+}
+
+// Reconcile matches the cleared balance of an account against a statement balance.
+// Any difference is booked as a cleared adjustment transaction, then every cleared
+// transaction of the account is marked reconciled and locked.
+func (s *accountService) Reconcile(
+	ctx context.Context,
+	id uuid.UUID,
+	req model.ReconcileRequest,
+) (*model.ReconcileResult, error) {
+	budgetId := utils.MustBudgetID(ctx)
+	if req.Date == "" {
+		req.Date = model.Date(time.Now().Format("2006-01-02"))
+	}
+	if err := req.Date.Valid(); err != nil {
+		return nil, err
+	}
+
+	result := &model.ReconcileResult{StatementBalance: req.StatementBalance}
+	err := withTx(ctx, s.repo.GetDB(), func(tx pgx.Tx) error {
+		account, err := s.repo.GetBalances(ctx, tx, budgetId, id)
+		if err != nil {
+			return errs.Wrap(errs.CodeAccountLookupFailed, "error getting account", err)
+		}
+		result.ClearedBalance = account.ClearedBalance
+
+		// compare in cents to avoid floating point residue
+		difference := math.Round((req.StatementBalance-account.ClearedBalance)*100) / 100
+		if difference != 0 {
+			adjustment, err := s.createAdjustment(ctx, tx, budgetId, *account, difference, req.Date)
+			if err != nil {
+				return err
+			}
+			result.Adjustment = adjustment
+		}
+
+		var cleared []model.Transaction
+		if s.auditService != nil {
+			cleared, err = s.transactionRepo.GetForRewriteTx(ctx, tx, budgetId, model.TransactionFilter{
+				AccountIDs: []uuid.UUID{id},
+				Cleared:    []model.ClearedStatus{model.ClearedStatusCleared},
+			})
+			if err != nil {
+				return errs.Wrap(errs.CodeTransactionLookupFailed, "error getting cleared transactions", err)
+			}
+		}
+		result.Reconciled, err = s.transactionRepo.MarkReconciled(ctx, tx, budgetId, id)
+		if err != nil {
+			return errs.Wrap(errs.CodeAccountReconcileFailed, "error reconciling transactions", err)
+		}
+		if s.auditService != nil {
+			err = recordTransactionRewrites(ctx, tx, s.auditService, cleared, func(txn *model.Transaction) {
+				txn.Cleared = model.ClearedStatusReconciled
+			})
+			if err != nil {
+				return err
+			}
+		}
+		if err = s.repo.UpdateLastReconciled(ctx, tx, budgetId, id); err != nil {
+			return errs.Wrap(errs.CodeAccountReconcileFailed, "error updating account", err)
+		}
+
+		result.Account, err = s.repo.GetBalances(ctx, tx, budgetId, id)
+		if err != nil {
+			return errs.Wrap(errs.CodeAccountLookupFailed, "error reloading account", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// createAdjustment books the difference between the statement and the cleared balance.
+// Positive adjustments on budget accounts go to the inflow category, negative ones are
+// left uncategorized since the inflow category can't go negative.
+func (s *accountService) createAdjustment(
+	ctx context.Context,
+	tx pgx.Tx,
+	budgetId uuid.UUID,
+	account model.Account,
+	amount float64,
+	date model.Date,
+) (*model.Transaction, error) {
+	payeeId, err := s.adjustmentPayee(ctx, tx, budgetId)
+	if err != nil {
+		return nil, err
+	}
+
+	var categoryId *uuid.UUID
+	isBudgetAccount := account.Type == "savings" || account.Type == "checking" || account.Type == "creditCard"
+	if isBudgetAccount && amount > 0 {
+		budget, err := s.budgetRepo.GetById(ctx, tx, budgetId)
+		if err != nil {
+			return nil, errs.Wrap(errs.CodeBudgetLookupFailed, "error fetching budget", err)
+		}
+		categoryId = &budget.Metadata.InflowCategoryID
+	}
+
+	created, err := s.transactionService.CreateWithTx(ctx, tx, model.Transaction{
+		BudgetID:   budgetId,
+		AccountID:  &account.ID,
+		PayeeID:    &payeeId,
+		CategoryID: categoryId,
+		Date:       date,
+		Amount:     amount,
+		Note:       "Reconciliation balance adjustment",
+		Status:     model.TransactionStatusManual,
+		Cleared:    model.ClearedStatusCleared,
+		TagIDs:     []uuid.UUID{},
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &created[0], nil
+}
+
+// adjustmentPayee finds or creates the reconciliation adjustment payee
+func (s *accountService) adjustmentPayee(ctx context.Context, tx pgx.Tx, budgetId uuid.UUID) (uuid.UUID, error) {
+	payees, err := s.payeeRepo.Search(ctx, budgetId, reconciliationPayeeName)
+	if err != nil {
+		return uuid.Nil, errs.Wrap(errs.CodePayeeLookupFailed, "error searching reconciliation payee", err)
+	}
+	for _, payee := range payees {
+		if strings.EqualFold(payee.Name, reconciliationPayeeName) && payee.TransferAccountID == nil {
+			return payee.ID, nil
+		}
+	}
+
+	created, err := s.payeeRepo.Create(ctx, tx, model.Payee{Name: reconciliationPayeeName, BudgetID: budgetId})
+	if err != nil {
+		return uuid.Nil, errs.Wrap(errs.CodePayeeCreateFailed, "error creating reconciliation payee", err)
+	}
+	return created.ID, nil
+}
+
+// isBudgetAccountType reports whether accounts of the type are on budget
+func isBudgetAccountType(accountType string) bool {
+	return accountType == "savings" || accountType == "checking" || accountType == "creditCard"
+}
+
+func accountLookupError(err error) error {
+	if errors.Is(err, pgx.ErrNoRows) {
+		return errs.Wrap(errs.CodeAccountNotFound, "account not found", err)
+	}
+	return errs.Wrap(errs.CodeAccountLookupFailed, "error getting account", err)
+}
+
+func (s *accountService) Update(ctx context.Context, id uuid.UUID, req model.UpdateAccountRequest) (*model.Account, error) {
+	budgetId := utils.MustBudgetID(ctx)
+
+	err := withTx(ctx, s.repo.GetDB(), func(tx pgx.Tx) error {
+		account, err := s.repo.GetById(ctx, tx, budgetId, id)
+		if err != nil {
+			return accountLookupError(err)
+		}
+
+		becomesCard, leavesCard := false, false
+		if req.Name != nil {
+			account.Name = strings.TrimSpace(*req.Name)
+			if account.Name == "" {
+				return errs.New(errs.CodeInvalidArgument, "name is required")
+			}
+		}
+		if req.Type != nil && *req.Type != account.Type {
+			if *req.Type == "" {
+				return errs.New(errs.CodeInvalidArgument, "type is required")
+			}
+			// moving an account on or off budget would leave its existing transactions categorized the wrong way,
+			// turning it into or out of a credit card would leave its payment category wrong
+			movesBudget := isBudgetAccountType(*req.Type) != isBudgetAccountType(account.Type)
+			movesCard := (*req.Type == "creditCard") != (account.Type == "creditCard")
+			if movesBudget || movesCard {
+				hasTransactions, err := s.repo.HasTransactions(ctx, tx, budgetId, id)
+				if err != nil {
+					return errs.Wrap(errs.CodeAccountLookupFailed, "error checking account transactions", err)
+				}
+				if hasTransactions && movesBudget {
+					return errs.New(
+						errs.CodeAccountHasTransactions,
+						"an account with transactions can't be moved between budget and tracking accounts",
+					)
+				}
+				if hasTransactions {
+					return errs.New(
+						errs.CodeAccountHasTransactions,
+						"an account with transactions can't be turned into or out of a credit card",
+					)
+				}
+			}
+			becomesCard = movesCard && *req.Type == "creditCard"
+			leavesCard = movesCard && !becomesCard
+			account.Type = *req.Type
+		}
+		if req.Suffix != nil {
+			suffix := strings.TrimSpace(*req.Suffix)
+			account.Suffix = &suffix
+			if suffix == "" {
+				account.Suffix = nil
+			}
+		}
+
+		if err := s.repo.Update(ctx, tx, budgetId, id, *account); err != nil {
+			return errs.Wrap(errs.CodeAccountUpdateFailed, "error updating account", err)
+		}
+		if leavesCard {
+			// the payment category is kept hidden in case the account turns back into a credit card
+			if err := s.categoryRepo.SetPaymentCategoryHidden(ctx, tx, budgetId, id, true); err != nil {
+				return errs.Wrap(errs.CodeAccountUpdateFailed, "error hiding credit card payment category", err)
+			}
+			return nil
+		}
+		if !becomesCard {
+			return nil
+		}
+		// the update brings back the payment category of an account that was a credit card before
+		paymentCategoryIDs, err := s.categoryRepo.GetPaymentCategoryIDs(ctx, tx, budgetId)
+		if err != nil {
+			return errs.Wrap(errs.CodeCategoryLookupFailed, "error getting credit card payment categories", err)
+		}
+		if _, ok := paymentCategoryIDs[id]; !ok {
+			return s.createPaymentCategory(ctx, tx, budgetId, *account)
+		}
+		if err := s.categoryRepo.SetPaymentCategoryHidden(ctx, tx, budgetId, id, account.Closed); err != nil {
+			return errs.Wrap(errs.CodeAccountUpdateFailed, "error showing credit card payment category", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s.getBalances(ctx, budgetId, id)
+}
+
+func (s *accountService) Close(
+	ctx context.Context,
+	id uuid.UUID,
+	req model.CloseAccountRequest,
+) (*model.CloseAccountResult, error) {
+	budgetId := utils.MustBudgetID(ctx)
+	if req.Date == "" {
+		req.Date = model.Date(time.Now().Format("2006-01-02"))
+	}
+	if err := req.Date.Valid(); err != nil {
+		return nil, err
+	}
+
+	result := &model.CloseAccountResult{}
+	err := withTx(ctx, s.repo.GetDB(), func(tx pgx.Tx) error {
+		// GetBalances locks the account so no transaction can land between the balance check and the close
+		account, err := s.repo.GetBalances(ctx, tx, budgetId, id)
+		if err != nil {
+			return accountLookupError(err)
+		}
+		if account.Closed {
+			return errs.New(errs.CodeAccountClosed, "account %s is already closed", account.Name)
+		}
+
+		// compare in cents to avoid floating point residue
+		balance := math.Round(account.Balance*100) / 100
+		if balance != 0 {
+			if req.TransferAccountID == nil {
+				return errs.New(
+					errs.CodeAccountHasBalance,
+					"account %s has a balance of %.2f, transfer it to another account to close it",
+					account.Name, balance,
+				)
+			}
+			transfer, err := s.createClosingTransfer(ctx, tx, budgetId, *account, *req.TransferAccountID, -balance, req.Date)
+			if err != nil {
+				return err
+			}
+			result.Transfer = transfer
+		}
+
+		if err := s.repo.SetClosed(ctx, tx, budgetId, id, true); err != nil {
+			return errs.Wrap(errs.CodeAccountCloseFailed, "error closing account", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	result.Account, err = s.getBalances(ctx, budgetId, id)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// createClosingTransfer moves the balance of a closing account to the target account
+func (s *accountService) createClosingTransfer(
+	ctx context.Context,
+	tx pgx.Tx,
+	budgetId uuid.UUID,
+	account model.Account,
+	targetId uuid.UUID,
+	amount float64,
+	date model.Date,
+) (*model.Transaction, error) {
+	if targetId == account.ID {
+		return nil, errs.New(errs.CodeInvalidArgument, "can't transfer the balance of an account to itself")
+	}
+	target, err := s.repo.GetById(ctx, tx, budgetId, targetId)
+	if err != nil {
+		return nil, accountLookupError(err)
+	}
+	if target.TransferPayeeID == nil {
+		return nil, errs.New(errs.CodeAccountLookupFailed, "account %s has no transfer payee", target.Name)
+	}
+
+	created, err := s.transactionService.CreateWithTx(ctx, tx, model.Transaction{
+		BudgetID:  budgetId,
+		AccountID: &account.ID,
+		PayeeID:   target.TransferPayeeID,
+		Date:      date,
+		Amount:    amount,
+		Note:      "Closing balance transfer",
+		Status:    model.TransactionStatusManual,
+		Cleared:   model.ClearedStatusCleared,
+		TagIDs:    []uuid.UUID{},
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &created[0], nil
+}
+
+func (s *accountService) Reopen(ctx context.Context, id uuid.UUID) (*model.Account, error) {
+	budgetId := utils.MustBudgetID(ctx)
+	err := withTx(ctx, s.repo.GetDB(), func(tx pgx.Tx) error {
+		account, err := s.repo.GetById(ctx, tx, budgetId, id)
+		if err != nil {
+			return accountLookupError(err)
+		}
+		if !account.Closed {
+			return nil
+		}
+		if err := s.repo.SetClosed(ctx, tx, budgetId, id, false); err != nil {
+			return errs.Wrap(errs.CodeAccountUpdateFailed, "error reopening account", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s.getBalances(ctx, budgetId, id)
+}
+
+func (s *accountService) DeleteById(ctx context.Context, id uuid.UUID) error {
+	budgetId := utils.MustBudgetID(ctx)
+	return withTx(ctx, s.repo.GetDB(), func(tx pgx.Tx) error {
+		// lock the account so a transaction can't be added while it is being deleted
+		if _, err := s.repo.GetBalances(ctx, tx, budgetId, id); err != nil {
+			return accountLookupError(err)
+		}
+		hasTransactions, err := s.repo.HasTransactions(ctx, tx, budgetId, id)
+		if err != nil {
+			return errs.Wrap(errs.CodeAccountLookupFailed, "error checking account transactions", err)
+		}
+		if hasTransactions {
+			return errs.New(
+				errs.CodeAccountHasTransactions,
+				"account has transactions, close it instead to keep its history",
+			)
+		}
+		if err := s.repo.DeleteById(ctx, tx, budgetId, id); err != nil {
+			return errs.Wrap(errs.CodeAccountDeleteFailed, "error deleting account", err)
+		}
+		return nil
+	})
+}
+
+// getBalances reloads an account with its balances after a change
+func (s *accountService) getBalances(ctx context.Context, budgetId uuid.UUID, id uuid.UUID) (*model.Account, error) {
+	account, err := s.repo.GetBalances(ctx, nil, budgetId, id)
+	if err != nil {
+		return nil, accountLookupError(err)
+	}
+	return account, nil
+}
@@ -0,0 +1,273 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	errs "github.com/Rishabh-Kapri/pennywise/backend/shared/errors"
+	"github.com/Rishabh-Kapri/pennywise/backend/shared/model"
+	utils "github.com/Rishabh-Kapri/pennywise/backend/shared/utils"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAccountService_Close(t *testing.T) {
+	budgetId := uuid.New()
+	accountId := uuid.New()
+	targetId := uuid.New()
+	targetPayeeId := uuid.New()
+	ctx := utils.WithBudgetID(context.Background(), budgetId)
+	var mockTx pgx.Tx
+
+	setup := func(t *testing.T) (*svcAccountRepo, *mockTxnService, AccountService) {
+		mockWithTxSuccess(mockTx)
+		t.Cleanup(func() { withTx = utils.WithTx })
+		accountRepo := &svcAccountRepo{}
+		txnService := &mockTxnService{}
+		return accountRepo, txnService, NewAccountService(accountRepo, nil, nil, nil, nil, txnService, nil)
+	}
+
+	t.Run("zero_balance_closes", func(t *testing.T) {
+		accountRepo, txnService, service := setup(t)
+		accountRepo.On("GetBalances", ctx, mockTx, budgetId, accountId).
+			Return(&model.Account{ID: accountId, Balance: 0.0000001}, nil).Once()
+		accountRepo.On("SetClosed", ctx, mockTx, budgetId, accountId, true).Return(nil).Once()
+		accountRepo.On("GetBalances", ctx, nil, budgetId, accountId).
+			Return(&model.Account{ID: accountId, Closed: true}, nil).Once()
+
+		result, err := service.Close(ctx, accountId, model.CloseAccountRequest{})
+		require.NoError(t, err)
+		assert.True(t, result.Account.Closed)
+		assert.Nil(t, result.Transfer)
+		txnService.AssertNotCalled(t, "CreateWithTx", mock.Anything, mock.Anything, mock.Anything)
+		accountRepo.AssertExpectations(t)
+	})
+
+	t.Run("balance_without_transfer_account", func(t *testing.T) {
+		accountRepo, _, service := setup(t)
+		accountRepo.On("GetBalances", ctx, mockTx, budgetId, accountId).
+			Return(&model.Account{ID: accountId, Name: "Wallet", Balance: 12.5}, nil).Once()
+
+		_, err := service.Close(ctx, accountId, model.CloseAccountRequest{})
+		assert.True(t, hasErrorCode(err, errs.CodeAccountHasBalance), err)
+		accountRepo.AssertNotCalled(t, "SetClosed", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("balance_is_transferred_before_closing", func(t *testing.T) {
+		accountRepo, txnService, service := setup(t)
+		accountRepo.On("GetBalances", ctx, mockTx, budgetId, accountId).
+			Return(&model.Account{ID: accountId, Balance: 12.5}, nil).Once()
+		accountRepo.On("GetById", ctx, mockTx, budgetId, targetId).
+			Return(&model.Account{ID: targetId, TransferPayeeID: &targetPayeeId}, nil).Once()
+		txnService.On("CreateWithTx", ctx, mockTx, mock.MatchedBy(func(txn model.Transaction) bool {
+			return txn.Amount == -12.5 &&
+				*txn.AccountID == accountId &&
+				*txn.PayeeID == targetPayeeId &&
+				txn.Date == "2024-05-31"
+		})).Return([]model.Transaction{{ID: uuid.New(), Amount: -12.5}}, nil).Once()
+		accountRepo.On("SetClosed", ctx, mockTx, budgetId, accountId, true).Return(nil).Once()
+		accountRepo.On("GetBalances", ctx, nil, budgetId, accountId).
+			Return(&model.Account{ID: accountId, Closed: true}, nil).Once()
+
+		result, err := service.Close(ctx, accountId, model.CloseAccountRequest{
+			TransferAccountID: &targetId,
+			Date:              "2024-05-31",
+		})
+		require.NoError(t, err)
+		require.NotNil(t, result.Transfer)
+		assert.Equal(t, -12.5, result.Transfer.Amount)
+		txnService.AssertExpectations(t)
+		accountRepo.AssertExpectations(t)
+	})
+
+	t.Run("already_closed", func(t *testing.T) {
+		accountRepo, _, service := setup(t)
+		accountRepo.On("GetBalances", ctx, mockTx, budgetId, accountId).
+			Return(&model.Account{ID: accountId, Closed: true}, nil).Once()
+
+		_, err := service.Close(ctx, accountId, model.CloseAccountRequest{})
+		assert.True(t, hasErrorCode(err, errs.CodeAccountClosed), err)
+	})
+
+	t.Run("not_found", func(t *testing.T) {
+		accountRepo, _, service := setup(t)
+		accountRepo.On("GetBalances", ctx, mockTx, budgetId, accountId).Return(nil, pgx.ErrNoRows).Once()
+
+		_, err := service.Close(ctx, accountId, model.CloseAccountRequest{})
+		assert.True(t, hasErrorCode(err, errs.CodeAccountNotFound), err)
+	})
+}
+
+func TestAccountService_Create(t *testing.T) {
+	budgetId := uuid.New()
+	accountId := uuid.New()
+	payeeId := uuid.New()
+	ccGroupId := uuid.New()
+	ctx := utils.WithBudgetID(context.Background(), budgetId)
+	var mockTx pgx.Tx
+	mockWithTxSuccess(mockTx)
+	t.Cleanup(func() { withTx = utils.WithTx })
+
+	t.Run("credit_card_gets_a_payment_category", func(t *testing.T) {
+		accountRepo := &svcAccountRepo{}
+		payeeRepo := &svcPayeeRepo{}
+		budgetRepo := &svcBudgetRepo{}
+		categoryRepo := &svcCategoryRepo{}
+		accountRepo.On("Create", ctx, mockTx, mock.Anything).
+			Return(&model.Account{ID: accountId, Name: "Visa", Type: "creditCard"}, nil).Once()
+		payeeRepo.On("Create", ctx, mockTx, mock.Anything).Return(&model.Payee{ID: payeeId}, nil).Once()
+		accountRepo.On("UpdateTransferPayee", ctx, mockTx, accountId, payeeId).Return(nil).Once()
+		budgetRepo.On("GetById", ctx, mockTx, budgetId).
+			Return(&model.Budget{Metadata: model.BudgetMetadata{CCGroupID: ccGroupId}}, nil).Once()
+		categoryRepo.On("Create", ctx, mockTx, mock.MatchedBy(func(category model.Category) bool {
+			return category.Name == "Visa" && category.CategoryGroupID == ccGroupId &&
+				category.IsSystem && *category.AccountID == accountId
+		})).Return(&model.Category{}, nil).Once()
+
+		_, err := NewAccountService(accountRepo, payeeRepo, nil, budgetRepo, categoryRepo, nil, nil).
+			Create(ctx, model.Account{Name: "Visa", Type: "creditCard"})
+		require.NoError(t, err)
+		categoryRepo.AssertExpectations(t)
+	})
+
+	t.Run("checking_has_no_payment_category", func(t *testing.T) {
+		accountRepo := &svcAccountRepo{}
+		payeeRepo := &svcPayeeRepo{}
+		categoryRepo := &svcCategoryRepo{}
+		accountRepo.On("Create", ctx, mockTx, mock.Anything).
+			Return(&model.Account{ID: accountId, Name: "Checking", Type: "checking"}, nil).Once()
+		payeeRepo.On("Create", ctx, mockTx, mock.Anything).Return(&model.Payee{ID: payeeId}, nil).Once()
+		accountRepo.On("UpdateTransferPayee", ctx, mockTx, accountId, payeeId).Return(nil).Once()
+
+		_, err := NewAccountService(accountRepo, payeeRepo, nil, nil, categoryRepo, nil, nil).
+			Create(ctx, model.Account{Name: "Checking", Type: "checking"})
+		require.NoError(t, err)
+		categoryRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func TestAccountService_DeleteById(t *testing.T) {
+	budgetId := uuid.New()
+	accountId := uuid.New()
+	ctx := utils.WithBudgetID(context.Background(), budgetId)
+	var mockTx pgx.Tx
+	mockWithTxSuccess(mockTx)
+	t.Cleanup(func() { withTx = utils.WithTx })
+
+	t.Run("with_transactions", func(t *testing.T) {
+		accountRepo := &svcAccountRepo{}
+		accountRepo.On("GetBalances", ctx, mockTx, budgetId, accountId).Return(&model.Account{ID: accountId}, nil).Once()
+		accountRepo.On("HasTransactions", ctx, mockTx, budgetId, accountId).Return(true, nil).Once()
+
+		err := NewAccountService(accountRepo, nil, nil, nil, nil, nil, nil).DeleteById(ctx, accountId)
+		assert.True(t, hasErrorCode(err, errs.CodeAccountHasTransactions), err)
+		accountRepo.AssertNotCalled(t, "DeleteById", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("without_transactions", func(t *testing.T) {
+		accountRepo := &svcAccountRepo{}
+		accountRepo.On("GetBalances", ctx, mockTx, budgetId, accountId).Return(&model.Account{ID: accountId}, nil).Once()
+		accountRepo.On("HasTransactions", ctx, mockTx, budgetId, accountId).Return(false, nil).Once()
+		accountRepo.On("DeleteById", ctx, mockTx, budgetId, accountId).Return(nil).Once()
+
+		require.NoError(t, NewAccountService(accountRepo, nil, nil, nil, nil, nil, nil).DeleteById(ctx, accountId))
+		accountRepo.AssertExpectations(t)
+	})
+}
+
+func TestAccountService_Update(t *testing.T) {
+	budgetId := uuid.New()
+	accountId := uuid.New()
+	ctx := utils.WithBudgetID(context.Background(), budgetId)
+	var mockTx pgx.Tx
+	mockWithTxSuccess(mockTx)
+	t.Cleanup(func() { withTx = utils.WithTx })
+	suffix := " 1234 "
+
+	t.Run("renames_and_sets_suffix", func(t *testing.T) {
+		accountRepo := &svcAccountRepo{}
+		name := "Joint Checking"
+		accountRepo.On("GetById", ctx, mockTx, budgetId, accountId).
+			Return(&model.Account{ID: accountId, Name: "Checking", Type: "checking"}, nil).Once()
+		accountRepo.On("Update", ctx, mockTx, budgetId, accountId, mock.MatchedBy(func(account model.Account) bool {
+			return account.Name == name && account.Type == "checking" && *account.Suffix == "1234"
+		})).Return(nil).Once()
+		accountRepo.On("GetBalances", ctx, nil, budgetId, accountId).
+			Return(&model.Account{ID: accountId, Name: name}, nil).Once()
+
+		account, err := NewAccountService(accountRepo, nil, nil, nil, nil, nil, nil).
+			Update(ctx, accountId, model.UpdateAccountRequest{Name: &name, Suffix: &suffix})
+		require.NoError(t, err)
+		assert.Equal(t, name, account.Name)
+		accountRepo.AssertExpectations(t)
+	})
+
+	t.Run("off_budget_with_transactions", func(t *testing.T) {
+		accountRepo := &svcAccountRepo{}
+		accountType := "investment"
+		accountRepo.On("GetById", ctx, mockTx, budgetId, accountId).
+			Return(&model.Account{ID: accountId, Name: "Savings", Type: "savings"}, nil).Once()
+		accountRepo.On("HasTransactions", ctx, mockTx, budgetId, accountId).Return(true, nil).Once()
+
+		_, err := NewAccountService(accountRepo, nil, nil, nil, nil, nil, nil).
+			Update(ctx, accountId, model.UpdateAccountRequest{Type: &accountType})
+		assert.True(t, hasErrorCode(err, errs.CodeAccountHasTransactions), err)
+		accountRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("credit_card_with_transactions", func(t *testing.T) {
+		accountRepo := &svcAccountRepo{}
+		accountType := "creditCard"
+		accountRepo.On("GetById", ctx, mockTx, budgetId, accountId).
+			Return(&model.Account{ID: accountId, Name: "Checking", Type: "checking"}, nil).Once()
+		accountRepo.On("HasTransactions", ctx, mockTx, budgetId, accountId).Return(true, nil).Once()
+
+		_, err := NewAccountService(accountRepo, nil, nil, nil, nil, nil, nil).
+			Update(ctx, accountId, model.UpdateAccountRequest{Type: &accountType})
+		assert.True(t, hasErrorCode(err, errs.CodeAccountHasTransactions), err)
+		accountRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("becoming_a_credit_card_keeps_an_existing_payment_category", func(t *testing.T) {
+		accountRepo := &svcAccountRepo{}
+		categoryRepo := &svcCategoryRepo{}
+		accountType := "creditCard"
+		accountRepo.On("GetById", ctx, mockTx, budgetId, accountId).
+			Return(&model.Account{ID: accountId, Name: "Visa", Type: "checking"}, nil).Once()
+		accountRepo.On("HasTransactions", ctx, mockTx, budgetId, accountId).Return(false, nil).Once()
+		accountRepo.On("Update", ctx, mockTx, budgetId, accountId, mock.Anything).Return(nil).Once()
+		categoryRepo.On("GetPaymentCategoryIDs", ctx, mockTx, budgetId).
+			Return(map[uuid.UUID]uuid.UUID{accountId: uuid.New()}, nil).Once()
+		categoryRepo.On("SetPaymentCategoryHidden", ctx, mockTx, budgetId, accountId, false).Return(nil).Once()
+		accountRepo.On("GetBalances", ctx, nil, budgetId, accountId).
+			Return(&model.Account{ID: accountId, Type: "creditCard"}, nil).Once()
+
+		_, err := NewAccountService(accountRepo, nil, nil, nil, categoryRepo, nil, nil).
+			Update(ctx, accountId, model.UpdateAccountRequest{Type: &accountType})
+		require.NoError(t, err)
+		categoryRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything, mock.Anything)
+		categoryRepo.AssertExpectations(t)
+	})
+
+	t.Run("leaving_credit_cards_hides_the_payment_category", func(t *testing.T) {
+		accountRepo := &svcAccountRepo{}
+		categoryRepo := &svcCategoryRepo{}
+		accountType := "checking"
+		accountRepo.On("GetById", ctx, mockTx, budgetId, accountId).
+			Return(&model.Account{ID: accountId, Name: "Visa", Type: "creditCard"}, nil).Once()
+		accountRepo.On("HasTransactions", ctx, mockTx, budgetId, accountId).Return(false, nil).Once()
+		accountRepo.On("Update", ctx, mockTx, budgetId, accountId, mock.Anything).Return(nil).Once()
+		categoryRepo.On("SetPaymentCategoryHidden", ctx, mockTx, budgetId, accountId, true).Return(nil).Once()
+		accountRepo.On("GetBalances", ctx, nil, budgetId, accountId).
+			Return(&model.Account{ID: accountId, Type: "checking"}, nil).Once()
+
+		_, err := NewAccountService(accountRepo, nil, nil, nil, categoryRepo, nil, nil).
+			Update(ctx, accountId, model.UpdateAccountRequest{Type: &accountType})
+		require.NoError(t, err)
+		categoryRepo.AssertExpectations(t)
+	})
+}
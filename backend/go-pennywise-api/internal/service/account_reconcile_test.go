@@ -0,0 +1,169 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Rishabh-Kapri/pennywise/backend/shared/model"
+	utils "github.com/Rishabh-Kapri/pennywise/backend/shared/utils"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAccountService_Reconcile(t *testing.T) {
+	budgetId := uuid.New()
+	accountId := uuid.New()
+	inflowCategoryId := uuid.New()
+	payeeId := uuid.New()
+	ctx := utils.WithBudgetID(context.Background(), budgetId)
+	var mockTx pgx.Tx
+
+	type mocks struct {
+		accountRepo *svcAccountRepo
+		payeeRepo   *svcPayeeRepo
+		txnRepo     *mockTransactionRepo
+		budgetRepo  *svcBudgetRepo
+		txnService  *mockTxnService
+	}
+	setup := func(t *testing.T) (*mocks, AccountService) {
+		mockWithTxSuccess(mockTx)
+		t.Cleanup(func() { withTx = utils.WithTx })
+		m := &mocks{
+			accountRepo: &svcAccountRepo{},
+			payeeRepo:   &svcPayeeRepo{},
+			txnRepo:     &mockTransactionRepo{},
+			budgetRepo:  &svcBudgetRepo{},
+			txnService:  &mockTxnService{},
+		}
+		return m, NewAccountService(m.accountRepo, m.payeeRepo, m.txnRepo, m.budgetRepo, nil, m.txnService, nil)
+	}
+
+	t.Run("creates_adjustment_for_difference", func(t *testing.T) {
+		m, service := setup(t)
+		m.accountRepo.On("GetBalances", ctx, mockTx, budgetId, accountId).Return(&model.Account{
+			ID:             accountId,
+			Type:           "checking",
+			ClearedBalance: 100.10,
+		}, nil).Once()
+		m.payeeRepo.On("Search", ctx, budgetId, reconciliationPayeeName).Return([]model.Payee{
+			{ID: uuid.New(), Name: "Transfer : " + reconciliationPayeeName, TransferAccountID: &accountId},
+			{ID: payeeId, Name: reconciliationPayeeName},
+		}, nil)
+		m.budgetRepo.On("GetById", ctx, mockTx, budgetId).Return(&model.Budget{
+			Metadata: model.BudgetMetadata{InflowCategoryID: inflowCategoryId},
+		}, nil)
+		m.txnService.On("CreateWithTx", ctx, mockTx, mock.MatchedBy(func(txn model.Transaction) bool {
+			return txn.Amount == 20.15 &&
+				*txn.AccountID == accountId &&
+				*txn.PayeeID == payeeId &&
+				*txn.CategoryID == inflowCategoryId &&
+				txn.Cleared == model.ClearedStatusCleared &&
+				txn.Date == "2024-03-31"
+		})).Return([]model.Transaction{{ID: uuid.New(), Amount: 20.15}}, nil)
+		m.txnRepo.On("MarkReconciled", ctx, mockTx, budgetId, accountId).Return(int64(4), nil)
+		m.accountRepo.On("UpdateLastReconciled", ctx, mockTx, budgetId, accountId).Return(nil)
+		m.accountRepo.On("GetBalances", ctx, mockTx, budgetId, accountId).Return(&model.Account{
+			ID:             accountId,
+			ClearedBalance: 120.25,
+		}, nil).Once()
+
+		result, err := service.Reconcile(ctx, accountId, model.ReconcileRequest{
+			StatementBalance: 120.25,
+			Date:             "2024-03-31",
+		})
+		require.NoError(t, err)
+		assert.Equal(t, 100.10, result.ClearedBalance)
+		assert.Equal(t, int64(4), result.Reconciled)
+		require.NotNil(t, result.Adjustment)
+		assert.Equal(t, 20.15, result.Adjustment.Amount)
+		assert.Equal(t, 120.25, result.Account.ClearedBalance)
+		m.txnService.AssertExpectations(t)
+		m.accountRepo.AssertExpectations(t)
+	})
+
+	t.Run("negative_adjustment_creates_payee_without_category", func(t *testing.T) {
+		m, service := setup(t)
+		m.accountRepo.On("GetBalances", ctx, mockTx, budgetId, accountId).Return(&model.Account{
+			ID:             accountId,
+			Type:           "checking",
+			ClearedBalance: 50,
+		}, nil)
+		m.payeeRepo.On("Search", ctx, budgetId, reconciliationPayeeName).Return([]model.Payee{}, nil)
+		m.payeeRepo.On("Create", ctx, mockTx, model.Payee{Name: reconciliationPayeeName, BudgetID: budgetId}).
+			Return(&model.Payee{ID: payeeId}, nil)
+		m.txnService.On("CreateWithTx", ctx, mockTx, mock.MatchedBy(func(txn model.Transaction) bool {
+			return txn.Amount == -10 && txn.CategoryID == nil && *txn.PayeeID == payeeId
+		})).Return([]model.Transaction{{ID: uuid.New(), Amount: -10}}, nil)
+		m.txnRepo.On("MarkReconciled", ctx, mockTx, budgetId, accountId).Return(int64(1), nil)
+		m.accountRepo.On("UpdateLastReconciled", ctx, mockTx, budgetId, accountId).Return(nil)
+
+		result, err := service.Reconcile(ctx, accountId, model.ReconcileRequest{StatementBalance: 40})
+		require.NoError(t, err)
+		require.NotNil(t, result.Adjustment)
+		m.budgetRepo.AssertNotCalled(t, "GetById", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("matching_balance_skips_adjustment", func(t *testing.T) {
+		m, service := setup(t)
+		m.accountRepo.On("GetBalances", ctx, mockTx, budgetId, accountId).Return(&model.Account{
+			ID:             accountId,
+			ClearedBalance: 99.999999,
+		}, nil)
+		m.txnRepo.On("MarkReconciled", ctx, mockTx, budgetId, accountId).Return(int64(2), nil)
+		m.accountRepo.On("UpdateLastReconciled", ctx, mockTx, budgetId, accountId).Return(nil)
+
+		result, err := service.Reconcile(ctx, accountId, model.ReconcileRequest{StatementBalance: 100})
+		require.NoError(t, err)
+		assert.Nil(t, result.Adjustment)
+		m.txnService.AssertNotCalled(t, "CreateWithTx", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("audits_reconciled_transactions", func(t *testing.T) {
+		m, _ := setup(t)
+		auditService := &mockAuditService{}
+		service := NewAccountService(m.accountRepo, m.payeeRepo, m.txnRepo, m.budgetRepo, nil, m.txnService, auditService)
+		clearedTxn := model.Transaction{ID: uuid.New(), AccountID: &accountId, Amount: -25, Cleared: model.ClearedStatusCleared}
+
+		m.accountRepo.On("GetBalances", ctx, mockTx, budgetId, accountId).Return(&model.Account{
+			ID:             accountId,
+			ClearedBalance: 100,
+		}, nil)
+		m.txnRepo.On("GetForRewriteTx", ctx, mockTx, budgetId, model.TransactionFilter{
+			AccountIDs: []uuid.UUID{accountId},
+			Cleared:    []model.ClearedStatus{model.ClearedStatusCleared},
+		}).Return([]model.Transaction{clearedTxn}, nil).Once()
+		m.txnRepo.On("MarkReconciled", ctx, mockTx, budgetId, accountId).Return(int64(1), nil)
+		m.accountRepo.On("UpdateLastReconciled", ctx, mockTx, budgetId, accountId).Return(nil)
+		auditService.On("Record", ctx, mockTx, mock.MatchedBy(func(record AuditRecord) bool {
+			before := record.Before.(transactionAuditSnapshot)
+			after := record.After.(transactionAuditSnapshot)
+			return record.EntityID == clearedTxn.ID &&
+				record.Action == model.AuditActionUpdate &&
+				before.Cleared == model.ClearedStatusCleared &&
+				after.Cleared == model.ClearedStatusReconciled
+		})).Return(nil).Once()
+
+		_, err := service.Reconcile(ctx, accountId, model.ReconcileRequest{StatementBalance: 100})
+		require.NoError(t, err)
+		auditService.AssertExpectations(t)
+	})
+
+	t.Run("account_lookup_error", func(t *testing.T) {
+		m, service := setup(t)
+		m.accountRepo.On("GetBalances", ctx, mockTx, budgetId, accountId).Return(nil, pgx.ErrNoRows)
+
+		_, err := service.Reconcile(ctx, accountId, model.ReconcileRequest{StatementBalance: 100})
+		assert.Error(t, err)
+		m.txnRepo.AssertNotCalled(t, "MarkReconciled", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("invalid_date", func(t *testing.T) {
+		_, service := setup(t)
+		_, err := service.Reconcile(ctx, accountId, model.ReconcileRequest{Date: "31/03/2024"})
+		assert.Error(t, err)
+	})
+}
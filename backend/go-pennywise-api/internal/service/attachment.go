@@ -0,0 +1,171 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/Rishabh-Kapri/pennywise/backend/go-pennywise-api/internal/blobstore"
+	repository "github.com/Rishabh-Kapri/pennywise/backend/shared/db"
+	errs "github.com/Rishabh-Kapri/pennywise/backend/shared/errors"
+	"github.com/Rishabh-Kapri/pennywise/backend/shared/logger"
+	"github.com/Rishabh-Kapri/pennywise/backend/shared/model"
+	utils "github.com/Rishabh-Kapri/pennywise/backend/shared/utils"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+// maxAttachmentNameLength caps the stored file name, longer names are truncated
+const maxAttachmentNameLength = 255
+
+type AttachmentService interface {
+	GetAll(ctx context.Context, transactionId uuid.UUID) ([]model.Attachment, error)
+	// Upload stores the file and links it to the transaction. The content type is
+	// sniffed from the file and has to be one of model.AllowedAttachmentTypes.
+	Upload(ctx context.Context, transactionId uuid.UUID, fileName string, r io.Reader) (*model.Attachment, error)
+	// Open returns the attachment along with its contents, the caller has to close the reader
+	Open(ctx context.Context, transactionId uuid.UUID, id uuid.UUID) (*model.Attachment, io.ReadCloser, error)
+	DeleteById(ctx context.Context, transactionId uuid.UUID, id uuid.UUID) error
+}
+
+type attachmentService struct {
+	repo    repository.AttachmentRepository
+	txnRepo repository.TransactionRepository
+	store   blobstore.BlobStore
+}
+
+func NewAttachmentService(
+	repo repository.AttachmentRepository,
+	txnRepo repository.TransactionRepository,
+	store blobstore.BlobStore,
+) AttachmentService {
+	return &attachmentService{repo: repo, txnRepo: txnRepo, store: store}
+}
+
+func (s *attachmentService) GetAll(ctx context.Context, transactionId uuid.UUID) ([]model.Attachment, error) {
+	budgetId := utils.MustBudgetID(ctx)
+	attachments, err := s.repo.GetAllByTransaction(ctx, budgetId, transactionId)
+	if err != nil {
+		return nil, errs.Wrap(errs.CodeAttachmentLookupFailed, "error getting attachments", err)
+	}
+	return attachments, nil
+}
+
+func (s *attachmentService) Upload(
+	ctx context.Context,
+	transactionId uuid.UUID,
+	fileName string,
+	r io.Reader,
+) (*model.Attachment, error) {
+	budgetId := utils.MustBudgetID(ctx)
+
+	if _, err := s.txnRepo.GetById(ctx, budgetId, transactionId); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, errs.New(errs.CodeTransactionNotFound, "transaction not found for id %v", transactionId)
+		}
+		return nil, errs.Wrap(errs.CodeTransactionLookupFailed, "error getting transaction", err)
+	}
+
+	// read one byte past the limit to tell a file of exactly the limit from a larger one
+	data, err := io.ReadAll(io.LimitReader(r, model.MaxAttachmentSize+1))
+	if err != nil {
+		return nil, errs.Wrap(errs.CodeInvalidArgument, "error reading file", err)
+	}
+	if len(data) == 0 {
+		return nil, errs.New(errs.CodeInvalidArgument, "file is empty")
+	}
+	if len(data) > model.MaxAttachmentSize {
+		return nil, errs.New(errs.CodeAttachmentTooLarge, "file is larger than %d bytes", model.MaxAttachmentSize)
+	}
+	contentType, _, err := mime.ParseMediaType(http.DetectContentType(data))
+	if err != nil || !model.AllowedAttachmentTypes[contentType] {
+		return nil, errs.New(errs.CodeAttachmentUnsupported, "file type %s is not supported", contentType)
+	}
+
+	attachment := model.Attachment{
+		ID:            uuid.New(),
+		BudgetID:      budgetId,
+		TransactionID: transactionId,
+		FileName:      sanitizeAttachmentName(fileName),
+		ContentType:   contentType,
+		Size:          int64(len(data)),
+	}
+	attachment.StorageKey = fmt.Sprintf("%s/%s/%s", budgetId, transactionId, attachment.ID)
+
+	if err := s.store.Put(ctx, attachment.StorageKey, bytes.NewReader(data)); err != nil {
+		return nil, errs.Wrap(errs.CodeAttachmentSaveFailed, "error storing file", err)
+	}
+	created, err := s.repo.Create(ctx, nil, attachment)
+	if err != nil {
+		// the blob is unreachable without its row
+		if deleteErr := s.store.Delete(ctx, attachment.StorageKey); deleteErr != nil {
+			logger.Logger(ctx).Warn("failed to remove orphaned attachment blob", "key", attachment.StorageKey, "error", deleteErr)
+		}
+		return nil, errs.Wrap(errs.CodeAttachmentSaveFailed, "error saving attachment", err)
+	}
+	return created, nil
+}
+
+func (s *attachmentService) Open(
+	ctx context.Context,
+	transactionId uuid.UUID,
+	id uuid.UUID,
+) (*model.Attachment, io.ReadCloser, error) {
+	attachment, err := s.getById(ctx, transactionId, id)
+	if err != nil {
+		return nil, nil, err
+	}
+	reader, err := s.store.Get(ctx, attachment.StorageKey)
+	if errors.Is(err, blobstore.ErrNotFound) {
+		return nil, nil, errs.New(errs.CodeAttachmentNotFound, "file of attachment %v is missing", id)
+	}
+	if err != nil {
+		return nil, nil, errs.Wrap(errs.CodeAttachmentLookupFailed, "error reading file", err)
+	}
+	return attachment, reader, nil
+}
+
+// DeleteById soft deletes the attachment. The file is kept so the attachment can be restored.
+func (s *attachmentService) DeleteById(ctx context.Context, transactionId uuid.UUID, id uuid.UUID) error {
+	budgetId := utils.MustBudgetID(ctx)
+	if _, err := s.getById(ctx, transactionId, id); err != nil {
+		return err
+	}
+	if err := s.repo.DeleteById(ctx, budgetId, transactionId, id); err != nil {
+		return errs.Wrap(errs.CodeAttachmentDeleteFailed, "error deleting attachment", err)
+	}
+	return nil
+}
+
+func (s *attachmentService) getById(ctx context.Context, transactionId uuid.UUID, id uuid.UUID) (*model.Attachment, error) {
+	budgetId := utils.MustBudgetID(ctx)
+	attachment, err := s.repo.GetById(ctx, budgetId, transactionId, id)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, errs.New(errs.CodeAttachmentNotFound, "attachment not found for id %v", id)
+	}
+	if err != nil {
+		return nil, errs.Wrap(errs.CodeAttachmentLookupFailed, "error getting attachment", err)
+	}
+	return attachment, nil
+}
+
+// sanitizeAttachmentName drops any directory part of the client supplied name
+func sanitizeAttachmentName(name string) string {
+	name = strings.TrimSpace(filepath.Base(strings.ReplaceAll(name, "\\", "/")))
+	if name == "" || name == "." || name == "/" {
+		return "attachment"
+	}
+	for len(name) > maxAttachmentNameLength {
+		_, size := utf8.DecodeLastRuneInString(name)
+		name = name[:len(name)-size]
+	}
+	return name
+}
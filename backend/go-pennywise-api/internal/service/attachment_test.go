@@ -0,0 +1,153 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/Rishabh-Kapri/pennywise/backend/go-pennywise-api/internal/blobstore"
+	errs "github.com/Rishabh-Kapri/pennywise/backend/shared/errors"
+	"github.com/Rishabh-Kapri/pennywise/backend/shared/model"
+	utils "github.com/Rishabh-Kapri/pennywise/backend/shared/utils"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type mockAttachmentRepo struct {
+	mockBaseRepo
+	mock.Mock
+}
+
+func (m *mockAttachmentRepo) GetAllByTransaction(
+	ctx context.Context,
+	budgetId uuid.UUID,
+	transactionId uuid.UUID,
+) ([]model.Attachment, error) {
+	args := m.Called(ctx, budgetId, transactionId)
+	if v := args.Get(0); v != nil {
+		return v.([]model.Attachment), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *mockAttachmentRepo) GetById(
+	ctx context.Context,
+	budgetId uuid.UUID,
+	transactionId uuid.UUID,
+	id uuid.UUID,
+) (*model.Attachment, error) {
+	args := m.Called(ctx, budgetId, transactionId, id)
+	if v := args.Get(0); v != nil {
+		return v.(*model.Attachment), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *mockAttachmentRepo) Create(ctx context.Context, tx pgx.Tx, attachment model.Attachment) (*model.Attachment, error) {
+	args := m.Called(ctx, tx, attachment)
+	if v := args.Get(0); v != nil {
+		return v.(*model.Attachment), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *mockAttachmentRepo) DeleteById(
+	ctx context.Context,
+	budgetId uuid.UUID,
+	transactionId uuid.UUID,
+	id uuid.UUID,
+) error {
+	return m.Called(ctx, budgetId, transactionId, id).Error(0)
+}
+
+func hasErrorCode(err error, code errs.Code) bool {
+	var apiErr *errs.Error
+	return errors.As(err, &apiErr) && apiErr.Code == code
+}
+
+func TestAttachmentService_Upload(t *testing.T) {
+	budgetId := uuid.New()
+	txnId := uuid.New()
+	ctx := utils.WithBudgetID(context.Background(), budgetId)
+	pdf := []byte("%PDF-1.4\n%receipt")
+
+	newService := func(t *testing.T) (*mockAttachmentRepo, *mockTransactionRepo, blobstore.BlobStore, AttachmentService) {
+		store, err := blobstore.NewLocalStore(t.TempDir())
+		require.NoError(t, err)
+		repo := &mockAttachmentRepo{}
+		txnRepo := &mockTransactionRepo{}
+		return repo, txnRepo, store, NewAttachmentService(repo, txnRepo, store)
+	}
+
+	t.Run("stores_file_and_row", func(t *testing.T) {
+		repo, txnRepo, store, service := newService(t)
+		txnRepo.On("GetById", ctx, budgetId, txnId).Return(&model.Transaction{ID: txnId}, nil).Once()
+		var created model.Attachment
+		repo.On("Create", ctx, nil, mock.MatchedBy(func(a model.Attachment) bool {
+			created = a
+			return a.FileName == "invoice.pdf" && a.ContentType == "application/pdf" && a.Size == int64(len(pdf)) &&
+				strings.HasPrefix(a.StorageKey, budgetId.String()+"/"+txnId.String()+"/")
+		})).Return(&created, nil).Once()
+
+		attachment, err := service.Upload(ctx, txnId, `C:\scans\invoice.pdf`, bytes.NewReader(pdf))
+		require.NoError(t, err)
+
+		reader, err := store.Get(ctx, attachment.StorageKey)
+		require.NoError(t, err)
+		defer reader.Close()
+		stored, _ := io.ReadAll(reader)
+		assert.Equal(t, pdf, stored)
+		repo.AssertExpectations(t)
+	})
+
+	t.Run("rejects_unsupported_type", func(t *testing.T) {
+		_, txnRepo, _, service := newService(t)
+		txnRepo.On("GetById", ctx, budgetId, txnId).Return(&model.Transaction{ID: txnId}, nil).Once()
+
+		_, err := service.Upload(ctx, txnId, "run.exe", bytes.NewReader([]byte("MZ\x90\x00\x03\x00\x00\x00")))
+		assert.True(t, hasErrorCode(err, errs.CodeAttachmentUnsupported), err)
+	})
+
+	t.Run("rejects_large_file", func(t *testing.T) {
+		_, txnRepo, _, service := newService(t)
+		txnRepo.On("GetById", ctx, budgetId, txnId).Return(&model.Transaction{ID: txnId}, nil).Once()
+
+		data := append(append([]byte{}, pdf...), make([]byte, model.MaxAttachmentSize)...)
+		_, err := service.Upload(ctx, txnId, "big.pdf", bytes.NewReader(data))
+		assert.True(t, hasErrorCode(err, errs.CodeAttachmentTooLarge), err)
+	})
+
+	t.Run("missing_transaction", func(t *testing.T) {
+		_, txnRepo, _, service := newService(t)
+		txnRepo.On("GetById", ctx, budgetId, txnId).Return(nil, pgx.ErrNoRows).Once()
+
+		_, err := service.Upload(ctx, txnId, "invoice.pdf", bytes.NewReader(pdf))
+		assert.True(t, hasErrorCode(err, errs.CodeTransactionNotFound), err)
+	})
+}
+
+func TestAttachmentService_DeleteById(t *testing.T) {
+	budgetId := uuid.New()
+	txnId := uuid.New()
+	id := uuid.New()
+	ctx := utils.WithBudgetID(context.Background(), budgetId)
+
+	repo := &mockAttachmentRepo{}
+	service := NewAttachmentService(repo, &mockTransactionRepo{}, nil)
+
+	repo.On("GetById", ctx, budgetId, txnId, id).Return(nil, pgx.ErrNoRows).Once()
+	err := service.DeleteById(ctx, txnId, id)
+	assert.True(t, hasErrorCode(err, errs.CodeAttachmentNotFound), err)
+
+	repo.On("GetById", ctx, budgetId, txnId, id).Return(&model.Attachment{ID: id}, nil).Once()
+	repo.On("DeleteById", ctx, budgetId, txnId, id).Return(nil).Once()
+	require.NoError(t, service.DeleteById(ctx, txnId, id))
+	repo.AssertExpectations(t)
+}
@@ -0,0 +1,259 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"slices"
+	"sort"
+
+	repository "github.com/Rishabh-Kapri/pennywise/backend/shared/db"
+	errs "github.com/Rishabh-Kapri/pennywise/backend/shared/errors"
+	"github.com/Rishabh-Kapri/pennywise/backend/shared/model"
+	utils "github.com/Rishabh-Kapri/pennywise/backend/shared/utils"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+// unknownServiceActor is recorded when a mutation carries neither a user, an API key nor a service name
+const unknownServiceActor = "unknown"
+
+type AuditService interface {
+	// Record appends an audit entry in the given db transaction. Before is nil for creates
+	// and after is nil for deletes. Updates that don't change anything are not recorded.
+	Record(ctx context.Context, tx pgx.Tx, record AuditRecord) error
+	GetHistory(
+		ctx context.Context,
+		entityType model.AuditEntityType,
+		entityId uuid.UUID,
+	) ([]model.AuditLogEntry, error)
+}
+
+// AuditRecord is a single mutation to audit. Before and After are snapshots that
+// get diffed field by field, so they should only hold the fields worth tracking.
+type AuditRecord struct {
+	EntityType model.AuditEntityType
+	EntityID   uuid.UUID
+	Action     model.AuditAction
+	Before     any
+	After      any
+}
+
+type auditService struct {
+	repo repository.AuditLogRepository
+}
+
+func NewAuditService(repo repository.AuditLogRepository) AuditService {
+	return &auditService{repo: repo}
+}
+
+func (s *auditService) Record(ctx context.Context, tx pgx.Tx, record AuditRecord) error {
+	before, err := marshalAuditSnapshot(record.Before)
+	if err != nil {
+		return errs.Wrap(errs.CodeAuditRecordFailed, "error encoding audit snapshot", err)
+	}
+	after, err := marshalAuditSnapshot(record.After)
+	if err != nil {
+		return errs.Wrap(errs.CodeAuditRecordFailed, "error encoding audit snapshot", err)
+	}
+	changes, err := auditDiff(before, after)
+	if err != nil {
+		return errs.Wrap(errs.CodeAuditRecordFailed, "error diffing audit snapshots", err)
+	}
+	if record.Action == model.AuditActionUpdate && len(changes) == 0 {
+		return nil
+	}
+
+	entry := model.AuditLogEntry{
+		BudgetID:   utils.MustBudgetID(ctx),
+		EntityType: record.EntityType,
+		EntityID:   record.EntityID,
+		Action:     record.Action,
+		Changes:    changes,
+		Before:     before,
+		After:      after,
+	}
+	entry.ActorType, entry.ActorID = auditActor(ctx)
+	if correlationId := utils.CorrelationIDFromContext(ctx); correlationId != "" {
+		entry.CorrelationID = &correlationId
+	}
+	if err := s.repo.Create(ctx, tx, entry); err != nil {
+		return errs.Wrap(errs.CodeAuditRecordFailed, "error creating audit log entry", err)
+	}
+	return nil
+}
+
+func (s *auditService) GetHistory(
+	ctx context.Context,
+	entityType model.AuditEntityType,
+	entityId uuid.UUID,
+) ([]model.AuditLogEntry, error) {
+	budgetId := utils.MustBudgetID(ctx)
+	entries, err := s.repo.GetByEntity(ctx, budgetId, entityType, entityId)
+	if err != nil {
+		return nil, errs.Wrap(errs.CodeAuditLookupFailed, "error getting audit history", err)
+	}
+	return entries, nil
+}
+
+// auditActor resolves who made the change. API keys win over the user that owns them,
+// and internal requests are attributed to the service that originated them.
+func auditActor(ctx context.Context) (model.AuditActorType, string) {
+	if apiKey := utils.APIKeyFromContext(ctx); apiKey != nil {
+		return model.AuditActorAPIKey, apiKey.ID.String()
+	}
+	metadata := utils.RequestMetadataFromContext(ctx)
+	if !metadata.InternalRequest && metadata.UserID != nil {
+		return model.AuditActorUser, metadata.UserID.String()
+	}
+	for _, name := range []string{metadata.OriginService, metadata.CallerService, metadata.LocalService} {
+		if name != "" {
+			return model.AuditActorService, name
+		}
+	}
+	if metadata.UserID != nil {
+		return model.AuditActorUser, metadata.UserID.String()
+	}
+	return model.AuditActorService, unknownServiceActor
+}
+
+func marshalAuditSnapshot(snapshot any) (json.RawMessage, error) {
+	if snapshot == nil {
+		return nil, nil
+	}
+	return json.Marshal(snapshot)
+}
+
+// auditDiff compares two json objects key by key. A missing snapshot diffs as an empty object.
+func auditDiff(before, after json.RawMessage) ([]model.AuditChange, error) {
+	beforeFields := map[string]any{}
+	afterFields := map[string]any{}
+	if before != nil {
+		if err := json.Unmarshal(before, &beforeFields); err != nil {
+			return nil, err
+		}
+	}
+	if after != nil {
+		if err := json.Unmarshal(after, &afterFields); err != nil {
+			return nil, err
+		}
+	}
+
+	fields := make([]string, 0, len(beforeFields)+len(afterFields))
+	for field := range beforeFields {
+		fields = append(fields, field)
+	}
+	for field := range afterFields {
+		if _, ok := beforeFields[field]; !ok {
+			fields = append(fields, field)
+		}
+	}
+	sort.Strings(fields)
+
+	changes := make([]model.AuditChange, 0)
+	for _, field := range fields {
+		if reflect.DeepEqual(beforeFields[field], afterFields[field]) {
+			continue
+		}
+		changes = append(changes, model.AuditChange{
+			Field:  field,
+			Before: beforeFields[field],
+			After:  afterFields[field],
+		})
+	}
+	return changes, nil
+}
+
+// transactionAuditSnapshot holds the user facing fields of a transaction. Derived
+// values like names, balances and timestamps are left out so they don't show up as changes.
+type transactionAuditSnapshot struct {
+	Date                  model.Date              `json:"date"`
+	AccountID             *uuid.UUID              `json:"accountId"`
+	PayeeID               *uuid.UUID              `json:"payeeId"`
+	CategoryID            *uuid.UUID              `json:"categoryId"`
+	Note                  string                  `json:"note"`
+	Amount                float64                 `json:"amount"`
+	Status                model.TransactionStatus `json:"status"`
+	Cleared               model.ClearedStatus     `json:"cleared"`
+	TransferAccountID     *uuid.UUID              `json:"transferAccountId"`
+	TransferTransactionID *uuid.UUID              `json:"transferTransactionId"`
+	OriginalAmount        *float64                `json:"originalAmount"`
+	OriginalCurrency      *string                 `json:"originalCurrency"`
+	FxRate                *float64                `json:"fxRate"`
+	TagIDs                []uuid.UUID             `json:"tagIds"`
+	Splits                []splitAuditSnapshot    `json:"splits"`
+}
+
+type splitAuditSnapshot struct {
+	CategoryID *uuid.UUID `json:"categoryId"`
+	Amount     float64    `json:"amount"`
+	Note       string     `json:"note"`
+}
+
+// newTransactionAuditSnapshot returns nil for a nil transaction so the record has no snapshot
+func newTransactionAuditSnapshot(txn *model.Transaction) any {
+	if txn == nil {
+		return nil
+	}
+	snapshot := transactionAuditSnapshot{
+		Date:                  txn.Date,
+		AccountID:             txn.AccountID,
+		PayeeID:               txn.PayeeID,
+		CategoryID:            txn.CategoryID,
+		Note:                  txn.Note,
+		Amount:                txn.Amount,
+		Status:                txn.Status,
+		Cleared:               txn.Cleared,
+		TransferAccountID:     txn.TransferAccountID,
+		TransferTransactionID: txn.TransferTransactionID,
+		OriginalAmount:        txn.OriginalAmount,
+		OriginalCurrency:      txn.OriginalCurrency,
+		FxRate:                txn.FxRate,
+		TagIDs:                txn.TagIDs,
+		Splits:                make([]splitAuditSnapshot, 0, len(txn.Splits)),
+	}
+	if snapshot.TagIDs == nil {
+		snapshot.TagIDs = []uuid.UUID{}
+	}
+	for _, split := range txn.Splits {
+		snapshot.Splits = append(snapshot.Splits, splitAuditSnapshot{
+			CategoryID: split.CategoryID,
+			Amount:     split.Amount,
+			Note:       split.Note,
+		})
+	}
+	return snapshot
+}
+
+// recordTransactionRewrites audits transactions changed in bulk by a single SQL statement. before holds
+// them as loaded ahead of the statement, rewrite applies the same change to a copy of each.
+func recordTransactionRewrites(
+	ctx context.Context,
+	tx pgx.Tx,
+	auditService AuditService,
+	before []model.Transaction,
+	rewrite func(txn *model.Transaction),
+) error {
+	for i := range before {
+		after := before[i]
+		after.Splits = slices.Clone(before[i].Splits)
+		rewrite(&after)
+		if err := auditService.Record(ctx, tx, AuditRecord{
+			EntityType: model.AuditEntityTransaction,
+			EntityID:   after.ID,
+			Action:     model.AuditActionUpdate,
+			Before:     newTransactionAuditSnapshot(&before[i]),
+			After:      newTransactionAuditSnapshot(&after),
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+type monthlyBudgetAuditSnapshot struct {
+	CategoryID uuid.UUID `json:"categoryId"`
+	Month      string    `json:"month"`
+	Budgeted   float64   `json:"budgeted"`
+}
@@ -0,0 +1,295 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/Rishabh-Kapri/pennywise/backend/shared/model"
+	utils "github.com/Rishabh-Kapri/pennywise/backend/shared/utils"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type mockAuditLogRepo struct {
+	mockBaseRepo
+	mock.Mock
+}
+
+func (m *mockAuditLogRepo) Create(ctx context.Context, tx pgx.Tx, entry model.AuditLogEntry) error {
+	return m.Called(ctx, tx, entry).Error(0)
+}
+
+func (m *mockAuditLogRepo) GetByEntity(
+	ctx context.Context,
+	budgetId uuid.UUID,
+	entityType model.AuditEntityType,
+	entityId uuid.UUID,
+) ([]model.AuditLogEntry, error) {
+	args := m.Called(ctx, budgetId, entityType, entityId)
+	if v := args.Get(0); v != nil {
+		return v.([]model.AuditLogEntry), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+type mockAuditService struct{ mock.Mock }
+
+func (m *mockAuditService) Record(ctx context.Context, tx pgx.Tx, record AuditRecord) error {
+	return m.Called(ctx, tx, record).Error(0)
+}
+
+func (m *mockAuditService) GetHistory(
+	ctx context.Context,
+	entityType model.AuditEntityType,
+	entityId uuid.UUID,
+) ([]model.AuditLogEntry, error) {
+	args := m.Called(ctx, entityType, entityId)
+	if v := args.Get(0); v != nil {
+		return v.([]model.AuditLogEntry), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func TestAuditDiff(t *testing.T) {
+	before := json.RawMessage(`{"amount": -10, "note": "lunch", "tagIds": []}`)
+	after := json.RawMessage(`{"amount": -12.5, "note": "lunch", "tagIds": [], "cleared": "CLEARED"}`)
+
+	changes, err := auditDiff(before, after)
+	require.NoError(t, err)
+	assert.Equal(t, []model.AuditChange{
+		{Field: "amount", Before: -10.0, After: -12.5},
+		{Field: "cleared", Before: nil, After: "CLEARED"},
+	}, changes)
+
+	changes, err = auditDiff(nil, json.RawMessage(`{"note": "new"}`))
+	require.NoError(t, err)
+	assert.Equal(t, []model.AuditChange{{Field: "note", Before: nil, After: "new"}}, changes)
+
+	_, err = auditDiff(json.RawMessage(`not json`), nil)
+	assert.Error(t, err)
+}
+
+func TestAuditActor(t *testing.T) {
+	userId := uuid.New()
+	apiKeyId := uuid.New()
+
+	tests := []struct {
+		name      string
+		ctx       func() context.Context
+		wantType  model.AuditActorType
+		wantActor string
+	}{
+		{
+			name:      "user",
+			ctx:       func() context.Context { return utils.WithUserID(context.Background(), userId) },
+			wantType:  model.AuditActorUser,
+			wantActor: userId.String(),
+		},
+		{
+			name: "api_key_wins_over_user",
+			ctx: func() context.Context {
+				ctx := utils.WithUserID(context.Background(), userId)
+				return utils.WithAPIKey(ctx, &model.APIKey{ID: apiKeyId, UserID: userId})
+			},
+			wantType:  model.AuditActorAPIKey,
+			wantActor: apiKeyId.String(),
+		},
+		{
+			name: "internal_request_uses_origin_service",
+			ctx: func() context.Context {
+				ctx := utils.WithUserID(context.Background(), userId)
+				ctx = utils.WithInternalRequest(ctx, true)
+				ctx = utils.WithCallerService(ctx, "cipher")
+				return utils.WithOriginService(ctx, "agent")
+			},
+			wantType:  model.AuditActorService,
+			wantActor: "agent",
+		},
+		{
+			name:      "local_service",
+			ctx:       func() context.Context { return utils.WithLocalService(context.Background(), "temporal-worker") },
+			wantType:  model.AuditActorService,
+			wantActor: "temporal-worker",
+		},
+		{
+			name:      "unknown",
+			ctx:       context.Background,
+			wantType:  model.AuditActorService,
+			wantActor: unknownServiceActor,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actorType, actorId := auditActor(tt.ctx())
+			assert.Equal(t, tt.wantType, actorType)
+			assert.Equal(t, tt.wantActor, actorId)
+		})
+	}
+}
+
+func TestAuditService_Record(t *testing.T) {
+	budgetId := uuid.New()
+	userId := uuid.New()
+	txnId := uuid.New()
+	accountId := uuid.New()
+	ctx := utils.WithBudgetID(context.Background(), budgetId)
+	ctx = utils.WithUserID(ctx, userId)
+	ctx = utils.WithCorrelationID(ctx, "corr-1")
+	var mockTx pgx.Tx
+
+	before := &model.Transaction{ID: txnId, AccountID: &accountId, Date: "2024-01-01", Amount: -10, Note: "lunch"}
+
+	t.Run("records_update_diff", func(t *testing.T) {
+		repo := &mockAuditLogRepo{}
+		after := *before
+		after.Amount = -12
+		after.AccountName = strPtr("ignored")
+		repo.On("Create", ctx, mockTx, mock.MatchedBy(func(entry model.AuditLogEntry) bool {
+			return entry.BudgetID == budgetId &&
+				entry.EntityType == model.AuditEntityTransaction &&
+				entry.EntityID == txnId &&
+				entry.Action == model.AuditActionUpdate &&
+				entry.ActorType == model.AuditActorUser &&
+				entry.ActorID == userId.String() &&
+				*entry.CorrelationID == "corr-1" &&
+				len(entry.Changes) == 1 &&
+				entry.Changes[0].Field == "amount" &&
+				entry.Before != nil && entry.After != nil
+		})).Return(nil).Once()
+
+		err := NewAuditService(repo).Record(ctx, mockTx, AuditRecord{
+			EntityType: model.AuditEntityTransaction,
+			EntityID:   txnId,
+			Action:     model.AuditActionUpdate,
+			Before:     newTransactionAuditSnapshot(before),
+			After:      newTransactionAuditSnapshot(&after),
+		})
+		require.NoError(t, err)
+		repo.AssertExpectations(t)
+	})
+
+	t.Run("skips_unchanged_update", func(t *testing.T) {
+		repo := &mockAuditLogRepo{}
+		after := *before
+		after.TagIDs = []uuid.UUID{}
+		err := NewAuditService(repo).Record(ctx, mockTx, AuditRecord{
+			EntityType: model.AuditEntityTransaction,
+			EntityID:   txnId,
+			Action:     model.AuditActionUpdate,
+			Before:     newTransactionAuditSnapshot(before),
+			After:      newTransactionAuditSnapshot(&after),
+		})
+		require.NoError(t, err)
+		repo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("delete_has_no_after_snapshot", func(t *testing.T) {
+		repo := &mockAuditLogRepo{}
+		repo.On("Create", ctx, mockTx, mock.MatchedBy(func(entry model.AuditLogEntry) bool {
+			return entry.Action == model.AuditActionDelete && entry.After == nil && len(entry.Changes) > 0
+		})).Return(assert.AnError).Once()
+
+		err := NewAuditService(repo).Record(ctx, mockTx, AuditRecord{
+			EntityType: model.AuditEntityTransaction,
+			EntityID:   txnId,
+			Action:     model.AuditActionDelete,
+			Before:     newTransactionAuditSnapshot(before),
+			After:      newTransactionAuditSnapshot(nil),
+		})
+		assert.Error(t, err)
+		repo.AssertExpectations(t)
+	})
+}
+
+func TestAuditService_GetHistory(t *testing.T) {
+	budgetId := uuid.New()
+	txnId := uuid.New()
+	ctx := utils.WithBudgetID(context.Background(), budgetId)
+
+	repo := &mockAuditLogRepo{}
+	repo.On("GetByEntity", ctx, budgetId, model.AuditEntityTransaction, txnId).
+		Return([]model.AuditLogEntry{{EntityID: txnId, Action: model.AuditActionCreate}}, nil).Once()
+	repo.On("GetByEntity", ctx, budgetId, model.AuditEntityTransaction, txnId).Return(nil, assert.AnError).Once()
+
+	history, err := NewAuditService(repo).GetHistory(ctx, model.AuditEntityTransaction, txnId)
+	require.NoError(t, err)
+	assert.Len(t, history, 1)
+
+	_, err = NewAuditService(repo).GetHistory(ctx, model.AuditEntityTransaction, txnId)
+	assert.Error(t, err)
+}
+
+func TestTransactionService_AuditsMutations(t *testing.T) {
+	var mockTx pgx.Tx
+	mockWithTxSuccess(mockTx)
+	defer func() { withTx = utils.WithTx }()
+
+	budgetId := uuid.New()
+	ctx := utils.WithBudgetID(context.Background(), budgetId)
+	txnId := uuid.New()
+
+	t.Run("delete", func(t *testing.T) {
+		mockRepo := &mockTransactionRepo{}
+		mockBudget := &mockBudgetRepo{}
+		audit := &mockAuditService{}
+		service := newTestTransactionService(mockRepo, mockBudget, nil, nil, nil, nil, nil)
+		service.auditService = audit
+
+		foundTxn := model.Transaction{ID: txnId, Amount: -20, Cleared: model.ClearedStatusCleared}
+		mockRepo.On("GetByIdTx", mock.Anything, mockTx, budgetId, txnId).Return(&foundTxn, nil).Once()
+		mockBudget.On("GetById", mock.Anything, mockTx, budgetId).Return(&model.Budget{}, nil).Once()
+		mockRepo.On("DeleteById", mock.Anything, mockTx, budgetId, txnId).Return(nil).Once()
+		audit.On("Record", mock.Anything, mockTx, mock.MatchedBy(func(record AuditRecord) bool {
+			return record.EntityID == txnId &&
+				record.Action == model.AuditActionDelete &&
+				record.Before != nil &&
+				record.After == nil
+		})).Return(nil).Once()
+
+		require.NoError(t, service.DeleteById(ctx, txnId))
+		audit.AssertExpectations(t)
+	})
+
+	t.Run("status_update", func(t *testing.T) {
+		mockRepo := &mockTransactionRepo{}
+		audit := &mockAuditService{}
+		service := newTestTransactionService(mockRepo, nil, nil, nil, nil, nil, nil)
+		service.auditService = audit
+
+		foundTxn := model.Transaction{ID: txnId, Status: model.TransactionStatusUnapproved}
+		mockRepo.On("GetByIdTx", mock.Anything, mockTx, budgetId, txnId).Return(&foundTxn, nil).Once()
+		mockRepo.On("UpdateStatus", mock.Anything, mockTx, budgetId, txnId, model.TransactionStatusRejected).
+			Return(nil).Once()
+		audit.On("Record", mock.Anything, mockTx, mock.MatchedBy(func(record AuditRecord) bool {
+			before := record.Before.(transactionAuditSnapshot)
+			after := record.After.(transactionAuditSnapshot)
+			return record.Action == model.AuditActionUpdate &&
+				before.Status == model.TransactionStatusUnapproved &&
+				after.Status == model.TransactionStatusRejected
+		})).Return(nil).Once()
+
+		require.NoError(t, service.UpdateStatus(ctx, txnId, model.TransactionStatusRejected))
+		audit.AssertExpectations(t)
+	})
+
+	t.Run("audit_failure_aborts_mutation", func(t *testing.T) {
+		mockRepo := &mockTransactionRepo{}
+		audit := &mockAuditService{}
+		service := newTestTransactionService(mockRepo, nil, nil, nil, nil, nil, nil)
+		service.auditService = audit
+
+		foundTxn := model.Transaction{ID: txnId, Status: model.TransactionStatusUnapproved}
+		mockRepo.On("GetByIdTx", mock.Anything, mockTx, budgetId, txnId).Return(&foundTxn, nil).Once()
+		mockRepo.On("UpdateStatus", mock.Anything, mockTx, budgetId, txnId, model.TransactionStatusRejected).
+			Return(nil).Once()
+		audit.On("Record", mock.Anything, mockTx, mock.Anything).Return(assert.AnError).Once()
+
+		assert.Error(t, service.UpdateStatus(ctx, txnId, model.TransactionStatusRejected))
+	})
+}
@@ -0,0 +1,187 @@
+package service
+
+import (
+	"context"
+	"math"
+	"sort"
+	"time"
+
+	errs "github.com/Rishabh-Kapri/pennywise/backend/shared/errors"
+	"github.com/Rishabh-Kapri/pennywise/backend/shared/logger"
+	"github.com/Rishabh-Kapri/pennywise/backend/shared/model"
+	utils "github.com/Rishabh-Kapri/pennywise/backend/shared/utils"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+const (
+	monthKeyLayout = "2006-01"
+	// maxAutoAssignMonths caps how far back AVERAGE_SPENT looks
+	maxAutoAssignMonths = 24
+)
+
+// previousMonthKey returns the month key n months before month
+func previousMonthKey(month string, n int) string {
+	parsed, _ := time.Parse(monthKeyLayout, month)
+	return parsed.AddDate(0, -n, 0).Format(monthKeyLayout)
+}
+
+// balanceBefore returns the category balance carried into month, what is available in it before its
+// own budgeted and activity
+func balanceBefore(category model.Category, month string) float64 {
+	return float64(category.AvailableIn(month) - category.Budgeted[month] - category.Activity[month])
+}
+
+// proposeBudgeted returns the amount the strategy would budget for the category in month
+func proposeBudgeted(category model.Category, month string, req model.AutoAssignRequest) float64 {
+	budgeted := float64(category.Budgeted[month])
+	switch req.Strategy {
+	case model.AutoAssignBudgetedLastMonth:
+		return float64(category.Budgeted[previousMonthKey(month, 1)])
+	case model.AutoAssignAverageSpent:
+		spent := 0.0
+		for i := 1; i <= req.Months; i++ {
+			spent -= float64(category.Activity[previousMonthKey(month, i)])
+		}
+		// categories that mostly received money have nothing to fund
+		return math.Max(math.Round(spent/float64(req.Months)*100)/100, 0)
+	case model.AutoAssignUnderfundedGoals:
+		if category.Goal == nil {
+			return budgeted
+		}
+		underfunded, _ := category.Goal.Evaluate(month, budgeted, balanceBefore(category, month)+budgeted)
+		return budgeted + underfunded
+	default:
+		return 0
+	}
+}
+
+func validateAutoAssignRequest(month string, req *model.AutoAssignRequest) error {
+	if _, err := time.Parse(monthKeyLayout, month); err != nil {
+		return errs.New(errs.CodeInvalidArgument, "month must be formatted as YYYY-MM")
+	}
+	switch req.Strategy {
+	case model.AutoAssignAverageSpent:
+		if req.Months == 0 {
+			req.Months = model.DefaultAutoAssignMonths
+		}
+		if req.Months < 1 || req.Months > maxAutoAssignMonths {
+			return errs.New(errs.CodeInvalidArgument, "months must be between 1 and %d", maxAutoAssignMonths)
+		}
+	case model.AutoAssignBudgetedLastMonth, model.AutoAssignUnderfundedGoals, model.AutoAssignReset:
+	default:
+		return errs.New(errs.CodeInvalidArgument, "unsupported auto-assign strategy %q", req.Strategy)
+	}
+	return nil
+}
+
+func (s *categoryService) AutoAssign(
+	ctx context.Context,
+	month string,
+	req model.AutoAssignRequest,
+) (*model.AutoAssignResult, error) {
+	budgetId := utils.MustBudgetID(ctx)
+	if err := validateAutoAssignRequest(month, &req); err != nil {
+		return nil, err
+	}
+
+	var result *model.AutoAssignResult
+	err := withTx(ctx, s.monthlyBudgetRepo.GetDB(), func(tx pgx.Tx) error {
+		// applying holds the budget lock from reading Ready to Assign until the amounts are saved, so two
+		// assignments can't both spend the same money
+		if !req.Preview {
+			if err := s.monthlyBudgetRepo.LockBudget(ctx, tx, budgetId); err != nil {
+				return errs.Wrap(errs.CodeMonthlyBudgetUpdateFailed, "error locking budget", err)
+			}
+		}
+		categories, err := s.repo.GetAll(ctx, budgetId)
+		if err != nil {
+			return errs.Wrap(errs.CodeCategoryLookupFailed, "error getting categories", err)
+		}
+		readyToAssign, err := s.repo.GetReadyToAssign(ctx, tx, budgetId, month, categories)
+		if err != nil {
+			return errs.Wrap(errs.CodeCategoryLookupFailed, "error getting ready to assign balance", err)
+		}
+
+		var assigned float64
+		result, assigned = proposeAutoAssign(categories, month, req, readyToAssign)
+		if req.Preview || len(result.Proposals) == 0 {
+			return nil
+		}
+		// moving money back to ready to assign is always allowed, assigning more than is there isn't
+		if assigned > 0 && result.ReadyToAssignAfter < 0 {
+			return errs.New(
+				errs.CodeMonthlyBudgetOverAssigned,
+				"assigning %.2f is more than the %.2f ready to assign", assigned, readyToAssign,
+			)
+		}
+
+		logger.Logger(ctx).Info(
+			"auto-assigning monthly budget",
+			"month", month, "strategy", req.Strategy, "count", len(result.Proposals),
+		)
+		for _, proposal := range result.Proposals {
+			if err := s.setBudgetedWithTx(ctx, tx, budgetId, proposal.CategoryID, proposal.Proposed, month); err != nil {
+				return errs.Wrap(errs.CodeMonthlyBudgetUpdateFailed, "error updating budgeted for "+proposal.CategoryName, err)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	result.Applied = !req.Preview
+	return result, nil
+}
+
+// proposeAutoAssign runs the strategy over the categories and returns the proposals with the total
+// they add to what is budgeted
+func proposeAutoAssign(
+	categories []model.Category,
+	month string,
+	req model.AutoAssignRequest,
+	readyToAssign float64,
+) (*model.AutoAssignResult, float64) {
+	selected := make(map[uuid.UUID]bool, len(req.CategoryIDs))
+	for _, id := range req.CategoryIDs {
+		selected[id] = true
+	}
+	result := &model.AutoAssignResult{
+		Month:         month,
+		Strategy:      req.Strategy,
+		Proposals:     []model.AutoAssignProposal{},
+		ReadyToAssign: readyToAssign,
+	}
+	assigned := 0.0
+	for _, category := range categories {
+		if category.IsSystem {
+			continue
+		}
+		// hidden categories are only touched when asked for explicitly
+		if len(selected) > 0 {
+			if !selected[category.ID] {
+				continue
+			}
+		} else if category.Hidden {
+			continue
+		}
+		current := float64(category.Budgeted[month])
+		proposed := proposeBudgeted(category, month, req)
+		if math.Round(proposed*100) == math.Round(current*100) {
+			continue
+		}
+		assigned += proposed - current
+		result.Proposals = append(result.Proposals, model.AutoAssignProposal{
+			CategoryID:   category.ID,
+			CategoryName: category.Name,
+			Current:      current,
+			Proposed:     proposed,
+		})
+	}
+	sort.Slice(result.Proposals, func(i, j int) bool {
+		return result.Proposals[i].CategoryName < result.Proposals[j].CategoryName
+	})
+	result.ReadyToAssignAfter = math.Round((readyToAssign-assigned)*100) / 100
+	return result, assigned
+}
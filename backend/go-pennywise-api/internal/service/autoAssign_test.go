@@ -0,0 +1,146 @@
+package service
+
+import (
+	"testing"
+
+	errs "github.com/Rishabh-Kapri/pennywise/backend/shared/errors"
+	"github.com/Rishabh-Kapri/pennywise/backend/shared/model"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProposeBudgeted(t *testing.T) {
+	targetMonth := "2025-06"
+	category := model.Category{
+		Budgeted: map[string]float32{"2025-02": 120, "2025-03": 50},
+		Activity: map[string]float32{"2025-01": -90, "2025-02": -150, "2025-03": 20},
+		Balance:  map[string]float32{"2025-02": 100, "2025-03": 170},
+		Goal:     &model.CategoryGoal{Type: model.CategoryGoalTargetBalance, Amount: 470, TargetMonth: &targetMonth},
+	}
+
+	tests := []struct {
+		name     string
+		req      model.AutoAssignRequest
+		proposed float64
+	}{
+		{"budgeted_last_month", model.AutoAssignRequest{Strategy: model.AutoAssignBudgetedLastMonth}, 50},
+		{"average_spent", model.AutoAssignRequest{Strategy: model.AutoAssignAverageSpent, Months: 3}, 73.33},
+		{"average_spent_ignores_inflows", model.AutoAssignRequest{Strategy: model.AutoAssignAverageSpent, Months: 1}, 0},
+		// 300 missing after the 170 carried over, spread over April to June
+		{"underfunded_goals", model.AutoAssignRequest{Strategy: model.AutoAssignUnderfundedGoals}, 100},
+		{"reset", model.AutoAssignRequest{Strategy: model.AutoAssignReset}, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.proposed, proposeBudgeted(category, "2025-04", tt.req))
+		})
+	}
+
+	t.Run("overspending_is_not_carried", func(t *testing.T) {
+		overspent := category
+		overspent.Balance = map[string]float32{"2025-02": 100, "2025-03": -30}
+		// April starts at zero, 470 missing over April to June
+		req := model.AutoAssignRequest{Strategy: model.AutoAssignUnderfundedGoals}
+		assert.Equal(t, 156.67, proposeBudgeted(overspent, "2025-04", req))
+
+		overspent.RolloverNegative = true
+		assert.Equal(t, 166.67, proposeBudgeted(overspent, "2025-04", req))
+	})
+}
+
+func TestCategoryService_AutoAssign(t *testing.T) {
+	useInlineTx(t)
+
+	budgetID := uuid.New()
+	ctx := budgetCtxWith(budgetID)
+	groceries := model.Category{
+		ID:       uuid.New(),
+		Name:     "Groceries",
+		Budgeted: map[string]float32{"2025-03": 400, "2025-04": 100},
+	}
+	rent := model.Category{ID: uuid.New(), Name: "Rent", Budgeted: map[string]float32{"2025-03": 1000}}
+	hidden := model.Category{ID: uuid.New(), Name: "Old", Hidden: true, Budgeted: map[string]float32{"2025-03": 10}}
+	inflow := model.Category{ID: uuid.New(), Name: "Inflow", IsSystem: true}
+	categories := []model.Category{rent, groceries, hidden, inflow}
+	req := model.AutoAssignRequest{Strategy: model.AutoAssignBudgetedLastMonth}
+
+	t.Run("preview", func(t *testing.T) {
+		repo := &svcCategoryRepo{}
+		mbRepo := &svcMonthlyBudgetRepo{}
+		repo.On("GetAll", ctx, budgetID).Return(categories, nil).Once()
+		repo.On("GetReadyToAssign", ctx, nil, budgetID, "2025-04", categories).Return(2000.0, nil).Once()
+
+		preview := req
+		preview.Preview = true
+		result, err := NewCategoryService(repo, mbRepo, nil, nil).AutoAssign(ctx, "2025-04", preview)
+		require.NoError(t, err)
+		assert.False(t, result.Applied)
+		assert.Equal(t, []model.AutoAssignProposal{
+			{CategoryID: groceries.ID, CategoryName: "Groceries", Current: 100, Proposed: 400},
+			{CategoryID: rent.ID, CategoryName: "Rent", Current: 0, Proposed: 1000},
+		}, result.Proposals)
+		assert.Equal(t, 700.0, result.ReadyToAssignAfter)
+		mbRepo.AssertNotCalled(t, "LockBudget", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("applies_atomically", func(t *testing.T) {
+		repo := &svcCategoryRepo{}
+		mbRepo := &svcMonthlyBudgetRepo{}
+		mbRepo.On("LockBudget", ctx, nil, budgetID).Return(nil).Once()
+		repo.On("GetAll", ctx, budgetID).Return(categories, nil).Once()
+		repo.On("GetReadyToAssign", ctx, nil, budgetID, "2025-04", categories).Return(2000.0, nil).Once()
+		mbRepo.On("GetByCatIdAndMonth", ctx, nil, budgetID, groceries.ID, "2025-04").
+			Return(&model.MonthlyBudget{Budgeted: 100}, nil).Once()
+		mbRepo.On("UpdateBudgetedByCatIdAndMonth", ctx, nil, budgetID, groceries.ID, "2025-04", 400.0).Return(nil).Once()
+		mbRepo.On("GetByCatIdAndMonth", ctx, nil, budgetID, rent.ID, "2025-04").Return(nil, pgx.ErrNoRows).Once()
+		mbRepo.On("Create", ctx, nil, budgetID, mock.MatchedBy(func(mb model.MonthlyBudget) bool {
+			return mb.CategoryID == rent.ID && mb.Budgeted == 1000
+		})).Return(nil).Once()
+
+		result, err := NewCategoryService(repo, mbRepo, nil, nil).AutoAssign(ctx, "2025-04", req)
+		require.NoError(t, err)
+		assert.True(t, result.Applied)
+		mbRepo.AssertExpectations(t)
+	})
+
+	t.Run("over_assigning_is_rejected", func(t *testing.T) {
+		repo := &svcCategoryRepo{}
+		mbRepo := &svcMonthlyBudgetRepo{}
+		mbRepo.On("LockBudget", ctx, nil, budgetID).Return(nil).Once()
+		repo.On("GetAll", ctx, budgetID).Return(categories, nil).Once()
+		repo.On("GetReadyToAssign", ctx, nil, budgetID, "2025-04", categories).Return(500.0, nil).Once()
+
+		_, err := NewCategoryService(repo, mbRepo, nil, nil).AutoAssign(ctx, "2025-04", req)
+		assert.True(t, hasErrorCode(err, errs.CodeMonthlyBudgetOverAssigned), err)
+		mbRepo.AssertNotCalled(t, "GetByCatIdAndMonth", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("selected_hidden_category", func(t *testing.T) {
+		repo := &svcCategoryRepo{}
+		repo.On("GetAll", ctx, budgetID).Return(categories, nil).Once()
+		repo.On("GetReadyToAssign", ctx, nil, budgetID, "2025-04", categories).Return(0.0, nil).Once()
+
+		result, err := NewCategoryService(repo, &svcMonthlyBudgetRepo{}, nil, nil).AutoAssign(ctx, "2025-04", model.AutoAssignRequest{
+			Strategy:    model.AutoAssignBudgetedLastMonth,
+			CategoryIDs: []uuid.UUID{hidden.ID},
+			Preview:     true,
+		})
+		require.NoError(t, err)
+		require.Len(t, result.Proposals, 1)
+		assert.Equal(t, hidden.ID, result.Proposals[0].CategoryID)
+	})
+
+	t.Run("invalid_requests", func(t *testing.T) {
+		service := NewCategoryService(&svcCategoryRepo{}, nil, nil, nil)
+		_, err := service.AutoAssign(ctx, "April", req)
+		assert.True(t, hasErrorCode(err, errs.CodeInvalidArgument), err)
+		_, err = service.AutoAssign(ctx, "2025-04", model.AutoAssignRequest{Strategy: "EVERYTHING"})
+		assert.True(t, hasErrorCode(err, errs.CodeInvalidArgument), err)
+		_, err = service.AutoAssign(ctx, "2025-04", model.AutoAssignRequest{Strategy: model.AutoAssignAverageSpent, Months: 36})
+		assert.True(t, hasErrorCode(err, errs.CodeInvalidArgument), err)
+	})
+}
@@ -46,6 +46,13 @@ func (s *budgetService) Create(ctx context.Context, input model.CreateBudgetRequ
 	if name == "" {
 		return nil, fmt.Errorf("budget name is required")
 	}
+	currency := model.DefaultCurrency
+	if input.Currency != "" {
+		var err error
+		if currency, err = model.NormalizeCurrency(input.Currency); err != nil {
+			return nil, err
+		}
+	}
 	existingBudgets, err := s.repo.GetAll(ctx, userID)
 	if err != nil {
 		return nil, fmt.Errorf("budgetService.Create; error checking existing budgets: %v", err)
@@ -143,6 +150,7 @@ func (s *budgetService) Create(ctx context.Context, input model.CreateBudgetRequ
 		updatedBudget := model.Budget{
 			Name:       budget.Name,
 			IsSelected: isFirstBudget,
+			Currency:   currency,
 			Metadata: model.BudgetMetadata{
 				InflowCategoryID:   createdCat.ID,
 				CCGroupID:          createdCCGroup.ID,
@@ -155,6 +163,7 @@ func (s *budgetService) Create(ctx context.Context, input model.CreateBudgetRequ
 		}
 		createdBudget.IsSelected = updatedBudget.IsSelected
 		createdBudget.Metadata = updatedBudget.Metadata
+		createdBudget.Currency = updatedBudget.Currency
 		return nil
 	})
 	if err != nil {
@@ -164,5 +173,12 @@ func (s *budgetService) Create(ctx context.Context, input model.CreateBudgetRequ
 }
 
 func (s *budgetService) UpdateById(ctx context.Context, id uuid.UUID, budget model.Budget) error {
+	if budget.Currency != "" {
+		currency, err := model.NormalizeCurrency(budget.Currency)
+		if err != nil {
+			return err
+		}
+		budget.Currency = currency
+	}
 	return s.repo.UpdateById(ctx, nil, id, budget)
 }
@@ -0,0 +1,220 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"math"
+	"strings"
+	"time"
+
+	repository "github.com/Rishabh-Kapri/pennywise/backend/shared/db"
+	errs "github.com/Rishabh-Kapri/pennywise/backend/shared/errors"
+	"github.com/Rishabh-Kapri/pennywise/backend/shared/logger"
+	"github.com/Rishabh-Kapri/pennywise/backend/shared/model"
+	utils "github.com/Rishabh-Kapri/pennywise/backend/shared/utils"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+// budgetMovedEvent is sent to the budget's websocket sessions after money is moved
+const budgetMovedEvent = "pennywise::monthly-budget::moved"
+
+type BudgetMoveService interface {
+	// GetAll lists the moves of the budget, of a single month when month is set
+	GetAll(ctx context.Context, month string) ([]model.BudgetMove, error)
+	// Move moves budgeted money between two categories, or to and from Ready to Assign, in a single
+	// db transaction and records the move
+	Move(ctx context.Context, req model.MoveMoneyRequest) (*model.BudgetMove, error)
+}
+
+type budgetMoveService struct {
+	repo              repository.BudgetMoveRepository
+	categoryRepo      repository.CategoryRepository
+	monthlyBudgetRepo repository.MonthlyBudgetRepository
+	categoryService   CategoryService
+	websocketService  WebsocketService
+}
+
+func NewBudgetMoveService(
+	r repository.BudgetMoveRepository,
+	categoryRepo repository.CategoryRepository,
+	mbR repository.MonthlyBudgetRepository,
+	categoryService CategoryService,
+	websocketService WebsocketService,
+) BudgetMoveService {
+	return &budgetMoveService{
+		repo:              r,
+		categoryRepo:      categoryRepo,
+		monthlyBudgetRepo: mbR,
+		categoryService:   categoryService,
+		websocketService:  websocketService,
+	}
+}
+
+func (s *budgetMoveService) GetAll(ctx context.Context, month string) ([]model.BudgetMove, error) {
+	budgetId := utils.MustBudgetID(ctx)
+	if month != "" {
+		if _, err := time.Parse(monthKeyLayout, month); err != nil {
+			return nil, errs.New(errs.CodeInvalidArgument, "month must be formatted as YYYY-MM")
+		}
+	}
+	return s.repo.GetAll(ctx, budgetId, month)
+}
+
+func validateMoveMoneyRequest(req *model.MoveMoneyRequest) error {
+	if _, err := time.Parse(monthKeyLayout, req.Month); err != nil {
+		return errs.New(errs.CodeInvalidArgument, "month must be formatted as YYYY-MM")
+	}
+	req.Amount = math.Round(req.Amount*100) / 100
+	if req.Amount <= 0 {
+		return errs.New(errs.CodeInvalidArgument, "amount must be greater than zero")
+	}
+	if req.FromCategoryID == nil && req.ToCategoryID == nil {
+		return errs.New(errs.CodeInvalidArgument, "fromCategoryId or toCategoryId is required")
+	}
+	if req.FromCategoryID != nil && req.ToCategoryID != nil && *req.FromCategoryID == *req.ToCategoryID {
+		return errs.New(errs.CodeInvalidArgument, "can't move money to the same category")
+	}
+	req.Note = strings.TrimSpace(req.Note)
+	return nil
+}
+
+// checkMoveCategory makes sure money can be moved to and from the category, nil is Ready to Assign
+func (s *budgetMoveService) checkMoveCategory(ctx context.Context, budgetId uuid.UUID, id *uuid.UUID) error {
+	if id == nil {
+		return nil
+	}
+	category, err := s.categoryRepo.GetById(ctx, budgetId, *id)
+	if err != nil {
+		return categoryLookupError(err)
+	}
+	// the inflow category is Ready to Assign, moves to it use a nil category. Credit card payment
+	// categories are system categories too, but money can be moved in to cover card debt.
+	if category.IsSystem && !category.IsPaymentCategory() {
+		return errs.New(errs.CodeInvalidArgument, "can't move money to or from a system category")
+	}
+	return nil
+}
+
+// budgetedWithTx returns what is budgeted for the category in month, zero when there is no monthly budget yet
+func (s *budgetMoveService) budgetedWithTx(
+	ctx context.Context,
+	tx pgx.Tx,
+	budgetId uuid.UUID,
+	categoryId uuid.UUID,
+	month string,
+) (float64, error) {
+	monthlyBudget, err := s.monthlyBudgetRepo.GetByCatIdAndMonth(ctx, tx, budgetId, categoryId, month)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return 0, nil
+		}
+		return 0, errs.Wrap(errs.CodeMonthlyBudgetLookupFailed, "error getting monthly budget", err)
+	}
+	return monthlyBudget.Budgeted, nil
+}
+
+// checkReadyToAssignWithTx makes sure Ready to Assign of the month covers the move. The budget stays
+// locked until tx ends, so two moves can't both spend the same money.
+func (s *budgetMoveService) checkReadyToAssignWithTx(
+	ctx context.Context,
+	tx pgx.Tx,
+	budgetId uuid.UUID,
+	req model.MoveMoneyRequest,
+) error {
+	if err := s.monthlyBudgetRepo.LockBudget(ctx, tx, budgetId); err != nil {
+		return errs.Wrap(errs.CodeMonthlyBudgetMoveFailed, "error locking budget", err)
+	}
+	categories, err := s.categoryRepo.GetAll(ctx, budgetId)
+	if err != nil {
+		return errs.Wrap(errs.CodeCategoryLookupFailed, "error getting categories", err)
+	}
+	readyToAssign, err := s.categoryRepo.GetReadyToAssign(ctx, tx, budgetId, req.Month, categories)
+	if err != nil {
+		return errs.Wrap(errs.CodeCategoryLookupFailed, "error getting ready to assign balance", err)
+	}
+	if math.Round(readyToAssign*100) < math.Round(req.Amount*100) {
+		return errs.New(
+			errs.CodeMonthlyBudgetOverAssigned,
+			"moving %.2f is more than the %.2f ready to assign", req.Amount, readyToAssign,
+		)
+	}
+	return nil
+}
+
+func (s *budgetMoveService) Move(ctx context.Context, req model.MoveMoneyRequest) (*model.BudgetMove, error) {
+	budgetId := utils.MustBudgetID(ctx)
+	if err := validateMoveMoneyRequest(&req); err != nil {
+		return nil, err
+	}
+	if err := s.checkMoveCategory(ctx, budgetId, req.FromCategoryID); err != nil {
+		return nil, err
+	}
+	if err := s.checkMoveCategory(ctx, budgetId, req.ToCategoryID); err != nil {
+		return nil, err
+	}
+
+	logger.Logger(ctx).Info(
+		"moving budgeted money",
+		"month", req.Month, "from", req.FromCategoryID, "to", req.ToCategoryID, "amount", req.Amount,
+	)
+	var move *model.BudgetMove
+	err := withTx(ctx, s.monthlyBudgetRepo.GetDB(), func(tx pgx.Tx) error {
+		if req.FromCategoryID == nil {
+			if err := s.checkReadyToAssignWithTx(ctx, tx, budgetId, req); err != nil {
+				return err
+			}
+		}
+		adjust := func(categoryId *uuid.UUID, delta float64) error {
+			if categoryId == nil {
+				return nil
+			}
+			budgeted, err := s.budgetedWithTx(ctx, tx, budgetId, *categoryId, req.Month)
+			if err != nil {
+				return err
+			}
+			newBudgeted := math.Round((budgeted+delta)*100) / 100
+			if err := s.categoryService.SetBudgetedWithTx(ctx, tx, *categoryId, newBudgeted, req.Month); err != nil {
+				return errs.Wrap(errs.CodeMonthlyBudgetUpdateFailed, "error updating budgeted", err)
+			}
+			return nil
+		}
+		if err := adjust(req.FromCategoryID, -req.Amount); err != nil {
+			return err
+		}
+		if err := adjust(req.ToCategoryID, req.Amount); err != nil {
+			return err
+		}
+
+		var err error
+		move, err = s.repo.Create(ctx, tx, model.BudgetMove{
+			BudgetID:       budgetId,
+			Month:          req.Month,
+			FromCategoryID: req.FromCategoryID,
+			ToCategoryID:   req.ToCategoryID,
+			Amount:         req.Amount,
+			Note:           req.Note,
+		})
+		if err != nil {
+			return errs.Wrap(errs.CodeMonthlyBudgetMoveFailed, "error recording budget move", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.sendMovedNotification(ctx, budgetId, move)
+	return move, nil
+}
+
+// sendMovedNotification lets the budget's other sessions know to refresh, the move is saved either way
+func (s *budgetMoveService) sendMovedNotification(ctx context.Context, budgetId uuid.UUID, move *model.BudgetMove) {
+	if s.websocketService == nil {
+		return
+	}
+	if err := s.websocketService.SendNotification(ctx, budgetId, budgetMovedEvent, move); err != nil {
+		logger.Logger(ctx).Warn("failed to send budget moved websocket notification", "error", err)
+	}
+}
@@ -0,0 +1,177 @@
+package service
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	errs "github.com/Rishabh-Kapri/pennywise/backend/shared/errors"
+	"github.com/Rishabh-Kapri/pennywise/backend/shared/model"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type svcBudgetMoveRepo struct {
+	mockBaseRepo
+	mock.Mock
+}
+
+func (m *svcBudgetMoveRepo) GetAll(ctx context.Context, budgetId uuid.UUID, month string) ([]model.BudgetMove, error) {
+	args := m.Called(ctx, budgetId, month)
+	if v := args.Get(0); v != nil {
+		return v.([]model.BudgetMove), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+func (m *svcBudgetMoveRepo) Create(ctx context.Context, tx pgx.Tx, move model.BudgetMove) (*model.BudgetMove, error) {
+	args := m.Called(ctx, tx, move)
+	if v := args.Get(0); v != nil {
+		return v.(*model.BudgetMove), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+type svcWebsocketService struct{ mock.Mock }
+
+func (m *svcWebsocketService) Connect(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	return m.Called(ctx, w, r).Error(0)
+}
+func (m *svcWebsocketService) SendNotification(ctx context.Context, budgetId uuid.UUID, eventName string, data any) error {
+	return m.Called(ctx, budgetId, eventName, data).Error(0)
+}
+func (m *svcWebsocketService) GetSessions(ctx context.Context) WebsocketSessionsResponse {
+	return m.Called(ctx).Get(0).(WebsocketSessionsResponse)
+}
+func (m *svcWebsocketService) SendTestEvent(ctx context.Context, eventName string, data any, roomID *string) error {
+	return m.Called(ctx, eventName, data, roomID).Error(0)
+}
+
+func TestBudgetMoveService_Move(t *testing.T) {
+	useInlineTx(t)
+
+	budgetID := uuid.New()
+	ctx := budgetCtxWith(budgetID)
+	groceries := &model.Category{ID: uuid.New(), Name: "Groceries"}
+	dining := &model.Category{ID: uuid.New(), Name: "Dining"}
+	inflow := &model.Category{ID: uuid.New(), Name: "Inflow", IsSystem: true}
+
+	newService := func(
+		repo *svcBudgetMoveRepo,
+		catRepo *svcCategoryRepo,
+		mbRepo *svcMonthlyBudgetRepo,
+		ws *svcWebsocketService,
+	) BudgetMoveService {
+		return NewBudgetMoveService(repo, catRepo, mbRepo, NewCategoryService(catRepo, mbRepo, nil, nil), ws)
+	}
+
+	t.Run("between_categories", func(t *testing.T) {
+		repo, catRepo, mbRepo, ws := &svcBudgetMoveRepo{}, &svcCategoryRepo{}, &svcMonthlyBudgetRepo{}, &svcWebsocketService{}
+		catRepo.On("GetById", ctx, budgetID, groceries.ID).Return(groceries, nil)
+		catRepo.On("GetById", ctx, budgetID, dining.ID).Return(dining, nil)
+		// read once for the move and once more when setting budgeted
+		mbRepo.On("GetByCatIdAndMonth", ctx, nil, budgetID, groceries.ID, "2025-04").
+			Return(&model.MonthlyBudget{Budgeted: 300}, nil).Twice()
+		mbRepo.On("UpdateBudgetedByCatIdAndMonth", ctx, nil, budgetID, groceries.ID, "2025-04", 250.0).Return(nil).Once()
+		mbRepo.On("GetByCatIdAndMonth", ctx, nil, budgetID, dining.ID, "2025-04").Return(nil, pgx.ErrNoRows).Twice()
+		mbRepo.On("Create", ctx, nil, budgetID, mock.MatchedBy(func(mb model.MonthlyBudget) bool {
+			return mb.CategoryID == dining.ID && mb.Budgeted == 50
+		})).Return(nil).Once()
+		saved := &model.BudgetMove{ID: uuid.New(), Amount: 50}
+		repo.On("Create", ctx, nil, model.BudgetMove{
+			BudgetID:       budgetID,
+			Month:          "2025-04",
+			FromCategoryID: &groceries.ID,
+			ToCategoryID:   &dining.ID,
+			Amount:         50,
+			Note:           "date night",
+		}).Return(saved, nil).Once()
+		ws.On("SendNotification", ctx, budgetID, budgetMovedEvent, saved).Return(nil).Once()
+
+		move, err := newService(repo, catRepo, mbRepo, ws).Move(ctx, model.MoveMoneyRequest{
+			Month:          "2025-04",
+			FromCategoryID: &groceries.ID,
+			ToCategoryID:   &dining.ID,
+			Amount:         50,
+			Note:           " date night ",
+		})
+		require.NoError(t, err)
+		assert.Equal(t, saved, move)
+		mbRepo.AssertExpectations(t)
+		repo.AssertExpectations(t)
+		ws.AssertExpectations(t)
+	})
+
+	t.Run("from_ready_to_assign", func(t *testing.T) {
+		repo, catRepo, mbRepo, ws := &svcBudgetMoveRepo{}, &svcCategoryRepo{}, &svcMonthlyBudgetRepo{}, &svcWebsocketService{}
+		catRepo.On("GetById", ctx, budgetID, dining.ID).Return(dining, nil)
+		mbRepo.On("LockBudget", ctx, nil, budgetID).Return(nil).Once()
+		catRepo.On("GetAll", ctx, budgetID).Return([]model.Category{*dining}, nil).Once()
+		catRepo.On("GetReadyToAssign", ctx, nil, budgetID, "2025-04", []model.Category{*dining}).Return(100.0, nil).Once()
+		mbRepo.On("GetByCatIdAndMonth", ctx, nil, budgetID, dining.ID, "2025-04").
+			Return(&model.MonthlyBudget{Budgeted: 20}, nil).Twice()
+		mbRepo.On("UpdateBudgetedByCatIdAndMonth", ctx, nil, budgetID, dining.ID, "2025-04", 120.0).Return(nil).Once()
+		saved := &model.BudgetMove{ID: uuid.New()}
+		repo.On("Create", ctx, nil, mock.Anything).Return(saved, nil).Once()
+		// a failed notification doesn't undo the move
+		ws.On("SendNotification", ctx, budgetID, budgetMovedEvent, saved).Return(assert.AnError).Once()
+
+		_, err := newService(repo, catRepo, mbRepo, ws).Move(ctx, model.MoveMoneyRequest{
+			Month:        "2025-04",
+			ToCategoryID: &dining.ID,
+			Amount:       100,
+		})
+		require.NoError(t, err)
+		mbRepo.AssertExpectations(t)
+	})
+
+	t.Run("more_than_ready_to_assign", func(t *testing.T) {
+		repo, catRepo, mbRepo := &svcBudgetMoveRepo{}, &svcCategoryRepo{}, &svcMonthlyBudgetRepo{}
+		catRepo.On("GetById", ctx, budgetID, dining.ID).Return(dining, nil)
+		mbRepo.On("LockBudget", ctx, nil, budgetID).Return(nil).Once()
+		catRepo.On("GetAll", ctx, budgetID).Return([]model.Category{*dining}, nil).Once()
+		catRepo.On("GetReadyToAssign", ctx, nil, budgetID, "2025-04", mock.Anything).Return(99.99, nil).Once()
+
+		_, err := newService(repo, catRepo, mbRepo, nil).Move(ctx, model.MoveMoneyRequest{
+			Month:        "2025-04",
+			ToCategoryID: &dining.ID,
+			Amount:       100,
+		})
+		assert.True(t, hasErrorCode(err, errs.CodeMonthlyBudgetOverAssigned), err)
+		repo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("invalid_requests", func(t *testing.T) {
+		catRepo := &svcCategoryRepo{}
+		catRepo.On("GetById", ctx, budgetID, inflow.ID).Return(inflow, nil)
+		missing := uuid.New()
+		catRepo.On("GetById", ctx, budgetID, missing).Return(nil, pgx.ErrNoRows)
+		service := newService(&svcBudgetMoveRepo{}, catRepo, &svcMonthlyBudgetRepo{}, nil)
+
+		tests := []struct {
+			name string
+			req  model.MoveMoneyRequest
+			code errs.Code
+		}{
+			{"bad_month", model.MoveMoneyRequest{Month: "April", ToCategoryID: &dining.ID, Amount: 1}, errs.CodeInvalidArgument},
+			{"no_amount", model.MoveMoneyRequest{Month: "2025-04", ToCategoryID: &dining.ID}, errs.CodeInvalidArgument},
+			{"no_categories", model.MoveMoneyRequest{Month: "2025-04", Amount: 1}, errs.CodeInvalidArgument},
+			{
+				"same_category",
+				model.MoveMoneyRequest{Month: "2025-04", FromCategoryID: &dining.ID, ToCategoryID: &dining.ID, Amount: 1},
+				errs.CodeInvalidArgument,
+			},
+			{"system_category", model.MoveMoneyRequest{Month: "2025-04", FromCategoryID: &inflow.ID, Amount: 1}, errs.CodeInvalidArgument},
+			{"missing_category", model.MoveMoneyRequest{Month: "2025-04", FromCategoryID: &missing, Amount: 1}, errs.CodeCategoryNotFound},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				_, err := service.Move(ctx, tt.req)
+				assert.True(t, hasErrorCode(err, tt.code), err)
+			})
+		}
+	})
+}
@@ -4,8 +4,10 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"time"
 
 	repository "github.com/Rishabh-Kapri/pennywise/backend/shared/db"
+	errs "github.com/Rishabh-Kapri/pennywise/backend/shared/errors"
 	"github.com/Rishabh-Kapri/pennywise/backend/shared/logger"
 	"github.com/Rishabh-Kapri/pennywise/backend/shared/model"
 	utils "github.com/Rishabh-Kapri/pennywise/backend/shared/utils"
@@ -20,23 +22,38 @@ type CategoryService interface {
 	Search(ctx context.Context, query string) ([]model.Category, error)
 	GetById(ctx context.Context, id uuid.UUID) (*model.Category, error)
 	Create(ctx context.Context, category model.Category) (*model.Category, error)
-	DeleteById(ctx context.Context, id uuid.UUID) error
-	Update(ctx context.Context, id uuid.UUID, category model.Category) error
+	// DeleteById deletes the category. Transactions, budgeted money, payee rules and embeddings are moved
+	// to the replacement category, which is required when the category is still in use.
+	DeleteById(ctx context.Context, id uuid.UUID, replacementId *uuid.UUID) (*model.CategoryDeleteResult, error)
+	// Update saves the category, a rolloverNegative left out of the update keeps its stored value
+	Update(ctx context.Context, id uuid.UUID, update model.CategoryUpdate) error
 	UpdateMonthlyBudget(ctx context.Context, categoryId uuid.UUID, newBudgeted float64, month string) error
+	GetGoal(ctx context.Context, categoryId uuid.UUID) (*model.CategoryGoal, error)
+	// SetGoal creates or replaces the goal of a category
+	SetGoal(ctx context.Context, categoryId uuid.UUID, goal model.CategoryGoal) (*model.CategoryGoal, error)
+	DeleteGoal(ctx context.Context, categoryId uuid.UUID) error
+	// AutoAssign proposes budgeted amounts for the month using a strategy and applies them in a single
+	// db transaction unless previewing. Applying fails when it would assign more than is ready to assign.
+	AutoAssign(ctx context.Context, month string, req model.AutoAssignRequest) (*model.AutoAssignResult, error)
+	// SetBudgetedWithTx sets the budgeted amount of a category for the month within tx, for services that
+	// change budgeted amounts as part of a bigger db transaction
+	SetBudgetedWithTx(ctx context.Context, tx pgx.Tx, categoryId uuid.UUID, budgeted float64, month string) error
 }
 
 type categoryService struct {
 	repo              repository.CategoryRepository
 	monthlyBudgetRepo repository.MonthlyBudgetRepository
 	transactionRepo   repository.TransactionRepository
+	auditService      AuditService
 }
 
 func NewCategoryService(
 	r repository.CategoryRepository,
 	mbR repository.MonthlyBudgetRepository,
 	txnR repository.TransactionRepository,
+	auditService AuditService,
 ) CategoryService {
-	return &categoryService{repo: r, monthlyBudgetRepo: mbR, transactionRepo: txnR}
+	return &categoryService{repo: r, monthlyBudgetRepo: mbR, transactionRepo: txnR, auditService: auditService}
 }
 
 func (s *categoryService) GetAll(ctx context.Context) ([]model.Category, error) {
@@ -88,16 +105,200 @@ func (s *categoryService) Create(ctx context.Context, category model.Category) (
 	return s.repo.Create(ctx, nil, category)
 }
 
-func (s *categoryService) DeleteById(ctx context.Context, id uuid.UUID) error {
+func categoryLookupError(err error) error {
+	if errors.Is(err, pgx.ErrNoRows) {
+		return errs.Wrap(errs.CodeCategoryNotFound, "category not found", err)
+	}
+	return errs.Wrap(errs.CodeCategoryLookupFailed, "error getting category", err)
+}
+
+func (s *categoryService) DeleteById(
+	ctx context.Context,
+	id uuid.UUID,
+	replacementId *uuid.UUID,
+) (*model.CategoryDeleteResult, error) {
 	budgetId := utils.MustBudgetID(ctx)
-	return s.repo.DeleteById(ctx, budgetId, id)
+	category, err := s.repo.GetById(ctx, budgetId, id)
+	if err != nil {
+		return nil, categoryLookupError(err)
+	}
+	// the inflow category holds the money to be budgeted and payment categories belong to their
+	// credit card, neither can go away
+	if category.IsSystem {
+		return nil, errs.New(errs.CodeInvalidArgument, "system categories can't be deleted")
+	}
+	if replacementId != nil {
+		if *replacementId == id {
+			return nil, errs.New(errs.CodeInvalidArgument, "a category can't be replaced by itself")
+		}
+		replacement, err := s.repo.GetById(ctx, budgetId, *replacementId)
+		if err != nil {
+			return nil, categoryLookupError(err)
+		}
+		if replacement.IsSystem {
+			return nil, errs.New(errs.CodeInvalidArgument, "the replacement can't be a system category")
+		}
+	}
+
+	result := &model.CategoryDeleteResult{}
+	err = withTx(ctx, s.repo.GetDB(), func(tx pgx.Tx) error {
+		if replacementId == nil {
+			inUse, err := s.repo.IsInUse(ctx, tx, budgetId, id)
+			if err != nil {
+				return errs.Wrap(errs.CodeCategoryDeleteFailed, "error checking category usage", err)
+			}
+			if inUse {
+				return errs.New(errs.CodeCategoryInUse, "category is in use, a replacement category is required")
+			}
+		} else {
+			var moved []model.Transaction
+			var monthlyBudgets []model.MonthlyBudget
+			if s.auditService != nil {
+				var err error
+				moved, err = s.transactionRepo.GetForRewriteTx(ctx, tx, budgetId, model.TransactionFilter{
+					CategoryIDs: []uuid.UUID{id},
+				})
+				if err != nil {
+					return errs.Wrap(errs.CodeTransactionLookupFailed, "error getting transactions to reassign", err)
+				}
+				monthlyBudgets, err = s.monthlyBudgetRepo.GetByBudget(ctx, tx, budgetId)
+				if err != nil {
+					return errs.Wrap(errs.CodeCategoryDeleteFailed, "error getting monthly budgets", err)
+				}
+			}
+
+			reassigned, err := s.repo.Reassign(ctx, tx, budgetId, id, *replacementId)
+			if err != nil {
+				return errs.Wrap(errs.CodeCategoryDeleteFailed, "error reassigning category", err)
+			}
+			reassigned.MonthlyBudgets, err = s.monthlyBudgetRepo.MergeCategory(ctx, tx, budgetId, id, *replacementId)
+			if err != nil {
+				return errs.Wrap(errs.CodeCategoryDeleteFailed, "error merging monthly budgets", err)
+			}
+			result = reassigned
+
+			if s.auditService != nil {
+				err = recordTransactionRewrites(ctx, tx, s.auditService, moved, func(txn *model.Transaction) {
+					if txn.CategoryID != nil && *txn.CategoryID == id {
+						txn.CategoryID = replacementId
+					}
+					for i := range txn.Splits {
+						if txn.Splits[i].CategoryID != nil && *txn.Splits[i].CategoryID == id {
+							txn.Splits[i].CategoryID = replacementId
+						}
+					}
+				})
+				if err != nil {
+					return err
+				}
+				if err = s.recordMergedBudgetAudits(ctx, tx, id, *replacementId, monthlyBudgets); err != nil {
+					return err
+				}
+			}
+		}
+
+		if err := s.repo.DeleteById(ctx, tx, budgetId, id); err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return categoryLookupError(err)
+			}
+			return errs.Wrap(errs.CodeCategoryDeleteFailed, "error deleting category", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
 }
 
-func (s *categoryService) Update(ctx context.Context, id uuid.UUID, category model.Category) error {
+func (s *categoryService) Update(ctx context.Context, id uuid.UUID, update model.CategoryUpdate) error {
 	budgetId := utils.MustBudgetID(ctx)
+	category := update.Category
+	if update.RolloverNegative != nil {
+		category.RolloverNegative = *update.RolloverNegative
+	} else {
+		stored, err := s.repo.GetByIdSimplified(ctx, budgetId, id)
+		if err != nil {
+			return categoryLookupError(err)
+		}
+		category.RolloverNegative = stored.RolloverNegative
+	}
 	return s.repo.Update(ctx, budgetId, id, category)
 }
 
+func categoryGoalLookupError(err error) error {
+	if errors.Is(err, pgx.ErrNoRows) {
+		return errs.Wrap(errs.CodeCategoryGoalNotFound, "category has no goal", err)
+	}
+	return errs.Wrap(errs.CodeCategoryGoalFailed, "error getting category goal", err)
+}
+
+// validateCategoryGoal validates the goal and drops the target month from goals that don't use it
+func validateCategoryGoal(goal *model.CategoryGoal) error {
+	switch goal.Type {
+	case model.CategoryGoalMonthlyFunding, model.CategoryGoalNeededForSpending:
+		goal.TargetMonth = nil
+	case model.CategoryGoalTargetBalance:
+		if goal.TargetMonth != nil {
+			if _, err := time.Parse("2006-01", *goal.TargetMonth); err != nil {
+				return errs.New(errs.CodeInvalidArgument, "target month must be formatted as YYYY-MM")
+			}
+		}
+	default:
+		return errs.New(errs.CodeInvalidArgument, "invalid goal type: %s", goal.Type)
+	}
+	if goal.Amount <= 0 {
+		return errs.New(errs.CodeInvalidArgument, "goal amount must be positive")
+	}
+	return nil
+}
+
+func (s *categoryService) GetGoal(ctx context.Context, categoryId uuid.UUID) (*model.CategoryGoal, error) {
+	budgetId := utils.MustBudgetID(ctx)
+	goal, err := s.repo.GetGoal(ctx, budgetId, categoryId)
+	if err != nil {
+		return nil, categoryGoalLookupError(err)
+	}
+	return goal, nil
+}
+
+func (s *categoryService) SetGoal(
+	ctx context.Context,
+	categoryId uuid.UUID,
+	goal model.CategoryGoal,
+) (*model.CategoryGoal, error) {
+	budgetId := utils.MustBudgetID(ctx)
+	if err := validateCategoryGoal(&goal); err != nil {
+		return nil, err
+	}
+	category, err := s.repo.GetById(ctx, budgetId, categoryId)
+	if err != nil {
+		return nil, categoryLookupError(err)
+	}
+	if category.IsSystem {
+		return nil, errs.New(errs.CodeInvalidArgument, "system categories can't have a goal")
+	}
+
+	goal.BudgetID = budgetId
+	goal.CategoryID = categoryId
+	saved, err := s.repo.UpsertGoal(ctx, goal)
+	if err != nil {
+		return nil, errs.Wrap(errs.CodeCategoryGoalFailed, "error saving category goal", err)
+	}
+	return saved, nil
+}
+
+func (s *categoryService) DeleteGoal(ctx context.Context, categoryId uuid.UUID) error {
+	budgetId := utils.MustBudgetID(ctx)
+	if err := s.repo.DeleteGoal(ctx, budgetId, categoryId); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return categoryGoalLookupError(err)
+		}
+		return errs.Wrap(errs.CodeCategoryGoalFailed, "error deleting category goal", err)
+	}
+	return nil
+}
+
 // updates the monthly budget for a category for a particular month
 // create a new record if it doesn't exist
 // gets the carryover from the previous month
@@ -110,35 +311,115 @@ func (s *categoryService) UpdateMonthlyBudget(
 	budgetId := utils.MustBudgetID(ctx)
 
 	return utils.WithTx(ctx, s.monthlyBudgetRepo.GetDB(), func(tx pgx.Tx) error {
-		exists, err := s.monthlyBudgetRepo.GetByCatIdAndMonth(ctx, tx, budgetId, categoryId, month)
-		if err != nil {
-			if errors.Is(err, pgx.ErrNoRows) {
-				logger.Logger(ctx).Info("monthly budget not found, creating", "month", month, "categoryId", categoryId)
-				// no budget exists for this category
-				monthyBudget := model.MonthlyBudget{
-					BudgetID:         budgetId,
-					CategoryID:       categoryId,
-					Month:            month,
-					Budgeted:         newBudgeted,
-					CarryoverBalance: 0.0,
-				}
-				err = s.monthlyBudgetRepo.Create(ctx, tx, budgetId, monthyBudget)
-				if err != nil {
-					return fmt.Errorf("error while creating monthly budget: %w", err)
-				}
-			} else {
-				return fmt.Errorf("error while fetching existing budget: %w", err)
-			}
-		} else {
-			if exists.Budgeted == newBudgeted {
-				logger.Logger(ctx).Debug("budgeted unchanged, skipping")
-				return nil
+		return s.setBudgetedWithTx(ctx, tx, budgetId, categoryId, newBudgeted, month)
+	})
+}
+
+func (s *categoryService) SetBudgetedWithTx(
+	ctx context.Context,
+	tx pgx.Tx,
+	categoryId uuid.UUID,
+	budgeted float64,
+	month string,
+) error {
+	return s.setBudgetedWithTx(ctx, tx, utils.MustBudgetID(ctx), categoryId, budgeted, month)
+}
+
+// setBudgetedWithTx sets the budgeted amount of a category for the month within tx and audits the change
+func (s *categoryService) setBudgetedWithTx(
+	ctx context.Context,
+	tx pgx.Tx,
+	budgetId uuid.UUID,
+	categoryId uuid.UUID,
+	newBudgeted float64,
+	month string,
+) error {
+	exists, err := s.monthlyBudgetRepo.GetByCatIdAndMonth(ctx, tx, budgetId, categoryId, month)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			logger.Logger(ctx).Info("monthly budget not found, creating", "month", month, "categoryId", categoryId)
+			// no budget exists for this category
+			monthyBudget := model.MonthlyBudget{
+				BudgetID:         budgetId,
+				CategoryID:       categoryId,
+				Month:            month,
+				Budgeted:         newBudgeted,
+				CarryoverBalance: 0.0,
 			}
-			err = s.monthlyBudgetRepo.UpdateBudgetedByCatIdAndMonth(ctx, tx, budgetId, categoryId, month, newBudgeted)
+			err = s.monthlyBudgetRepo.Create(ctx, tx, budgetId, monthyBudget)
 			if err != nil {
-				return err
+				return fmt.Errorf("error while creating monthly budget: %w", err)
 			}
+			return s.recordBudgetedAudit(ctx, tx, categoryId, month, nil, &newBudgeted)
+		} else {
+			return fmt.Errorf("error while fetching existing budget: %w", err)
+		}
+	} else {
+		if exists.Budgeted == newBudgeted {
+			logger.Logger(ctx).Debug("budgeted unchanged, skipping")
+			return nil
 		}
+		err = s.monthlyBudgetRepo.UpdateBudgetedByCatIdAndMonth(ctx, tx, budgetId, categoryId, month, newBudgeted)
+		if err != nil {
+			return err
+		}
+		return s.recordBudgetedAudit(ctx, tx, categoryId, month, &exists.Budgeted, &newBudgeted)
+	}
+}
+
+// recordMergedBudgetAudits audits the budgeted amounts MergeCategory folded into the replacement category,
+// monthlyBudgets holds the monthly budgets of the budget as they were before
+func (s *categoryService) recordMergedBudgetAudits(
+	ctx context.Context,
+	tx pgx.Tx,
+	fromId uuid.UUID,
+	toId uuid.UUID,
+	monthlyBudgets []model.MonthlyBudget,
+) error {
+	replacement := make(map[string]float64)
+	for _, mb := range monthlyBudgets {
+		if mb.CategoryID == toId {
+			replacement[mb.Month] = mb.Budgeted
+		}
+	}
+	for _, mb := range monthlyBudgets {
+		if mb.CategoryID != fromId {
+			continue
+		}
+		after := mb.Budgeted
+		var before *float64
+		if budgeted, ok := replacement[mb.Month]; ok {
+			before = &budgeted
+			after += budgeted
+		}
+		if err := s.recordBudgetedAudit(ctx, tx, toId, mb.Month, before, &after); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// recordBudgetedAudit audits a change to the budgeted amount of a category, a nil before means the month was created
+func (s *categoryService) recordBudgetedAudit(
+	ctx context.Context,
+	tx pgx.Tx,
+	categoryId uuid.UUID,
+	month string,
+	before *float64,
+	after *float64,
+) error {
+	if s.auditService == nil {
 		return nil
-	})
+	}
+	record := AuditRecord{
+		EntityType: model.AuditEntityMonthlyBudget,
+		EntityID:   categoryId,
+		Action:     model.AuditActionCreate,
+		After:      monthlyBudgetAuditSnapshot{CategoryID: categoryId, Month: month, Budgeted: *after},
+	}
+	if before != nil {
+		record.Action = model.AuditActionUpdate
+		record.Before = monthlyBudgetAuditSnapshot{CategoryID: categoryId, Month: month, Budgeted: *before}
+	}
+	return s.auditService.Record(ctx, tx, record)
 }
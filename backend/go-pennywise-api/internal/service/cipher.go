@@ -4,6 +4,8 @@ import (
 	"context"
 
 	"github.com/Rishabh-Kapri/pennywise/backend/shared/transport"
+
+	"github.com/google/uuid"
 )
 
 type TransactionEmbeddingRequest struct {
@@ -17,8 +19,31 @@ type TransactionEmbeddingResponse struct {
 	Embedding     string `json:"embedding"`
 }
 
+type PredictExtractedInputs struct {
+	Merchant string `json:"merchant"`
+	Account  string `json:"account"`
+	Date     string `json:"date"`
+}
+
+type PredictRequest struct {
+	EmailText       string                  `json:"emailText"`
+	Amount          float64                 `json:"amount"`
+	ExtractedInputs *PredictExtractedInputs `json:"extractedInputs"`
+}
+
+type PredictResponse struct {
+	PayeeID    uuid.UUID `json:"payeeId"`
+	CategoryID uuid.UUID `json:"categoryId"`
+	Payee      string    `json:"payee"`
+	Category   string    `json:"category"`
+	Amount     float64   `json:"amount"`
+	Confidence string    `json:"confidence"`
+	Source     string    `json:"source"` // pgvector | mlp | fallback
+}
+
 type CipherClient interface {
 	GenerateTransactionEmbedding(ctx context.Context, req TransactionEmbeddingRequest) (*TransactionEmbeddingResponse, error)
+	Predict(ctx context.Context, req PredictRequest) (*PredictResponse, error)
 }
 
 type cipherClient struct {
@@ -32,3 +57,7 @@ func NewCipherClient(client *transport.Client) CipherClient {
 func (c *cipherClient) GenerateTransactionEmbedding(ctx context.Context, req TransactionEmbeddingRequest) (*TransactionEmbeddingResponse, error) {
 	return transport.Post[*TransactionEmbeddingResponse](ctx, c.client, "/api/embeddings/transaction", nil, req)
 }
+
+func (c *cipherClient) Predict(ctx context.Context, req PredictRequest) (*PredictResponse, error) {
+	return transport.Post[*PredictResponse](ctx, c.client, "/api/predict", nil, req)
+}
@@ -0,0 +1,575 @@
+package service
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	repository "github.com/Rishabh-Kapri/pennywise/backend/shared/db"
+	errs "github.com/Rishabh-Kapri/pennywise/backend/shared/errors"
+	"github.com/Rishabh-Kapri/pennywise/backend/shared/logger"
+	"github.com/Rishabh-Kapri/pennywise/backend/shared/model"
+	utils "github.com/Rishabh-Kapri/pennywise/backend/shared/utils"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// exportPageSize is the number of transactions fetched per page while streaming
+	exportPageSize = 500
+	ynabDateLayout = "01/02/2006"
+)
+
+type ExportService interface {
+	// Export writes the ledger to w in the requested format. Transactions are read page by
+	// page and flushed as they go, except for OFX which groups them into per account statements.
+	// Amounts are converted into the requested currency using the fx rate table, if any.
+	Export(ctx context.Context, req model.ExportRequest, w io.Writer) error
+}
+
+type exportService struct {
+	transactionRepo   repository.TransactionRepository
+	monthlyBudgetRepo repository.MonthlyBudgetRepository
+	accountRepo       repository.AccountRepository
+	categoryGroupRepo repository.CategoryGroupRepository
+	tagRepo           repository.TagRepository
+	fxRateRepo        repository.FxRateRepository
+}
+
+func NewExportService(
+	transactionRepo repository.TransactionRepository,
+	monthlyBudgetRepo repository.MonthlyBudgetRepository,
+	accountRepo repository.AccountRepository,
+	categoryGroupRepo repository.CategoryGroupRepository,
+	tagRepo repository.TagRepository,
+	fxRateRepo repository.FxRateRepository,
+) ExportService {
+	return &exportService{
+		transactionRepo:   transactionRepo,
+		monthlyBudgetRepo: monthlyBudgetRepo,
+		accountRepo:       accountRepo,
+		categoryGroupRepo: categoryGroupRepo,
+		tagRepo:           tagRepo,
+		fxRateRepo:        fxRateRepo,
+	}
+}
+
+// exportLookups holds the names the export needs that aren't part of the normalized transactions
+type exportLookups struct {
+	accounts       map[uuid.UUID]string
+	categoryGroups map[uuid.UUID]string
+	tags           map[uuid.UUID]string
+}
+
+// exportAmounts converts transaction amounts from their account currency into the export
+// currency. Without an export currency amounts are written as they are.
+type exportAmounts struct {
+	currencies map[uuid.UUID]string
+	converter  *fxConverter
+}
+
+// exportLine is a single ledger line. Split transactions produce one line per split.
+type exportLine struct {
+	txn          model.Transaction
+	categoryID   *uuid.UUID
+	categoryName string
+	amount       float64
+	memo         string
+	split        string
+}
+
+func (s *exportService) Export(ctx context.Context, req model.ExportRequest, w io.Writer) error {
+	budgetId := utils.MustBudgetID(ctx)
+	req.Normalize()
+	if err := req.Valid(); err != nil {
+		return err
+	}
+	logger.Logger(ctx).Info("exporting ledger", "type", req.Type, "format", req.Format)
+
+	if req.Type == model.ExportTypeBudgets {
+		return s.exportBudgets(ctx, budgetId, req, w)
+	}
+
+	amounts, err := s.loadAmounts(ctx, budgetId, req)
+	if err != nil {
+		return err
+	}
+	if req.Format == model.ExportFormatOFX {
+		return s.exportOFX(ctx, budgetId, req.Filter, amounts, w)
+	}
+	lookups, err := s.loadLookups(ctx, budgetId)
+	if err != nil {
+		return err
+	}
+	switch req.Format {
+	case model.ExportFormatYNAB:
+		return s.exportTransactionsCSV(ctx, budgetId, req.Filter, amounts, w, ynabRegisterHeader, lookups.ynabRecord)
+	default:
+		return s.exportTransactionsCSV(ctx, budgetId, req.Filter, amounts, w, transactionCSVHeader, lookups.csvRecord)
+	}
+}
+
+// loadAmounts loads the account currencies, OFX statements need them even without conversion
+func (s *exportService) loadAmounts(
+	ctx context.Context,
+	budgetId uuid.UUID,
+	req model.ExportRequest,
+) (*exportAmounts, error) {
+	amounts := &exportAmounts{currencies: map[uuid.UUID]string{}}
+	if req.Currency == "" && req.Format != model.ExportFormatOFX {
+		return amounts, nil
+	}
+	if req.Currency != "" {
+		if s.fxRateRepo == nil {
+			return nil, errs.New(errs.CodeInternalError, "fx rates are not configured")
+		}
+		amounts.converter = newFxConverter(s.fxRateRepo, budgetId, req.Currency)
+	}
+	// closed accounts are included so their transactions are converted as well
+	accounts, err := s.accountRepo.GetAll(ctx, budgetId)
+	if err != nil {
+		return nil, errs.Wrap(errs.CodeAccountLookupFailed, "error getting accounts", err)
+	}
+	for _, account := range accounts {
+		amounts.currencies[account.ID] = account.Currency
+	}
+	return amounts, nil
+}
+
+// currency returns the currency amounts of txn are written in
+func (a *exportAmounts) currency(txn model.Transaction) string {
+	if a.converter != nil {
+		return a.converter.to
+	}
+	if txn.AccountID != nil && a.currencies[*txn.AccountID] != "" {
+		return a.currencies[*txn.AccountID]
+	}
+	return model.DefaultCurrency
+}
+
+func (a *exportAmounts) convert(ctx context.Context, txn model.Transaction, amount float64) (float64, error) {
+	if a.converter == nil || txn.AccountID == nil {
+		return amount, nil
+	}
+	return a.converter.convert(ctx, amount, a.currencies[*txn.AccountID], txn.Date)
+}
+
+func (s *exportService) loadLookups(ctx context.Context, budgetId uuid.UUID) (*exportLookups, error) {
+	lookups := &exportLookups{
+		accounts:       map[uuid.UUID]string{},
+		categoryGroups: map[uuid.UUID]string{},
+		tags:           map[uuid.UUID]string{},
+	}
+
+	accounts, err := s.accountRepo.GetAllSimplified(ctx, budgetId)
+	if err != nil {
+		return nil, errs.Wrap(errs.CodeAccountLookupFailed, "error getting accounts", err)
+	}
+	for _, account := range accounts {
+		lookups.accounts[account.ID] = account.Name
+	}
+
+	groups, err := s.categoryGroupRepo.GetAll(ctx, budgetId)
+	if err != nil {
+		return nil, errs.Wrap(errs.CodeCategoryLookupFailed, "error getting category groups", err)
+	}
+	for _, group := range groups {
+		for _, category := range group.Categories {
+			lookups.categoryGroups[category.ID] = group.Name
+		}
+	}
+
+	tags, err := s.tagRepo.GetAll(ctx, budgetId)
+	if err != nil {
+		return nil, errs.Wrap(errs.CodeInternalError, "error getting tags", err)
+	}
+	for _, tag := range tags {
+		lookups.tags[tag.ID] = tag.Name
+	}
+	return lookups, nil
+}
+
+// eachTransactionPage pages through the filtered transactions oldest first
+func (s *exportService) eachTransactionPage(
+	ctx context.Context,
+	budgetId uuid.UUID,
+	filter model.TransactionFilter,
+	fn func(txns []model.Transaction) error,
+) error {
+	filter.Limit = exportPageSize
+	filter.SortOrder = "ASC"
+	filter.CursorString = ""
+	for {
+		page, err := s.transactionRepo.GetAllNormalized(ctx, budgetId, &filter)
+		if err != nil {
+			return errs.Wrap(errs.CodeTransactionLookupFailed, "error getting transactions", err)
+		}
+		if err := fn(page.Data); err != nil {
+			return err
+		}
+		if len(page.Data) == 0 || page.Pagination.NextCursor == "" {
+			return nil
+		}
+		filter.CursorString = page.Pagination.NextCursor
+	}
+}
+
+// exportLines expands a transaction into its ledger lines
+func exportLines(txn model.Transaction) []exportLine {
+	if !txn.IsSplit() {
+		return []exportLine{{
+			txn:          txn,
+			categoryID:   txn.CategoryID,
+			categoryName: derefString(txn.CategoryName),
+			amount:       txn.Amount,
+			memo:         txn.Note,
+		}}
+	}
+	lines := make([]exportLine, 0, len(txn.Splits))
+	for i, split := range txn.Splits {
+		memo := split.Note
+		if memo == "" {
+			memo = txn.Note
+		}
+		lines = append(lines, exportLine{
+			txn:          txn,
+			categoryID:   split.CategoryID,
+			categoryName: derefString(split.CategoryName),
+			amount:       split.Amount,
+			memo:         memo,
+			split:        fmt.Sprintf("%d/%d", i+1, len(txn.Splits)),
+		})
+	}
+	return lines
+}
+
+func derefString(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
+
+func formatExportAmount(amount float64) string {
+	return fmt.Sprintf("%.2f", amount)
+}
+
+func (s *exportService) exportTransactionsCSV(
+	ctx context.Context,
+	budgetId uuid.UUID,
+	filter model.TransactionFilter,
+	amounts *exportAmounts,
+	w io.Writer,
+	header []string,
+	record func(line exportLine) []string,
+) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write(header); err != nil {
+		return err
+	}
+	err := s.eachTransactionPage(ctx, budgetId, filter, func(txns []model.Transaction) error {
+		for _, txn := range txns {
+			for _, line := range exportLines(txn) {
+				amount, err := amounts.convert(ctx, txn, line.amount)
+				if err != nil {
+					return err
+				}
+				line.amount = amount
+				if err := writer.Write(record(line)); err != nil {
+					return err
+				}
+			}
+		}
+		writer.Flush()
+		return writer.Error()
+	})
+	if err != nil {
+		return err
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+var transactionCSVHeader = []string{
+	"ID", "Date", "Account", "Payee", "Category Group", "Category", "Memo", "Amount", "Inflow", "Outflow",
+	"Status", "Cleared", "Tags", "Split", "Transfer Account", "Transfer Transaction ID",
+}
+
+func (l *exportLookups) csvRecord(line exportLine) []string {
+	txn := line.txn
+	inflow, outflow := 0.0, 0.0
+	if line.amount >= 0 {
+		inflow = line.amount
+	} else {
+		outflow = -line.amount
+	}
+	tags := make([]string, 0, len(txn.TagIDs))
+	for _, tagId := range txn.TagIDs {
+		if name, ok := l.tags[tagId]; ok {
+			tags = append(tags, name)
+		}
+	}
+	transferAccount, transferTxn := "", ""
+	if txn.TransferAccountID != nil {
+		transferAccount = l.accounts[*txn.TransferAccountID]
+	}
+	if txn.TransferTransactionID != nil {
+		transferTxn = txn.TransferTransactionID.String()
+	}
+	return []string{
+		txn.ID.String(),
+		txn.Date.String(),
+		derefString(txn.AccountName),
+		derefString(txn.PayeeName),
+		l.categoryGroup(line.categoryID),
+		line.categoryName,
+		line.memo,
+		formatExportAmount(line.amount),
+		formatExportAmount(inflow),
+		formatExportAmount(outflow),
+		string(txn.Status),
+		string(txn.Cleared),
+		strings.Join(tags, ","),
+		line.split,
+		transferAccount,
+		transferTxn,
+	}
+}
+
+func (l *exportLookups) categoryGroup(categoryId *uuid.UUID) string {
+	if categoryId == nil {
+		return ""
+	}
+	return l.categoryGroups[*categoryId]
+}
+
+var ynabRegisterHeader = []string{
+	"Account", "Flag", "Date", "Payee", "Category Group/Category", "Category Group", "Category",
+	"Memo", "Outflow", "Inflow", "Cleared",
+}
+
+func (l *exportLookups) ynabRecord(line exportLine) []string {
+	txn := line.txn
+	date := txn.Date.String()
+	if parsed, err := time.Parse(importDateLayout, date); err == nil {
+		date = parsed.Format(ynabDateLayout)
+	}
+	group := l.categoryGroup(line.categoryID)
+	groupCategory := ""
+	if line.categoryName != "" {
+		groupCategory = group + ": " + line.categoryName
+	}
+	inflow, outflow := "0.00", "0.00"
+	if line.amount >= 0 {
+		inflow = formatExportAmount(line.amount)
+	} else {
+		outflow = formatExportAmount(-line.amount)
+	}
+	cleared := "Uncleared"
+	switch txn.Cleared {
+	case model.ClearedStatusCleared:
+		cleared = "Cleared"
+	case model.ClearedStatusReconciled:
+		cleared = "Reconciled"
+	}
+	memo := line.memo
+	if line.split != "" {
+		memo = strings.TrimSpace(fmt.Sprintf("(Split %s) %s", line.split, memo))
+	}
+	return []string{
+		derefString(txn.AccountName),
+		"",
+		date,
+		derefString(txn.PayeeName),
+		groupCategory,
+		group,
+		line.categoryName,
+		memo,
+		outflow,
+		inflow,
+		cleared,
+	}
+}
+
+func (s *exportService) exportBudgets(
+	ctx context.Context,
+	budgetId uuid.UUID,
+	req model.ExportRequest,
+	w io.Writer,
+) error {
+	startMonth, endMonth := "", ""
+	if req.Filter.StartDate != nil && len(*req.Filter.StartDate) >= 7 {
+		startMonth = (*req.Filter.StartDate)[:7]
+	}
+	if req.Filter.EndDate != nil && len(*req.Filter.EndDate) >= 7 {
+		endMonth = (*req.Filter.EndDate)[:7]
+	}
+	summaries, err := s.monthlyBudgetRepo.GetSummaries(ctx, budgetId, startMonth, endMonth)
+	if err != nil {
+		return errs.Wrap(errs.CodeMonthlyBudgetLookupFailed, "error getting monthly budgets", err)
+	}
+
+	writer := csv.NewWriter(w)
+	if req.Format == model.ExportFormatYNAB {
+		err = writer.Write([]string{
+			"Month", "Category Group/Category", "Category Group", "Category", "Budgeted", "Activity", "Available",
+		})
+	} else {
+		err = writer.Write([]string{"Month", "Category Group", "Category", "Budgeted", "Activity", "Available"})
+	}
+	if err != nil {
+		return err
+	}
+
+	for _, summary := range summaries {
+		record := []string{
+			summary.Month,
+			summary.CategoryGroupName,
+			summary.CategoryName,
+			formatExportAmount(summary.Budgeted),
+			formatExportAmount(summary.Activity),
+			formatExportAmount(summary.Available),
+		}
+		if req.Format == model.ExportFormatYNAB {
+			month := summary.Month
+			if parsed, err := time.Parse("2006-01", month); err == nil {
+				month = parsed.Format("Jan 2006")
+			}
+			record = append(
+				[]string{month, summary.CategoryGroupName + ": " + summary.CategoryName},
+				record[1:]...,
+			)
+		}
+		if err := writer.Write(record); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+// ofxStatement collects the transactions of a single account
+type ofxStatement struct {
+	accountId uuid.UUID
+	currency  string
+	start     string
+	end       string
+	lines     []model.Transaction
+}
+
+func ofxEscape(s string) string {
+	var b strings.Builder
+	_ = xml.EscapeText(&b, []byte(s))
+	return b.String()
+}
+
+func ofxDate(date model.Date) string {
+	return strings.ReplaceAll(date.String(), "-", "")
+}
+
+// exportOFX writes an OFX 2.1.1 document with one bank statement per account.
+// The ledger balance of each statement is the sum of its exported transactions.
+func (s *exportService) exportOFX(
+	ctx context.Context,
+	budgetId uuid.UUID,
+	filter model.TransactionFilter,
+	amounts *exportAmounts,
+	w io.Writer,
+) error {
+	var statements []*ofxStatement
+	byAccount := map[uuid.UUID]*ofxStatement{}
+	err := s.eachTransactionPage(ctx, budgetId, filter, func(txns []model.Transaction) error {
+		for _, txn := range txns {
+			if txn.AccountID == nil {
+				continue
+			}
+			statement, ok := byAccount[*txn.AccountID]
+			if !ok {
+				statement = &ofxStatement{
+					accountId: *txn.AccountID,
+					currency:  amounts.currency(txn),
+					start:     txn.Date.String(),
+				}
+				byAccount[*txn.AccountID] = statement
+				statements = append(statements, statement)
+			}
+			amount, err := amounts.convert(ctx, txn, txn.Amount)
+			if err != nil {
+				return err
+			}
+			txn.Amount = amount
+			statement.end = txn.Date.String()
+			statement.lines = append(statement.lines, txn)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	now := time.Now().UTC().Format("20060102150405")
+	var b strings.Builder
+	b.WriteString(`<?xml version="1.0" encoding="UTF-8" standalone="no"?>` + "\n")
+	b.WriteString(`<?OFX OFXHEADER="200" VERSION="211" SECURITY="NONE" OLDFILEUID="NONE" NEWFILEUID="NONE"?>` + "\n")
+	b.WriteString("<OFX>\n<SIGNONMSGSRSV1><SONRS>")
+	b.WriteString("<STATUS><CODE>0</CODE><SEVERITY>INFO</SEVERITY></STATUS>")
+	fmt.Fprintf(&b, "<DTSERVER>%s</DTSERVER><LANGUAGE>ENG</LANGUAGE></SONRS></SIGNONMSGSRSV1>\n", now)
+	b.WriteString("<BANKMSGSRSV1>\n")
+	for i, statement := range statements {
+		fmt.Fprintf(&b, "<STMTTRNRS><TRNUID>%d</TRNUID>", i+1)
+		b.WriteString("<STATUS><CODE>0</CODE><SEVERITY>INFO</SEVERITY></STATUS><STMTRS>")
+		fmt.Fprintf(&b, "<CURDEF>%s</CURDEF>", statement.currency)
+		fmt.Fprintf(
+			&b,
+			"<BANKACCTFROM><BANKID>PENNYWISE</BANKID><ACCTID>%s</ACCTID><ACCTTYPE>CHECKING</ACCTTYPE></BANKACCTFROM>\n",
+			statement.accountId,
+		)
+		fmt.Fprintf(
+			&b,
+			"<BANKTRANLIST><DTSTART>%s</DTSTART><DTEND>%s</DTEND>\n",
+			ofxDate(model.Date(statement.start)),
+			ofxDate(model.Date(statement.end)),
+		)
+		balance := 0.0
+		for _, txn := range statement.lines {
+			balance += txn.Amount
+			trnType := "DEBIT"
+			if txn.Amount >= 0 {
+				trnType = "CREDIT"
+			}
+			name := derefString(txn.PayeeName)
+			if len([]rune(name)) > 32 {
+				name = string([]rune(name)[:32])
+			}
+			fmt.Fprintf(
+				&b,
+				"<STMTTRN><TRNTYPE>%s</TRNTYPE><DTPOSTED>%s</DTPOSTED><TRNAMT>%s</TRNAMT><FITID>%s</FITID><NAME>%s</NAME>",
+				trnType,
+				ofxDate(txn.Date),
+				formatExportAmount(txn.Amount),
+				txn.ID,
+				ofxEscape(name),
+			)
+			if txn.Note != "" {
+				fmt.Fprintf(&b, "<MEMO>%s</MEMO>", ofxEscape(txn.Note))
+			}
+			b.WriteString("</STMTTRN>\n")
+		}
+		b.WriteString("</BANKTRANLIST>")
+		fmt.Fprintf(
+			&b,
+			"<LEDGERBAL><BALAMT>%s</BALAMT><DTASOF>%s</DTASOF></LEDGERBAL>",
+			formatExportAmount(balance),
+			ofxDate(model.Date(statement.end)),
+		)
+		b.WriteString("</STMTRS></STMTTRNRS>\n")
+	}
+	b.WriteString("</BANKMSGSRSV1>\n</OFX>\n")
+
+	_, err = io.WriteString(w, b.String())
+	return err
+}
@@ -0,0 +1,231 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"strings"
+	"testing"
+
+	"github.com/Rishabh-Kapri/pennywise/backend/shared/model"
+	utils "github.com/Rishabh-Kapri/pennywise/backend/shared/utils"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExport(t *testing.T) {
+	budgetId := uuid.New()
+	ctx := utils.WithBudgetID(context.Background(), budgetId)
+	accountId := uuid.New()
+	savingsId := uuid.New()
+	groceriesId := uuid.New()
+	diningId := uuid.New()
+	tagId := uuid.New()
+	transferTxnId := uuid.New()
+
+	str := func(s string) *string { return &s }
+	firstPage := []model.Transaction{
+		{
+			ID:           uuid.New(),
+			Date:         "2024-01-05",
+			AccountID:    &accountId,
+			AccountName:  str("Checking"),
+			PayeeName:    str("Grocer"),
+			CategoryID:   &groceriesId,
+			CategoryName: str("Groceries"),
+			Note:         "weekly",
+			Amount:       -20,
+			Status:       model.TransactionStatusApproved,
+			Cleared:      model.ClearedStatusReconciled,
+			TagIDs:       []uuid.UUID{tagId},
+		},
+		{
+			ID:                    uuid.New(),
+			Date:                  "2024-01-06",
+			AccountID:             &accountId,
+			AccountName:           str("Checking"),
+			PayeeName:             str("Transfer : Savings"),
+			Amount:                -100,
+			Status:                model.TransactionStatusUnapproved,
+			Cleared:               model.ClearedStatusUncleared,
+			TransferAccountID:     &savingsId,
+			TransferTransactionID: &transferTxnId,
+		},
+	}
+	secondPage := []model.Transaction{
+		{
+			ID:          uuid.New(),
+			Date:        "2024-01-07",
+			AccountID:   &accountId,
+			AccountName: str("Checking"),
+			PayeeName:   str("Mart"),
+			Amount:      -30,
+			Status:      model.TransactionStatusApproved,
+			Cleared:     model.ClearedStatusCleared,
+			Splits: []model.TransactionSplit{
+				{CategoryID: &groceriesId, CategoryName: str("Groceries"), Amount: -20},
+				{CategoryID: &diningId, CategoryName: str("Dining"), Amount: -10, Note: "snacks"},
+			},
+		},
+	}
+
+	newService := func() (*mockTransactionRepo, *mockFxRateRepo, ExportService) {
+		txnRepo := &mockTransactionRepo{}
+		accountRepo := &svcAccountRepo{}
+		groupRepo := &svcCategoryGroupRepo{}
+		tagRepo := &svcTagRepo{}
+		accountRepo.On("GetAllSimplified", mock.Anything, budgetId).
+			Return([]model.AccountSimplified{{ID: accountId, Name: "Checking"}, {ID: savingsId, Name: "Savings"}}, nil)
+		accountRepo.On("GetAll", mock.Anything, budgetId).Return([]model.Account{
+			{ID: accountId, Name: "Checking", Currency: "USD"},
+			{ID: savingsId, Name: "Savings", Currency: "INR"},
+		}, nil)
+		groupRepo.On("GetAll", mock.Anything, budgetId).Return([]model.CategoryGroup{{
+			Name:       "Everyday",
+			Categories: []model.Category{{ID: groceriesId}, {ID: diningId}},
+		}}, nil)
+		tagRepo.On("GetAll", mock.Anything, budgetId).Return([]model.Tag{{ID: tagId, Name: "family"}}, nil)
+
+		txnRepo.On("GetAllNormalized", mock.Anything, budgetId, mock.MatchedBy(func(f *model.TransactionFilter) bool {
+			return f.CursorString == "" && f.SortOrder == "ASC" && f.Limit == exportPageSize
+		})).Return(model.PaginatedResponse[model.Transaction]{
+			Data:       firstPage,
+			Pagination: model.Pagination{NextCursor: "next"},
+		}, nil).Once()
+		txnRepo.On("GetAllNormalized", mock.Anything, budgetId, mock.MatchedBy(func(f *model.TransactionFilter) bool {
+			return f.CursorString == "next"
+		})).Return(model.PaginatedResponse[model.Transaction]{Data: secondPage}, nil).Once()
+		fxRateRepo := &mockFxRateRepo{}
+		return txnRepo, fxRateRepo, NewExportService(
+			txnRepo,
+			&svcMonthlyBudgetRepo{},
+			accountRepo,
+			groupRepo,
+			tagRepo,
+			fxRateRepo,
+		)
+	}
+
+	t.Run("csv_pages_through_transactions", func(t *testing.T) {
+		txnRepo, _, service := newService()
+		var buf bytes.Buffer
+		err := service.Export(ctx, model.ExportRequest{Format: "csv"}, &buf)
+		require.NoError(t, err)
+		txnRepo.AssertExpectations(t)
+
+		records, err := csv.NewReader(&buf).ReadAll()
+		require.NoError(t, err)
+		require.Len(t, records, 5)
+		assert.Equal(t, transactionCSVHeader, records[0])
+
+		assert.Equal(t, []string{
+			firstPage[0].ID.String(), "2024-01-05", "Checking", "Grocer", "Everyday", "Groceries", "weekly",
+			"-20.00", "0.00", "20.00", "APPROVED", "RECONCILED", "family", "", "", "",
+		}, records[1])
+		assert.Equal(t, "Savings", records[2][14])
+		assert.Equal(t, transferTxnId.String(), records[2][15])
+		assert.Equal(t, "1/2", records[3][13])
+		assert.Equal(t, "-20.00", records[3][7])
+		assert.Equal(t, "Dining", records[4][5])
+		assert.Equal(t, "snacks", records[4][6])
+	})
+
+	t.Run("ynab_register", func(t *testing.T) {
+		_, _, service := newService()
+		var buf bytes.Buffer
+		require.NoError(t, service.Export(ctx, model.ExportRequest{Format: "ynab"}, &buf))
+
+		records, err := csv.NewReader(&buf).ReadAll()
+		require.NoError(t, err)
+		assert.Equal(t, ynabRegisterHeader, records[0])
+		assert.Equal(t, []string{
+			"Checking", "", "01/05/2024", "Grocer", "Everyday: Groceries", "Everyday", "Groceries",
+			"weekly", "20.00", "0.00", "Reconciled",
+		}, records[1])
+		assert.Equal(t, "Uncleared", records[2][10])
+		assert.Equal(t, "Cleared", records[3][10])
+		assert.Equal(t, "(Split 2/2) snacks", records[4][7])
+	})
+
+	t.Run("ofx_groups_by_account", func(t *testing.T) {
+		_, _, service := newService()
+		var buf bytes.Buffer
+		require.NoError(t, service.Export(ctx, model.ExportRequest{Format: "ofx"}, &buf))
+
+		out := buf.String()
+		assert.Equal(t, 1, strings.Count(out, "<STMTTRNRS>"))
+		assert.Equal(t, 3, strings.Count(out, "<STMTTRN>"))
+		assert.Contains(t, out, "<ACCTID>"+accountId.String()+"</ACCTID>")
+		assert.Contains(t, out, "<DTSTART>20240105</DTSTART><DTEND>20240107</DTEND>")
+		assert.Contains(t, out, "<BALAMT>-150.00</BALAMT>")
+		assert.Contains(t, out, "<CURDEF>USD</CURDEF>")
+
+		// the exported statement can be imported again
+		rows, err := parseOFX(buf.Bytes())
+		require.NoError(t, err)
+		require.Len(t, rows, 3)
+		assert.Equal(t, "Transfer : Savings", rows[1].Payee)
+	})
+
+	t.Run("converts_into_currency", func(t *testing.T) {
+		_, fxRateRepo, service := newService()
+		for _, date := range []string{"2024-01-05", "2024-01-06", "2024-01-07"} {
+			fxRateRepo.On("GetRate", mock.Anything, budgetId, "USD", "INR", date).
+				Return(&model.FxRate{BaseCurrency: "USD", QuoteCurrency: "INR", Rate: 80}, nil).Once()
+		}
+		var buf bytes.Buffer
+		require.NoError(t, service.Export(ctx, model.ExportRequest{Format: "ofx", Currency: "inr"}, &buf))
+		fxRateRepo.AssertExpectations(t)
+
+		out := buf.String()
+		assert.Contains(t, out, "<CURDEF>INR</CURDEF>")
+		assert.Contains(t, out, "<TRNAMT>-1600.00</TRNAMT>")
+		assert.Contains(t, out, "<BALAMT>-12000.00</BALAMT>")
+	})
+
+	t.Run("budgets", func(t *testing.T) {
+		mbRepo := &svcMonthlyBudgetRepo{}
+		service := NewExportService(
+			&mockTransactionRepo{},
+			mbRepo,
+			&svcAccountRepo{},
+			&svcCategoryGroupRepo{},
+			&svcTagRepo{},
+			nil,
+		)
+		mbRepo.On("GetSummaries", mock.Anything, budgetId, "2024-01", "").Return([]model.MonthlyBudgetSummary{{
+			Month:             "2024-01",
+			CategoryName:      "Groceries",
+			CategoryGroupName: "Everyday",
+			Budgeted:          200,
+			Activity:          -40,
+			Available:         160,
+		}}, nil)
+
+		start := "2024-01-15"
+		var buf bytes.Buffer
+		err := service.Export(ctx, model.ExportRequest{
+			Type:   model.ExportTypeBudgets,
+			Format: model.ExportFormatYNAB,
+			Filter: model.TransactionFilter{StartDate: &start},
+		}, &buf)
+		require.NoError(t, err)
+
+		records, err := csv.NewReader(&buf).ReadAll()
+		require.NoError(t, err)
+		assert.Equal(t, []string{
+			"Jan 2024", "Everyday: Groceries", "Everyday", "Groceries", "200.00", "-40.00", "160.00",
+		}, records[1])
+	})
+
+	t.Run("invalid_request", func(t *testing.T) {
+		service := NewExportService(nil, nil, nil, nil, nil, nil)
+		var buf bytes.Buffer
+		err := service.Export(ctx, model.ExportRequest{Type: model.ExportTypeBudgets, Format: "ofx"}, &buf)
+		assert.Error(t, err)
+		assert.Zero(t, buf.Len())
+	})
+}
@@ -0,0 +1,207 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"errors"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+
+	repository "github.com/Rishabh-Kapri/pennywise/backend/shared/db"
+	errs "github.com/Rishabh-Kapri/pennywise/backend/shared/errors"
+	"github.com/Rishabh-Kapri/pennywise/backend/shared/logger"
+	"github.com/Rishabh-Kapri/pennywise/backend/shared/model"
+	utils "github.com/Rishabh-Kapri/pennywise/backend/shared/utils"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+// fxRateImportColumns maps the accepted CSV header names to the rate fields
+var fxRateImportColumns = map[string]string{
+	"date":           "date",
+	"base":           "base",
+	"base_currency":  "base",
+	"from":           "base",
+	"quote":          "quote",
+	"quote_currency": "quote",
+	"to":             "quote",
+	"rate":           "rate",
+}
+
+type FxRateService interface {
+	GetAll(ctx context.Context, filter model.FxRateFilter) ([]model.FxRate, error)
+	Save(ctx context.Context, rate model.FxRate) (*model.FxRate, error)
+	DeleteById(ctx context.Context, id uuid.UUID) error
+	// Import saves the rates of a CSV file with date, base, quote and rate columns.
+	// Existing rates of the same pair and date are replaced, invalid rows are reported and skipped.
+	Import(ctx context.Context, data []byte) (*model.FxRateImportResult, error)
+}
+
+type fxRateService struct {
+	repo repository.FxRateRepository
+}
+
+func NewFxRateService(repo repository.FxRateRepository) FxRateService {
+	return &fxRateService{repo: repo}
+}
+
+func (s *fxRateService) GetAll(ctx context.Context, filter model.FxRateFilter) ([]model.FxRate, error) {
+	budgetId := utils.MustBudgetID(ctx)
+	for _, currency := range []*string{filter.BaseCurrency, filter.QuoteCurrency} {
+		if currency == nil {
+			continue
+		}
+		normalized, err := model.NormalizeCurrency(*currency)
+		if err != nil {
+			return nil, err
+		}
+		*currency = normalized
+	}
+	rates, err := s.repo.GetAll(ctx, budgetId, filter)
+	if err != nil {
+		return nil, errs.Wrap(errs.CodeFxRateLookupFailed, "error getting fx rates", err)
+	}
+	return rates, nil
+}
+
+func (s *fxRateService) Save(ctx context.Context, rate model.FxRate) (*model.FxRate, error) {
+	rate.BudgetID = utils.MustBudgetID(ctx)
+	if err := rate.Normalize(); err != nil {
+		return nil, err
+	}
+	saved, err := s.repo.Upsert(ctx, nil, rate)
+	if err != nil {
+		return nil, errs.Wrap(errs.CodeFxRateSaveFailed, "error saving fx rate", err)
+	}
+	return saved, nil
+}
+
+func (s *fxRateService) DeleteById(ctx context.Context, id uuid.UUID) error {
+	budgetId := utils.MustBudgetID(ctx)
+	if err := s.repo.DeleteById(ctx, budgetId, id); err != nil {
+		return errs.Wrap(errs.CodeFxRateSaveFailed, "error deleting fx rate", err)
+	}
+	return nil
+}
+
+func (s *fxRateService) Import(ctx context.Context, data []byte) (*model.FxRateImportResult, error) {
+	budgetId := utils.MustBudgetID(ctx)
+	rates, result, err := parseFxRatesCSV(data)
+	if err != nil {
+		return nil, err
+	}
+	logger.Logger(ctx).Info("importing fx rates", "rates", len(rates), "failed", len(result.Failed))
+
+	err = withTx(ctx, s.repo.GetDB(), func(tx pgx.Tx) error {
+		for _, rate := range rates {
+			rate.BudgetID = budgetId
+			if _, err := s.repo.Upsert(ctx, tx, rate); err != nil {
+				return errs.Wrap(errs.CodeFxRateSaveFailed, "error saving fx rate", err)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	result.Saved = len(rates)
+	return result, nil
+}
+
+// parseFxRatesCSV returns the valid rates of the file along with the rows that failed
+func parseFxRatesCSV(data []byte) ([]model.FxRate, *model.FxRateImportResult, error) {
+	reader := csv.NewReader(bytes.NewReader(bytes.TrimPrefix(data, []byte("\xef\xbb\xbf"))))
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, nil, errs.Wrap(errs.CodeImportParseFailed, "error reading csv header", err)
+	}
+	columns := map[string]int{}
+	for i, name := range header {
+		if field, ok := fxRateImportColumns[strings.ToLower(strings.TrimSpace(name))]; ok {
+			columns[field] = i
+		}
+	}
+	for _, field := range []string{"date", "base", "quote", "rate"} {
+		if _, ok := columns[field]; !ok {
+			return nil, nil, errs.New(errs.CodeImportParseFailed, "column %q not found in csv header", field)
+		}
+	}
+
+	result := &model.FxRateImportResult{Failed: make([]model.FxRateImportRowResult, 0)}
+	rates := make([]model.FxRate, 0)
+	for line := 2; ; line++ {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, nil, errs.Wrap(errs.CodeImportParseFailed, "error reading csv", err)
+		}
+		if len(rates)+len(result.Failed) >= maxImportRows {
+			return nil, nil, errs.New(errs.CodeInvalidArgument, "at most %d rows can be imported at once", maxImportRows)
+		}
+		field := func(name string) string {
+			if idx := columns[name]; idx < len(record) {
+				return strings.TrimSpace(record[idx])
+			}
+			return ""
+		}
+		rate := model.FxRate{
+			Date:          model.Date(field("date")),
+			BaseCurrency:  field("base"),
+			QuoteCurrency: field("quote"),
+		}
+		rate.Rate, err = strconv.ParseFloat(field("rate"), 64)
+		if err == nil {
+			err = rate.Normalize()
+		}
+		if err != nil {
+			result.Failed = append(result.Failed, model.FxRateImportRowResult{Line: line, Error: err.Error()})
+			continue
+		}
+		rates = append(rates, rate)
+	}
+	return rates, result, nil
+}
+
+// fxConverter converts amounts into a single currency. Rates are looked up once per
+// currency and date since reports convert many amounts of the same day.
+type fxConverter struct {
+	repo     repository.FxRateRepository
+	budgetId uuid.UUID
+	to       string
+	rates    map[string]float64
+}
+
+func newFxConverter(repo repository.FxRateRepository, budgetId uuid.UUID, to string) *fxConverter {
+	return &fxConverter{repo: repo, budgetId: budgetId, to: to, rates: map[string]float64{}}
+}
+
+// convert converts amount from the given currency using the latest rate on or before date.
+// An empty from currency is treated as already being in the target currency.
+func (c *fxConverter) convert(ctx context.Context, amount float64, from string, date model.Date) (float64, error) {
+	if from == "" || from == c.to {
+		return amount, nil
+	}
+	key := from + ":" + date.String()
+	rate, ok := c.rates[key]
+	if !ok {
+		fxRate, err := c.repo.GetRate(ctx, c.budgetId, from, c.to, date.String())
+		if errors.Is(err, pgx.ErrNoRows) {
+			return 0, errs.New(errs.CodeFxRateNotFound, "no %s to %s rate on or before %s", from, c.to, date)
+		}
+		if err != nil {
+			return 0, errs.Wrap(errs.CodeFxRateLookupFailed, "error getting fx rate", err)
+		}
+		rate = fxRate.Rate
+		c.rates[key] = rate
+	}
+	return math.Round(amount*rate*100) / 100, nil
+}
@@ -0,0 +1,141 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Rishabh-Kapri/pennywise/backend/shared/model"
+	utils "github.com/Rishabh-Kapri/pennywise/backend/shared/utils"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type mockFxRateRepo struct {
+	mockBaseRepo
+	mock.Mock
+}
+
+func (m *mockFxRateRepo) GetAll(
+	ctx context.Context,
+	budgetId uuid.UUID,
+	filter model.FxRateFilter,
+) ([]model.FxRate, error) {
+	args := m.Called(ctx, budgetId, filter)
+	if v := args.Get(0); v != nil {
+		return v.([]model.FxRate), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *mockFxRateRepo) GetRate(
+	ctx context.Context,
+	budgetId uuid.UUID,
+	from string,
+	to string,
+	date string,
+) (*model.FxRate, error) {
+	args := m.Called(ctx, budgetId, from, to, date)
+	if v := args.Get(0); v != nil {
+		return v.(*model.FxRate), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *mockFxRateRepo) Upsert(ctx context.Context, tx pgx.Tx, rate model.FxRate) (*model.FxRate, error) {
+	args := m.Called(ctx, tx, rate)
+	if v := args.Get(0); v != nil {
+		return v.(*model.FxRate), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *mockFxRateRepo) DeleteById(ctx context.Context, budgetId uuid.UUID, id uuid.UUID) error {
+	return m.Called(ctx, budgetId, id).Error(0)
+}
+
+func TestParseFxRatesCSV(t *testing.T) {
+	data := []byte("Date,From,To,Rate\n" +
+		"2024-01-05,usd,inr,83.12\n" +
+		"2024-01-05,EUR,INR,abc\n" +
+		"05/01/2024,EUR,INR,90\n" +
+		"2024-01-06,INR,INR,1\n" +
+		"2024-01-06,EUR,INR,90.5\n")
+
+	rates, result, err := parseFxRatesCSV(data)
+	require.NoError(t, err)
+	require.Len(t, rates, 2)
+	assert.Equal(t, model.FxRate{Date: "2024-01-05", BaseCurrency: "USD", QuoteCurrency: "INR", Rate: 83.12}, rates[0])
+	assert.Equal(t, "EUR", rates[1].BaseCurrency)
+	require.Len(t, result.Failed, 3)
+	assert.Equal(t, []int{3, 4, 5}, []int{result.Failed[0].Line, result.Failed[1].Line, result.Failed[2].Line})
+
+	_, _, err = parseFxRatesCSV([]byte("date,base,rate\n2024-01-05,USD,83\n"))
+	assert.ErrorContains(t, err, "quote")
+}
+
+func TestFxRateService_Import(t *testing.T) {
+	var mockTx pgx.Tx
+	mockWithTxSuccess(mockTx)
+	defer func() { withTx = utils.WithTx }()
+
+	budgetId := uuid.New()
+	ctx := utils.WithBudgetID(context.Background(), budgetId)
+	repo := &mockFxRateRepo{}
+	repo.On("Upsert", ctx, mockTx, mock.MatchedBy(func(rate model.FxRate) bool {
+		return rate.BudgetID == budgetId && rate.BaseCurrency == "USD" && rate.Rate == 83.5
+	})).Return(&model.FxRate{}, nil).Once()
+
+	result, err := NewFxRateService(repo).Import(ctx, []byte("date,base,quote,rate\n2024-01-05,USD,INR,83.5\n,USD,INR,1\n"))
+	require.NoError(t, err)
+	assert.Equal(t, 1, result.Saved)
+	assert.Len(t, result.Failed, 1)
+	repo.AssertExpectations(t)
+}
+
+func TestFxConverter(t *testing.T) {
+	budgetId := uuid.New()
+	ctx := context.Background()
+	repo := &mockFxRateRepo{}
+	repo.On("GetRate", ctx, budgetId, "USD", "INR", "2024-01-05").
+		Return(&model.FxRate{BaseCurrency: "USD", QuoteCurrency: "INR", Rate: 83.333}, nil).Once()
+	repo.On("GetRate", ctx, budgetId, "EUR", "INR", "2024-01-05").Return(nil, pgx.ErrNoRows).Once()
+
+	converter := newFxConverter(repo, budgetId, "INR")
+	amount, err := converter.convert(ctx, -12, "USD", "2024-01-05")
+	require.NoError(t, err)
+	assert.Equal(t, -1000.0, amount)
+
+	// the rate of a day is only looked up once
+	amount, err = converter.convert(ctx, 3, "USD", "2024-01-05")
+	require.NoError(t, err)
+	assert.Equal(t, 250.0, amount)
+
+	amount, err = converter.convert(ctx, 7, "INR", "2024-01-05")
+	require.NoError(t, err)
+	assert.Equal(t, 7.0, amount)
+
+	_, err = converter.convert(ctx, 1, "EUR", "2024-01-05")
+	assert.ErrorContains(t, err, "no EUR to INR rate")
+	repo.AssertExpectations(t)
+}
+
+func TestTransaction_ApplyOriginalAmount(t *testing.T) {
+	amount, currency := 12.0, " usd"
+	txn := model.Transaction{Amount: -1024.5, OriginalAmount: &amount, OriginalCurrency: &currency}
+	require.NoError(t, txn.ApplyOriginalAmount())
+	assert.Equal(t, -12.0, *txn.OriginalAmount)
+	assert.Equal(t, "USD", *txn.OriginalCurrency)
+	assert.Equal(t, 85.375, *txn.FxRate)
+
+	txn = model.Transaction{Amount: -10, OriginalCurrency: &currency}
+	assert.Error(t, txn.ApplyOriginalAmount())
+
+	rate := 2.0
+	txn = model.Transaction{Amount: -10, FxRate: &rate}
+	require.NoError(t, txn.ApplyOriginalAmount())
+	assert.Nil(t, txn.FxRate)
+}
@@ -0,0 +1,114 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	errs "github.com/Rishabh-Kapri/pennywise/backend/shared/errors"
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	// how long a completed response is replayed for
+	idempotencyTTL = 24 * time.Hour
+	// how long a key stays claimed while its first request runs, so a crashed
+	// request doesn't block retries forever
+	idempotencyLockTTL = 2 * time.Minute
+)
+
+type IdempotencyService interface {
+	// Begin claims key for a request with the given fingerprint. It returns nil when the request
+	// should run, or the stored response when the same request already completed.
+	// A key reused with a different fingerprint fails with CodeIdempotencyKeyReused and a key
+	// whose first request is still running fails with CodeIdempotencyInProgress.
+	Begin(ctx context.Context, key string, fingerprint string) (*IdempotentResponse, error)
+	// Complete stores the response of a claimed key for replay
+	Complete(ctx context.Context, key string, response IdempotentResponse) error
+	// Release drops the claim on a key so the request can be retried
+	Release(ctx context.Context, key string) error
+}
+
+type idempotencyService struct {
+	client *redis.Client
+}
+
+// Stores idempotency keys and their responses in Redis
+func NewIdempotencyService(redisClient *redis.Client) IdempotencyService {
+	return &idempotencyService{client: redisClient}
+}
+
+// IdempotentResponse is the response recorded for an idempotency key
+type IdempotentResponse struct {
+	Fingerprint string `json:"fingerprint"`
+	Completed   bool   `json:"completed"`
+	Status      int    `json:"status,omitempty"`
+	ContentType string `json:"contentType,omitempty"`
+	Body        []byte `json:"body,omitempty"`
+}
+
+func (s *idempotencyService) Begin(ctx context.Context, key string, fingerprint string) (*IdempotentResponse, error) {
+	redisKey := idempotencyRedisKey(key)
+	pending, err := json.Marshal(IdempotentResponse{Fingerprint: fingerprint})
+	if err != nil {
+		return nil, errs.Wrap(errs.CodeInternalError, "error encoding idempotency record", err)
+	}
+
+	claimed, err := s.client.SetNX(ctx, redisKey, pending, idempotencyLockTTL).Result()
+	if err != nil {
+		return nil, errs.Wrap(errs.CodeInternalError, "idempotency check failed", err)
+	}
+	if claimed {
+		return nil, nil
+	}
+
+	stored, err := s.client.Get(ctx, redisKey).Bytes()
+	if errors.Is(err, redis.Nil) {
+		// the claim expired in between, the caller can retry
+		return nil, errs.New(errs.CodeIdempotencyInProgress, "request with this idempotency key is in progress")
+	}
+	if err != nil {
+		return nil, errs.Wrap(errs.CodeInternalError, "idempotency check failed", err)
+	}
+	var record IdempotentResponse
+	if err := json.Unmarshal(stored, &record); err != nil {
+		return nil, errs.Wrap(errs.CodeInternalError, "error decoding idempotency record", err)
+	}
+	return checkIdempotentRecord(&record, fingerprint)
+}
+
+func (s *idempotencyService) Complete(ctx context.Context, key string, response IdempotentResponse) error {
+	response.Completed = true
+	record, err := json.Marshal(response)
+	if err != nil {
+		return errs.Wrap(errs.CodeInternalError, "error encoding idempotency record", err)
+	}
+	if err := s.client.Set(ctx, idempotencyRedisKey(key), record, idempotencyTTL).Err(); err != nil {
+		return errs.Wrap(errs.CodeInternalError, "error storing idempotent response", err)
+	}
+	return nil
+}
+
+func (s *idempotencyService) Release(ctx context.Context, key string) error {
+	if err := s.client.Del(ctx, idempotencyRedisKey(key)).Err(); err != nil {
+		return errs.Wrap(errs.CodeInternalError, "error releasing idempotency key", err)
+	}
+	return nil
+}
+
+// checkIdempotentRecord decides what a request does with a key that is already claimed
+func checkIdempotentRecord(record *IdempotentResponse, fingerprint string) (*IdempotentResponse, error) {
+	if record.Fingerprint != fingerprint {
+		return nil, errs.New(errs.CodeIdempotencyKeyReused, "idempotency key was used for a different request")
+	}
+	if !record.Completed {
+		return nil, errs.New(errs.CodeIdempotencyInProgress, "request with this idempotency key is in progress")
+	}
+	return record, nil
+}
+
+func idempotencyRedisKey(key string) string {
+	return fmt.Sprintf("idempotency:%s", key)
+}
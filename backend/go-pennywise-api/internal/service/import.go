@@ -0,0 +1,327 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	repository "github.com/Rishabh-Kapri/pennywise/backend/shared/db"
+	errs "github.com/Rishabh-Kapri/pennywise/backend/shared/errors"
+	"github.com/Rishabh-Kapri/pennywise/backend/shared/logger"
+	"github.com/Rishabh-Kapri/pennywise/backend/shared/model"
+	utils "github.com/Rishabh-Kapri/pennywise/backend/shared/utils"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+// maxImportRows caps the number of rows of a single statement
+const maxImportRows = 5000
+
+type ImportService interface {
+	GetMappings(ctx context.Context) ([]model.ImportMapping, error)
+	GetMapping(ctx context.Context, accountId uuid.UUID) (*model.ImportMapping, error)
+	SaveMapping(ctx context.Context, accountId uuid.UUID, mapping model.ImportMapping) (*model.ImportMapping, error)
+	DeleteMapping(ctx context.Context, accountId uuid.UUID) error
+	// Import parses a statement file and creates an UNAPPROVED transaction per row.
+	// Rows imported before are skipped using the dedupe hash, so re-importing a statement is a no-op.
+	Import(ctx context.Context, req model.ImportRequest, data []byte) (*model.ImportResult, error)
+}
+
+type importService struct {
+	repo               repository.ImportMappingRepository
+	accountRepo        repository.AccountRepository
+	transactionService TransactionService
+	payeeService       PayeeService
+	cipherClient       CipherClient
+}
+
+func NewImportService(
+	r repository.ImportMappingRepository,
+	accountRepo repository.AccountRepository,
+	transactionService TransactionService,
+	payeeService PayeeService,
+	cipherClient CipherClient,
+) ImportService {
+	return &importService{
+		repo:               r,
+		accountRepo:        accountRepo,
+		transactionService: transactionService,
+		payeeService:       payeeService,
+		cipherClient:       cipherClient,
+	}
+}
+
+// validateImportMapping validates the mapping and fills in the default delimiter and date format
+func validateImportMapping(mapping *model.ImportMapping) error {
+	if mapping.Delimiter == "" {
+		mapping.Delimiter = ","
+	}
+	if len([]rune(mapping.Delimiter)) != 1 {
+		return errs.New(errs.CodeInvalidArgument, "delimiter must be a single character")
+	}
+	if mapping.SkipRows < 0 {
+		return errs.New(errs.CodeInvalidArgument, "skip rows can't be negative")
+	}
+	if strings.TrimSpace(mapping.DateColumn) == "" {
+		return errs.New(errs.CodeInvalidArgument, "date column is required")
+	}
+	if mapping.DateFormat == "" {
+		mapping.DateFormat = "YYYY-MM-DD"
+	}
+	hasAmount := mapping.AmountColumn != nil && *mapping.AmountColumn != ""
+	hasInflowOutflow := (mapping.InflowColumn != nil && *mapping.InflowColumn != "") ||
+		(mapping.OutflowColumn != nil && *mapping.OutflowColumn != "")
+	if !hasAmount && !hasInflowOutflow {
+		return errs.New(errs.CodeInvalidArgument, "amount column or inflow/outflow columns are required")
+	}
+	if hasAmount && hasInflowOutflow {
+		return errs.New(errs.CodeInvalidArgument, "use either the amount column or inflow/outflow columns")
+	}
+	return nil
+}
+
+func (s *importService) GetMappings(ctx context.Context) ([]model.ImportMapping, error) {
+	budgetId := utils.MustBudgetID(ctx)
+	return s.repo.GetAll(ctx, budgetId)
+}
+
+func (s *importService) GetMapping(ctx context.Context, accountId uuid.UUID) (*model.ImportMapping, error) {
+	budgetId := utils.MustBudgetID(ctx)
+	mapping, err := s.repo.GetByAccountId(ctx, budgetId, accountId)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, errs.New(errs.CodeImportMappingLookupFailed, "import mapping not found for account id %v", accountId)
+		}
+		return nil, errs.Wrap(errs.CodeImportMappingLookupFailed, "error getting import mapping", err)
+	}
+	return mapping, nil
+}
+
+func (s *importService) SaveMapping(
+	ctx context.Context,
+	accountId uuid.UUID,
+	mapping model.ImportMapping,
+) (*model.ImportMapping, error) {
+	budgetId := utils.MustBudgetID(ctx)
+	mapping.BudgetID = budgetId
+	mapping.AccountID = accountId
+	if err := validateImportMapping(&mapping); err != nil {
+		return nil, err
+	}
+	if _, err := s.accountRepo.GetById(ctx, nil, budgetId, accountId); err != nil {
+		return nil, errs.Wrap(errs.CodeAccountLookupFailed, "error getting account", err)
+	}
+
+	saved, err := s.repo.Upsert(ctx, mapping)
+	if err != nil {
+		return nil, errs.Wrap(errs.CodeImportMappingSaveFailed, "error saving import mapping", err)
+	}
+	return saved, nil
+}
+
+func (s *importService) DeleteMapping(ctx context.Context, accountId uuid.UUID) error {
+	budgetId := utils.MustBudgetID(ctx)
+	if err := s.repo.DeleteByAccountId(ctx, budgetId, accountId); err != nil {
+		return errs.Wrap(errs.CodeImportMappingDeleteFailed, "error deleting import mapping", err)
+	}
+	return nil
+}
+
+func (s *importService) Import(
+	ctx context.Context,
+	req model.ImportRequest,
+	data []byte,
+) (*model.ImportResult, error) {
+	budgetId := utils.MustBudgetID(ctx)
+	log := logger.Logger(ctx).With("accountId", req.AccountID, "format", req.Format)
+	log.Info("importing statement", "bytes", len(data), "predict", req.Predict)
+
+	if req.AccountID == uuid.Nil {
+		return nil, errs.New(errs.CodeInvalidArgument, "accountId is required")
+	}
+	if len(data) == 0 {
+		return nil, errs.New(errs.CodeInvalidArgument, "statement file is empty")
+	}
+	account, err := s.accountRepo.GetById(ctx, nil, budgetId, req.AccountID)
+	if err != nil {
+		return nil, errs.Wrap(errs.CodeAccountLookupFailed, "error getting account", err)
+	}
+
+	rows, err := s.parse(ctx, req, data)
+	if err != nil {
+		return nil, err
+	}
+	if len(rows) > maxImportRows {
+		return nil, errs.New(errs.CodeInvalidArgument, "at most %d rows can be imported at once", maxImportRows)
+	}
+
+	payees, err := s.payeeService.GetAll(ctx)
+	if err != nil {
+		return nil, errs.Wrap(errs.CodePayeeLookupFailed, "error getting payees", err)
+	}
+	payeeIds := make(map[string]uuid.UUID, len(payees))
+	for _, payee := range payees {
+		payeeIds[strings.ToLower(payee.Name)] = payee.ID
+	}
+
+	result := &model.ImportResult{Rows: make([]model.ImportRowResult, 0, len(rows))}
+	occurrences := map[string]int{}
+	for _, row := range rows {
+		rowResult := model.ImportRowResult{Line: row.Line, Date: row.Date, Amount: row.Amount, Payee: row.Payee}
+		if row.Err != nil {
+			rowResult.Status = model.ImportRowFailed
+			rowResult.Error = row.Err.Error()
+			result.Failed++
+			result.Rows = append(result.Rows, rowResult)
+			continue
+		}
+
+		hash := importDedupeHash(req.AccountID, row, occurrences)
+		var created []model.Transaction
+		txn, err := s.buildImportTransaction(ctx, budgetId, *account, row, hash, req.Predict, payeeIds)
+		if err == nil {
+			created, err = s.transactionService.Create(ctx, txn)
+		}
+		var apiErr *errs.Error
+		switch {
+		case utils.IsUniqueViolation(err),
+			errors.As(err, &apiErr) && apiErr.Code == errs.CodeTransactionNotCreated:
+			rowResult.Status = model.ImportRowSkipped
+			result.Skipped++
+		case err != nil:
+			rowResult.Status = model.ImportRowFailed
+			rowResult.Error = err.Error()
+			result.Failed++
+		default:
+			rowResult.Status = model.ImportRowCreated
+			if len(created) > 0 {
+				rowResult.TransactionID = &created[0].ID
+			}
+			result.Created++
+		}
+		result.Rows = append(result.Rows, rowResult)
+	}
+
+	log.Info("statement imported", "created", result.Created, "skipped", result.Skipped, "failed", result.Failed)
+	return result, nil
+}
+
+// parse parses the statement into rows. CSV statements use the mapping of the request,
+// saving it when asked to, or the mapping saved for the account.
+func (s *importService) parse(ctx context.Context, req model.ImportRequest, data []byte) ([]importRow, error) {
+	switch model.ImportFormat(strings.ToUpper(string(req.Format))) {
+	case model.ImportFormatCSV:
+		mapping, err := s.csvMapping(ctx, req)
+		if err != nil {
+			return nil, err
+		}
+		return parseCSV(data, *mapping)
+	case model.ImportFormatOFX:
+		return parseOFX(data)
+	case model.ImportFormatQIF:
+		layout := ""
+		if req.Mapping != nil && req.Mapping.DateFormat != "" {
+			layout = dateFormatToLayout(req.Mapping.DateFormat)
+		}
+		return parseQIF(data, layout)
+	default:
+		return nil, errs.New(errs.CodeInvalidArgument, "unsupported import format %q", req.Format)
+	}
+}
+
+func (s *importService) csvMapping(ctx context.Context, req model.ImportRequest) (*model.ImportMapping, error) {
+	if req.Mapping == nil {
+		return s.GetMapping(ctx, req.AccountID)
+	}
+	if req.SaveMapping {
+		return s.SaveMapping(ctx, req.AccountID, *req.Mapping)
+	}
+	mapping := *req.Mapping
+	if err := validateImportMapping(&mapping); err != nil {
+		return nil, err
+	}
+	return &mapping, nil
+}
+
+// importDedupeHash hashes the row the same way the email pipeline does. Identical rows
+// within the same statement are told apart by their occurrence, which keeps re-imports stable.
+func importDedupeHash(accountId uuid.UUID, row importRow, occurrences map[string]int) string {
+	amount := fmt.Sprintf("%.2f", row.Amount)
+	key := row.Date + amount + row.DedupeKey
+	text := row.DedupeKey
+	if n := occurrences[key]; n > 0 {
+		text = fmt.Sprintf("%s#%d", text, n)
+	}
+	occurrences[key]++
+	return utils.Hash(accountId.String() + row.Date + amount + text)
+}
+
+// buildImportTransaction builds the transaction for a row. When predict is set cipher picks the
+// payee and category, otherwise, or when the prediction fails, the payee is matched by name and
+// created if missing.
+func (s *importService) buildImportTransaction(
+	ctx context.Context,
+	budgetId uuid.UUID,
+	account model.Account,
+	row importRow,
+	hash string,
+	predict bool,
+	payeeIds map[string]uuid.UUID,
+) (model.Transaction, error) {
+	rawText := row.RawText
+	txn := model.Transaction{
+		BudgetID:    budgetId,
+		AccountID:   &account.ID,
+		Date:        model.Date(row.Date),
+		Amount:      row.Amount,
+		Note:        row.Memo,
+		Status:      model.TransactionStatusUnapproved,
+		DedupeHash:  &hash,
+		RawBankText: &rawText,
+	}
+
+	payeeName := row.Payee
+	if predict && s.cipherClient != nil {
+		prediction, err := s.cipherClient.Predict(ctx, PredictRequest{
+			EmailText: row.RawText,
+			Amount:    row.Amount,
+			ExtractedInputs: &PredictExtractedInputs{
+				Merchant: row.Payee,
+				Account:  account.Name,
+				Date:     row.Date,
+			},
+		})
+		if err != nil {
+			logger.Logger(ctx).Warn("cipher prediction failed, matching payee by name", "line", row.Line, "error", err)
+		} else if prediction != nil {
+			if prediction.PayeeID != uuid.Nil {
+				txn.PayeeID = &prediction.PayeeID
+			} else if prediction.Payee != "" {
+				payeeName = prediction.Payee
+			}
+			if prediction.CategoryID != uuid.Nil {
+				txn.CategoryID = &prediction.CategoryID
+			}
+		}
+	}
+	if txn.PayeeID != nil {
+		return txn, nil
+	}
+
+	if payeeName == "" {
+		payeeName = "Unknown Payee"
+	}
+	payeeId, ok := payeeIds[strings.ToLower(payeeName)]
+	if !ok {
+		payee, err := s.payeeService.Create(ctx, model.Payee{Name: payeeName})
+		if err != nil {
+			return txn, errs.Wrap(errs.CodePayeeCreateFailed, "error creating payee", err)
+		}
+		payeeId = payee.ID
+		payeeIds[strings.ToLower(payeeName)] = payeeId
+	}
+	txn.PayeeID = &payeeId
+	return txn, nil
+}
@@ -0,0 +1,334 @@
+package service
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"html"
+	"io"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	errs "github.com/Rishabh-Kapri/pennywise/backend/shared/errors"
+	"github.com/Rishabh-Kapri/pennywise/backend/shared/model"
+)
+
+const importDateLayout = "2006-01-02"
+
+// importRow is a single normalised statement row
+type importRow struct {
+	Line    int
+	Date    string
+	Amount  float64
+	Payee   string
+	Memo    string
+	RawText string
+	// DedupeKey identifies the row within the statement: the bank's FITID for OFX, the raw text otherwise
+	DedupeKey string
+	// Err is set when the row could not be parsed, the rest of the statement is still imported
+	Err error
+}
+
+var dateFormatReplacer = strings.NewReplacer(
+	"YYYY", "2006",
+	"YY", "06",
+	"MMM", "Jan",
+	"MM", "01",
+	"M", "1",
+	"DD", "02",
+	"D", "2",
+)
+
+// dateFormatToLayout converts a YYYY/YY/MMM/MM/M/DD/D date format into a go time layout
+func dateFormatToLayout(format string) string {
+	if format == "" {
+		return importDateLayout
+	}
+	return dateFormatReplacer.Replace(strings.ToUpper(format))
+}
+
+// parseImportAmount parses a statement amount. Currency symbols and thousands separators
+// are ignored and amounts in parentheses are negative.
+func parseImportAmount(value string) (float64, error) {
+	value = strings.TrimSpace(value)
+	negative := false
+	if strings.HasPrefix(value, "(") && strings.HasSuffix(value, ")") {
+		negative = true
+		value = strings.Trim(value, "()")
+	}
+	cleaned := strings.Map(func(r rune) rune {
+		if (r >= '0' && r <= '9') || r == '.' || r == '-' || r == '+' {
+			return r
+		}
+		return -1
+	}, value)
+	if cleaned == "" {
+		return 0, errs.New(errs.CodeInvalidArgument, "invalid amount %q", value)
+	}
+	amount, err := strconv.ParseFloat(cleaned, 64)
+	if err != nil {
+		return 0, errs.Wrap(errs.CodeInvalidArgument, "invalid amount "+strconv.Quote(value), err)
+	}
+	if negative {
+		amount = -math.Abs(amount)
+	}
+	return math.Round(amount*100) / 100, nil
+}
+
+// parseCSV parses a CSV statement using the column mapping of the account
+func parseCSV(data []byte, mapping model.ImportMapping) ([]importRow, error) {
+	reader := csv.NewReader(bytes.NewReader(bytes.TrimPrefix(data, []byte("\xef\xbb\xbf"))))
+	reader.Comma = []rune(mapping.Delimiter)[0]
+	reader.FieldsPerRecord = -1
+	reader.LazyQuotes = true
+	reader.TrimLeadingSpace = true
+
+	for i := 0; i < mapping.SkipRows; i++ {
+		if _, err := reader.Read(); err != nil {
+			return nil, errs.Wrap(errs.CodeImportParseFailed, "error skipping csv rows", err)
+		}
+	}
+	header, err := reader.Read()
+	if err != nil {
+		return nil, errs.Wrap(errs.CodeImportParseFailed, "error reading csv header", err)
+	}
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	column := func(name *string) (int, error) {
+		if name == nil || *name == "" {
+			return -1, nil
+		}
+		idx, ok := columns[strings.ToLower(strings.TrimSpace(*name))]
+		if !ok {
+			return -1, errs.New(errs.CodeImportParseFailed, "column %q not found in csv header", *name)
+		}
+		return idx, nil
+	}
+
+	var dateIdx, amountIdx, inflowIdx, outflowIdx, payeeIdx, memoIdx int
+	for _, c := range []struct {
+		idx  *int
+		name *string
+	}{
+		{&dateIdx, &mapping.DateColumn},
+		{&amountIdx, mapping.AmountColumn},
+		{&inflowIdx, mapping.InflowColumn},
+		{&outflowIdx, mapping.OutflowColumn},
+		{&payeeIdx, mapping.PayeeColumn},
+		{&memoIdx, mapping.MemoColumn},
+	} {
+		if *c.idx, err = column(c.name); err != nil {
+			return nil, err
+		}
+	}
+	layout := dateFormatToLayout(mapping.DateFormat)
+
+	var rows []importRow
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, errs.Wrap(errs.CodeImportParseFailed, "error reading csv row", err)
+		}
+		line, _ := reader.FieldPos(0)
+		cell := func(idx int) string {
+			if idx < 0 || idx >= len(record) {
+				return ""
+			}
+			return strings.TrimSpace(record[idx])
+		}
+
+		rawText := strings.Join(record, string(reader.Comma))
+		row := importRow{
+			Line:      line,
+			Payee:     cell(payeeIdx),
+			Memo:      cell(memoIdx),
+			RawText:   rawText,
+			DedupeKey: rawText,
+		}
+		date, err := time.Parse(layout, cell(dateIdx))
+		if err != nil {
+			row.Err = errs.Wrap(errs.CodeInvalidArgument, "invalid date "+strconv.Quote(cell(dateIdx)), err)
+			rows = append(rows, row)
+			continue
+		}
+		row.Date = date.Format(importDateLayout)
+		row.Amount, row.Err = csvRowAmount(cell(amountIdx), cell(inflowIdx), cell(outflowIdx), amountIdx >= 0)
+		if mapping.InvertAmount {
+			row.Amount = -row.Amount
+		}
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
+
+// csvRowAmount returns the signed amount of a csv row, either from the amount column
+// or as inflow minus outflow where empty cells count as zero
+func csvRowAmount(amount, inflow, outflow string, hasAmountColumn bool) (float64, error) {
+	if hasAmountColumn {
+		return parseImportAmount(amount)
+	}
+	total := 0.0
+	if inflow != "" {
+		in, err := parseImportAmount(inflow)
+		if err != nil {
+			return 0, err
+		}
+		total += math.Abs(in)
+	}
+	if outflow != "" {
+		out, err := parseImportAmount(outflow)
+		if err != nil {
+			return 0, err
+		}
+		total -= math.Abs(out)
+	}
+	return math.Round(total*100) / 100, nil
+}
+
+var (
+	ofxTransactionRe = regexp.MustCompile(`(?is)<STMTTRN>(.*?)</STMTTRN>`)
+	ofxFieldRe       = regexp.MustCompile(`(?i)<([A-Z0-9.]+)>([^<\r\n]*)`)
+)
+
+// parseOFX parses the STMTTRN blocks of an OFX statement. Both the SGML (1.x) and
+// XML (2.x) flavours work since only leaf elements are read.
+func parseOFX(data []byte) ([]importRow, error) {
+	text := string(data)
+	matches := ofxTransactionRe.FindAllStringSubmatchIndex(text, -1)
+	if len(matches) == 0 {
+		return nil, errs.New(errs.CodeImportParseFailed, "no transactions found in ofx file")
+	}
+
+	rows := make([]importRow, 0, len(matches))
+	for _, match := range matches {
+		fields := map[string]string{}
+		for _, field := range ofxFieldRe.FindAllStringSubmatch(text[match[2]:match[3]], -1) {
+			fields[strings.ToUpper(field[1])] = html.UnescapeString(strings.TrimSpace(field[2]))
+		}
+
+		rawText := strings.TrimSpace(fields["NAME"] + " " + fields["MEMO"])
+		row := importRow{
+			Line:      strings.Count(text[:match[0]], "\n") + 1,
+			Payee:     fields["NAME"],
+			Memo:      fields["MEMO"],
+			RawText:   rawText,
+			DedupeKey: rawText,
+		}
+		if fields["FITID"] != "" {
+			row.DedupeKey = fields["FITID"]
+		}
+
+		posted := fields["DTPOSTED"]
+		if len(posted) < 8 {
+			row.Err = errs.New(errs.CodeInvalidArgument, "invalid date %q", posted)
+			rows = append(rows, row)
+			continue
+		}
+		date, err := time.Parse("20060102", posted[:8])
+		if err != nil {
+			row.Err = errs.Wrap(errs.CodeInvalidArgument, "invalid date "+strconv.Quote(posted), err)
+			rows = append(rows, row)
+			continue
+		}
+		row.Date = date.Format(importDateLayout)
+		// some banks use a decimal comma
+		amount := fields["TRNAMT"]
+		if !strings.Contains(amount, ".") {
+			amount = strings.ReplaceAll(amount, ",", ".")
+		}
+		row.Amount, row.Err = parseImportAmount(amount)
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
+
+// qifDateLayouts are tried in order when no date format is given. QIF dates are
+// month first and may use an apostrophe before the year, e.g. 1/ 5'24.
+var qifDateLayouts = []string{"1/2/2006", "1/2/06", "2006-01-02", "2.1.2006"}
+
+func parseQIFDate(value string, layout string) (string, error) {
+	if layout != "" {
+		date, err := time.Parse(layout, value)
+		if err != nil {
+			return "", errs.Wrap(errs.CodeInvalidArgument, "invalid date "+strconv.Quote(value), err)
+		}
+		return date.Format(importDateLayout), nil
+	}
+	normalized := strings.ReplaceAll(strings.ReplaceAll(value, "'", "/"), " ", "")
+	for _, l := range qifDateLayouts {
+		if date, err := time.Parse(l, normalized); err == nil {
+			return date.Format(importDateLayout), nil
+		}
+	}
+	return "", errs.New(errs.CodeInvalidArgument, "invalid date %q", value)
+}
+
+// parseQIF parses a QIF statement. Records are terminated by ^ and only the
+// date, amount, payee, memo and check number fields are read.
+func parseQIF(data []byte, layout string) ([]importRow, error) {
+	var (
+		rows    []importRow
+		row     importRow
+		date    string
+		amount  string
+		number  string
+		hasData bool
+	)
+	flush := func() {
+		if !hasData {
+			return
+		}
+		row.RawText = strings.TrimSpace(row.Payee + " " + row.Memo)
+		row.DedupeKey = strings.TrimSpace(number + " " + row.RawText)
+		var err error
+		if row.Date, err = parseQIFDate(date, layout); err != nil {
+			row.Err = err
+		} else {
+			row.Amount, row.Err = parseImportAmount(amount)
+		}
+		rows = append(rows, row)
+		row, date, amount, number, hasData = importRow{}, "", "", "", false
+	}
+
+	for i, line := range strings.Split(string(bytes.TrimPrefix(data, []byte("\xef\xbb\xbf"))), "\n") {
+		line = strings.TrimRight(line, "\r")
+		if line == "" || line[0] == '!' {
+			continue
+		}
+		if line[0] == '^' {
+			flush()
+			continue
+		}
+		if !hasData {
+			row.Line = i + 1
+			hasData = true
+		}
+		value := strings.TrimSpace(line[1:])
+		switch line[0] {
+		case 'D':
+			date = value
+		case 'T', 'U':
+			amount = value
+		case 'P':
+			row.Payee = value
+		case 'M':
+			row.Memo = value
+		case 'N':
+			number = value
+		}
+	}
+	flush()
+
+	if len(rows) == 0 {
+		return nil, errs.New(errs.CodeImportParseFailed, "no transactions found in qif file")
+	}
+	return rows, nil
+}
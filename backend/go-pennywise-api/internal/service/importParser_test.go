@@ -0,0 +1,174 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/Rishabh-Kapri/pennywise/backend/shared/model"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func strPtr(s string) *string { return &s }
+
+func TestParseImportAmount(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    float64
+		wantErr bool
+	}{
+		{value: "12.50", want: 12.5},
+		{value: "-1,234.56", want: -1234.56},
+		{value: "₹ 2,000", want: 2000},
+		{value: "(45.10)", want: -45.1},
+		{value: "", wantErr: true},
+		{value: "abc", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			got, err := parseImportAmount(tt.value)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestDateFormatToLayout(t *testing.T) {
+	assert.Equal(t, "2006-01-02", dateFormatToLayout(""))
+	assert.Equal(t, "02/01/2006", dateFormatToLayout("DD/MM/YYYY"))
+	assert.Equal(t, "1/2/06", dateFormatToLayout("M/D/YY"))
+	assert.Equal(t, "02 Jan 2006", dateFormatToLayout("dd MMM yyyy"))
+}
+
+func TestParseCSV(t *testing.T) {
+	t.Run("amount_column", func(t *testing.T) {
+		data := "Exported on 2024-02-01\n" +
+			"Date,Description,Amount,Ref\n" +
+			"31/01/2024,\"Coffee, Beans\",-4.50,A1\n" +
+			"01/02/2024,Salary,\"1,000.00\",A2\n" +
+			"bad-date,Oops,1,A3\n"
+		rows, err := parseCSV([]byte(data), model.ImportMapping{
+			Delimiter:    ",",
+			SkipRows:     1,
+			DateColumn:   "date",
+			DateFormat:   "DD/MM/YYYY",
+			AmountColumn: strPtr("Amount"),
+			PayeeColumn:  strPtr("Description"),
+			MemoColumn:   strPtr("Ref"),
+		})
+		require.NoError(t, err)
+		require.Len(t, rows, 3)
+
+		assert.Equal(t, 3, rows[0].Line)
+		assert.Equal(t, "2024-01-31", rows[0].Date)
+		assert.Equal(t, -4.5, rows[0].Amount)
+		assert.Equal(t, "Coffee, Beans", rows[0].Payee)
+		assert.Equal(t, "A1", rows[0].Memo)
+		assert.NoError(t, rows[0].Err)
+
+		assert.Equal(t, 1000.0, rows[1].Amount)
+		assert.Error(t, rows[2].Err)
+	})
+
+	t.Run("inflow_outflow_columns", func(t *testing.T) {
+		data := "Date;Payee;Debit;Credit\n2024-03-01;Rent;500;\n2024-03-02;Refund;;20.25\n"
+		rows, err := parseCSV([]byte(data), model.ImportMapping{
+			Delimiter:     ";",
+			DateColumn:    "Date",
+			OutflowColumn: strPtr("Debit"),
+			InflowColumn:  strPtr("Credit"),
+			PayeeColumn:   strPtr("Payee"),
+		})
+		require.NoError(t, err)
+		require.Len(t, rows, 2)
+		assert.Equal(t, -500.0, rows[0].Amount)
+		assert.Equal(t, 20.25, rows[1].Amount)
+	})
+
+	t.Run("invert_amount", func(t *testing.T) {
+		data := "Date,Amount\n2024-03-01,15\n"
+		rows, err := parseCSV([]byte(data), model.ImportMapping{
+			Delimiter:    ",",
+			DateColumn:   "Date",
+			AmountColumn: strPtr("Amount"),
+			InvertAmount: true,
+		})
+		require.NoError(t, err)
+		assert.Equal(t, -15.0, rows[0].Amount)
+	})
+
+	t.Run("missing_column", func(t *testing.T) {
+		_, err := parseCSV([]byte("Date,Amount\n"), model.ImportMapping{
+			Delimiter:    ",",
+			DateColumn:   "Posted",
+			AmountColumn: strPtr("Amount"),
+		})
+		assert.Error(t, err)
+	})
+}
+
+func TestParseOFX(t *testing.T) {
+	t.Run("sgml", func(t *testing.T) {
+		data := "OFXHEADER:100\nDATA:OFXSGML\n\n<OFX>\n<BANKTRANLIST>\n" +
+			"<STMTTRN>\n<TRNTYPE>DEBIT\n<DTPOSTED>20240105120000[-5:EST]\n<TRNAMT>-23.40\n" +
+			"<FITID>2024010501\n<NAME>GROCER &amp; CO\n<MEMO>POS purchase\n</STMTTRN>\n" +
+			"<STMTTRN>\n<TRNTYPE>CREDIT\n<DTPOSTED>20240106\n<TRNAMT>100,00\n<FITID>2024010601\n" +
+			"<NAME>Transfer in\n</STMTTRN>\n</BANKTRANLIST>\n</OFX>\n"
+		rows, err := parseOFX([]byte(data))
+		require.NoError(t, err)
+		require.Len(t, rows, 2)
+
+		assert.Equal(t, "2024-01-05", rows[0].Date)
+		assert.Equal(t, -23.4, rows[0].Amount)
+		assert.Equal(t, "GROCER & CO", rows[0].Payee)
+		assert.Equal(t, "POS purchase", rows[0].Memo)
+		assert.Equal(t, "2024010501", rows[0].DedupeKey)
+		assert.Equal(t, 100.0, rows[1].Amount)
+	})
+
+	t.Run("xml", func(t *testing.T) {
+		data := `<?xml version="1.0"?><OFX><STMTTRN><DTPOSTED>20240201</DTPOSTED>` +
+			`<TRNAMT>-9.99</TRNAMT><FITID>X1</FITID><NAME>Streaming</NAME></STMTTRN></OFX>`
+		rows, err := parseOFX([]byte(data))
+		require.NoError(t, err)
+		require.Len(t, rows, 1)
+		assert.Equal(t, "2024-02-01", rows[0].Date)
+		assert.Equal(t, -9.99, rows[0].Amount)
+		assert.Equal(t, "Streaming", rows[0].Payee)
+	})
+
+	t.Run("no_transactions", func(t *testing.T) {
+		_, err := parseOFX([]byte("<OFX></OFX>"))
+		assert.Error(t, err)
+	})
+}
+
+func TestParseQIF(t *testing.T) {
+	data := "!Type:Bank\nD1/ 5'24\nT-1,250.00\nPLandlord\nMJanuary rent\nN1001\n^\n" +
+		"D01/06/2024\nU42.10\nPEmployer\n^\nDnot-a-date\nT1\n^\n"
+
+	rows, err := parseQIF([]byte(data), "")
+	require.NoError(t, err)
+	require.Len(t, rows, 3)
+
+	assert.Equal(t, 2, rows[0].Line)
+	assert.Equal(t, "2024-01-05", rows[0].Date)
+	assert.Equal(t, -1250.0, rows[0].Amount)
+	assert.Equal(t, "Landlord", rows[0].Payee)
+	assert.Equal(t, "January rent", rows[0].Memo)
+	assert.Equal(t, "1001 Landlord January rent", rows[0].DedupeKey)
+
+	assert.Equal(t, "2024-01-06", rows[1].Date)
+	assert.Equal(t, 42.1, rows[1].Amount)
+	assert.Error(t, rows[2].Err)
+
+	t.Run("date_format", func(t *testing.T) {
+		rows, err := parseQIF([]byte("D05/01/2024\nT-1\n^\n"), dateFormatToLayout("DD/MM/YYYY"))
+		require.NoError(t, err)
+		assert.Equal(t, "2024-01-05", rows[0].Date)
+	})
+}
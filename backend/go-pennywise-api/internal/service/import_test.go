@@ -0,0 +1,224 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/Rishabh-Kapri/pennywise/backend/shared/model"
+	utils "github.com/Rishabh-Kapri/pennywise/backend/shared/utils"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type mockImportMappingRepo struct {
+	mockBaseRepo
+	mock.Mock
+}
+
+func (m *mockImportMappingRepo) GetAll(ctx context.Context, budgetId uuid.UUID) ([]model.ImportMapping, error) {
+	args := m.Called(ctx, budgetId)
+	if obj := args.Get(0); obj != nil {
+		return obj.([]model.ImportMapping), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *mockImportMappingRepo) GetByAccountId(
+	ctx context.Context,
+	budgetId uuid.UUID,
+	accountId uuid.UUID,
+) (*model.ImportMapping, error) {
+	args := m.Called(ctx, budgetId, accountId)
+	if obj := args.Get(0); obj != nil {
+		return obj.(*model.ImportMapping), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *mockImportMappingRepo) Upsert(ctx context.Context, mapping model.ImportMapping) (*model.ImportMapping, error) {
+	args := m.Called(ctx, mapping)
+	if obj := args.Get(0); obj != nil {
+		return obj.(*model.ImportMapping), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *mockImportMappingRepo) DeleteByAccountId(ctx context.Context, budgetId uuid.UUID, accountId uuid.UUID) error {
+	args := m.Called(ctx, budgetId, accountId)
+	return args.Error(0)
+}
+
+// mockPayeeService only implements the methods the importer needs
+type mockPayeeService struct {
+	PayeeService
+	mock.Mock
+}
+
+func (m *mockPayeeService) GetAll(ctx context.Context) ([]model.Payee, error) {
+	args := m.Called(ctx)
+	if obj := args.Get(0); obj != nil {
+		return obj.([]model.Payee), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *mockPayeeService) Create(ctx context.Context, payee model.Payee) (*model.Payee, error) {
+	args := m.Called(ctx, payee)
+	if obj := args.Get(0); obj != nil {
+		return obj.(*model.Payee), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func TestValidateImportMapping(t *testing.T) {
+	t.Run("defaults", func(t *testing.T) {
+		mapping := model.ImportMapping{DateColumn: "Date", AmountColumn: strPtr("Amount")}
+		require.NoError(t, validateImportMapping(&mapping))
+		assert.Equal(t, ",", mapping.Delimiter)
+		assert.Equal(t, "YYYY-MM-DD", mapping.DateFormat)
+	})
+	t.Run("missing_amount", func(t *testing.T) {
+		mapping := model.ImportMapping{DateColumn: "Date"}
+		assert.Error(t, validateImportMapping(&mapping))
+	})
+	t.Run("amount_and_inflow", func(t *testing.T) {
+		mapping := model.ImportMapping{DateColumn: "Date", AmountColumn: strPtr("Amount"), InflowColumn: strPtr("In")}
+		assert.Error(t, validateImportMapping(&mapping))
+	})
+	t.Run("multi_character_delimiter", func(t *testing.T) {
+		mapping := model.ImportMapping{DateColumn: "Date", AmountColumn: strPtr("Amount"), Delimiter: "||"}
+		assert.Error(t, validateImportMapping(&mapping))
+	})
+}
+
+func TestImportDedupeHash(t *testing.T) {
+	accountId := uuid.New()
+	row := importRow{Date: "2024-01-05", Amount: -4.5, DedupeKey: "Coffee"}
+	occurrences := map[string]int{}
+
+	first := importDedupeHash(accountId, row, occurrences)
+	second := importDedupeHash(accountId, row, occurrences)
+
+	// the first occurrence hashes like the email pipeline does
+	assert.Equal(t, utils.Hash(accountId.String()+"2024-01-05"+fmt.Sprintf("%.2f", -4.5)+"Coffee"), first)
+	assert.NotEqual(t, first, second)
+	assert.Equal(t, second, importDedupeHash(accountId, row, map[string]int{row.Date + "-4.50" + "Coffee": 1}))
+}
+
+func TestImport(t *testing.T) {
+	budgetId := uuid.New()
+	ctx := utils.WithBudgetID(context.Background(), budgetId)
+	accountId := uuid.New()
+	account := &model.Account{ID: accountId, Name: "Checking", Type: "checking"}
+	grocerId := uuid.New()
+	csv := "Date,Payee,Amount\n2024-01-05,Grocer,-20\n2024-01-06,New Shop,-5\n2024-01-07,Grocer,-20\nbad,Grocer,1\n"
+	mapping := &model.ImportMapping{
+		AccountID:    accountId,
+		Delimiter:    ",",
+		DateColumn:   "Date",
+		DateFormat:   "YYYY-MM-DD",
+		AmountColumn: strPtr("Amount"),
+		PayeeColumn:  strPtr("Payee"),
+	}
+
+	t.Run("reports_created_skipped_and_failed", func(t *testing.T) {
+		repo := &mockImportMappingRepo{}
+		accountRepo := &mockAccountRepo{}
+		txnService := &mockTxnService{}
+		payeeService := &mockPayeeService{}
+		service := NewImportService(repo, accountRepo, txnService, payeeService, nil)
+
+		accountRepo.On("GetById", mock.Anything, nil, budgetId, accountId).Return(account, nil).Once()
+		repo.On("GetByAccountId", mock.Anything, budgetId, accountId).Return(mapping, nil).Once()
+		payeeService.On("GetAll", mock.Anything).Return([]model.Payee{{ID: grocerId, Name: "grocer"}}, nil).Once()
+		newShopId := uuid.New()
+		payeeService.On("Create", mock.Anything, model.Payee{Name: "New Shop"}).
+			Return(&model.Payee{ID: newShopId, Name: "New Shop"}, nil).
+			Once()
+
+		createdId := uuid.New()
+		txnService.On("Create", mock.Anything, mock.MatchedBy(func(txn model.Transaction) bool {
+			return txn.Date == "2024-01-05" && *txn.PayeeID == grocerId &&
+				txn.Status == model.TransactionStatusUnapproved && txn.DedupeHash != nil
+		})).Return([]model.Transaction{{ID: createdId}}, nil).Once()
+		txnService.On("Create", mock.Anything, mock.MatchedBy(func(txn model.Transaction) bool {
+			return txn.Date == "2024-01-06" && *txn.PayeeID == newShopId
+		})).Return(nil, &pgconn.PgError{Code: "23505"}).Once()
+		txnService.On("Create", mock.Anything, mock.MatchedBy(func(txn model.Transaction) bool {
+			return txn.Date == "2024-01-07"
+		})).Return(nil, assert.AnError).Once()
+
+		result, err := service.Import(ctx, model.ImportRequest{AccountID: accountId, Format: "csv"}, []byte(csv))
+		require.NoError(t, err)
+		assert.Equal(t, 1, result.Created)
+		assert.Equal(t, 1, result.Skipped)
+		assert.Equal(t, 2, result.Failed)
+		require.Len(t, result.Rows, 4)
+		assert.Equal(t, model.ImportRowCreated, result.Rows[0].Status)
+		assert.Equal(t, &createdId, result.Rows[0].TransactionID)
+		assert.Equal(t, model.ImportRowSkipped, result.Rows[1].Status)
+		assert.Equal(t, model.ImportRowFailed, result.Rows[2].Status)
+		assert.Equal(t, model.ImportRowFailed, result.Rows[3].Status)
+		txnService.AssertExpectations(t)
+		payeeService.AssertExpectations(t)
+	})
+
+	t.Run("uses_cipher_predictions", func(t *testing.T) {
+		accountRepo := &mockAccountRepo{}
+		txnService := &mockTxnService{}
+		payeeService := &mockPayeeService{}
+		cipher := &mockCipherClient{}
+		service := NewImportService(&mockImportMappingRepo{}, accountRepo, txnService, payeeService, cipher)
+
+		categoryId := uuid.New()
+		predictedPayeeId := uuid.New()
+		accountRepo.On("GetById", mock.Anything, nil, budgetId, accountId).Return(account, nil).Once()
+		payeeService.On("GetAll", mock.Anything).Return([]model.Payee{}, nil).Once()
+		cipher.On("Predict", mock.Anything, mock.MatchedBy(func(req PredictRequest) bool {
+			return req.ExtractedInputs.Merchant == "GROCER" && req.ExtractedInputs.Account == "Checking" &&
+				req.Amount == -20
+		})).Return(&PredictResponse{PayeeID: predictedPayeeId, CategoryID: categoryId}, nil).Once()
+		txnService.On("Create", mock.Anything, mock.MatchedBy(func(txn model.Transaction) bool {
+			return *txn.PayeeID == predictedPayeeId && *txn.CategoryID == categoryId
+		})).Return([]model.Transaction{{ID: uuid.New()}}, nil).Once()
+
+		ofx := "<STMTTRN><DTPOSTED>20240105<TRNAMT>-20<FITID>F1<NAME>GROCER</STMTTRN>"
+		result, err := service.Import(
+			ctx,
+			model.ImportRequest{AccountID: accountId, Format: model.ImportFormatOFX, Predict: true},
+			[]byte(ofx),
+		)
+		require.NoError(t, err)
+		assert.Equal(t, 1, result.Created)
+		cipher.AssertExpectations(t)
+		txnService.AssertExpectations(t)
+	})
+
+	t.Run("csv_without_mapping", func(t *testing.T) {
+		repo := &mockImportMappingRepo{}
+		accountRepo := &mockAccountRepo{}
+		service := NewImportService(repo, accountRepo, &mockTxnService{}, &mockPayeeService{}, nil)
+
+		accountRepo.On("GetById", mock.Anything, nil, budgetId, accountId).Return(account, nil).Once()
+		repo.On("GetByAccountId", mock.Anything, budgetId, accountId).Return(nil, pgx.ErrNoRows).Once()
+
+		_, err := service.Import(ctx, model.ImportRequest{AccountID: accountId, Format: "csv"}, []byte(csv))
+		assert.Error(t, err)
+	})
+
+	t.Run("unsupported_format", func(t *testing.T) {
+		accountRepo := &mockAccountRepo{}
+		service := NewImportService(&mockImportMappingRepo{}, accountRepo, &mockTxnService{}, &mockPayeeService{}, nil)
+
+		accountRepo.On("GetById", mock.Anything, nil, budgetId, accountId).Return(account, nil).Once()
+
+		_, err := service.Import(ctx, model.ImportRequest{AccountID: accountId, Format: "xlsx"}, []byte("data"))
+		assert.Error(t, err)
+	})
+}
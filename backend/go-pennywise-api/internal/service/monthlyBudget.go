@@ -3,12 +3,13 @@ package service
 import (
 	"context"
 	"errors"
-
-	utils "github.com/Rishabh-Kapri/pennywise/backend/go-pennywise-api/pkg"
+	"math"
+	"time"
 
 	repository "github.com/Rishabh-Kapri/pennywise/backend/shared/db"
 	errs "github.com/Rishabh-Kapri/pennywise/backend/shared/errors"
 	"github.com/Rishabh-Kapri/pennywise/backend/shared/model"
+	utils "github.com/Rishabh-Kapri/pennywise/backend/shared/utils"
 
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
@@ -36,16 +37,27 @@ type MonthlyBudgetService interface {
 		budgetId uuid.UUID,
 		oldTxn *model.Transaction,
 		newTxn *model.Transaction,
-		inflowCategoryID uuid.UUID,
+		rules carryoverRules,
 	) error
+	// GetMonth sums up a month of the budget: Ready to Assign, what was budgeted and spent, and the categories
+	// left overspent, with how much of it was cash and how much credit, see model.ApplyOverspending
+	GetMonth(ctx context.Context, month string) (*model.BudgetMonth, error)
+	// Rebuild recomputes the carryover balance of every category month of the budget from its transactions
+	// and budgeted amounts and reports the ones stored wrong. With repair they are all overwritten in the
+	// same db transaction.
+	Rebuild(ctx context.Context, repair bool) (*model.BudgetRebuildResult, error)
 }
 
 type monthlyBudgetService struct {
-	repo repository.MonthlyBudgetRepository
+	repo         repository.MonthlyBudgetRepository
+	categoryRepo repository.CategoryRepository
 }
 
-func NewMonthlyBudgetService(r repository.MonthlyBudgetRepository) MonthlyBudgetService {
-	return &monthlyBudgetService{repo: r}
+func NewMonthlyBudgetService(
+	r repository.MonthlyBudgetRepository,
+	categoryRepo repository.CategoryRepository,
+) MonthlyBudgetService {
+	return &monthlyBudgetService{repo: r, categoryRepo: categoryRepo}
 }
 
 type txnDiff struct {
@@ -57,6 +69,105 @@ type txnDiff struct {
 	newAmount   float64
 }
 
+// carryoverRules is what decides which carryovers a transaction touches besides its own categories
+type carryoverRules struct {
+	inflowCategoryID uuid.UUID
+	// paymentCategoryIDs maps credit card accounts to their payment category
+	paymentCategoryIDs map[uuid.UUID]uuid.UUID
+}
+
+// paymentCategoryOf returns the payment category of the transaction's account, if it is a credit card
+func (r carryoverRules) paymentCategoryOf(txn *model.Transaction) (uuid.UUID, bool) {
+	if txn.AccountID == nil {
+		return uuid.Nil, false
+	}
+	id, ok := r.paymentCategoryIDs[*txn.AccountID]
+	return id, ok
+}
+
+// carryoverLines returns the category lines of a transaction that affect carryovers.
+// Split transactions contribute one line per split, others a single line for their category.
+// Uncategorized and inflow category lines are skipped.
+//
+// On a credit card the money spent moves from the spending categories into the card's payment
+// category, and a transfer into the card, i.e. a payment, takes it out again.
+func carryoverLines(txn *model.Transaction, rules carryoverRules) []carryoverOp {
+	var lines []carryoverOp
+	if !txn.IsSplit() {
+		if txn.CategoryID != nil && *txn.CategoryID != rules.inflowCategoryID {
+			lines = append(lines, carryoverOp{
+				categoryId:  *txn.CategoryID,
+				monthKey:    utils.GetMonthKey(txn.Date.String()),
+				amountDelta: txn.Amount,
+			})
+		}
+	}
+	for _, split := range txn.Splits {
+		if split.CategoryID == nil || *split.CategoryID == rules.inflowCategoryID {
+			continue
+		}
+		lines = append(lines, carryoverOp{
+			categoryId:  *split.CategoryID,
+			monthKey:    utils.GetMonthKey(txn.Date.String()),
+			amountDelta: split.Amount,
+		})
+	}
+
+	paymentCategoryId, ok := rules.paymentCategoryOf(txn)
+	if !ok {
+		return lines
+	}
+	payment := 0.0
+	for _, line := range lines {
+		payment -= line.amountDelta
+	}
+	// a transfer into the card is a payment, it spends what the payment category set aside
+	if txn.CategoryID == nil && !txn.IsSplit() && txn.TransferAccountID != nil && txn.Amount > 0 {
+		payment -= txn.Amount
+	}
+	if math.Round(payment*100) != 0 {
+		lines = append(lines, carryoverOp{
+			categoryId:  paymentCategoryId,
+			monthKey:    utils.GetMonthKey(txn.Date.String()),
+			amountDelta: payment,
+		})
+	}
+	return lines
+}
+
+// getSplitCarryoverOps nets the carryover lines of the old and new transaction per category and month.
+// The old lines are reversed and the new lines applied, unchanged lines cancel out.
+func getSplitCarryoverOps(oldTxn, newTxn *model.Transaction, rules carryoverRules) []carryoverOp {
+	var carryoverOps []carryoverOp
+	indexByKey := make(map[string]int)
+	addOp := func(op carryoverOp) {
+		key := op.categoryId.String() + ":" + op.monthKey
+		if idx, ok := indexByKey[key]; ok {
+			carryoverOps[idx].amountDelta += op.amountDelta
+			return
+		}
+		indexByKey[key] = len(carryoverOps)
+		carryoverOps = append(carryoverOps, op)
+	}
+	for _, op := range carryoverLines(oldTxn, rules) {
+		op.amountDelta = -op.amountDelta
+		addOp(op)
+	}
+	for _, op := range carryoverLines(newTxn, rules) {
+		addOp(op)
+	}
+
+	filtered := carryoverOps[:0]
+	for _, op := range carryoverOps {
+		// compare in cents to avoid floating point residue
+		if math.Round(op.amountDelta*100) == 0 {
+			continue
+		}
+		filtered = append(filtered, op)
+	}
+	return filtered
+}
+
 // carryoverCase is a helper struct for carryover logic
 type carryoverCase struct {
 	sameCategory bool
@@ -170,14 +281,26 @@ func (s *monthlyBudgetService) ApplyCarryoverOps(
 }
 
 // updateCarryovers computes and applies carryover adjustments when a transaction changes
+// Split and credit card transactions are netted line by line, see getSplitCarryoverOps.
 func (s *monthlyBudgetService) UpdateCarryovers(
 	ctx context.Context,
 	tx pgx.Tx,
 	budgetId uuid.UUID,
 	oldTxn *model.Transaction,
 	newTxn *model.Transaction,
-	inflowCategoryID uuid.UUID,
+	rules carryoverRules,
 ) error {
+	_, oldOnCard := rules.paymentCategoryOf(oldTxn)
+	_, newOnCard := rules.paymentCategoryOf(newTxn)
+	if oldTxn.IsSplit() || newTxn.IsSplit() || oldOnCard || newOnCard {
+		for _, op := range getSplitCarryoverOps(oldTxn, newTxn, rules) {
+			if err := s.UpsertCarryover(ctx, tx, budgetId, op.categoryId, op.monthKey, op.amountDelta); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
 	diff := &txnDiff{
 		oldCatId:    oldTxn.CategoryID,
 		newCatId:    newTxn.CategoryID,
@@ -187,10 +310,10 @@ func (s *monthlyBudgetService) UpdateCarryovers(
 		newAmount:   newTxn.Amount,
 	}
 	// don't add or update carryover for inflow category id
-	if diff.oldCatId != nil && *diff.oldCatId == inflowCategoryID {
+	if diff.oldCatId != nil && *diff.oldCatId == rules.inflowCategoryID {
 		diff.oldCatId = nil
 	}
-	if diff.newCatId != nil && *diff.newCatId == inflowCategoryID {
+	if diff.newCatId != nil && *diff.newCatId == rules.inflowCategoryID {
 		diff.newCatId = nil
 	}
 	cc := carryoverCase{
@@ -199,3 +322,58 @@ func (s *monthlyBudgetService) UpdateCarryovers(
 	}
 	return s.ApplyCarryoverOps(ctx, tx, budgetId, diff, cc)
 }
+
+func (s *monthlyBudgetService) GetMonth(ctx context.Context, month string) (*model.BudgetMonth, error) {
+	if _, err := time.Parse(monthKeyLayout, month); err != nil {
+		return nil, errs.New(errs.CodeInvalidArgument, "month must be formatted as YYYY-MM")
+	}
+	budgetId := utils.MustBudgetID(ctx)
+
+	categories, err := s.categoryRepo.GetAll(ctx, budgetId)
+	if err != nil {
+		return nil, errs.Wrap(errs.CodeCategoryLookupFailed, "error getting categories", err)
+	}
+	readyToAssign, err := s.categoryRepo.GetReadyToAssign(ctx, nil, budgetId, month, categories)
+	if err != nil {
+		return nil, errs.Wrap(errs.CodeCategoryLookupFailed, "error getting ready to assign balance", err)
+	}
+
+	summary := model.SummarizeMonth(categories, month)
+	summary.ReadyToAssign = readyToAssign
+	return summary, nil
+}
+
+func (s *monthlyBudgetService) Rebuild(ctx context.Context, repair bool) (*model.BudgetRebuildResult, error) {
+	budgetId := utils.MustBudgetID(ctx)
+	result := &model.BudgetRebuildResult{BudgetID: budgetId}
+	// every monthly budget of the budget stays locked until the rebuild is done
+	txCtx, txCancel := context.WithTimeout(ctx, 60*time.Second)
+	defer txCancel()
+
+	err := withTx(txCtx, s.repo.GetDB(), func(tx pgx.Tx) error {
+		stored, err := s.repo.GetByBudget(txCtx, tx, budgetId)
+		if err != nil {
+			return errs.Wrap(errs.CodeMonthlyBudgetLookupFailed, "error getting monthly budgets", err)
+		}
+		activity, err := s.repo.GetActivity(txCtx, tx, budgetId)
+		if err != nil {
+			return errs.Wrap(errs.CodeMonthlyBudgetLookupFailed, "error getting category activity", err)
+		}
+		result.Checked, result.Discrepancies = model.RebuildCarryovers(stored, activity)
+		if !repair {
+			return nil
+		}
+
+		for _, d := range result.Discrepancies {
+			if err := s.repo.SetCarryover(txCtx, tx, budgetId, d.CategoryID, d.Month, d.Expected); err != nil {
+				return errs.Wrap(errs.CodeMonthlyBudgetUpdateFailed, "error repairing carryover", err)
+			}
+		}
+		result.Repaired = len(result.Discrepancies) > 0
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
@@ -0,0 +1,211 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	errs "github.com/Rishabh-Kapri/pennywise/backend/shared/errors"
+	"github.com/Rishabh-Kapri/pennywise/backend/shared/model"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCarryoverLinesOnCreditCard(t *testing.T) {
+	cardID := uuid.New()
+	checkingID := uuid.New()
+	paymentID := uuid.New()
+	groceriesID := uuid.New()
+	householdID := uuid.New()
+	inflowCategoryID := uuid.New()
+	rules := carryoverRules{
+		inflowCategoryID:   inflowCategoryID,
+		paymentCategoryIDs: map[uuid.UUID]uuid.UUID{cardID: paymentID},
+	}
+
+	tests := []struct {
+		name  string
+		txn   model.Transaction
+		lines []carryoverOp
+	}{
+		{
+			name: "spending_moves_money_to_the_payment_category",
+			txn:  model.Transaction{Date: "2025-03-10", AccountID: &cardID, CategoryID: &groceriesID, Amount: -50},
+			lines: []carryoverOp{
+				{categoryId: groceriesID, monthKey: "2025-03", amountDelta: -50},
+				{categoryId: paymentID, monthKey: "2025-03", amountDelta: 50},
+			},
+		},
+		{
+			name: "split_lines_add_up_in_one_payment_line",
+			txn: model.Transaction{Date: "2025-03-10", AccountID: &cardID, Amount: -100, Splits: []model.TransactionSplit{
+				{CategoryID: &groceriesID, Amount: -70},
+				{CategoryID: &householdID, Amount: -30},
+			}},
+			lines: []carryoverOp{
+				{categoryId: groceriesID, monthKey: "2025-03", amountDelta: -70},
+				{categoryId: householdID, monthKey: "2025-03", amountDelta: -30},
+				{categoryId: paymentID, monthKey: "2025-03", amountDelta: 100},
+			},
+		},
+		{
+			name: "refund_takes_money_back_out",
+			txn:  model.Transaction{Date: "2025-03-10", AccountID: &cardID, CategoryID: &groceriesID, Amount: 20},
+			lines: []carryoverOp{
+				{categoryId: groceriesID, monthKey: "2025-03", amountDelta: 20},
+				{categoryId: paymentID, monthKey: "2025-03", amountDelta: -20},
+			},
+		},
+		{
+			name: "income_goes_to_ready_to_assign",
+			txn:  model.Transaction{Date: "2025-03-10", AccountID: &cardID, CategoryID: &inflowCategoryID, Amount: 20},
+		},
+		{
+			name: "payment_draws_the_payment_category_down",
+			txn:  model.Transaction{Date: "2025-03-25", AccountID: &cardID, TransferAccountID: &checkingID, Amount: 200},
+			lines: []carryoverOp{
+				{categoryId: paymentID, monthKey: "2025-03", amountDelta: -200},
+			},
+		},
+		{
+			name: "cash_advance_is_not_funded",
+			txn:  model.Transaction{Date: "2025-03-25", AccountID: &cardID, TransferAccountID: &checkingID, Amount: -100},
+		},
+		{
+			name: "checking_side_of_a_payment",
+			txn:  model.Transaction{Date: "2025-03-25", AccountID: &checkingID, TransferAccountID: &cardID, Amount: -200},
+		},
+		{
+			name: "spending_from_checking",
+			txn:  model.Transaction{Date: "2025-03-10", AccountID: &checkingID, CategoryID: &groceriesID, Amount: -50},
+			lines: []carryoverOp{
+				{categoryId: groceriesID, monthKey: "2025-03", amountDelta: -50},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.lines, carryoverLines(&tt.txn, rules))
+		})
+	}
+
+	t.Run("changed_amount_nets_both_sides", func(t *testing.T) {
+		oldTxn := &model.Transaction{Date: "2025-03-10", AccountID: &cardID, CategoryID: &groceriesID, Amount: -50}
+		newTxn := &model.Transaction{Date: "2025-03-10", AccountID: &cardID, CategoryID: &groceriesID, Amount: -80}
+		assert.Equal(t, []carryoverOp{
+			{categoryId: groceriesID, monthKey: "2025-03", amountDelta: -30},
+			{categoryId: paymentID, monthKey: "2025-03", amountDelta: 30},
+		}, getSplitCarryoverOps(oldTxn, newTxn, rules))
+	})
+
+	t.Run("moving_to_checking_empties_the_payment_category", func(t *testing.T) {
+		oldTxn := &model.Transaction{Date: "2025-03-10", AccountID: &cardID, CategoryID: &groceriesID, Amount: -50}
+		newTxn := &model.Transaction{Date: "2025-03-10", AccountID: &checkingID, CategoryID: &groceriesID, Amount: -50}
+		assert.Equal(t, []carryoverOp{
+			{categoryId: paymentID, monthKey: "2025-03", amountDelta: -50},
+		}, getSplitCarryoverOps(oldTxn, newTxn, rules))
+	})
+}
+
+func TestValidatePaymentCategories(t *testing.T) {
+	paymentID := uuid.New()
+	groceriesID := uuid.New()
+	paymentCategoryIDs := map[uuid.UUID]uuid.UUID{uuid.New(): paymentID}
+
+	assert.NoError(t, validatePaymentCategories(model.Transaction{CategoryID: &groceriesID}, paymentCategoryIDs))
+	assert.Error(t, validatePaymentCategories(model.Transaction{CategoryID: &paymentID}, paymentCategoryIDs))
+	assert.Error(t, validatePaymentCategories(model.Transaction{Splits: []model.TransactionSplit{
+		{CategoryID: &groceriesID, Amount: -10},
+		{CategoryID: &paymentID, Amount: -10},
+	}}, paymentCategoryIDs))
+}
+
+func TestMonthlyBudgetService_GetMonth(t *testing.T) {
+	budgetID := uuid.New()
+	ctx := budgetCtxWith(budgetID)
+	groceriesID := uuid.New()
+
+	t.Run("lists_overspent_categories", func(t *testing.T) {
+		categoryRepo := &svcCategoryRepo{}
+		categoryRepo.On("GetAll", mock.Anything, budgetID).Return([]model.Category{
+			{
+				ID:              groceriesID,
+				Name:            "Groceries",
+				Budgeted:        map[string]float32{"2025-03": 100},
+				Activity:        map[string]float32{"2025-03": -150},
+				Balance:         map[string]float32{"2025-03": -50},
+				CashOverspent:   map[string]float32{"2025-03": 20},
+				CreditOverspent: map[string]float32{"2025-03": 30},
+			},
+			{Name: "Rent", Budgeted: map[string]float32{"2025-03": 900}, Balance: map[string]float32{"2025-03": 900}},
+		}, nil)
+		categoryRepo.On("GetReadyToAssign", mock.Anything, nil, budgetID, "2025-03", mock.Anything).Return(250.0, nil)
+
+		month, err := NewMonthlyBudgetService(nil, categoryRepo).GetMonth(ctx, "2025-03")
+		require.NoError(t, err)
+		assert.Equal(t, 250.0, month.ReadyToAssign)
+		assert.Equal(t, 1000.0, month.Budgeted)
+		assert.Equal(t, 20.0, month.CashOverspent)
+		assert.Equal(t, 30.0, month.CreditOverspent)
+		require.Len(t, month.Overspent, 1)
+		assert.Equal(t, groceriesID, month.Overspent[0].CategoryID)
+		assert.Equal(t, -50.0, month.Overspent[0].Available)
+		categoryRepo.AssertExpectations(t)
+	})
+
+	t.Run("rejects_invalid_month", func(t *testing.T) {
+		_, err := NewMonthlyBudgetService(nil, &svcCategoryRepo{}).GetMonth(context.Background(), "March")
+		assert.True(t, hasErrorCode(err, errs.CodeInvalidArgument), err)
+	})
+}
+
+func TestMonthlyBudgetService_Rebuild(t *testing.T) {
+	useInlineTx(t)
+	budgetID := uuid.New()
+	ctx := budgetCtxWith(budgetID)
+	groceriesID := uuid.New()
+	stored := []model.MonthlyBudget{
+		{CategoryID: groceriesID, Month: "2025-01", Budgeted: 100, CarryoverBalance: 40},
+		{CategoryID: groceriesID, Month: "2025-02", Budgeted: 0, CarryoverBalance: 40},
+	}
+	activity := []model.CategoryMonthActivity{
+		{CategoryID: groceriesID, Month: "2025-01", Activity: -60},
+		{CategoryID: groceriesID, Month: "2025-02", Activity: -15},
+	}
+	newRepo := func() *svcMonthlyBudgetRepo {
+		repo := &svcMonthlyBudgetRepo{}
+		repo.On("GetByBudget", mock.Anything, mock.Anything, budgetID).Return(stored, nil)
+		repo.On("GetActivity", mock.Anything, mock.Anything, budgetID).Return(activity, nil)
+		return repo
+	}
+
+	t.Run("reports_without_repairing", func(t *testing.T) {
+		repo := newRepo()
+		result, err := NewMonthlyBudgetService(repo, nil).Rebuild(ctx, false)
+		require.NoError(t, err)
+		assert.Equal(t, 2, result.Checked)
+		require.Len(t, result.Discrepancies, 1)
+		assert.Equal(t, "2025-02", result.Discrepancies[0].Month)
+		assert.Equal(t, 25.0, result.Discrepancies[0].Expected)
+		assert.False(t, result.Repaired)
+		repo.AssertNotCalled(t, "SetCarryover", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("repairs_discrepancies", func(t *testing.T) {
+		repo := newRepo()
+		repo.On("SetCarryover", mock.Anything, mock.Anything, budgetID, groceriesID, "2025-02", 25.0).Return(nil)
+		result, err := NewMonthlyBudgetService(repo, nil).Rebuild(ctx, true)
+		require.NoError(t, err)
+		assert.True(t, result.Repaired)
+		repo.AssertExpectations(t)
+	})
+
+	t.Run("repair_failure_returns_error", func(t *testing.T) {
+		repo := newRepo()
+		repo.On("SetCarryover", mock.Anything, mock.Anything, budgetID, groceriesID, "2025-02", 25.0).Return(assert.AnError)
+		_, err := NewMonthlyBudgetService(repo, nil).Rebuild(ctx, true)
+		assert.True(t, hasErrorCode(err, errs.CodeMonthlyBudgetUpdateFailed), err)
+	})
+}
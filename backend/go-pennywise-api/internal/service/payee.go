@@ -2,8 +2,10 @@ package service
 
 import (
 	"context"
+	"errors"
 
 	repository "github.com/Rishabh-Kapri/pennywise/backend/shared/db"
+	errs "github.com/Rishabh-Kapri/pennywise/backend/shared/errors"
 	"github.com/Rishabh-Kapri/pennywise/backend/shared/model"
 	utils "github.com/Rishabh-Kapri/pennywise/backend/shared/utils"
 
@@ -23,15 +25,30 @@ type PayeeService interface {
 	CreateWithTx(ctx context.Context, tx pgx.Tx, payee model.Payee) (*model.Payee, error)
 	DeleteById(ctx context.Context, id uuid.UUID) error
 	Update(ctx context.Context, id uuid.UUID, payee model.Payee) error
+	// Merge folds the source payees into the target payee in one db transaction. Transactions, rules,
+	// embeddings and cipher predictions move to the target and the sources are soft deleted.
+	Merge(ctx context.Context, targetId uuid.UUID, req model.MergePayeesRequest) (*model.PayeeMergeResult, error)
 }
 
 type payeeService struct {
-	repo          repository.PayeesRepository
-	payeeRuleRepo repository.PayeeRuleRepository
+	repo            repository.PayeesRepository
+	payeeRuleRepo   repository.PayeeRuleRepository
+	transactionRepo repository.TransactionRepository
+	auditService    AuditService
 }
 
-func NewPayeeService(repo repository.PayeesRepository, payeeRuleRepo repository.PayeeRuleRepository) PayeeService {
-	return &payeeService{repo: repo, payeeRuleRepo: payeeRuleRepo}
+func NewPayeeService(
+	repo repository.PayeesRepository,
+	payeeRuleRepo repository.PayeeRuleRepository,
+	transactionRepo repository.TransactionRepository,
+	auditService AuditService,
+) PayeeService {
+	return &payeeService{
+		repo:            repo,
+		payeeRuleRepo:   payeeRuleRepo,
+		transactionRepo: transactionRepo,
+		auditService:    auditService,
+	}
 }
 
 func (s *payeeService) GetAll(ctx context.Context) ([]model.Payee, error) {
@@ -100,3 +117,82 @@ func (s *payeeService) Update(ctx context.Context, id uuid.UUID, payee model.Pay
 	budgetId := utils.MustBudgetID(ctx)
 	return s.repo.Update(ctx, budgetId, id, payee)
 }
+
+func (s *payeeService) Merge(
+	ctx context.Context,
+	targetId uuid.UUID,
+	req model.MergePayeesRequest,
+) (*model.PayeeMergeResult, error) {
+	budgetId := utils.MustBudgetID(ctx)
+	if len(req.SourceIDs) == 0 {
+		return nil, errs.New(errs.CodeInvalidArgument, "at least one payee to merge is required")
+	}
+
+	var result *model.PayeeMergeResult
+	err := withTx(ctx, s.repo.GetDB(), func(tx pgx.Tx) error {
+		target, err := s.getMergeablePayee(ctx, tx, budgetId, targetId)
+		if err != nil {
+			return err
+		}
+
+		sourceIds := make([]uuid.UUID, 0, len(req.SourceIDs))
+		seen := map[uuid.UUID]bool{}
+		for _, sourceId := range req.SourceIDs {
+			if sourceId == targetId {
+				return errs.New(errs.CodeInvalidArgument, "a payee can't be merged into itself")
+			}
+			if seen[sourceId] {
+				continue
+			}
+			seen[sourceId] = true
+			if _, err := s.getMergeablePayee(ctx, tx, budgetId, sourceId); err != nil {
+				return err
+			}
+			sourceIds = append(sourceIds, sourceId)
+		}
+
+		// deleted transactions are moved too but only live ones get an audit entry
+		var moved []model.Transaction
+		if s.auditService != nil {
+			moved, err = s.transactionRepo.GetForRewriteTx(ctx, tx, budgetId, model.TransactionFilter{PayeeIDs: sourceIds})
+			if err != nil {
+				return errs.Wrap(errs.CodeTransactionLookupFailed, "error getting transactions to merge", err)
+			}
+		}
+		result, err = s.repo.Merge(ctx, tx, budgetId, targetId, sourceIds)
+		if err != nil {
+			return errs.Wrap(errs.CodePayeeMergeFailed, "error merging payees", err)
+		}
+		result.Payee = target
+		if s.auditService == nil {
+			return nil
+		}
+		return recordTransactionRewrites(ctx, tx, s.auditService, moved, func(txn *model.Transaction) {
+			txn.PayeeID = &targetId
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// getMergeablePayee returns a live payee that isn't the transfer payee of an account
+func (s *payeeService) getMergeablePayee(
+	ctx context.Context,
+	tx pgx.Tx,
+	budgetId uuid.UUID,
+	id uuid.UUID,
+) (*model.Payee, error) {
+	payee, err := s.repo.GetByIdTx(ctx, tx, budgetId, id)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, errs.Wrap(errs.CodePayeeNotFound, "payee not found", err)
+		}
+		return nil, errs.Wrap(errs.CodePayeeLookupFailed, "error getting payee", err)
+	}
+	if payee.TransferAccountID != nil {
+		return nil, errs.New(errs.CodeInvalidArgument, "transfer payee %s can't be merged", payee.Name)
+	}
+	return payee, nil
+}
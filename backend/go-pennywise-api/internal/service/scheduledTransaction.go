@@ -0,0 +1,317 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	repository "github.com/Rishabh-Kapri/pennywise/backend/shared/db"
+	errs "github.com/Rishabh-Kapri/pennywise/backend/shared/errors"
+	"github.com/Rishabh-Kapri/pennywise/backend/shared/logger"
+	"github.com/Rishabh-Kapri/pennywise/backend/shared/model"
+	utils "github.com/Rishabh-Kapri/pennywise/backend/shared/utils"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+const scheduledDateLayout = "2006-01-02"
+
+type ScheduledTransactionService interface {
+	GetAll(ctx context.Context) ([]model.ScheduledTransaction, error)
+	GetById(ctx context.Context, id uuid.UUID) (*model.ScheduledTransaction, error)
+	Create(ctx context.Context, st model.ScheduledTransaction) (*model.ScheduledTransaction, error)
+	Update(ctx context.Context, id uuid.UUID, st model.ScheduledTransaction) (*model.ScheduledTransaction, error)
+	DeleteById(ctx context.Context, id uuid.UUID) error
+	// MaterializeDue creates UNAPPROVED transactions for every occurrence due on or before asOf,
+	// across all budgets, and advances each schedule to its next occurrence.
+	MaterializeDue(ctx context.Context, asOf time.Time) ([]model.Transaction, error)
+}
+
+type scheduledTransactionService struct {
+	repo               repository.ScheduledTransactionRepository
+	transactionService TransactionService
+}
+
+func NewScheduledTransactionService(
+	r repository.ScheduledTransactionRepository,
+	transactionService TransactionService,
+) ScheduledTransactionService {
+	return &scheduledTransactionService{repo: r, transactionService: transactionService}
+}
+
+// validateScheduledTransaction validates the schedule and fills in defaults for interval and day of month
+func validateScheduledTransaction(st *model.ScheduledTransaction) error {
+	if st.AccountID == nil {
+		return errs.New(errs.CodeInvalidArgument, "account_id is required")
+	}
+	if st.PayeeID == nil {
+		return errs.New(errs.CodeInvalidArgument, "payee_id is required")
+	}
+	if err := st.StartDate.Valid(); err != nil {
+		return err
+	}
+	if st.EndDate != nil {
+		if err := st.EndDate.Valid(); err != nil {
+			return err
+		}
+		if *st.EndDate < st.StartDate {
+			return errs.New(errs.CodeInvalidArgument, "end date can't be before the start date")
+		}
+	}
+	if st.Interval == 0 {
+		st.Interval = 1
+	}
+	if st.Interval < 0 {
+		return errs.New(errs.CodeInvalidArgument, "interval must be positive")
+	}
+
+	switch st.Frequency {
+	case model.ScheduledFrequencyMonthlyOnDay:
+		if st.DayOfMonth == nil {
+			start, _ := time.Parse(scheduledDateLayout, st.StartDate.String())
+			day := start.Day()
+			st.DayOfMonth = &day
+		}
+		if *st.DayOfMonth < 1 || *st.DayOfMonth > 31 {
+			return errs.New(errs.CodeInvalidArgument, "day of month must be between 1 and 31")
+		}
+	case model.ScheduledFrequencyEveryNWeeks, model.ScheduledFrequencyLastBusinessDay:
+		st.DayOfMonth = nil
+	default:
+		return errs.New(errs.CodeInvalidArgument, "unsupported frequency %q", st.Frequency)
+	}
+	return nil
+}
+
+// lastBusinessDay returns the last weekday of the month
+func lastBusinessDay(year int, month time.Month) time.Time {
+	day := time.Date(year, month+1, 0, 0, 0, 0, 0, time.UTC)
+	for day.Weekday() == time.Saturday || day.Weekday() == time.Sunday {
+		day = day.AddDate(0, 0, -1)
+	}
+	return day
+}
+
+// nextMonthlyOccurrence returns the first monthly occurrence on or after from,
+// stepping interval months from the start month. dayFn picks the day within a month.
+func nextMonthlyOccurrence(
+	start time.Time,
+	from time.Time,
+	interval int,
+	dayFn func(year int, month time.Month) time.Time,
+) time.Time {
+	monthsFromStart := (from.Year()-start.Year())*12 + int(from.Month()-start.Month())
+	step := max(monthsFromStart/interval, 0)
+	for {
+		month := time.Date(start.Year(), start.Month()+time.Month(step*interval), 1, 0, 0, 0, 0, time.UTC)
+		candidate := dayFn(month.Year(), month.Month())
+		if !candidate.Before(from) {
+			return candidate
+		}
+		step++
+	}
+}
+
+// sameRecurrence reports whether both schedules occur on the same dates
+func sameRecurrence(a, b model.ScheduledTransaction) bool {
+	return a.Frequency == b.Frequency &&
+		max(a.Interval, 1) == max(b.Interval, 1) &&
+		ptrEqual(a.DayOfMonth, b.DayOfMonth) &&
+		a.StartDate == b.StartDate &&
+		ptrEqual(a.EndDate, b.EndDate)
+}
+
+// ptrEqual reports whether both pointers are nil or point to equal values
+func ptrEqual[T comparable](a, b *T) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
+
+// nextOccurrence returns the first occurrence of the schedule on or after from,
+// or nil if the schedule has ended by then
+func nextOccurrence(st model.ScheduledTransaction, from time.Time) *model.Date {
+	start, err := time.Parse(scheduledDateLayout, st.StartDate.String())
+	if err != nil {
+		return nil
+	}
+	from = time.Date(from.Year(), from.Month(), from.Day(), 0, 0, 0, 0, time.UTC)
+	if from.Before(start) {
+		from = start
+	}
+	interval := max(st.Interval, 1)
+
+	var next time.Time
+	switch st.Frequency {
+	case model.ScheduledFrequencyEveryNWeeks:
+		stepDays := 7 * interval
+		elapsed := int(from.Sub(start).Hours() / 24)
+		steps := (elapsed + stepDays - 1) / stepDays
+		next = start.AddDate(0, 0, steps*stepDays)
+	case model.ScheduledFrequencyMonthlyOnDay:
+		if st.DayOfMonth == nil {
+			return nil
+		}
+		next = nextMonthlyOccurrence(start, from, interval, func(year int, month time.Month) time.Time {
+			lastDay := time.Date(year, month+1, 0, 0, 0, 0, 0, time.UTC).Day()
+			return time.Date(year, month, min(*st.DayOfMonth, lastDay), 0, 0, 0, 0, time.UTC)
+		})
+	case model.ScheduledFrequencyLastBusinessDay:
+		next = nextMonthlyOccurrence(start, from, interval, lastBusinessDay)
+	default:
+		return nil
+	}
+
+	nextDate := model.Date(next.Format(scheduledDateLayout))
+	if st.EndDate != nil && nextDate > *st.EndDate {
+		return nil
+	}
+	return &nextDate
+}
+
+func (s *scheduledTransactionService) GetAll(ctx context.Context) ([]model.ScheduledTransaction, error) {
+	budgetId := utils.MustBudgetID(ctx)
+	return s.repo.GetAll(ctx, budgetId)
+}
+
+func (s *scheduledTransactionService) GetById(ctx context.Context, id uuid.UUID) (*model.ScheduledTransaction, error) {
+	budgetId := utils.MustBudgetID(ctx)
+	st, err := s.repo.GetById(ctx, budgetId, id)
+	if err != nil {
+		return nil, errs.Wrap(errs.CodeScheduledTransactionLookupFailed, "error getting scheduled transaction", err)
+	}
+	return st, nil
+}
+
+func (s *scheduledTransactionService) Create(
+	ctx context.Context,
+	st model.ScheduledTransaction,
+) (*model.ScheduledTransaction, error) {
+	budgetId := utils.MustBudgetID(ctx)
+	st.BudgetID = budgetId
+	if err := validateScheduledTransaction(&st); err != nil {
+		return nil, err
+	}
+	// occurrences before today are not back filled
+	st.NextDate = nextOccurrence(st, time.Now())
+
+	created, err := s.repo.Create(ctx, st)
+	if err != nil {
+		return nil, errs.Wrap(errs.CodeScheduledTransactionCreateFailed, "error creating scheduled transaction", err)
+	}
+	return created, nil
+}
+
+func (s *scheduledTransactionService) Update(
+	ctx context.Context,
+	id uuid.UUID,
+	st model.ScheduledTransaction,
+) (*model.ScheduledTransaction, error) {
+	budgetId := utils.MustBudgetID(ctx)
+	found, err := s.repo.GetById(ctx, budgetId, id)
+	if err != nil {
+		return nil, errs.Wrap(errs.CodeScheduledTransactionLookupFailed, "error getting scheduled transaction", err)
+	}
+
+	st.ID = id
+	st.BudgetID = budgetId
+	if err := validateScheduledTransaction(&st); err != nil {
+		return nil, err
+	}
+	// the next date only moves when the recurrence rule changes, otherwise an occurrence that is due but
+	// not materialized yet would be skipped
+	st.NextDate = found.NextDate
+	if !sameRecurrence(*found, st) {
+		st.NextDate = nextOccurrence(st, time.Now())
+	}
+
+	if err := s.repo.Update(ctx, budgetId, id, st); err != nil {
+		return nil, errs.Wrap(errs.CodeScheduledTransactionUpdateFailed, "error updating scheduled transaction", err)
+	}
+	return &st, nil
+}
+
+func (s *scheduledTransactionService) DeleteById(ctx context.Context, id uuid.UUID) error {
+	budgetId := utils.MustBudgetID(ctx)
+	if err := s.repo.DeleteById(ctx, budgetId, id); err != nil {
+		return errs.Wrap(errs.CodeScheduledTransactionDeleteFailed, "error deleting scheduled transaction", err)
+	}
+	return nil
+}
+
+func (s *scheduledTransactionService) MaterializeDue(ctx context.Context, asOf time.Time) ([]model.Transaction, error) {
+	asOfDate := model.Date(asOf.Format(scheduledDateLayout))
+	due, err := s.repo.GetDue(ctx, asOfDate.String())
+	if err != nil {
+		return nil, errs.Wrap(errs.CodeScheduledTransactionLookupFailed, "error getting due scheduled transactions", err)
+	}
+	logger.Logger(ctx).Info("materializing scheduled transactions", "asOf", asOfDate, "count", len(due))
+
+	var created []model.Transaction
+	var failed []error
+	for _, st := range due {
+		txns, err := s.materialize(ctx, st, asOfDate)
+		if err != nil {
+			// keep going so that one broken schedule doesn't hold back the rest
+			logger.Logger(ctx).Error("error materializing scheduled transaction", "id", st.ID, "error", err)
+			failed = append(failed, err)
+			continue
+		}
+		created = append(created, txns...)
+	}
+	return created, errors.Join(failed...)
+}
+
+// materialize creates the due occurrences of a single schedule and advances its next date in one db transaction
+func (s *scheduledTransactionService) materialize(
+	ctx context.Context,
+	st model.ScheduledTransaction,
+	asOf model.Date,
+) ([]model.Transaction, error) {
+	budgetCtx := utils.WithBudgetID(ctx, st.BudgetID)
+
+	var created []model.Transaction
+	err := withTx(budgetCtx, s.repo.GetDB(), func(tx pgx.Tx) error {
+		next := st.NextDate
+		for next != nil && *next <= asOf {
+			// the hash makes re-running a half applied schedule safe, an occurrence that already exists
+			// isn't created again through idx_transactions_dedupe and the schedule moves past it
+			hash := utils.Hash(st.ID.String() + next.String())
+			txn := model.Transaction{
+				BudgetID:   st.BudgetID,
+				AccountID:  st.AccountID,
+				PayeeID:    st.PayeeID,
+				CategoryID: st.CategoryID,
+				Amount:     st.Amount,
+				Note:       st.Note,
+				TagIDs:     st.TagIDs,
+				Date:       *next,
+				Status:     model.TransactionStatusUnapproved,
+				DedupeHash: &hash,
+			}
+			txns, err := s.transactionService.CreateWithTx(budgetCtx, tx, txn)
+			var apiErr *errs.Error
+			switch {
+			case errors.As(err, &apiErr) && apiErr.Code == errs.CodeTransactionNotCreated:
+				logger.Logger(ctx).Info("scheduled occurrence already exists", "id", st.ID, "date", *next)
+			case err != nil:
+				return err
+			default:
+				created = append(created, txns...)
+			}
+
+			occurredOn, _ := time.Parse(scheduledDateLayout, next.String())
+			next = nextOccurrence(st, occurredOn.AddDate(0, 0, 1))
+		}
+		if err := s.repo.UpdateNextDate(budgetCtx, tx, st.BudgetID, st.ID, next); err != nil {
+			return errs.Wrap(errs.CodeScheduledTransactionUpdateFailed, "error advancing scheduled transaction", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return created, nil
+}
@@ -0,0 +1,399 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	errs "github.com/Rishabh-Kapri/pennywise/backend/shared/errors"
+	"github.com/Rishabh-Kapri/pennywise/backend/shared/model"
+	utils "github.com/Rishabh-Kapri/pennywise/backend/shared/utils"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type mockScheduledTransactionRepo struct {
+	mockBaseRepo
+	mock.Mock
+}
+
+func (m *mockScheduledTransactionRepo) GetAll(
+	ctx context.Context,
+	budgetId uuid.UUID,
+) ([]model.ScheduledTransaction, error) {
+	args := m.Called(ctx, budgetId)
+	if obj := args.Get(0); obj != nil {
+		return obj.([]model.ScheduledTransaction), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *mockScheduledTransactionRepo) GetById(
+	ctx context.Context,
+	budgetId uuid.UUID,
+	id uuid.UUID,
+) (*model.ScheduledTransaction, error) {
+	args := m.Called(ctx, budgetId, id)
+	if obj := args.Get(0); obj != nil {
+		return obj.(*model.ScheduledTransaction), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *mockScheduledTransactionRepo) GetDue(ctx context.Context, asOf string) ([]model.ScheduledTransaction, error) {
+	args := m.Called(ctx, asOf)
+	if obj := args.Get(0); obj != nil {
+		return obj.([]model.ScheduledTransaction), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *mockScheduledTransactionRepo) Create(
+	ctx context.Context,
+	st model.ScheduledTransaction,
+) (*model.ScheduledTransaction, error) {
+	args := m.Called(ctx, st)
+	if obj := args.Get(0); obj != nil {
+		return obj.(*model.ScheduledTransaction), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *mockScheduledTransactionRepo) Update(
+	ctx context.Context,
+	budgetId uuid.UUID,
+	id uuid.UUID,
+	st model.ScheduledTransaction,
+) error {
+	args := m.Called(ctx, budgetId, id, st)
+	return args.Error(0)
+}
+
+func (m *mockScheduledTransactionRepo) UpdateNextDate(
+	ctx context.Context,
+	tx pgx.Tx,
+	budgetId uuid.UUID,
+	id uuid.UUID,
+	nextDate *model.Date,
+) error {
+	args := m.Called(ctx, tx, budgetId, id, nextDate)
+	return args.Error(0)
+}
+
+func (m *mockScheduledTransactionRepo) DeleteById(ctx context.Context, budgetId uuid.UUID, id uuid.UUID) error {
+	args := m.Called(ctx, budgetId, id)
+	return args.Error(0)
+}
+
+// mockTxnService only implements the create methods, which is all the scheduler and importer need
+type mockTxnService struct {
+	TransactionService
+	mock.Mock
+}
+
+func (m *mockTxnService) Create(ctx context.Context, txn model.Transaction) ([]model.Transaction, error) {
+	args := m.Called(ctx, txn)
+	if obj := args.Get(0); obj != nil {
+		return obj.([]model.Transaction), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *mockTxnService) CreateWithTx(
+	ctx context.Context,
+	tx pgx.Tx,
+	txn model.Transaction,
+) ([]model.Transaction, error) {
+	args := m.Called(ctx, tx, txn)
+	if obj := args.Get(0); obj != nil {
+		return obj.([]model.Transaction), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func datePtr(d string) *model.Date {
+	date := model.Date(d)
+	return &date
+}
+
+func mustDate(t *testing.T, d string) time.Time {
+	t.Helper()
+	parsed, err := time.Parse("2006-01-02", d)
+	require.NoError(t, err)
+	return parsed
+}
+
+func TestNextOccurrence(t *testing.T) {
+	day := func(d int) *int { return &d }
+
+	tests := []struct {
+		name string
+		st   model.ScheduledTransaction
+		from string
+		want *model.Date
+	}{
+		{
+			name: "monthly_on_day_same_month",
+			st:   model.ScheduledTransaction{Frequency: model.ScheduledFrequencyMonthlyOnDay, DayOfMonth: day(5), StartDate: "2024-01-01"},
+			from: "2024-03-02",
+			want: datePtr("2024-03-05"),
+		},
+		{
+			name: "monthly_on_day_rolls_to_next_month",
+			st:   model.ScheduledTransaction{Frequency: model.ScheduledFrequencyMonthlyOnDay, DayOfMonth: day(5), StartDate: "2024-01-01"},
+			from: "2024-03-06",
+			want: datePtr("2024-04-05"),
+		},
+		{
+			name: "monthly_on_day_clamped_to_month_end",
+			st:   model.ScheduledTransaction{Frequency: model.ScheduledFrequencyMonthlyOnDay, DayOfMonth: day(31), StartDate: "2024-01-31"},
+			from: "2024-02-01",
+			want: datePtr("2024-02-29"),
+		},
+		{
+			name: "monthly_every_three_months",
+			st: model.ScheduledTransaction{
+				Frequency:  model.ScheduledFrequencyMonthlyOnDay,
+				Interval:   3,
+				DayOfMonth: day(10),
+				StartDate:  "2024-01-10",
+			},
+			from: "2024-02-01",
+			want: datePtr("2024-04-10"),
+		},
+		{
+			name: "monthly_not_before_start",
+			st:   model.ScheduledTransaction{Frequency: model.ScheduledFrequencyMonthlyOnDay, DayOfMonth: day(10), StartDate: "2024-01-15"},
+			from: "2023-12-01",
+			want: datePtr("2024-02-10"),
+		},
+		{
+			name: "every_two_weeks",
+			st:   model.ScheduledTransaction{Frequency: model.ScheduledFrequencyEveryNWeeks, Interval: 2, StartDate: "2024-01-01"},
+			from: "2024-01-16",
+			want: datePtr("2024-01-29"),
+		},
+		{
+			name: "every_week_on_occurrence",
+			st:   model.ScheduledTransaction{Frequency: model.ScheduledFrequencyEveryNWeeks, Interval: 1, StartDate: "2024-01-01"},
+			from: "2024-01-08",
+			want: datePtr("2024-01-08"),
+		},
+		{
+			name: "last_business_day_skips_weekend",
+			// 2024-03-31 is a Sunday
+			st:   model.ScheduledTransaction{Frequency: model.ScheduledFrequencyLastBusinessDay, StartDate: "2024-01-01"},
+			from: "2024-03-01",
+			want: datePtr("2024-03-29"),
+		},
+		{
+			name: "ended",
+			st: model.ScheduledTransaction{
+				Frequency:  model.ScheduledFrequencyMonthlyOnDay,
+				DayOfMonth: day(5),
+				StartDate:  "2024-01-01",
+				EndDate:    datePtr("2024-03-01"),
+			},
+			from: "2024-02-06",
+			want: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := nextOccurrence(tt.st, mustDate(t, tt.from))
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestValidateScheduledTransaction(t *testing.T) {
+	accountId := uuid.New()
+	payeeId := uuid.New()
+
+	t.Run("defaults_interval_and_day", func(t *testing.T) {
+		st := model.ScheduledTransaction{
+			AccountID: &accountId,
+			PayeeID:   &payeeId,
+			Frequency: model.ScheduledFrequencyMonthlyOnDay,
+			StartDate: "2024-01-17",
+		}
+		require.NoError(t, validateScheduledTransaction(&st))
+		assert.Equal(t, 1, st.Interval)
+		require.NotNil(t, st.DayOfMonth)
+		assert.Equal(t, 17, *st.DayOfMonth)
+	})
+
+	t.Run("end_before_start", func(t *testing.T) {
+		st := model.ScheduledTransaction{
+			AccountID: &accountId,
+			PayeeID:   &payeeId,
+			Frequency: model.ScheduledFrequencyEveryNWeeks,
+			StartDate: "2024-01-17",
+			EndDate:   datePtr("2024-01-01"),
+		}
+		assert.Error(t, validateScheduledTransaction(&st))
+	})
+
+	t.Run("unknown_frequency", func(t *testing.T) {
+		st := model.ScheduledTransaction{
+			AccountID: &accountId,
+			PayeeID:   &payeeId,
+			Frequency: "YEARLY",
+			StartDate: "2024-01-17",
+		}
+		assert.Error(t, validateScheduledTransaction(&st))
+	})
+
+	t.Run("missing_payee", func(t *testing.T) {
+		st := model.ScheduledTransaction{
+			AccountID: &accountId,
+			Frequency: model.ScheduledFrequencyEveryNWeeks,
+			StartDate: "2024-01-17",
+		}
+		assert.Error(t, validateScheduledTransaction(&st))
+	})
+}
+
+func TestMaterializeDue(t *testing.T) {
+	var mockTx pgx.Tx
+	mockWithTxSuccess(mockTx)
+	defer func() { withTx = utils.WithTx }()
+
+	budgetId := uuid.New()
+	accountId := uuid.New()
+	payeeId := uuid.New()
+	scheduleId := uuid.New()
+	schedule := model.ScheduledTransaction{
+		ID:        scheduleId,
+		BudgetID:  budgetId,
+		AccountID: &accountId,
+		PayeeID:   &payeeId,
+		Amount:    -1200,
+		Frequency: model.ScheduledFrequencyEveryNWeeks,
+		Interval:  1,
+		StartDate: "2024-01-01",
+		NextDate:  datePtr("2024-01-08"),
+	}
+
+	repo := &mockScheduledTransactionRepo{}
+	txnService := &mockTxnService{}
+	service := NewScheduledTransactionService(repo, txnService)
+
+	repo.On("GetDue", mock.Anything, "2024-01-16").Return([]model.ScheduledTransaction{schedule}, nil).Once()
+	for _, date := range []string{"2024-01-08", "2024-01-15"} {
+		expectedDate := model.Date(date)
+		txnService.On("CreateWithTx", mock.Anything, mockTx, mock.MatchedBy(func(txn model.Transaction) bool {
+			return txn.Date == expectedDate &&
+				txn.Status == model.TransactionStatusUnapproved &&
+				txn.DedupeHash != nil &&
+				*txn.DedupeHash == utils.Hash(scheduleId.String()+date)
+		})).Return([]model.Transaction{{ID: uuid.New(), BudgetID: budgetId, Date: expectedDate}}, nil).Once()
+	}
+	repo.On("UpdateNextDate", mock.Anything, mockTx, budgetId, scheduleId, datePtr("2024-01-22")).Return(nil).Once()
+
+	created, err := service.MaterializeDue(context.Background(), mustDate(t, "2024-01-16"))
+	assert.NoError(t, err)
+	assert.Len(t, created, 2)
+	repo.AssertExpectations(t)
+	txnService.AssertExpectations(t)
+}
+
+func TestMaterializeDueSkipsExistingOccurrence(t *testing.T) {
+	var mockTx pgx.Tx
+	mockWithTxSuccess(mockTx)
+	defer func() { withTx = utils.WithTx }()
+
+	budgetId := uuid.New()
+	accountId := uuid.New()
+	payeeId := uuid.New()
+	scheduleId := uuid.New()
+	schedule := model.ScheduledTransaction{
+		ID:        scheduleId,
+		BudgetID:  budgetId,
+		AccountID: &accountId,
+		PayeeID:   &payeeId,
+		Amount:    -1200,
+		Frequency: model.ScheduledFrequencyEveryNWeeks,
+		Interval:  1,
+		StartDate: "2024-01-01",
+		NextDate:  datePtr("2024-01-08"),
+	}
+
+	repo := &mockScheduledTransactionRepo{}
+	txnService := &mockTxnService{}
+	service := NewScheduledTransactionService(repo, txnService)
+
+	repo.On("GetDue", mock.Anything, "2024-01-16").Return([]model.ScheduledTransaction{schedule}, nil).Once()
+	txnService.On("CreateWithTx", mock.Anything, mockTx, mock.MatchedBy(func(txn model.Transaction) bool {
+		return txn.Date == "2024-01-08"
+	})).Return(nil, errs.New(errs.CodeTransactionNotCreated, "no transaction was created")).Once()
+	txnService.On("CreateWithTx", mock.Anything, mockTx, mock.MatchedBy(func(txn model.Transaction) bool {
+		return txn.Date == "2024-01-15"
+	})).Return([]model.Transaction{{ID: uuid.New(), BudgetID: budgetId, Date: "2024-01-15"}}, nil).Once()
+	repo.On("UpdateNextDate", mock.Anything, mockTx, budgetId, scheduleId, datePtr("2024-01-22")).Return(nil).Once()
+
+	created, err := service.MaterializeDue(context.Background(), mustDate(t, "2024-01-16"))
+	assert.NoError(t, err)
+	assert.Len(t, created, 1)
+	repo.AssertExpectations(t)
+	txnService.AssertExpectations(t)
+}
+
+func TestUpdateScheduledTransactionNextDate(t *testing.T) {
+	budgetId := uuid.New()
+	scheduleId := uuid.New()
+	ctx := utils.WithBudgetID(context.Background(), budgetId)
+	accountId := uuid.New()
+	payeeId := uuid.New()
+	// due on the 8th but not materialized yet
+	stored := model.ScheduledTransaction{
+		ID:        scheduleId,
+		BudgetID:  budgetId,
+		AccountID: &accountId,
+		PayeeID:   &payeeId,
+		Amount:    -1200,
+		Frequency: model.ScheduledFrequencyEveryNWeeks,
+		Interval:  1,
+		StartDate: "2024-01-01",
+		NextDate:  datePtr("2024-01-08"),
+	}
+
+	t.Run("same_rule_keeps_next_date", func(t *testing.T) {
+		repo := &mockScheduledTransactionRepo{}
+		service := NewScheduledTransactionService(repo, &mockTxnService{})
+		update := stored
+		update.NextDate = nil
+		update.Amount = -1300
+
+		repo.On("GetById", ctx, budgetId, scheduleId).Return(&stored, nil).Once()
+		repo.On("Update", ctx, budgetId, scheduleId, mock.MatchedBy(func(st model.ScheduledTransaction) bool {
+			return st.Amount == -1300 && st.NextDate != nil && *st.NextDate == "2024-01-08"
+		})).Return(nil).Once()
+
+		updated, err := service.Update(ctx, scheduleId, update)
+		require.NoError(t, err)
+		assert.Equal(t, datePtr("2024-01-08"), updated.NextDate)
+		repo.AssertExpectations(t)
+	})
+
+	t.Run("new_rule_starts_over", func(t *testing.T) {
+		repo := &mockScheduledTransactionRepo{}
+		service := NewScheduledTransactionService(repo, &mockTxnService{})
+		update := stored
+		update.StartDate = model.Date(time.Now().AddDate(0, 0, 3).Format(scheduledDateLayout))
+
+		repo.On("GetById", ctx, budgetId, scheduleId).Return(&stored, nil).Once()
+		repo.On("Update", ctx, budgetId, scheduleId, mock.MatchedBy(func(st model.ScheduledTransaction) bool {
+			return st.NextDate != nil && *st.NextDate == update.StartDate
+		})).Return(nil).Once()
+
+		_, err := service.Update(ctx, scheduleId, update)
+		require.NoError(t, err)
+		repo.AssertExpectations(t)
+	})
+}
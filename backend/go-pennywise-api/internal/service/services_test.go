@@ -8,6 +8,7 @@ import (
 	"time"
 
 	"github.com/Rishabh-Kapri/pennywise/backend/go-pennywise-api/internal/config"
+	errs "github.com/Rishabh-Kapri/pennywise/backend/shared/errors"
 	"github.com/Rishabh-Kapri/pennywise/backend/shared/model"
 	utils "github.com/Rishabh-Kapri/pennywise/backend/shared/utils"
 
@@ -16,6 +17,7 @@ import (
 	"github.com/jackc/pgx/v5"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 )
 
 // ─────────────────────────────────────────────────────────────────────────────
@@ -66,6 +68,16 @@ func (m *svcAccountRepo) Create(ctx context.Context, tx pgx.Tx, account model.Ac
 func (m *svcAccountRepo) UpdateTransferPayee(ctx context.Context, tx pgx.Tx, accountId, payeeId uuid.UUID) error {
 	return m.Called(ctx, tx, accountId, payeeId).Error(0)
 }
+func (m *svcAccountRepo) GetBalances(ctx context.Context, tx pgx.Tx, budgetId, accountId uuid.UUID) (*model.Account, error) {
+	args := m.Called(ctx, tx, budgetId, accountId)
+	if v := args.Get(0); v != nil {
+		return v.(*model.Account), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+func (m *svcAccountRepo) UpdateLastReconciled(ctx context.Context, tx pgx.Tx, budgetId, accountId uuid.UUID) error {
+	return m.Called(ctx, tx, budgetId, accountId).Error(0)
+}
 func (m *svcAccountRepo) GetAllSimplified(ctx context.Context, budgetId uuid.UUID) ([]model.AccountSimplified, error) {
 	args := m.Called(ctx, budgetId)
 	if v := args.Get(0); v != nil {
@@ -73,6 +85,19 @@ func (m *svcAccountRepo) GetAllSimplified(ctx context.Context, budgetId uuid.UUI
 	}
 	return nil, args.Error(1)
 }
+func (m *svcAccountRepo) Update(ctx context.Context, tx pgx.Tx, budgetId, accountId uuid.UUID, account model.Account) error {
+	return m.Called(ctx, tx, budgetId, accountId, account).Error(0)
+}
+func (m *svcAccountRepo) SetClosed(ctx context.Context, tx pgx.Tx, budgetId, accountId uuid.UUID, closed bool) error {
+	return m.Called(ctx, tx, budgetId, accountId, closed).Error(0)
+}
+func (m *svcAccountRepo) HasTransactions(ctx context.Context, tx pgx.Tx, budgetId, accountId uuid.UUID) (bool, error) {
+	args := m.Called(ctx, tx, budgetId, accountId)
+	return args.Bool(0), args.Error(1)
+}
+func (m *svcAccountRepo) DeleteById(ctx context.Context, tx pgx.Tx, budgetId, accountId uuid.UUID) error {
+	return m.Called(ctx, tx, budgetId, accountId).Error(0)
+}
 
 type svcTagRepo struct {
 	mockBaseRepo
@@ -161,6 +186,18 @@ func (m *svcPayeeRepo) DeleteById(ctx context.Context, budgetId, id uuid.UUID) e
 func (m *svcPayeeRepo) Update(ctx context.Context, budgetId, id uuid.UUID, payee model.Payee) error {
 	return m.Called(ctx, budgetId, id, payee).Error(0)
 }
+func (m *svcPayeeRepo) Merge(
+	ctx context.Context,
+	tx pgx.Tx,
+	budgetId, targetId uuid.UUID,
+	sourceIds []uuid.UUID,
+) (*model.PayeeMergeResult, error) {
+	args := m.Called(ctx, tx, budgetId, targetId, sourceIds)
+	if v := args.Get(0); v != nil {
+		return v.(*model.PayeeMergeResult), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
 
 // svcPayeeRuleRepo
 type svcPayeeRuleRepo struct {
@@ -251,6 +288,16 @@ func (m *svcCategoryRepo) GetInflowBalance(ctx context.Context, budgetId uuid.UU
 	args := m.Called(ctx, budgetId)
 	return args.Get(0).(float64), args.Error(1)
 }
+func (m *svcCategoryRepo) GetReadyToAssign(
+	ctx context.Context,
+	tx pgx.Tx,
+	budgetId uuid.UUID,
+	month string,
+	categories []model.Category,
+) (float64, error) {
+	args := m.Called(ctx, tx, budgetId, month, categories)
+	return args.Get(0).(float64), args.Error(1)
+}
 func (m *svcCategoryRepo) GetByFilter(ctx context.Context, budgetId uuid.UUID, filter model.CategoryFilter) ([]model.Category, error) {
 	args := m.Called(ctx, budgetId, filter)
 	if v := args.Get(0); v != nil {
@@ -293,8 +340,52 @@ func (m *svcCategoryRepo) Create(ctx context.Context, tx pgx.Tx, category model.
 	}
 	return nil, args.Error(1)
 }
-func (m *svcCategoryRepo) DeleteById(ctx context.Context, budgetId, id uuid.UUID) error {
-	return m.Called(ctx, budgetId, id).Error(0)
+func (m *svcCategoryRepo) GetPaymentCategoryIDs(ctx context.Context, tx pgx.Tx, budgetId uuid.UUID) (map[uuid.UUID]uuid.UUID, error) {
+	args := m.Called(ctx, tx, budgetId)
+	if v := args.Get(0); v != nil {
+		return v.(map[uuid.UUID]uuid.UUID), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+func (m *svcCategoryRepo) SetPaymentCategoryHidden(
+	ctx context.Context,
+	tx pgx.Tx,
+	budgetId uuid.UUID,
+	accountId uuid.UUID,
+	hidden bool,
+) error {
+	return m.Called(ctx, tx, budgetId, accountId, hidden).Error(0)
+}
+func (m *svcCategoryRepo) IsInUse(ctx context.Context, tx pgx.Tx, budgetId, id uuid.UUID) (bool, error) {
+	args := m.Called(ctx, tx, budgetId, id)
+	return args.Bool(0), args.Error(1)
+}
+func (m *svcCategoryRepo) Reassign(ctx context.Context, tx pgx.Tx, budgetId, fromId, toId uuid.UUID) (*model.CategoryDeleteResult, error) {
+	args := m.Called(ctx, tx, budgetId, fromId, toId)
+	if v := args.Get(0); v != nil {
+		return v.(*model.CategoryDeleteResult), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+func (m *svcCategoryRepo) DeleteById(ctx context.Context, tx pgx.Tx, budgetId, id uuid.UUID) error {
+	return m.Called(ctx, tx, budgetId, id).Error(0)
+}
+func (m *svcCategoryRepo) GetGoal(ctx context.Context, budgetId, categoryId uuid.UUID) (*model.CategoryGoal, error) {
+	args := m.Called(ctx, budgetId, categoryId)
+	if v := args.Get(0); v != nil {
+		return v.(*model.CategoryGoal), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+func (m *svcCategoryRepo) UpsertGoal(ctx context.Context, goal model.CategoryGoal) (*model.CategoryGoal, error) {
+	args := m.Called(ctx, goal)
+	if v := args.Get(0); v != nil {
+		return v.(*model.CategoryGoal), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+func (m *svcCategoryRepo) DeleteGoal(ctx context.Context, budgetId, categoryId uuid.UUID) error {
+	return m.Called(ctx, budgetId, categoryId).Error(0)
 }
 func (m *svcCategoryRepo) Update(ctx context.Context, budgetId, id uuid.UUID, category model.Category) error {
 	return m.Called(ctx, budgetId, id, category).Error(0)
@@ -497,7 +588,7 @@ func TestAccountService_GetAll(t *testing.T) {
 		repo := &svcAccountRepo{}
 		payeeRepo := &svcPayeeRepo{}
 		repo.On("GetAll", mock.Anything, budgetID).Return([]model.Account{{ID: uuid.New()}}, nil)
-		accounts, err := NewAccountService(repo, payeeRepo).GetAll(ctx)
+		accounts, err := NewAccountService(repo, payeeRepo, nil, nil, nil, nil, nil).GetAll(ctx)
 		assert.NoError(t, err)
 		assert.Len(t, accounts, 1)
 		repo.AssertExpectations(t)
@@ -506,7 +597,7 @@ func TestAccountService_GetAll(t *testing.T) {
 		repo := &svcAccountRepo{}
 		payeeRepo := &svcPayeeRepo{}
 		repo.On("GetAll", mock.Anything, budgetID).Return(nil, assert.AnError)
-		accounts, err := NewAccountService(repo, payeeRepo).GetAll(ctx)
+		accounts, err := NewAccountService(repo, payeeRepo, nil, nil, nil, nil, nil).GetAll(ctx)
 		assert.Error(t, err)
 		assert.Nil(t, accounts)
 		repo.AssertExpectations(t)
@@ -519,7 +610,7 @@ func TestAccountService_Search(t *testing.T) {
 	repo := &svcAccountRepo{}
 	payeeRepo := &svcPayeeRepo{}
 	repo.On("Search", mock.Anything, budgetID, "savings").Return([]model.Account{{Name: "Savings"}}, nil)
-	accounts, err := NewAccountService(repo, payeeRepo).Search(ctx, "savings")
+	accounts, err := NewAccountService(repo, payeeRepo, nil, nil, nil, nil, nil).Search(ctx, "savings")
 	assert.NoError(t, err)
 	assert.Len(t, accounts, 1)
 	repo.AssertExpectations(t)
@@ -645,7 +736,7 @@ func TestCategoryService_GetAll(t *testing.T) {
 	ctx := budgetCtxWith(budgetID)
 	repo := &svcCategoryRepo{}
 	repo.On("GetAll", mock.Anything, budgetID).Return([]model.Category{{ID: uuid.New()}}, nil)
-	cats, err := NewCategoryService(repo, nil, nil).GetAll(ctx)
+	cats, err := NewCategoryService(repo, nil, nil, nil).GetAll(ctx)
 	assert.NoError(t, err)
 	assert.Len(t, cats, 1)
 	repo.AssertExpectations(t)
@@ -656,7 +747,7 @@ func TestCategoryService_GetInflowBalance(t *testing.T) {
 	ctx := budgetCtxWith(budgetID)
 	repo := &svcCategoryRepo{}
 	repo.On("GetInflowBalance", mock.Anything, budgetID).Return(float64(100.5), nil)
-	balance, err := NewCategoryService(repo, nil, nil).GetInflowBalance(ctx)
+	balance, err := NewCategoryService(repo, nil, nil, nil).GetInflowBalance(ctx)
 	assert.NoError(t, err)
 	assert.Equal(t, 100.5, balance)
 	repo.AssertExpectations(t)
@@ -672,7 +763,7 @@ func TestCategoryService_Create(t *testing.T) {
 		repo.On("Create", mock.Anything, (pgx.Tx)(nil), mock.MatchedBy(func(c model.Category) bool {
 			return c.Name == "Groceries" && c.BudgetID == budgetID
 		})).Return(created, nil)
-		result, err := NewCategoryService(repo, nil, nil).Create(ctx, model.Category{Name: "Groceries"})
+		result, err := NewCategoryService(repo, nil, nil, nil).Create(ctx, model.Category{Name: "Groceries"})
 		assert.NoError(t, err)
 		assert.Equal(t, created.ID, result.ID)
 		repo.AssertExpectations(t)
@@ -680,23 +771,221 @@ func TestCategoryService_Create(t *testing.T) {
 }
 
 func TestCategoryService_DeleteById(t *testing.T) {
+	useInlineTx(t)
+
+	budgetID := uuid.New()
+	catID := uuid.New()
+	replacementID := uuid.New()
+	ctx := budgetCtxWith(budgetID)
+	category := &model.Category{ID: catID}
+
+	t.Run("unused_category", func(t *testing.T) {
+		repo := &svcCategoryRepo{}
+		repo.On("GetById", ctx, budgetID, catID).Return(category, nil).Once()
+		repo.On("IsInUse", ctx, nil, budgetID, catID).Return(false, nil).Once()
+		repo.On("DeleteById", ctx, nil, budgetID, catID).Return(nil).Once()
+
+		result, err := NewCategoryService(repo, nil, nil, nil).DeleteById(ctx, catID, nil)
+		require.NoError(t, err)
+		assert.Nil(t, result.ReplacementID)
+		repo.AssertExpectations(t)
+	})
+
+	t.Run("in_use_requires_replacement", func(t *testing.T) {
+		repo := &svcCategoryRepo{}
+		repo.On("GetById", ctx, budgetID, catID).Return(category, nil).Once()
+		repo.On("IsInUse", ctx, nil, budgetID, catID).Return(true, nil).Once()
+
+		_, err := NewCategoryService(repo, nil, nil, nil).DeleteById(ctx, catID, nil)
+		assert.True(t, hasErrorCode(err, errs.CodeCategoryInUse), err)
+		repo.AssertNotCalled(t, "DeleteById", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("reassigns_and_merges_budgets", func(t *testing.T) {
+		repo := &svcCategoryRepo{}
+		mbRepo := &svcMonthlyBudgetRepo{}
+		repo.On("GetById", ctx, budgetID, catID).Return(category, nil).Once()
+		repo.On("GetById", ctx, budgetID, replacementID).Return(&model.Category{ID: replacementID}, nil).Once()
+		repo.On("Reassign", ctx, nil, budgetID, catID, replacementID).
+			Return(&model.CategoryDeleteResult{ReplacementID: &replacementID, Transactions: 4, PayeeRules: 1}, nil).Once()
+		mbRepo.On("MergeCategory", ctx, nil, budgetID, catID, replacementID).Return(int64(3), nil).Once()
+		repo.On("DeleteById", ctx, nil, budgetID, catID).Return(nil).Once()
+
+		result, err := NewCategoryService(repo, mbRepo, nil, nil).DeleteById(ctx, catID, &replacementID)
+		require.NoError(t, err)
+		assert.Equal(t, int64(4), result.Transactions)
+		assert.Equal(t, int64(3), result.MonthlyBudgets)
+		assert.Equal(t, int64(1), result.PayeeRules)
+		repo.AssertNotCalled(t, "IsInUse", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+		repo.AssertExpectations(t)
+		mbRepo.AssertExpectations(t)
+	})
+
+	t.Run("audits_reassigned_transactions_and_budgets", func(t *testing.T) {
+		repo := &svcCategoryRepo{}
+		mbRepo := &svcMonthlyBudgetRepo{}
+		txnRepo := &mockTransactionRepo{}
+		auditService := &mockAuditService{}
+		otherID := uuid.New()
+		splitTxn := model.Transaction{ID: uuid.New(), Splits: []model.TransactionSplit{
+			{CategoryID: &catID, Amount: -30},
+			{CategoryID: &otherID, Amount: -20},
+		}}
+		repo.On("GetById", ctx, budgetID, catID).Return(category, nil).Once()
+		repo.On("GetById", ctx, budgetID, replacementID).Return(&model.Category{ID: replacementID}, nil).Once()
+		txnRepo.On("GetForRewriteTx", ctx, nil, budgetID, model.TransactionFilter{CategoryIDs: []uuid.UUID{catID}}).
+			Return([]model.Transaction{splitTxn}, nil).Once()
+		mbRepo.On("GetByBudget", ctx, nil, budgetID).Return([]model.MonthlyBudget{
+			{CategoryID: catID, Month: "2025-01", Budgeted: 40},
+			{CategoryID: catID, Month: "2025-02", Budgeted: 10},
+			{CategoryID: replacementID, Month: "2025-01", Budgeted: 60},
+		}, nil).Once()
+		repo.On("Reassign", ctx, nil, budgetID, catID, replacementID).
+			Return(&model.CategoryDeleteResult{ReplacementID: &replacementID, Splits: 1}, nil).Once()
+		mbRepo.On("MergeCategory", ctx, nil, budgetID, catID, replacementID).Return(int64(2), nil).Once()
+		repo.On("DeleteById", ctx, nil, budgetID, catID).Return(nil).Once()
+		auditService.On("Record", ctx, nil, mock.MatchedBy(func(record AuditRecord) bool {
+			after, ok := record.After.(transactionAuditSnapshot)
+			return ok && record.EntityID == splitTxn.ID &&
+				*after.Splits[0].CategoryID == replacementID && *after.Splits[1].CategoryID == otherID
+		})).Return(nil).Once()
+		auditService.On("Record", ctx, nil, AuditRecord{
+			EntityType: model.AuditEntityMonthlyBudget,
+			EntityID:   replacementID,
+			Action:     model.AuditActionUpdate,
+			Before:     monthlyBudgetAuditSnapshot{CategoryID: replacementID, Month: "2025-01", Budgeted: 60},
+			After:      monthlyBudgetAuditSnapshot{CategoryID: replacementID, Month: "2025-01", Budgeted: 100},
+		}).Return(nil).Once()
+		auditService.On("Record", ctx, nil, AuditRecord{
+			EntityType: model.AuditEntityMonthlyBudget,
+			EntityID:   replacementID,
+			Action:     model.AuditActionCreate,
+			After:      monthlyBudgetAuditSnapshot{CategoryID: replacementID, Month: "2025-02", Budgeted: 10},
+		}).Return(nil).Once()
+
+		_, err := NewCategoryService(repo, mbRepo, txnRepo, auditService).DeleteById(ctx, catID, &replacementID)
+		require.NoError(t, err)
+		// the split line of the deleted category moves, the other one stays
+		assert.Equal(t, catID, *splitTxn.Splits[0].CategoryID)
+		auditService.AssertExpectations(t)
+	})
+
+	t.Run("rejects_invalid_replacements", func(t *testing.T) {
+		repo := &svcCategoryRepo{}
+		repo.On("GetById", ctx, budgetID, catID).Return(category, nil)
+		repo.On("GetById", ctx, budgetID, replacementID).Return(&model.Category{ID: replacementID, IsSystem: true}, nil).Once()
+		service := NewCategoryService(repo, nil, nil, nil)
+
+		_, err := service.DeleteById(ctx, catID, &catID)
+		assert.True(t, hasErrorCode(err, errs.CodeInvalidArgument), err)
+		_, err = service.DeleteById(ctx, catID, &replacementID)
+		assert.True(t, hasErrorCode(err, errs.CodeInvalidArgument), err)
+
+		repo.On("GetById", ctx, budgetID, replacementID).Return(nil, pgx.ErrNoRows).Once()
+		_, err = service.DeleteById(ctx, catID, &replacementID)
+		assert.True(t, hasErrorCode(err, errs.CodeCategoryNotFound), err)
+	})
+
+	t.Run("system_category", func(t *testing.T) {
+		repo := &svcCategoryRepo{}
+		repo.On("GetById", ctx, budgetID, catID).Return(&model.Category{ID: catID, IsSystem: true}, nil).Once()
+
+		_, err := NewCategoryService(repo, nil, nil, nil).DeleteById(ctx, catID, &replacementID)
+		assert.True(t, hasErrorCode(err, errs.CodeInvalidArgument), err)
+	})
+}
+
+func TestCategoryService_SetGoal(t *testing.T) {
+	budgetID := uuid.New()
+	catID := uuid.New()
+	ctx := budgetCtxWith(budgetID)
+
+	t.Run("drops_target_month_for_monthly_goals", func(t *testing.T) {
+		targetMonth := "2025-12"
+		repo := &svcCategoryRepo{}
+		repo.On("GetById", ctx, budgetID, catID).Return(&model.Category{ID: catID}, nil).Once()
+		repo.On("UpsertGoal", ctx, model.CategoryGoal{
+			BudgetID:   budgetID,
+			CategoryID: catID,
+			Type:       model.CategoryGoalMonthlyFunding,
+			Amount:     200,
+		}).Return(&model.CategoryGoal{CategoryID: catID}, nil).Once()
+
+		_, err := NewCategoryService(repo, nil, nil, nil).SetGoal(ctx, catID, model.CategoryGoal{
+			Type:        model.CategoryGoalMonthlyFunding,
+			Amount:      200,
+			TargetMonth: &targetMonth,
+		})
+		require.NoError(t, err)
+		repo.AssertExpectations(t)
+	})
+
+	t.Run("invalid_goals", func(t *testing.T) {
+		badMonth := "2025-13"
+		service := NewCategoryService(&svcCategoryRepo{}, nil, nil, nil)
+		for _, goal := range []model.CategoryGoal{
+			{Type: "WEEKLY", Amount: 10},
+			{Type: model.CategoryGoalNeededForSpending, Amount: 0},
+			{Type: model.CategoryGoalTargetBalance, Amount: 10, TargetMonth: &badMonth},
+		} {
+			_, err := service.SetGoal(ctx, catID, goal)
+			assert.True(t, hasErrorCode(err, errs.CodeInvalidArgument), err)
+		}
+	})
+
+	t.Run("system_category", func(t *testing.T) {
+		repo := &svcCategoryRepo{}
+		repo.On("GetById", ctx, budgetID, catID).Return(&model.Category{ID: catID, IsSystem: true}, nil).Once()
+
+		_, err := NewCategoryService(repo, nil, nil, nil).SetGoal(ctx, catID, model.CategoryGoal{
+			Type:   model.CategoryGoalMonthlyFunding,
+			Amount: 200,
+		})
+		assert.True(t, hasErrorCode(err, errs.CodeInvalidArgument), err)
+		repo.AssertNotCalled(t, "UpsertGoal", mock.Anything, mock.Anything)
+	})
+}
+
+func TestCategoryService_DeleteGoal(t *testing.T) {
 	budgetID := uuid.New()
 	catID := uuid.New()
 	ctx := budgetCtxWith(budgetID)
 	repo := &svcCategoryRepo{}
-	repo.On("DeleteById", mock.Anything, budgetID, catID).Return(nil)
-	assert.NoError(t, NewCategoryService(repo, nil, nil).DeleteById(ctx, catID))
-	repo.AssertExpectations(t)
+	repo.On("DeleteGoal", ctx, budgetID, catID).Return(pgx.ErrNoRows).Once()
+
+	err := NewCategoryService(repo, nil, nil, nil).DeleteGoal(ctx, catID)
+	assert.True(t, hasErrorCode(err, errs.CodeCategoryGoalNotFound), err)
 }
 
 func TestCategoryService_Update(t *testing.T) {
 	budgetID := uuid.New()
 	catID := uuid.New()
 	ctx := budgetCtxWith(budgetID)
-	repo := &svcCategoryRepo{}
-	repo.On("Update", mock.Anything, budgetID, catID, mock.Anything).Return(nil)
-	assert.NoError(t, NewCategoryService(repo, nil, nil).Update(ctx, catID, model.Category{Name: "Updated"}))
-	repo.AssertExpectations(t)
+
+	t.Run("keeps_stored_rollover_negative", func(t *testing.T) {
+		repo := &svcCategoryRepo{}
+		repo.On("GetByIdSimplified", ctx, budgetID, catID).Return(&model.Category{ID: catID, RolloverNegative: true}, nil)
+		repo.On("Update", mock.Anything, budgetID, catID, mock.MatchedBy(func(c model.Category) bool {
+			return c.Name == "Updated" && c.RolloverNegative
+		})).Return(nil)
+		update := model.CategoryUpdate{Category: model.Category{Name: "Updated"}}
+		assert.NoError(t, NewCategoryService(repo, nil, nil, nil).Update(ctx, catID, update))
+		repo.AssertExpectations(t)
+	})
+
+	t.Run("sets_rollover_negative", func(t *testing.T) {
+		repo := &svcCategoryRepo{}
+		rolloverNegative := false
+		repo.On("Update", mock.Anything, budgetID, catID, mock.MatchedBy(func(c model.Category) bool {
+			return !c.RolloverNegative
+		})).Return(nil)
+		update := model.CategoryUpdate{
+			Category:         model.Category{Name: "Updated", RolloverNegative: true},
+			RolloverNegative: &rolloverNegative,
+		}
+		assert.NoError(t, NewCategoryService(repo, nil, nil, nil).Update(ctx, catID, update))
+		repo.AssertNotCalled(t, "GetByIdSimplified", mock.Anything, mock.Anything, mock.Anything)
+	})
 }
 
 // ─────────────────────────────────────────────────────────────────────────────
@@ -709,7 +998,7 @@ func TestPayeeService_GetAll(t *testing.T) {
 	repo := &svcPayeeRepo{}
 	ruleRepo := &svcPayeeRuleRepo{}
 	repo.On("GetAll", mock.Anything, budgetID).Return([]model.Payee{{ID: uuid.New(), Name: "Amazon"}}, nil)
-	payees, err := NewPayeeService(repo, ruleRepo).GetAll(ctx)
+	payees, err := NewPayeeService(repo, ruleRepo, nil, nil).GetAll(ctx)
 	assert.NoError(t, err)
 	assert.Len(t, payees, 1)
 	repo.AssertExpectations(t)
@@ -722,7 +1011,7 @@ func TestPayeeService_GetById(t *testing.T) {
 	repo := &svcPayeeRepo{}
 	ruleRepo := &svcPayeeRuleRepo{}
 	repo.On("GetById", mock.Anything, budgetID, payeeID).Return(&model.Payee{ID: payeeID}, nil)
-	p, err := NewPayeeService(repo, ruleRepo).GetById(ctx, payeeID)
+	p, err := NewPayeeService(repo, ruleRepo, nil, nil).GetById(ctx, payeeID)
 	assert.NoError(t, err)
 	assert.Equal(t, payeeID, p.ID)
 	repo.AssertExpectations(t)
@@ -739,7 +1028,7 @@ func TestPayeeService_Create(t *testing.T) {
 		repo.On("Create", mock.Anything, (pgx.Tx)(nil), mock.MatchedBy(func(p model.Payee) bool {
 			return p.Name == "Walmart" && p.BudgetID == budgetID
 		})).Return(created, nil)
-		result, err := NewPayeeService(repo, ruleRepo).Create(ctx, model.Payee{Name: "Walmart"})
+		result, err := NewPayeeService(repo, ruleRepo, nil, nil).Create(ctx, model.Payee{Name: "Walmart"})
 		assert.NoError(t, err)
 		assert.Equal(t, created.ID, result.ID)
 		repo.AssertExpectations(t)
@@ -753,7 +1042,7 @@ func TestPayeeService_DeleteById(t *testing.T) {
 	repo := &svcPayeeRepo{}
 	ruleRepo := &svcPayeeRuleRepo{}
 	repo.On("DeleteById", mock.Anything, budgetID, payeeID).Return(nil)
-	assert.NoError(t, NewPayeeService(repo, ruleRepo).DeleteById(ctx, payeeID))
+	assert.NoError(t, NewPayeeService(repo, ruleRepo, nil, nil).DeleteById(ctx, payeeID))
 	repo.AssertExpectations(t)
 }
 
@@ -764,7 +1053,7 @@ func TestPayeeService_Update(t *testing.T) {
 	repo := &svcPayeeRepo{}
 	ruleRepo := &svcPayeeRuleRepo{}
 	repo.On("Update", mock.Anything, budgetID, payeeID, mock.Anything).Return(nil)
-	assert.NoError(t, NewPayeeService(repo, ruleRepo).Update(ctx, payeeID, model.Payee{Name: "Updated"}))
+	assert.NoError(t, NewPayeeService(repo, ruleRepo, nil, nil).Update(ctx, payeeID, model.Payee{Name: "Updated"}))
 	repo.AssertExpectations(t)
 }
 
@@ -775,7 +1064,7 @@ func TestPayeeService_GetRules(t *testing.T) {
 	repo := &svcPayeeRepo{}
 	ruleRepo := &svcPayeeRuleRepo{}
 	ruleRepo.On("FindByPayeeID", mock.Anything, budgetID, payeeID).Return([]model.PayeeRuleDetails{{ID: uuid.New()}}, nil)
-	rules, err := NewPayeeService(repo, ruleRepo).GetRules(ctx, payeeID)
+	rules, err := NewPayeeService(repo, ruleRepo, nil, nil).GetRules(ctx, payeeID)
 	assert.NoError(t, err)
 	assert.Len(t, rules, 1)
 	ruleRepo.AssertExpectations(t)
@@ -790,7 +1079,7 @@ func TestPayeeService_CreateRule(t *testing.T) {
 	ruleRepo.On("CreatePayeeRule", mock.Anything, (pgx.Tx)(nil), mock.MatchedBy(func(r model.PayeeRule) bool {
 		return r.MatchType == "EXACT" && r.PayeeID == payeeID && r.BudgetID == budgetID
 	})).Return(nil)
-	err := NewPayeeService(repo, ruleRepo).CreateRule(ctx, payeeID, model.PayeeRule{MatchString: "test"})
+	err := NewPayeeService(repo, ruleRepo, nil, nil).CreateRule(ctx, payeeID, model.PayeeRule{MatchString: "test"})
 	assert.NoError(t, err)
 	ruleRepo.AssertExpectations(t)
 }
@@ -802,10 +1091,70 @@ func TestPayeeService_DeleteRule(t *testing.T) {
 	repo := &svcPayeeRepo{}
 	ruleRepo := &svcPayeeRuleRepo{}
 	ruleRepo.On("DeleteByID", mock.Anything, budgetID, ruleID).Return(nil)
-	assert.NoError(t, NewPayeeService(repo, ruleRepo).DeleteRule(ctx, ruleID))
+	assert.NoError(t, NewPayeeService(repo, ruleRepo, nil, nil).DeleteRule(ctx, ruleID))
 	ruleRepo.AssertExpectations(t)
 }
 
+func TestPayeeService_Merge(t *testing.T) {
+	useInlineTx(t)
+	budgetID := uuid.New()
+	ctx := budgetCtxWith(budgetID)
+	targetID, sourceID, otherSourceID := uuid.New(), uuid.New(), uuid.New()
+	transferAccountID := uuid.New()
+
+	t.Run("merges_sources_into_target", func(t *testing.T) {
+		repo := &svcPayeeRepo{}
+		repo.On("GetByIdTx", mock.Anything, (pgx.Tx)(nil), budgetID, targetID).
+			Return(&model.Payee{ID: targetID, Name: "Swiggy"}, nil).Once()
+		repo.On("GetByIdTx", mock.Anything, (pgx.Tx)(nil), budgetID, sourceID).
+			Return(&model.Payee{ID: sourceID, Name: "SWIGGY LTD"}, nil).Once()
+		repo.On("GetByIdTx", mock.Anything, (pgx.Tx)(nil), budgetID, otherSourceID).
+			Return(&model.Payee{ID: otherSourceID, Name: "Swiggy Instamart"}, nil).Once()
+		// duplicate source ids are merged once
+		repo.On("Merge", mock.Anything, (pgx.Tx)(nil), budgetID, targetID, []uuid.UUID{sourceID, otherSourceID}).
+			Return(&model.PayeeMergeResult{MergedPayeeIDs: []uuid.UUID{sourceID, otherSourceID}, Transactions: 7}, nil).Once()
+
+		result, err := NewPayeeService(repo, nil, nil, nil).Merge(ctx, targetID, model.MergePayeesRequest{
+			SourceIDs: []uuid.UUID{sourceID, otherSourceID, sourceID},
+		})
+		require.NoError(t, err)
+		assert.Equal(t, "Swiggy", result.Payee.Name)
+		assert.Equal(t, int64(7), result.Transactions)
+		repo.AssertExpectations(t)
+	})
+
+	t.Run("rejects_merging_into_itself", func(t *testing.T) {
+		repo := &svcPayeeRepo{}
+		repo.On("GetByIdTx", mock.Anything, (pgx.Tx)(nil), budgetID, targetID).
+			Return(&model.Payee{ID: targetID}, nil).Once()
+
+		_, err := NewPayeeService(repo, nil, nil, nil).Merge(ctx, targetID, model.MergePayeesRequest{SourceIDs: []uuid.UUID{targetID}})
+		assert.True(t, hasErrorCode(err, errs.CodeInvalidArgument), err)
+	})
+
+	t.Run("rejects_transfer_payees", func(t *testing.T) {
+		repo := &svcPayeeRepo{}
+		repo.On("GetByIdTx", mock.Anything, (pgx.Tx)(nil), budgetID, targetID).
+			Return(&model.Payee{ID: targetID}, nil).Once()
+		repo.On("GetByIdTx", mock.Anything, (pgx.Tx)(nil), budgetID, sourceID).
+			Return(&model.Payee{ID: sourceID, Name: "Transfer : Savings", TransferAccountID: &transferAccountID}, nil).Once()
+
+		_, err := NewPayeeService(repo, nil, nil, nil).Merge(ctx, targetID, model.MergePayeesRequest{SourceIDs: []uuid.UUID{sourceID}})
+		assert.True(t, hasErrorCode(err, errs.CodeInvalidArgument), err)
+		repo.AssertNotCalled(t, "Merge", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("missing_source", func(t *testing.T) {
+		repo := &svcPayeeRepo{}
+		repo.On("GetByIdTx", mock.Anything, (pgx.Tx)(nil), budgetID, targetID).
+			Return(&model.Payee{ID: targetID}, nil).Once()
+		repo.On("GetByIdTx", mock.Anything, (pgx.Tx)(nil), budgetID, sourceID).Return(nil, pgx.ErrNoRows).Once()
+
+		_, err := NewPayeeService(repo, nil, nil, nil).Merge(ctx, targetID, model.MergePayeesRequest{SourceIDs: []uuid.UUID{sourceID}})
+		assert.True(t, hasErrorCode(err, errs.CodePayeeNotFound), err)
+	})
+}
+
 // ─────────────────────────────────────────────────────────────────────────────
 // APIKeyService tests
 // ─────────────────────────────────────────────────────────────────────────────
@@ -1318,6 +1667,37 @@ func (m *svcMonthlyBudgetRepo) UpdateBudgetedByCatIdAndMonth(ctx context.Context
 func (m *svcMonthlyBudgetRepo) UpdateCarryoverByCatIdAndMonth(ctx context.Context, tx pgx.Tx, budgetId uuid.UUID, categoryId uuid.UUID, month string, amount float64) error {
 	return m.Called(ctx, tx, budgetId, categoryId, month, amount).Error(0)
 }
+func (m *svcMonthlyBudgetRepo) GetSummaries(ctx context.Context, budgetId uuid.UUID, startMonth string, endMonth string) ([]model.MonthlyBudgetSummary, error) {
+	args := m.Called(ctx, budgetId, startMonth, endMonth)
+	if v := args.Get(0); v != nil {
+		return v.([]model.MonthlyBudgetSummary), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+func (m *svcMonthlyBudgetRepo) MergeCategory(ctx context.Context, tx pgx.Tx, budgetId, fromCategoryId, toCategoryId uuid.UUID) (int64, error) {
+	args := m.Called(ctx, tx, budgetId, fromCategoryId, toCategoryId)
+	return args.Get(0).(int64), args.Error(1)
+}
+func (m *svcMonthlyBudgetRepo) LockBudget(ctx context.Context, tx pgx.Tx, budgetId uuid.UUID) error {
+	return m.Called(ctx, tx, budgetId).Error(0)
+}
+func (m *svcMonthlyBudgetRepo) GetByBudget(ctx context.Context, tx pgx.Tx, budgetId uuid.UUID) ([]model.MonthlyBudget, error) {
+	args := m.Called(ctx, tx, budgetId)
+	if v := args.Get(0); v != nil {
+		return v.([]model.MonthlyBudget), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+func (m *svcMonthlyBudgetRepo) GetActivity(ctx context.Context, tx pgx.Tx, budgetId uuid.UUID) ([]model.CategoryMonthActivity, error) {
+	args := m.Called(ctx, tx, budgetId)
+	if v := args.Get(0); v != nil {
+		return v.([]model.CategoryMonthActivity), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+func (m *svcMonthlyBudgetRepo) SetCarryover(ctx context.Context, tx pgx.Tx, budgetId uuid.UUID, categoryId uuid.UUID, month string, carryover float64) error {
+	return m.Called(ctx, tx, budgetId, categoryId, month, carryover).Error(0)
+}
 
 // ─────────────────────────────────────────────────────────────────────────────
 // MonthlyBudgetService.UpsertCarryover tests
@@ -1327,7 +1707,7 @@ func TestMonthlyBudgetService_UpsertCarryover_CreatesWhenNotFound(t *testing.T)
 	budgetID := uuid.New()
 	catID := uuid.New()
 	repo := &svcMonthlyBudgetRepo{}
-	svc := NewMonthlyBudgetService(repo)
+	svc := NewMonthlyBudgetService(repo, nil)
 
 	// Simulate: GetByCatIdAndMonth returns pgx.ErrNoRows → repo.Create is called
 	repo.On("GetByCatIdAndMonth", mock.Anything, (pgx.Tx)(nil), budgetID, catID, "2025-01").Return(nil, pgx.ErrNoRows)
@@ -1344,7 +1724,7 @@ func TestMonthlyBudgetService_UpsertCarryover_UpdatesWhenExists(t *testing.T) {
 	budgetID := uuid.New()
 	catID := uuid.New()
 	repo := &svcMonthlyBudgetRepo{}
-	svc := NewMonthlyBudgetService(repo)
+	svc := NewMonthlyBudgetService(repo, nil)
 	existing := &model.MonthlyBudget{CategoryID: catID}
 
 	repo.On("GetByCatIdAndMonth", mock.Anything, (pgx.Tx)(nil), budgetID, catID, "2025-01").Return(existing, nil)
@@ -1359,7 +1739,7 @@ func TestMonthlyBudgetService_UpsertCarryover_LookupErrorPropagates(t *testing.T
 	budgetID := uuid.New()
 	catID := uuid.New()
 	repo := &svcMonthlyBudgetRepo{}
-	svc := NewMonthlyBudgetService(repo)
+	svc := NewMonthlyBudgetService(repo, nil)
 
 	repo.On("GetByCatIdAndMonth", mock.Anything, (pgx.Tx)(nil), budgetID, catID, "2025-01").Return(nil, assert.AnError)
 
@@ -1379,7 +1759,7 @@ func TestCategoryService_Search(t *testing.T) {
 	t.Run("returns_results", func(t *testing.T) {
 		repo := &svcCategoryRepo{}
 		repo.On("Search", mock.Anything, budgetID, "gro").Return([]model.Category{{Name: "Groceries"}}, nil)
-		cats, err := NewCategoryService(repo, nil, nil).Search(ctx, "gro")
+		cats, err := NewCategoryService(repo, nil, nil, nil).Search(ctx, "gro")
 		assert.NoError(t, err)
 		assert.Len(t, cats, 1)
 		repo.AssertExpectations(t)
@@ -1387,7 +1767,7 @@ func TestCategoryService_Search(t *testing.T) {
 	t.Run("repo_error_propagates", func(t *testing.T) {
 		repo := &svcCategoryRepo{}
 		repo.On("Search", mock.Anything, budgetID, "bad").Return(nil, assert.AnError)
-		cats, err := NewCategoryService(repo, nil, nil).Search(ctx, "bad")
+		cats, err := NewCategoryService(repo, nil, nil, nil).Search(ctx, "bad")
 		assert.Error(t, err)
 		assert.Nil(t, cats)
 		repo.AssertExpectations(t)
@@ -1402,7 +1782,7 @@ func TestCategoryService_GetById(t *testing.T) {
 	t.Run("returns_category", func(t *testing.T) {
 		repo := &svcCategoryRepo{}
 		repo.On("GetById", mock.Anything, budgetID, catID).Return(&model.Category{ID: catID}, nil)
-		cat, err := NewCategoryService(repo, nil, nil).GetById(ctx, catID)
+		cat, err := NewCategoryService(repo, nil, nil, nil).GetById(ctx, catID)
 		assert.NoError(t, err)
 		assert.Equal(t, catID, cat.ID)
 		repo.AssertExpectations(t)
@@ -1410,7 +1790,7 @@ func TestCategoryService_GetById(t *testing.T) {
 	t.Run("repo_error_propagates", func(t *testing.T) {
 		repo := &svcCategoryRepo{}
 		repo.On("GetById", mock.Anything, budgetID, catID).Return(nil, assert.AnError)
-		cat, err := NewCategoryService(repo, nil, nil).GetById(ctx, catID)
+		cat, err := NewCategoryService(repo, nil, nil, nil).GetById(ctx, catID)
 		assert.Error(t, err)
 		assert.Nil(t, cat)
 		repo.AssertExpectations(t)
@@ -1435,7 +1815,7 @@ func TestPayeeService_Search(t *testing.T) {
 
 	t.Run("returns_payees", func(t *testing.T) {
 		repo.On("Search", mock.Anything, budgetID, "ama").Return([]model.Payee{{Name: "Amazon"}}, nil)
-		payees, err := NewPayeeService(repo, ruleRepo).Search(ctx, "ama")
+		payees, err := NewPayeeService(repo, ruleRepo, nil, nil).Search(ctx, "ama")
 		assert.NoError(t, err)
 		assert.Len(t, payees, 1)
 		repo.AssertExpectations(t)
@@ -1454,14 +1834,14 @@ func TestPayeeService_UpdateRule(t *testing.T) {
 		ruleRepo.On("Update", mock.Anything, budgetID, ruleID, mock.MatchedBy(func(r model.PayeeRule) bool {
 			return r.MatchType == "EXACT" && r.PayeeID == payeeID && r.BudgetID == budgetID
 		})).Return(nil)
-		err := NewPayeeService(repo, ruleRepo).UpdateRule(ctx, payeeID, ruleID, model.PayeeRule{MatchString: "test"})
+		err := NewPayeeService(repo, ruleRepo, nil, nil).UpdateRule(ctx, payeeID, ruleID, model.PayeeRule{MatchString: "test"})
 		assert.NoError(t, err)
 		ruleRepo.AssertExpectations(t)
 	})
 	t.Run("repo_error_propagates", func(t *testing.T) {
 		ruleRepo2 := &svcPayeeRuleRepo{}
 		ruleRepo2.On("Update", mock.Anything, budgetID, ruleID, mock.Anything).Return(assert.AnError)
-		err := NewPayeeService(repo, ruleRepo2).UpdateRule(ctx, payeeID, ruleID, model.PayeeRule{MatchString: "test"})
+		err := NewPayeeService(repo, ruleRepo2, nil, nil).UpdateRule(ctx, payeeID, ruleID, model.PayeeRule{MatchString: "test"})
 		assert.Error(t, err)
 		ruleRepo2.AssertExpectations(t)
 	})
@@ -1747,7 +2127,7 @@ func TestMonthlyBudgetService_ApplyCarryoverOps_SameCategoryAndMonth_NoDiff(t *t
 	budgetID := uuid.New()
 	catID := uuid.New()
 	repo := &svcMonthlyBudgetRepo{}
-	svc := NewMonthlyBudgetService(repo)
+	svc := NewMonthlyBudgetService(repo, nil)
 
 	diff := &txnDiff{
 		oldCatId:    &catID,
@@ -2025,7 +2405,7 @@ func TestMonthlyBudgetService_ApplyCarryoverOps_SameCategoryAndMonth_NilNewCatId
 	budgetID := uuid.New()
 	oldCatID := uuid.New()
 	repo := &svcMonthlyBudgetRepo{}
-	svc := NewMonthlyBudgetService(repo)
+	svc := NewMonthlyBudgetService(repo, nil)
 
 	diff := &txnDiff{
 		oldCatId:    &oldCatID,
@@ -2045,7 +2425,7 @@ func TestMonthlyBudgetService_ApplyCarryoverOps_SameCategoryAndMonth_AmountChang
 	budgetID := uuid.New()
 	catID := uuid.New()
 	repo := &svcMonthlyBudgetRepo{}
-	svc := NewMonthlyBudgetService(repo)
+	svc := NewMonthlyBudgetService(repo, nil)
 
 	diff := &txnDiff{
 		oldCatId:    &catID,
@@ -2070,7 +2450,7 @@ func TestMonthlyBudgetService_ApplyCarryoverOps_DifferentCategory(t *testing.T)
 	oldCatID := uuid.New()
 	newCatID := uuid.New()
 	repo := &svcMonthlyBudgetRepo{}
-	svc := NewMonthlyBudgetService(repo)
+	svc := NewMonthlyBudgetService(repo, nil)
 
 	diff := &txnDiff{
 		oldCatId:    &oldCatID,
@@ -2156,7 +2536,7 @@ func TestMonthlyBudgetService_UpsertCarryover_UpdateError(t *testing.T) {
 	budgetID := uuid.New()
 	catID := uuid.New()
 	repo := &svcMonthlyBudgetRepo{}
-	svc := NewMonthlyBudgetService(repo)
+	svc := NewMonthlyBudgetService(repo, nil)
 	existing := &model.MonthlyBudget{CategoryID: catID}
 	repo.On("GetByCatIdAndMonth", mock.Anything, (pgx.Tx)(nil), budgetID, catID, "2025-01").Return(existing, nil)
 	repo.On("UpdateCarryoverByCatIdAndMonth", mock.Anything, (pgx.Tx)(nil), budgetID, catID, "2025-01", 50.0).Return(assert.AnError)
@@ -2173,7 +2553,7 @@ func TestMonthlyBudgetService_ApplyCarryoverOps_UpsertError(t *testing.T) {
 	budgetID := uuid.New()
 	catID := uuid.New()
 	repo := &svcMonthlyBudgetRepo{}
-	svc := NewMonthlyBudgetService(repo)
+	svc := NewMonthlyBudgetService(repo, nil)
 	diff := &txnDiff{
 		oldCatId:    &catID,
 		newCatId:    &catID,
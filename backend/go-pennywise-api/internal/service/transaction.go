@@ -2,6 +2,7 @@ package service
 
 import (
 	"context"
+	"math"
 	"strings"
 	"time"
 
@@ -29,6 +30,24 @@ type TransactionService interface {
 	Create(ctx context.Context, txn model.Transaction) ([]model.Transaction, error)
 	CreateWithTx(ctx context.Context, tx pgx.Tx, txn model.Transaction) ([]model.Transaction, error)
 	DeleteById(ctx context.Context, id uuid.UUID) error
+	// Restore brings back a deleted transaction along with its transfer counterpart
+	Restore(ctx context.Context, id uuid.UUID) error
+	Bulk(ctx context.Context, ops []model.BulkTransactionOperation) (*model.BulkTransactionResponse, error)
+	// FindTransferMatches proposes pairs of unlinked transactions that look like the two sides of a
+	// transfer. A transaction is proposed at most once, paired with its closest match by date.
+	FindTransferMatches(ctx context.Context, windowDays int) ([]model.TransferMatch, error)
+	// AcceptTransferMatch links an outflow and an inflow into one transfer
+	AcceptTransferMatch(ctx context.Context, outflowId uuid.UUID, inflowId uuid.UUID) (*model.TransferMatch, error)
+	// GetDuplicates returns the suspected duplicates of a status, pending ones when status is empty
+	GetDuplicates(ctx context.Context, status model.DuplicateStatus) ([]model.TransactionDuplicate, error)
+	// MergeDuplicate keeps one transaction of a suspected duplicate and deletes the other. Details only
+	// the deleted transaction has, like its note, category or tags, are carried over to the kept one.
+	MergeDuplicate(ctx context.Context, id uuid.UUID, keepId *uuid.UUID) (*model.Transaction, error)
+	DismissDuplicate(ctx context.Context, id uuid.UUID) error
+	// Undo reverts the last count mutations the current user made in the budget, newest first
+	Undo(ctx context.Context, count int) (*model.UndoResponse, error)
+	// Redo reapplies the last count undone mutations, in the order they were undone
+	Redo(ctx context.Context, count int) (*model.UndoResponse, error)
 }
 
 type transactionService struct {
@@ -43,6 +62,9 @@ type transactionService struct {
 	payeeRepo            repository.PayeesRepository
 	categoryRepo         repository.CategoryRepository
 	mbService            MonthlyBudgetService
+	auditService         AuditService
+	journalRepo          repository.OperationJournalRepository
+	duplicateRepo        repository.TransactionDuplicateRepository
 }
 
 func NewTransactionService(
@@ -57,6 +79,9 @@ func NewTransactionService(
 	payeeRepo repository.PayeesRepository,
 	catRepo repository.CategoryRepository,
 	mbService MonthlyBudgetService,
+	auditService AuditService,
+	journalRepo repository.OperationJournalRepository,
+	duplicateRepo repository.TransactionDuplicateRepository,
 ) TransactionService {
 	return &transactionService{
 		repo:                 r,
@@ -70,6 +95,9 @@ func NewTransactionService(
 		payeeRepo:            payeeRepo,
 		categoryRepo:         catRepo,
 		mbService:            mbService,
+		auditService:         auditService,
+		journalRepo:          journalRepo,
+		duplicateRepo:        duplicateRepo,
 	}
 }
 
@@ -160,10 +188,67 @@ func (s *transactionService) validateTransactionPayload(txn model.Transaction, b
 	if err := txn.Date.Valid(); err != nil {
 		return err
 	}
+	if txn.Cleared != "" {
+		if err := txn.Cleared.Valid(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
 
+// checkReconciledLock keeps the cleared status of foundTxn when the update doesn't set one
+// and rejects balance changes to reconciled transactions. A reconciled transaction has to be
+// moved back to cleared or uncleared before its amount, date or account can be edited.
+func checkReconciledLock(foundTxn *model.Transaction, toUpdate *model.Transaction) error {
+	if toUpdate.Cleared == "" {
+		toUpdate.Cleared = foundTxn.Cleared
+	}
+	if toUpdate.Cleared != model.ClearedStatusReconciled {
+		return nil
+	}
+	if foundTxn.Cleared != model.ClearedStatusReconciled {
+		return errs.New(errs.CodeInvalidArgument, "transactions can only be reconciled through account reconciliation")
+	}
+	accountChanged := (foundTxn.AccountID == nil) != (toUpdate.AccountID == nil) ||
+		(foundTxn.AccountID != nil && toUpdate.AccountID != nil && *foundTxn.AccountID != *toUpdate.AccountID)
+	if math.Round(foundTxn.Amount*100) != math.Round(toUpdate.Amount*100) ||
+		foundTxn.Date != toUpdate.Date ||
+		accountChanged {
+		return errs.New(
+			errs.CodeTransactionLocked,
+			"transaction %v is reconciled, its amount, date and account can't change",
+			foundTxn.ID,
+		)
+	}
 	return nil
 }
 
+// checkCounterpartLock applies the reconciled lock to the counterpart of a transfer. A nil change means
+// the counterpart is deleted, which a reconciled counterpart never is.
+func (s *transactionService) checkCounterpartLock(
+	ctx context.Context,
+	tx pgx.Tx,
+	budgetId uuid.UUID,
+	id uuid.UUID,
+	change *model.Transaction,
+) error {
+	counterpart, err := s.repo.GetByIdTx(ctx, tx, budgetId, id)
+	if err == pgx.ErrNoRows {
+		return nil
+	}
+	if err != nil {
+		return errs.Wrap(errs.CodeTransactionLookupFailed, "error getting transfer transaction", err)
+	}
+	if counterpart == nil || counterpart.Cleared != model.ClearedStatusReconciled {
+		return nil
+	}
+	if change == nil {
+		return errs.New(errs.CodeTransactionLocked, "transfer transaction %v is reconciled and can't be deleted", id)
+	}
+	return checkReconciledLock(counterpart, change)
+}
+
 // loadDependencies loads the budget, account, and payee for the transaction
 func (s *transactionService) loadDependencies(
 	ctx context.Context,
@@ -207,13 +292,7 @@ func (s *transactionService) validateCategory(
 	amount float64,
 ) error {
 	// budget -> budget transfers don't have a category
-	isBudgetAcount := account.Type == "savings" || account.Type == "checking" || account.Type == "creditCard"
-	isTransferBudget := false
-	if transferAccount != nil && isBudgetAcount {
-		isTransferBudget = transferAccount.Type == "savings" || transferAccount.Type == "checking" ||
-			transferAccount.Type == "creditCard"
-	}
-	if isBudgetAcount && isTransferBudget {
+	if isBudgetTransfer(account, transferAccount) {
 		if categoryID != nil {
 			return errs.New(errs.CodeInvalidArgument, "category is not allowed for budget transfers")
 		}
@@ -224,16 +303,97 @@ func (s *transactionService) validateCategory(
 	return nil
 }
 
-// createCounterpartTxn creates the counterpart transaction for a transfer
-func (s *transactionService) createCounterpartTxn(
+// validatePaymentCategories rejects categorizing the transaction or any of its split lines into the
+// payment category of a credit card, money only gets there by spending on the card
+func validatePaymentCategories(txn model.Transaction, paymentCategoryIDs map[uuid.UUID]uuid.UUID) error {
+	isPayment := func(categoryID *uuid.UUID) bool {
+		if categoryID == nil {
+			return false
+		}
+		for _, paymentCategoryID := range paymentCategoryIDs {
+			if *categoryID == paymentCategoryID {
+				return true
+			}
+		}
+		return false
+	}
+	if isPayment(txn.CategoryID) {
+		return errs.New(errs.CodeInvalidArgument, "transactions can't be categorized into a credit card payment category")
+	}
+	for i, split := range txn.Splits {
+		if isPayment(split.CategoryID) {
+			return errs.New(errs.CodeInvalidArgument, "split line %d can't use a credit card payment category", i+1)
+		}
+	}
+	return nil
+}
+
+// loadPaymentCategoryIDs maps the credit card accounts of the budget to their payment category
+func (s *transactionService) loadPaymentCategoryIDs(
 	ctx context.Context,
 	tx pgx.Tx,
 	budgetId uuid.UUID,
+) (map[uuid.UUID]uuid.UUID, error) {
+	if s.categoryRepo == nil {
+		return nil, nil
+	}
+	ids, err := s.categoryRepo.GetPaymentCategoryIDs(ctx, tx, budgetId)
+	if err != nil {
+		return nil, errs.Wrap(errs.CodeCategoryLookupFailed, "error getting credit card payment categories", err)
+	}
+	return ids, nil
+}
+
+// isBudgetTransfer reports whether money moves between two on-budget accounts
+func isBudgetTransfer(account model.Account, transferAccount *model.Account) bool {
+	isBudgetAccount := func(account model.Account) bool {
+		return account.Type == "savings" || account.Type == "checking" || account.Type == "creditCard"
+	}
+	return transferAccount != nil && isBudgetAccount(account) && isBudgetAccount(*transferAccount)
+}
+
+// validateSplits validates the split lines of a transaction.
+// Split transactions can't be transfers, need at least two lines and the lines must add up to the amount.
+// The parent category is cleared since the category lives on each line.
+func (s *transactionService) validateSplits(txn *model.Transaction, inflowCategoryID uuid.UUID, payee model.Payee) error {
+	if !txn.IsSplit() {
+		return nil
+	}
+	if payee.TransferAccountID != nil {
+		return errs.New(errs.CodeInvalidArgument, "transfers can't be split")
+	}
+	if len(txn.Splits) < 2 {
+		return errs.New(errs.CodeInvalidArgument, "split transactions need at least two lines")
+	}
+	for i, split := range txn.Splits {
+		if split.CategoryID == nil {
+			return errs.New(errs.CodeInvalidArgument, "category is required for split line %d", i+1)
+		}
+		if *split.CategoryID == inflowCategoryID && split.Amount < 0 {
+			return errs.New(errs.CodeInvalidArgument, "negative inflow category amounts are not allowed")
+		}
+	}
+	// compare in cents to avoid floating point residue
+	if math.Round(txn.SplitsTotal()*100) != math.Round(txn.Amount*100) {
+		return errs.New(
+			errs.CodeInvalidArgument,
+			"split amounts (%.2f) must add up to the transaction amount (%.2f)",
+			txn.SplitsTotal(),
+			txn.Amount,
+		)
+	}
+	txn.CategoryID = nil
+	return nil
+}
+
+// transferCounterpart returns the other side of the transfer txn in account makes to payee
+func transferCounterpart(
+	budgetId uuid.UUID,
 	parentId uuid.UUID,
 	txn model.Transaction,
 	account model.Account,
 	payee model.Payee,
-) (uuid.UUID, error) {
+) model.Transaction {
 	counterpart := model.Transaction{
 		BudgetID:              budgetId,
 		AccountID:             payee.TransferAccountID,
@@ -249,6 +409,20 @@ func (s *transactionService) createCounterpartTxn(
 	if counterpart.Status == "" {
 		counterpart.Status = model.TransactionStatusManual
 	}
+	return counterpart
+}
+
+// createCounterpartTxn creates the counterpart transaction for a transfer
+func (s *transactionService) createCounterpartTxn(
+	ctx context.Context,
+	tx pgx.Tx,
+	budgetId uuid.UUID,
+	parentId uuid.UUID,
+	txn model.Transaction,
+	account model.Account,
+	payee model.Payee,
+) (uuid.UUID, error) {
+	counterpart := transferCounterpart(budgetId, parentId, txn, account, payee)
 	created, err := s.repo.Create(ctx, tx, counterpart)
 	if err != nil {
 		return uuid.Nil, errs.Wrap(errs.CodeTransferCreateFailed, "error creating transfer transaction", err)
@@ -256,19 +430,196 @@ func (s *transactionService) createCounterpartTxn(
 	if len(created) == 0 {
 		return uuid.Nil, errs.New(errs.CodeTransferNotCreated, "no transfer transaction was created")
 	}
+	counterpart.ID = created[0].ID
+	counterpart.Cleared = created[0].Cleared
+	if err = s.recordAudit(ctx, tx, model.AuditActionCreate, counterpart.ID, nil, &counterpart); err != nil {
+		return uuid.Nil, err
+	}
 	return created[0].ID, nil
 }
 
+// updateCounterpart overwrites the counterpart of a transfer with the given transaction
+func (s *transactionService) updateCounterpart(
+	ctx context.Context,
+	tx pgx.Tx,
+	budgetId uuid.UUID,
+	id uuid.UUID,
+	counterpart model.Transaction,
+) error {
+	before, err := s.loadForAudit(ctx, tx, budgetId, id)
+	if err != nil {
+		return err
+	}
+	if err = s.repo.Update(ctx, tx, budgetId, id, counterpart); err != nil {
+		return err
+	}
+	if before == nil {
+		return nil
+	}
+	counterpart.ID = id
+	if counterpart.Cleared == "" {
+		counterpart.Cleared = before.Cleared
+	}
+	return s.recordAudit(ctx, tx, model.AuditActionUpdate, id, before, &counterpart)
+}
+
+// deleteCounterpart soft deletes the counterpart of a transfer
+func (s *transactionService) deleteCounterpart(ctx context.Context, tx pgx.Tx, budgetId uuid.UUID, id uuid.UUID) error {
+	before, err := s.loadForAudit(ctx, tx, budgetId, id)
+	if err != nil {
+		return err
+	}
+	if err = s.repo.DeleteById(ctx, tx, budgetId, id); err != nil {
+		return err
+	}
+	return s.recordAudit(ctx, tx, model.AuditActionDelete, id, before, nil)
+}
+
+// loadForAudit returns the current state of a transaction, or nil when auditing isn't configured
+func (s *transactionService) loadForAudit(
+	ctx context.Context,
+	tx pgx.Tx,
+	budgetId uuid.UUID,
+	id uuid.UUID,
+) (*model.Transaction, error) {
+	if s.auditService == nil {
+		return nil, nil
+	}
+	txn, err := s.repo.GetByIdTx(ctx, tx, budgetId, id)
+	if err != nil {
+		return nil, errs.Wrap(errs.CodeTransactionLookupFailed, "error loading transaction for audit", err)
+	}
+	return txn, nil
+}
+
+// applyCarryoverLines adds the carryover lines of txn, or takes them out again with sign -1
+func (s *transactionService) applyCarryoverLines(
+	ctx context.Context,
+	tx pgx.Tx,
+	budgetId uuid.UUID,
+	txn *model.Transaction,
+	rules carryoverRules,
+	sign float64,
+) error {
+	for _, line := range carryoverLines(txn, rules) {
+		if err := s.mbService.UpsertCarryover(
+			ctx,
+			tx,
+			budgetId,
+			line.categoryId,
+			line.monthKey,
+			sign*line.amountDelta,
+		); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// loadCounterpartForCarryovers returns the current state of a transfer counterpart. Counterparts carry
+// no category, only the payment category of a credit card is touched by them, so nothing is loaded
+// when the budget has no credit cards.
+func (s *transactionService) loadCounterpartForCarryovers(
+	ctx context.Context,
+	tx pgx.Tx,
+	budgetId uuid.UUID,
+	id uuid.UUID,
+	rules carryoverRules,
+) (*model.Transaction, error) {
+	if len(rules.paymentCategoryIDs) == 0 {
+		return nil, nil
+	}
+	txn, err := s.repo.GetByIdTx(ctx, tx, budgetId, id)
+	if err != nil {
+		return nil, errs.Wrap(errs.CodeTransactionLookupFailed, "error getting transfer transaction", err)
+	}
+	return txn, nil
+}
+
+// createCounterpartWithCarryovers creates the counterpart of a transfer and applies its carryover lines
+func (s *transactionService) createCounterpartWithCarryovers(
+	ctx context.Context,
+	tx pgx.Tx,
+	budgetId uuid.UUID,
+	parentId uuid.UUID,
+	txn model.Transaction,
+	account model.Account,
+	payee model.Payee,
+	rules carryoverRules,
+) (uuid.UUID, error) {
+	createdId, err := s.createCounterpartTxn(ctx, tx, budgetId, parentId, txn, account, payee)
+	if err != nil {
+		return uuid.Nil, err
+	}
+	counterpart := transferCounterpart(budgetId, parentId, txn, account, payee)
+	if err = s.applyCarryoverLines(ctx, tx, budgetId, &counterpart, rules, 1); err != nil {
+		return uuid.Nil, err
+	}
+	return createdId, nil
+}
+
+// deleteCounterpartWithCarryovers deletes the counterpart of a transfer and takes out its carryover lines
+func (s *transactionService) deleteCounterpartWithCarryovers(
+	ctx context.Context,
+	tx pgx.Tx,
+	budgetId uuid.UUID,
+	id uuid.UUID,
+	rules carryoverRules,
+) error {
+	before, err := s.loadCounterpartForCarryovers(ctx, tx, budgetId, id, rules)
+	if err != nil {
+		return err
+	}
+	if err = s.deleteCounterpart(ctx, tx, budgetId, id); err != nil {
+		return err
+	}
+	if before == nil {
+		return nil
+	}
+	return s.applyCarryoverLines(ctx, tx, budgetId, before, rules, -1)
+}
+
+// recordAudit appends a transaction mutation to the audit log when auditing is configured
+func (s *transactionService) recordAudit(
+	ctx context.Context,
+	tx pgx.Tx,
+	action model.AuditAction,
+	id uuid.UUID,
+	before *model.Transaction,
+	after *model.Transaction,
+) error {
+	if s.auditService == nil {
+		return nil
+	}
+	return s.auditService.Record(ctx, tx, AuditRecord{
+		EntityType: model.AuditEntityTransaction,
+		EntityID:   id,
+		Action:     action,
+		Before:     newTransactionAuditSnapshot(before),
+		After:      newTransactionAuditSnapshot(after),
+	})
+}
+
 // sideEffectInput holds the context for applying side effects to a transaction.
 // oldTxn == nil means create, newTxn == nil means delete, both non-nil means update.
 type sideEffectInput struct {
-	budgetId      uuid.UUID
-	oldTxn        *model.Transaction // nil for create
-	newTxn        *model.Transaction // nil for delete
-	budget        *model.Budget      // required for create (inflow category check)
-	account       *model.Account     // nil for delete
-	payee         *model.Payee       // nil for delete
-	queueLearning func(model.Transaction)
+	budgetId           uuid.UUID
+	oldTxn             *model.Transaction      // nil for create
+	newTxn             *model.Transaction      // nil for delete
+	budget             *model.Budget           // required for create (inflow category check)
+	account            *model.Account          // nil for delete
+	payee              *model.Payee            // nil for delete
+	paymentCategoryIDs map[uuid.UUID]uuid.UUID // credit card account -> payment category
+	queueLearning      func(model.Transaction)
+}
+
+// carryoverRules returns the rules the carryovers of the transaction are applied with
+func (input sideEffectInput) carryoverRules() carryoverRules {
+	rules := carryoverRules{paymentCategoryIDs: input.paymentCategoryIDs}
+	if input.budget != nil {
+		rules.inflowCategoryID = input.budget.Metadata.InflowCategoryID
+	}
+	return rules
 }
 
 func transactionMappingChanged(oldTxn, newTxn *model.Transaction) bool {
@@ -361,20 +712,19 @@ func (s *transactionService) applySideEffects(ctx context.Context, tx pgx.Tx, in
 	isDelete := input.oldTxn != nil && input.newTxn == nil
 
 	// --- Carryovers ---
+	rules := input.carryoverRules()
+	// the transfer fields of the new transaction are only linked further down
+	var newTxn model.Transaction
+	if input.newTxn != nil {
+		newTxn = *input.newTxn
+		if input.payee != nil {
+			newTxn.TransferAccountID = input.payee.TransferAccountID
+		}
+	}
 	switch {
 	case isCreate:
-		if input.newTxn.CategoryID != nil && *input.newTxn.CategoryID != input.budget.Metadata.InflowCategoryID {
-			monthKey := utils.GetMonthKey(input.newTxn.Date.String())
-			if err := s.mbService.UpsertCarryover(
-				ctx,
-				tx,
-				input.budgetId,
-				*input.newTxn.CategoryID,
-				monthKey,
-				input.newTxn.Amount,
-			); err != nil {
-				return err
-			}
+		if err := s.applyCarryoverLines(ctx, tx, input.budgetId, &newTxn, rules, 1); err != nil {
+			return err
 		}
 	case isUpdate:
 		if err := s.mbService.UpdateCarryovers(
@@ -382,25 +732,14 @@ func (s *transactionService) applySideEffects(ctx context.Context, tx pgx.Tx, in
 			tx,
 			input.budgetId,
 			input.oldTxn,
-			input.newTxn,
-			input.budget.Metadata.InflowCategoryID,
+			&newTxn,
+			rules,
 		); err != nil {
 			return err
 		}
 	case isDelete:
-		if input.oldTxn.CategoryID != nil &&
-			(input.budget == nil || *input.oldTxn.CategoryID != input.budget.Metadata.InflowCategoryID) {
-			monthKey := utils.GetMonthKey(input.oldTxn.Date.String())
-			if err := s.mbService.UpsertCarryover(
-				ctx,
-				tx,
-				input.budgetId,
-				*input.oldTxn.CategoryID,
-				monthKey,
-				-input.oldTxn.Amount,
-			); err != nil {
-				return err
-			}
+		if err := s.applyCarryoverLines(ctx, tx, input.budgetId, input.oldTxn, rules, -1); err != nil {
+			return err
 		}
 	}
 
@@ -408,7 +747,7 @@ func (s *transactionService) applySideEffects(ctx context.Context, tx pgx.Tx, in
 	switch {
 	case isCreate:
 		if input.payee != nil && input.payee.TransferAccountID != nil {
-			createdId, err := s.createCounterpartTxn(
+			createdId, err := s.createCounterpartWithCarryovers(
 				ctx,
 				tx,
 				input.budgetId,
@@ -416,6 +755,7 @@ func (s *transactionService) applySideEffects(ctx context.Context, tx pgx.Tx, in
 				*input.newTxn,
 				*input.account,
 				*input.payee,
+				rules,
 			)
 			if err != nil {
 				return err
@@ -435,12 +775,23 @@ func (s *transactionService) applySideEffects(ctx context.Context, tx pgx.Tx, in
 			input.newTxn,
 			*input.account,
 			*input.payee,
+			rules,
 		); err != nil {
 			return err
 		}
 	case isDelete:
 		if input.oldTxn.TransferTransactionID != nil {
-			if err := s.repo.DeleteById(ctx, tx, input.budgetId, *input.oldTxn.TransferTransactionID); err != nil {
+			err := s.checkCounterpartLock(ctx, tx, input.budgetId, *input.oldTxn.TransferTransactionID, nil)
+			if err != nil {
+				return err
+			}
+			if err := s.deleteCounterpartWithCarryovers(
+				ctx,
+				tx,
+				input.budgetId,
+				*input.oldTxn.TransferTransactionID,
+				rules,
+			); err != nil {
 				return errs.Wrap(errs.CodeTransactionDeleteFailed, "error deleting transfer transaction", err)
 			}
 		}
@@ -495,18 +846,24 @@ func (s *transactionService) reconcileTransfer(
 	newTxn *model.Transaction,
 	account model.Account,
 	payee model.Payee,
+	rules carryoverRules,
 ) error {
 	// reconcile transfer transactions
 	wasTransfer := foundTxn.TransferTransactionID != nil
 	isTransfer := payee.TransferAccountID != nil
 	samePayee := foundTxn.PayeeID != nil && newTxn.PayeeID != nil && *foundTxn.PayeeID == *newTxn.PayeeID
+	if wasTransfer && !(isTransfer && samePayee) {
+		if err := s.checkCounterpartLock(ctx, tx, budgetId, *foundTxn.TransferTransactionID, nil); err != nil {
+			return err
+		}
+	}
 
 	switch {
 	case wasTransfer && !isTransfer:
 		// transfer → regular: delete counterpart, clear fields
 		logger.Logger(ctx).
 			Info("converting transfer to regular, deleting counterpart", "transferTxnId", *foundTxn.TransferTransactionID)
-		if err := s.repo.DeleteById(ctx, tx, budgetId, *foundTxn.TransferTransactionID); err != nil {
+		if err := s.deleteCounterpartWithCarryovers(ctx, tx, budgetId, *foundTxn.TransferTransactionID, rules); err != nil {
 			return errs.Wrap(errs.CodeTransactionDeleteFailed, "error deleting transfer transaction", err)
 		}
 		newTxn.TransferAccountID = nil
@@ -515,7 +872,7 @@ func (s *transactionService) reconcileTransfer(
 	case !wasTransfer && isTransfer:
 		// regular → transfer: create counterpart
 		logger.Logger(ctx).Info("converting regular to transfer, creating counterpart")
-		createdId, err := s.createCounterpartTxn(ctx, tx, budgetId, foundTxn.ID, *newTxn, account, payee)
+		createdId, err := s.createCounterpartWithCarryovers(ctx, tx, budgetId, foundTxn.ID, *newTxn, account, payee, rules)
 		if err != nil {
 			return err
 		}
@@ -525,10 +882,10 @@ func (s *transactionService) reconcileTransfer(
 	case wasTransfer && isTransfer && !samePayee:
 		// transfer → different transfer: delete old counterpart, create new
 		logger.Logger(ctx).Info("changing transfer destination, recreating counterpart")
-		if err := s.repo.DeleteById(ctx, tx, budgetId, *foundTxn.TransferTransactionID); err != nil {
+		if err := s.deleteCounterpartWithCarryovers(ctx, tx, budgetId, *foundTxn.TransferTransactionID, rules); err != nil {
 			return errs.Wrap(errs.CodeTransactionDeleteFailed, "error deleting old transfer transaction", err)
 		}
-		createdId, err := s.createCounterpartTxn(ctx, tx, budgetId, foundTxn.ID, *newTxn, account, payee)
+		createdId, err := s.createCounterpartWithCarryovers(ctx, tx, budgetId, foundTxn.ID, *newTxn, account, payee, rules)
 		if err != nil {
 			return err
 		}
@@ -554,9 +911,21 @@ func (s *transactionService) reconcileTransfer(
 		if counterpart.Status == "" {
 			counterpart.Status = model.TransactionStatusManual
 		}
-		if err := s.repo.Update(ctx, tx, budgetId, *foundTxn.TransferTransactionID, counterpart); err != nil {
+		if err := s.checkCounterpartLock(ctx, tx, budgetId, *foundTxn.TransferTransactionID, &counterpart); err != nil {
+			return err
+		}
+		before, err := s.loadCounterpartForCarryovers(ctx, tx, budgetId, *foundTxn.TransferTransactionID, rules)
+		if err != nil {
+			return err
+		}
+		if err = s.updateCounterpart(ctx, tx, budgetId, *foundTxn.TransferTransactionID, counterpart); err != nil {
 			return errs.Wrap(errs.CodeTransactionUpdateFailed, "error updating transfer counterpart", err)
 		}
+		if before != nil {
+			if err = s.mbService.UpdateCarryovers(ctx, tx, budgetId, before, &counterpart, rules); err != nil {
+				return err
+			}
+		}
 		newTxn.TransferAccountID = foundTxn.TransferAccountID
 		newTxn.TransferTransactionID = foundTxn.TransferTransactionID
 	}
@@ -608,16 +977,31 @@ func (s *transactionService) CreateWithTx(
 	if txn.Status == "" {
 		txn.Status = model.TransactionStatusManual
 	}
+	if txn.Cleared == "" {
+		txn.Cleared = model.ClearedStatusUncleared
+	}
 
 	if err := s.validateTransactionPayload(txn, budgetID); err != nil {
 		return nil, err
 	}
+	if err := txn.ApplyOriginalAmount(); err != nil {
+		return nil, err
+	}
+	if txn.Cleared == model.ClearedStatusReconciled {
+		return nil, errs.New(errs.CodeInvalidArgument, "transactions can only be reconciled through account reconciliation")
+	}
 
 	var createdTxn []model.Transaction
 	budget, account, payee, transferAccount, err := s.loadDependencies(ctx, tx, budgetID, txn)
 	if err != nil {
 		return nil, err
 	}
+	if account.Closed {
+		return nil, errs.New(errs.CodeAccountClosed, "account %s is closed", account.Name)
+	}
+	if transferAccount != nil && transferAccount.Closed {
+		return nil, errs.New(errs.CodeAccountClosed, "can't transfer to closed account %s", transferAccount.Name)
+	}
 
 	if err = s.validateCategory(
 		txn.CategoryID,
@@ -629,6 +1013,16 @@ func (s *transactionService) CreateWithTx(
 	); err != nil {
 		return nil, err
 	}
+	if err = s.validateSplits(&txn, budget.Metadata.InflowCategoryID, *payee); err != nil {
+		return nil, err
+	}
+	paymentCategoryIDs, err := s.loadPaymentCategoryIDs(ctx, tx, budgetID)
+	if err != nil {
+		return nil, err
+	}
+	if err = validatePaymentCategories(txn, paymentCategoryIDs); err != nil {
+		return nil, err
+	}
 
 	// clear transfer fields in case they are set
 	txn.TransferAccountID = nil
@@ -644,13 +1038,20 @@ func (s *transactionService) CreateWithTx(
 
 	txn.ID = createdTxn[0].ID
 
+	if txn.IsSplit() {
+		if err = s.repo.ReplaceSplits(ctx, tx, budgetID, txn.ID, txn.Splits); err != nil {
+			return nil, errs.Wrap(errs.CodeTransactionCreateFailed, "failed to create split lines", err)
+		}
+	}
+
 	if err = s.applySideEffects(ctx, tx, sideEffectInput{
-		budgetId: budgetID,
-		oldTxn:   nil,
-		newTxn:   &txn,
-		budget:   budget,
-		account:  account,
-		payee:    payee,
+		budgetId:           budgetID,
+		oldTxn:             nil,
+		newTxn:             &txn,
+		budget:             budget,
+		account:            account,
+		payee:              payee,
+		paymentCategoryIDs: paymentCategoryIDs,
 	}); err != nil {
 		return nil, err
 	}
@@ -662,6 +1063,17 @@ func (s *transactionService) CreateWithTx(
 	}
 	createdTxn[0] = *final
 
+	if err = s.flagDuplicates(ctx, tx, budgetID, final); err != nil {
+		return nil, err
+	}
+
+	if err = s.recordAudit(ctx, tx, model.AuditActionCreate, final.ID, nil, final); err != nil {
+		return nil, err
+	}
+	if err = s.recordJournal(ctx, tx, model.AuditActionCreate, final.ID, nil, final); err != nil {
+		return nil, err
+	}
+
 	return createdTxn, nil
 }
 
@@ -693,54 +1105,8 @@ func (s *transactionService) Update(ctx context.Context, id uuid.UUID, txn model
 			return errs.New(errs.CodeTransactionLookupFailed, "transaction id mismatch")
 		}
 
-		toUpdate.ID = id
-		toUpdate.Status = foundTxn.Status
-		if foundTxn.Status == model.TransactionStatusUnapproved {
-			toUpdate.Status = model.TransactionStatusApproved
-		}
-		same := foundTxn.Compare(&toUpdate)
-		if same {
-			logger.Logger(txCtx).Info("transaction is the same as the existing transaction, skipping update")
-			return nil
-		}
-
-		// fetch updated txn account and payee
-		budget, account, payee, transferAccount, err := s.loadDependencies(txCtx, tx, budgetId, toUpdate)
-		if err != nil {
-			return err
-		}
-
-		err = s.validateCategory(
-			toUpdate.CategoryID,
-			budget.Metadata.InflowCategoryID,
-			*account,
-			*payee,
-			transferAccount,
-			toUpdate.Amount,
-		)
-		if err != nil {
-			return err
-		}
-
-		if err = s.applySideEffects(txCtx, tx, sideEffectInput{
-			budgetId: budgetId,
-			oldTxn:   foundTxn,
-			newTxn:   &toUpdate,
-			budget:   budget,
-			account:  account,
-			payee:    payee,
-			queueLearning: func(txn model.Transaction) {
-				learningTxn = &txn
-			},
-		}); err != nil {
-			return err
-		}
-
-		if err = s.repo.Update(txCtx, tx, budgetId, id, toUpdate); err != nil {
-			return errs.Wrap(errs.CodeTransactionUpdateFailed, "error updating transaction", err)
-		}
-
-		return nil
+		learningTxn, err = s.updateWithTx(txCtx, tx, budgetId, foundTxn, toUpdate)
+		return err
 	})
 	if err != nil {
 		return err
@@ -752,6 +1118,117 @@ func (s *transactionService) Update(ctx context.Context, id uuid.UUID, txn model
 	return nil
 }
 
+// updateWithTx applies the update of foundTxn to toUpdate along with all its side effects.
+// It returns the transaction to learn from once the db transaction commits, if any.
+func (s *transactionService) updateWithTx(
+	ctx context.Context,
+	tx pgx.Tx,
+	budgetId uuid.UUID,
+	foundTxn *model.Transaction,
+	toUpdate model.Transaction,
+) (*model.Transaction, error) {
+	id := foundTxn.ID
+	toUpdate.ID = id
+	// an update without splits keeps the split lines, an empty list turns the transaction back into a
+	// single category one
+	if toUpdate.Splits == nil {
+		toUpdate.Splits = foundTxn.Splits
+	}
+	if toUpdate.IsSplit() {
+		toUpdate.CategoryID = nil
+	}
+	toUpdate.Status = foundTxn.Status
+	if foundTxn.Status == model.TransactionStatusUnapproved {
+		toUpdate.Status = model.TransactionStatusApproved
+	}
+	// clients that don't know about foreign currency charges keep the original amount,
+	// the fx rate follows the new amount
+	if toUpdate.OriginalAmount == nil && toUpdate.OriginalCurrency == nil {
+		toUpdate.OriginalAmount = foundTxn.OriginalAmount
+		toUpdate.OriginalCurrency = foundTxn.OriginalCurrency
+	}
+	if err := toUpdate.ApplyOriginalAmount(); err != nil {
+		return nil, err
+	}
+	if err := checkReconciledLock(foundTxn, &toUpdate); err != nil {
+		return nil, err
+	}
+	same := foundTxn.Compare(&toUpdate)
+	if same {
+		logger.Logger(ctx).Info("transaction is the same as the existing transaction, skipping update")
+		return nil, nil
+	}
+
+	// fetch updated txn account and payee
+	budget, account, payee, transferAccount, err := s.loadDependencies(ctx, tx, budgetId, toUpdate)
+	if err != nil {
+		return nil, err
+	}
+	// transactions already in a closed account stay editable, nothing new can be moved into one
+	if account.Closed && (foundTxn.AccountID == nil || *foundTxn.AccountID != account.ID) {
+		return nil, errs.New(errs.CodeAccountClosed, "account %s is closed", account.Name)
+	}
+	if transferAccount != nil && transferAccount.Closed &&
+		(foundTxn.PayeeID == nil || *foundTxn.PayeeID != payee.ID) {
+		return nil, errs.New(errs.CodeAccountClosed, "can't transfer to closed account %s", transferAccount.Name)
+	}
+
+	err = s.validateCategory(
+		toUpdate.CategoryID,
+		budget.Metadata.InflowCategoryID,
+		*account,
+		*payee,
+		transferAccount,
+		toUpdate.Amount,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if err = s.validateSplits(&toUpdate, budget.Metadata.InflowCategoryID, *payee); err != nil {
+		return nil, err
+	}
+	paymentCategoryIDs, err := s.loadPaymentCategoryIDs(ctx, tx, budgetId)
+	if err != nil {
+		return nil, err
+	}
+	if err = validatePaymentCategories(toUpdate, paymentCategoryIDs); err != nil {
+		return nil, err
+	}
+
+	var learningTxn *model.Transaction
+	if err = s.applySideEffects(ctx, tx, sideEffectInput{
+		budgetId:           budgetId,
+		oldTxn:             foundTxn,
+		newTxn:             &toUpdate,
+		budget:             budget,
+		account:            account,
+		payee:              payee,
+		paymentCategoryIDs: paymentCategoryIDs,
+		queueLearning: func(txn model.Transaction) {
+			learningTxn = &txn
+		},
+	}); err != nil {
+		return nil, err
+	}
+
+	if err = s.repo.Update(ctx, tx, budgetId, id, toUpdate); err != nil {
+		return nil, errs.Wrap(errs.CodeTransactionUpdateFailed, "error updating transaction", err)
+	}
+	if toUpdate.IsSplit() || foundTxn.IsSplit() {
+		if err = s.repo.ReplaceSplits(ctx, tx, budgetId, id, toUpdate.Splits); err != nil {
+			return nil, errs.Wrap(errs.CodeTransactionUpdateFailed, "error updating split lines", err)
+		}
+	}
+	if err = s.recordAudit(ctx, tx, model.AuditActionUpdate, id, foundTxn, &toUpdate); err != nil {
+		return nil, err
+	}
+	if err = s.recordJournal(ctx, tx, model.AuditActionUpdate, id, foundTxn, &toUpdate); err != nil {
+		return nil, err
+	}
+
+	return learningTxn, nil
+}
+
 func (s *transactionService) UpdateStatus(ctx context.Context, id uuid.UUID, status model.TransactionStatus) error {
 	txCtx, txCancel := context.WithTimeout(ctx, 30*time.Second)
 	defer txCancel()
@@ -760,7 +1237,7 @@ func (s *transactionService) UpdateStatus(ctx context.Context, id uuid.UUID, sta
 
 	if status != model.TransactionStatusApproved {
 		return withTx(txCtx, s.repo.GetDB(), func(tx pgx.Tx) error {
-			return s.repo.UpdateStatus(txCtx, tx, budgetId, id, status)
+			return s.updateStatusWithTx(txCtx, tx, budgetId, id, status)
 		})
 	}
 
@@ -780,29 +1257,23 @@ func (s *transactionService) UpdateStatus(ctx context.Context, id uuid.UUID, sta
 	if err != nil {
 		if err == pgx.ErrNoRows {
 			return withTx(txCtx, s.repo.GetDB(), func(tx pgx.Tx) error {
-				return s.repo.UpdateStatus(txCtx, tx, budgetId, id, status)
+				return s.updateStatusWithTx(txCtx, tx, budgetId, id, status)
 			})
 		}
 		return errs.Wrap(errs.CodeTransactionLookupFailed, "error getting cipher prediction", err)
 	}
 	if cipherPrediction == nil {
 		return withTx(txCtx, s.repo.GetDB(), func(tx pgx.Tx) error {
-			return s.repo.UpdateStatus(txCtx, tx, budgetId, id, status)
+			return s.updateStatusWithTx(txCtx, tx, budgetId, id, status)
 		})
 	}
 
-	if foundTxn.PayeeID == nil {
-		return errs.New(errs.CodeInvalidArgument, "payee is required to approve transaction")
-	}
-	if foundTxn.CategoryID == nil {
-		return errs.New(errs.CodeInvalidArgument, "category is required to approve transaction")
-	}
-	if foundTxn.RawBankText == nil || strings.TrimSpace(*foundTxn.RawBankText) == "" {
-		return errs.New(errs.CodeInvalidArgument, "raw bank text is required to approve transaction")
+	if err := validatePredictionApproval(foundTxn); err != nil {
+		return err
 	}
 
 	if err := withTx(txCtx, s.repo.GetDB(), func(tx pgx.Tx) error {
-		return s.repo.UpdateStatus(txCtx, tx, budgetId, id, status)
+		return s.updateStatusWithTx(txCtx, tx, budgetId, id, status)
 	}); err != nil {
 		return errs.Wrap(errs.CodeTransactionUpdateFailed, "error updating transaction status", err)
 	}
@@ -812,6 +1283,47 @@ func (s *transactionService) UpdateStatus(ctx context.Context, id uuid.UUID, sta
 	return nil
 }
 
+// validatePredictionApproval checks that a cipher predicted transaction has everything its mapping
+// is learned from before it is approved
+func validatePredictionApproval(txn *model.Transaction) error {
+	if txn.PayeeID == nil {
+		return errs.New(errs.CodeInvalidArgument, "payee is required to approve transaction")
+	}
+	if txn.CategoryID == nil {
+		return errs.New(errs.CodeInvalidArgument, "category is required to approve transaction")
+	}
+	if txn.RawBankText == nil || strings.TrimSpace(*txn.RawBankText) == "" {
+		return errs.New(errs.CodeInvalidArgument, "raw bank text is required to approve transaction")
+	}
+	return nil
+}
+
+// updateStatusWithTx sets the approval status of a transaction
+func (s *transactionService) updateStatusWithTx(
+	ctx context.Context,
+	tx pgx.Tx,
+	budgetId uuid.UUID,
+	id uuid.UUID,
+	status model.TransactionStatus,
+) error {
+	if s.auditService == nil && !s.journaling(ctx) {
+		return s.repo.UpdateStatus(ctx, tx, budgetId, id, status)
+	}
+	before, err := s.repo.GetByIdTx(ctx, tx, budgetId, id)
+	if err != nil {
+		return errs.Wrap(errs.CodeTransactionLookupFailed, "error getting transaction", err)
+	}
+	if err = s.repo.UpdateStatus(ctx, tx, budgetId, id, status); err != nil {
+		return err
+	}
+	after := *before
+	after.Status = status
+	if err = s.recordAudit(ctx, tx, model.AuditActionUpdate, id, before, &after); err != nil {
+		return err
+	}
+	return s.recordJournal(ctx, tx, model.AuditActionUpdate, id, before, &after)
+}
+
 func (s *transactionService) DeleteById(ctx context.Context, id uuid.UUID) error {
 	txCtx, txCancel := context.WithTimeout(ctx, 30*time.Second)
 	defer txCancel()
@@ -820,33 +1332,50 @@ func (s *transactionService) DeleteById(ctx context.Context, id uuid.UUID) error
 	logger.Logger(ctx).Info("deleting transaction", "id", id)
 
 	return withTx(txCtx, s.repo.GetDB(), func(tx pgx.Tx) error {
-		foundTxn, err := s.repo.GetByIdTx(txCtx, tx, budgetId, id)
-		if err != nil {
-			return errs.Wrap(errs.CodeTransactionLookupFailed, "error getting transaction", err)
-		}
-		if foundTxn == nil {
-			return errs.New(errs.CodeTransactionLookupFailed, "transaction not found for id %v", id)
-		}
-		logger.Logger(txCtx).Debug("found transaction for delete", "txn", foundTxn.String())
+		return s.deleteWithTx(txCtx, tx, budgetId, id)
+	})
+}
 
-		budget, err := s.budgetRepo.GetById(txCtx, tx, budgetId)
-		if err != nil {
-			return errs.Wrap(errs.CodeBudgetLookupFailed, "error fetching budget", err)
-		}
+// deleteWithTx soft deletes a transaction and reverses its side effects
+func (s *transactionService) deleteWithTx(ctx context.Context, tx pgx.Tx, budgetId uuid.UUID, id uuid.UUID) error {
+	foundTxn, err := s.repo.GetByIdTx(ctx, tx, budgetId, id)
+	if err != nil {
+		return errs.Wrap(errs.CodeTransactionLookupFailed, "error getting transaction", err)
+	}
+	if foundTxn == nil {
+		return errs.New(errs.CodeTransactionLookupFailed, "transaction not found for id %v", id)
+	}
+	logger.Logger(ctx).Debug("found transaction for delete", "txn", foundTxn.String())
+	if foundTxn.Cleared == model.ClearedStatusReconciled {
+		return errs.New(errs.CodeTransactionLocked, "reconciled transaction %v can't be deleted", id)
+	}
 
-		if err = s.applySideEffects(txCtx, tx, sideEffectInput{
-			budgetId: budgetId,
-			oldTxn:   foundTxn,
-			newTxn:   nil,
-			budget:   budget,
-		}); err != nil {
-			return err
-		}
+	budget, err := s.budgetRepo.GetById(ctx, tx, budgetId)
+	if err != nil {
+		return errs.Wrap(errs.CodeBudgetLookupFailed, "error fetching budget", err)
+	}
 
-		if err = s.repo.DeleteById(txCtx, tx, budgetId, id); err != nil {
-			return errs.Wrap(errs.CodeTransactionDeleteFailed, "error deleting transaction", err)
-		}
+	paymentCategoryIDs, err := s.loadPaymentCategoryIDs(ctx, tx, budgetId)
+	if err != nil {
+		return err
+	}
 
-		return nil
-	})
+	if err = s.applySideEffects(ctx, tx, sideEffectInput{
+		budgetId:           budgetId,
+		oldTxn:             foundTxn,
+		newTxn:             nil,
+		budget:             budget,
+		paymentCategoryIDs: paymentCategoryIDs,
+	}); err != nil {
+		return err
+	}
+
+	if err = s.repo.DeleteById(ctx, tx, budgetId, id); err != nil {
+		return errs.Wrap(errs.CodeTransactionDeleteFailed, "error deleting transaction", err)
+	}
+
+	if err = s.recordAudit(ctx, tx, model.AuditActionDelete, id, foundTxn, nil); err != nil {
+		return err
+	}
+	return s.recordJournal(ctx, tx, model.AuditActionDelete, id, foundTxn, nil)
 }
@@ -0,0 +1,198 @@
+package service
+
+import (
+	"context"
+	"slices"
+	"time"
+
+	errs "github.com/Rishabh-Kapri/pennywise/backend/shared/errors"
+	"github.com/Rishabh-Kapri/pennywise/backend/shared/logger"
+	"github.com/Rishabh-Kapri/pennywise/backend/shared/model"
+	utils "github.com/Rishabh-Kapri/pennywise/backend/shared/utils"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+// maxBulkOperations caps the size of a single bulk request
+const maxBulkOperations = 500
+
+// validateBulkOperation checks that an operation carries the fields its action needs
+func validateBulkOperation(op model.BulkTransactionOperation) error {
+	if op.TransactionID == uuid.Nil {
+		return errs.New(errs.CodeInvalidArgument, "transactionId is required")
+	}
+	switch op.Action {
+	case model.BulkActionApprove, model.BulkActionReject, model.BulkActionDelete:
+	case model.BulkActionRecategorize:
+		if op.CategoryID == nil {
+			return errs.New(errs.CodeInvalidArgument, "categoryId is required to recategorize")
+		}
+	case model.BulkActionSetPayee:
+		if op.PayeeID == nil {
+			return errs.New(errs.CodeInvalidArgument, "payeeId is required to set payee")
+		}
+	case model.BulkActionAddTags, model.BulkActionRemoveTags:
+		if len(op.TagIDs) == 0 {
+			return errs.New(errs.CodeInvalidArgument, "tagIds are required to %s", op.Action)
+		}
+	default:
+		return errs.New(errs.CodeInvalidArgument, "unsupported bulk action %q", op.Action)
+	}
+	return nil
+}
+
+// Bulk applies all operations in a single db transaction. The first failing operation
+// rolls back every operation and is reported in the results along with the error.
+func (s *transactionService) Bulk(
+	ctx context.Context,
+	ops []model.BulkTransactionOperation,
+) (*model.BulkTransactionResponse, error) {
+	txCtx, txCancel := context.WithTimeout(ctx, 60*time.Second)
+	defer txCancel()
+
+	budgetId := utils.MustBudgetID(ctx)
+	logger.Logger(ctx).Info("applying bulk transaction operations", "count", len(ops))
+
+	if len(ops) == 0 {
+		return nil, errs.New(errs.CodeInvalidArgument, "at least one operation is required")
+	}
+	if len(ops) > maxBulkOperations {
+		return nil, errs.New(errs.CodeInvalidArgument, "at most %d operations are allowed", maxBulkOperations)
+	}
+
+	response := &model.BulkTransactionResponse{Results: make([]model.BulkTransactionResult, len(ops))}
+	for i, op := range ops {
+		response.Results[i] = model.BulkTransactionResult{
+			Index:         i,
+			TransactionID: op.TransactionID,
+			Action:        op.Action,
+			Status:        model.BulkResultNotApplied,
+		}
+	}
+
+	failedIdx := -1
+	var learningTxns []model.Transaction
+	err := withTx(txCtx, s.repo.GetDB(), func(tx pgx.Tx) error {
+		for i, op := range ops {
+			learningTxn, err := s.applyBulkOperation(txCtx, tx, budgetId, op)
+			if err != nil {
+				failedIdx = i
+				return err
+			}
+			if learningTxn != nil {
+				learningTxns = append(learningTxns, *learningTxn)
+			}
+			response.Results[i].Status = model.BulkResultApplied
+		}
+		return nil
+	})
+	if err != nil {
+		for i := range response.Results {
+			if response.Results[i].Status == model.BulkResultApplied {
+				response.Results[i].Status = model.BulkResultRolledBack
+			}
+		}
+		if failedIdx >= 0 {
+			response.Results[failedIdx].Status = model.BulkResultFailed
+			response.Results[failedIdx].Error = err.Error()
+		}
+		return response, err
+	}
+
+	response.Applied = true
+	for _, txn := range learningTxns {
+		s.learnTransactionMappingAsync(ctx, budgetId, txn)
+	}
+	return response, nil
+}
+
+// applyBulkOperation applies a single bulk operation inside tx.
+// It returns the transaction to learn from once the db transaction commits, if any.
+func (s *transactionService) applyBulkOperation(
+	ctx context.Context,
+	tx pgx.Tx,
+	budgetId uuid.UUID,
+	op model.BulkTransactionOperation,
+) (*model.Transaction, error) {
+	if err := validateBulkOperation(op); err != nil {
+		return nil, err
+	}
+	if op.Action == model.BulkActionDelete {
+		return nil, s.deleteWithTx(ctx, tx, budgetId, op.TransactionID)
+	}
+
+	foundTxn, err := s.repo.GetByIdTx(ctx, tx, budgetId, op.TransactionID)
+	if err != nil {
+		return nil, errs.Wrap(errs.CodeTransactionLookupFailed, "error getting transaction", err)
+	}
+	if foundTxn == nil {
+		return nil, errs.New(errs.CodeTransactionLookupFailed, "transaction not found for id %v", op.TransactionID)
+	}
+
+	toUpdate := *foundTxn
+	switch op.Action {
+	case model.BulkActionApprove, model.BulkActionReject:
+		return s.applyBulkStatus(ctx, tx, budgetId, foundTxn, op.Action)
+	case model.BulkActionRecategorize:
+		if foundTxn.IsSplit() {
+			return nil, errs.New(errs.CodeInvalidArgument, "split transactions can't be recategorized in bulk")
+		}
+		toUpdate.CategoryID = op.CategoryID
+	case model.BulkActionSetPayee:
+		toUpdate.PayeeID = op.PayeeID
+	case model.BulkActionAddTags:
+		toUpdate.TagIDs = slices.Clone(foundTxn.TagIDs)
+		for _, tagId := range op.TagIDs {
+			if !slices.Contains(toUpdate.TagIDs, tagId) {
+				toUpdate.TagIDs = append(toUpdate.TagIDs, tagId)
+			}
+		}
+	case model.BulkActionRemoveTags:
+		toUpdate.TagIDs = slices.DeleteFunc(slices.Clone(foundTxn.TagIDs), func(tagId uuid.UUID) bool {
+			return slices.Contains(op.TagIDs, tagId)
+		})
+	}
+
+	return s.updateWithTx(ctx, tx, budgetId, foundTxn, toUpdate)
+}
+
+// applyBulkStatus approves or rejects a transaction. Approving a cipher predicted
+// transaction queues the mapping for learning, the same way UpdateStatus does.
+func (s *transactionService) applyBulkStatus(
+	ctx context.Context,
+	tx pgx.Tx,
+	budgetId uuid.UUID,
+	foundTxn *model.Transaction,
+	action model.BulkTransactionAction,
+) (*model.Transaction, error) {
+	status := model.TransactionStatusRejected
+	if action == model.BulkActionApprove {
+		status = model.TransactionStatusApproved
+	}
+	if foundTxn.Status == status {
+		return nil, nil
+	}
+
+	var cipherPrediction *model.CipherPredictionRecord
+	if status == model.TransactionStatusApproved && s.cipherPredictionRepo != nil {
+		var err error
+		cipherPrediction, err = s.cipherPredictionRepo.GetByTransactionID(ctx, budgetId, foundTxn.ID)
+		if err != nil && err != pgx.ErrNoRows {
+			return nil, errs.Wrap(errs.CodeTransactionLookupFailed, "error getting cipher prediction", err)
+		}
+		if cipherPrediction != nil {
+			if err = validatePredictionApproval(foundTxn); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if err := s.updateStatusWithTx(ctx, tx, budgetId, foundTxn.ID, status); err != nil {
+		return nil, errs.Wrap(errs.CodeTransactionUpdateFailed, "error updating transaction status", err)
+	}
+	if cipherPrediction == nil {
+		return nil, nil
+	}
+	return foundTxn, nil
+}
@@ -0,0 +1,186 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	errs "github.com/Rishabh-Kapri/pennywise/backend/shared/errors"
+	"github.com/Rishabh-Kapri/pennywise/backend/shared/model"
+	utils "github.com/Rishabh-Kapri/pennywise/backend/shared/utils"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateBulkOperation(t *testing.T) {
+	txnId := uuid.New()
+	categoryId := uuid.New()
+
+	tests := []struct {
+		name    string
+		op      model.BulkTransactionOperation
+		wantErr bool
+	}{
+		{name: "approve", op: model.BulkTransactionOperation{TransactionID: txnId, Action: model.BulkActionApprove}},
+		{
+			name: "recategorize",
+			op:   model.BulkTransactionOperation{TransactionID: txnId, Action: model.BulkActionRecategorize, CategoryID: &categoryId},
+		},
+		{
+			name:    "recategorize_without_category",
+			op:      model.BulkTransactionOperation{TransactionID: txnId, Action: model.BulkActionRecategorize},
+			wantErr: true,
+		},
+		{
+			name:    "set_payee_without_payee",
+			op:      model.BulkTransactionOperation{TransactionID: txnId, Action: model.BulkActionSetPayee},
+			wantErr: true,
+		},
+		{
+			name:    "add_tags_without_tags",
+			op:      model.BulkTransactionOperation{TransactionID: txnId, Action: model.BulkActionAddTags},
+			wantErr: true,
+		},
+		{
+			name:    "missing_transaction",
+			op:      model.BulkTransactionOperation{Action: model.BulkActionDelete},
+			wantErr: true,
+		},
+		{
+			name:    "unknown_action",
+			op:      model.BulkTransactionOperation{TransactionID: txnId, Action: "ARCHIVE"},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateBulkOperation(tt.op)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestBulk(t *testing.T) {
+	var mockTx pgx.Tx
+	mockWithTxSuccess(mockTx)
+	defer func() { withTx = utils.WithTx }()
+
+	budgetId := uuid.New()
+	ctx := utils.WithBudgetID(context.Background(), budgetId)
+	accountId := uuid.New()
+	payeeId := uuid.New()
+	tagA := uuid.New()
+	tagB := uuid.New()
+
+	t.Run("no_operations", func(t *testing.T) {
+		service := newTestTransactionService(nil, nil, nil, nil, nil, nil, nil)
+		_, err := service.Bulk(ctx, nil)
+		assert.Error(t, err)
+	})
+
+	t.Run("applies_all_operations", func(t *testing.T) {
+		mockRepo := &mockTransactionRepo{}
+		mockBudget := &mockBudgetRepo{}
+		mockAccount := &mockAccountRepo{}
+		mockPayee := &mockPayeesRepo{}
+		service := newTestTransactionService(mockRepo, mockBudget, nil, mockAccount, mockPayee, nil, nil)
+
+		approveId := uuid.New()
+		tagId := uuid.New()
+		mockRepo.On("GetByIdTx", mock.Anything, mockTx, budgetId, approveId).
+			Return(&model.Transaction{ID: approveId, Status: model.TransactionStatusUnapproved}, nil).
+			Once()
+		mockRepo.On("UpdateStatus", mock.Anything, mockTx, budgetId, approveId, model.TransactionStatusApproved).
+			Return(nil).
+			Once()
+
+		mockRepo.On("GetByIdTx", mock.Anything, mockTx, budgetId, tagId).
+			Return(&model.Transaction{
+				ID:        tagId,
+				AccountID: &accountId,
+				PayeeID:   &payeeId,
+				Date:      "2024-01-01",
+				Amount:    -10,
+				Status:    model.TransactionStatusApproved,
+				TagIDs:    []uuid.UUID{tagA},
+			}, nil).
+			Once()
+		mockBudget.On("GetById", mock.Anything, mockTx, budgetId).Return(&model.Budget{}, nil).Once()
+		mockAccount.On("GetById", mock.Anything, mockTx, budgetId, accountId).
+			Return(&model.Account{Type: "checking"}, nil).
+			Once()
+		mockPayee.On("GetByIdTx", mock.Anything, mockTx, budgetId, payeeId).Return(&model.Payee{}, nil).Once()
+		mockRepo.On("Update", mock.Anything, mockTx, budgetId, tagId, mock.MatchedBy(func(txn model.Transaction) bool {
+			return len(txn.TagIDs) == 2 && txn.TagIDs[0] == tagA && txn.TagIDs[1] == tagB
+		})).Return(nil).Once()
+
+		res, err := service.Bulk(ctx, []model.BulkTransactionOperation{
+			{TransactionID: approveId, Action: model.BulkActionApprove},
+			{TransactionID: tagId, Action: model.BulkActionAddTags, TagIDs: []uuid.UUID{tagA, tagB}},
+		})
+		require.NoError(t, err)
+		assert.True(t, res.Applied)
+		for _, result := range res.Results {
+			assert.Equal(t, model.BulkResultApplied, result.Status)
+		}
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("predicted_approval_is_validated", func(t *testing.T) {
+		mockRepo := &mockTransactionRepo{}
+		cipherPredictionRepo := &mockCipherPredictionRepo{}
+		service := &transactionService{repo: mockRepo, cipherPredictionRepo: cipherPredictionRepo}
+
+		predictedId := uuid.New()
+		mockRepo.On("GetByIdTx", mock.Anything, mockTx, budgetId, predictedId).
+			Return(&model.Transaction{ID: predictedId, PayeeID: &payeeId, Status: model.TransactionStatusUnapproved}, nil).
+			Once()
+		cipherPredictionRepo.On("GetByTransactionID", mock.Anything, budgetId, predictedId).
+			Return(&model.CipherPredictionRecord{}, nil).
+			Once()
+
+		res, err := service.Bulk(ctx, []model.BulkTransactionOperation{
+			{TransactionID: predictedId, Action: model.BulkActionApprove},
+		})
+		require.Error(t, err)
+		assert.True(t, hasErrorCode(err, errs.CodeInvalidArgument))
+		assert.False(t, res.Applied)
+		mockRepo.AssertNotCalled(t, "UpdateStatus", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("failure_rolls_back_everything", func(t *testing.T) {
+		mockRepo := &mockTransactionRepo{}
+		service := newTestTransactionService(mockRepo, nil, nil, nil, nil, nil, nil)
+
+		rejectId := uuid.New()
+		missingId := uuid.New()
+		mockRepo.On("GetByIdTx", mock.Anything, mockTx, budgetId, rejectId).
+			Return(&model.Transaction{ID: rejectId, Status: model.TransactionStatusUnapproved}, nil).
+			Once()
+		mockRepo.On("UpdateStatus", mock.Anything, mockTx, budgetId, rejectId, model.TransactionStatusRejected).
+			Return(nil).
+			Once()
+		mockRepo.On("GetByIdTx", mock.Anything, mockTx, budgetId, missingId).Return(nil, pgx.ErrNoRows).Once()
+
+		res, err := service.Bulk(ctx, []model.BulkTransactionOperation{
+			{TransactionID: rejectId, Action: model.BulkActionReject},
+			{TransactionID: missingId, Action: model.BulkActionDelete},
+			{TransactionID: uuid.New(), Action: model.BulkActionApprove},
+		})
+		require.Error(t, err)
+		require.NotNil(t, res)
+		assert.False(t, res.Applied)
+		assert.Equal(t, model.BulkResultRolledBack, res.Results[0].Status)
+		assert.Equal(t, model.BulkResultFailed, res.Results[1].Status)
+		assert.NotEmpty(t, res.Results[1].Error)
+		assert.Equal(t, model.BulkResultNotApplied, res.Results[2].Status)
+	})
+}
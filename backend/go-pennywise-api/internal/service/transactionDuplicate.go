@@ -0,0 +1,189 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"time"
+
+	errs "github.com/Rishabh-Kapri/pennywise/backend/shared/errors"
+	"github.com/Rishabh-Kapri/pennywise/backend/shared/logger"
+	"github.com/Rishabh-Kapri/pennywise/backend/shared/model"
+	utils "github.com/Rishabh-Kapri/pennywise/backend/shared/utils"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+// flagDuplicates records the suspected duplicates of a newly created transaction.
+// Transfers are skipped since both sides are created together.
+func (s *transactionService) flagDuplicates(
+	ctx context.Context,
+	tx pgx.Tx,
+	budgetId uuid.UUID,
+	txn *model.Transaction,
+) error {
+	if s.duplicateRepo == nil || txn.TransferTransactionID != nil {
+		return nil
+	}
+	flagged, err := s.duplicateRepo.Flag(ctx, tx, budgetId, txn.ID)
+	if err != nil {
+		return errs.Wrap(errs.CodeDuplicateUpdateFailed, "error flagging duplicate transactions", err)
+	}
+	if flagged > 0 {
+		logger.Logger(ctx).Info("flagged suspected duplicate transaction", "txnId", txn.ID, "matches", flagged)
+	}
+	return nil
+}
+
+func (s *transactionService) GetDuplicates(
+	ctx context.Context,
+	status model.DuplicateStatus,
+) ([]model.TransactionDuplicate, error) {
+	budgetId := utils.MustBudgetID(ctx)
+	if status == "" {
+		status = model.DuplicateStatusPending
+	}
+	if !status.Valid() {
+		return nil, errs.New(errs.CodeInvalidArgument, "invalid duplicate status %q", status)
+	}
+	if s.duplicateRepo == nil {
+		return nil, errs.New(errs.CodeInternalError, "duplicate repository is not configured")
+	}
+	duplicates, err := s.duplicateRepo.GetAll(ctx, budgetId, status)
+	if err != nil {
+		return nil, errs.Wrap(errs.CodeDuplicateLookupFailed, "error getting duplicate transactions", err)
+	}
+	return duplicates, nil
+}
+
+func (s *transactionService) MergeDuplicate(
+	ctx context.Context,
+	id uuid.UUID,
+	keepId *uuid.UUID,
+) (*model.Transaction, error) {
+	txCtx, txCancel := context.WithTimeout(ctx, 30*time.Second)
+	defer txCancel()
+
+	budgetId := utils.MustBudgetID(ctx)
+	logger.Logger(ctx).Info("merging duplicate transactions", "duplicateId", id)
+
+	var kept *model.Transaction
+	err := withTx(txCtx, s.repo.GetDB(), func(tx pgx.Tx) error {
+		duplicate, err := s.getPendingDuplicate(txCtx, tx, budgetId, id)
+		if err != nil {
+			return err
+		}
+		keep, remove := duplicate.DuplicateOfID, duplicate.TransactionID
+		if keepId != nil {
+			switch *keepId {
+			case duplicate.DuplicateOfID:
+			case duplicate.TransactionID:
+				keep, remove = remove, keep
+			default:
+				return errs.New(errs.CodeInvalidArgument, "transaction %v is not part of the duplicate", *keepId)
+			}
+		}
+
+		keptTxn, err := s.getForMerge(txCtx, tx, budgetId, keep)
+		if err != nil {
+			return err
+		}
+		removedTxn, err := s.getForMerge(txCtx, tx, budgetId, remove)
+		if err != nil {
+			return err
+		}
+
+		merged := mergeDuplicateDetails(*keptTxn, *removedTxn)
+		if !keptTxn.Compare(&merged) {
+			if _, err = s.updateWithTx(txCtx, tx, budgetId, keptTxn, merged); err != nil {
+				return err
+			}
+		}
+		if err = s.deleteWithTx(txCtx, tx, budgetId, remove); err != nil {
+			return err
+		}
+		if err = s.duplicateRepo.UpdateStatus(txCtx, tx, budgetId, id, model.DuplicateStatusMerged); err != nil {
+			return errs.Wrap(errs.CodeDuplicateUpdateFailed, "error updating duplicate", err)
+		}
+
+		kept, err = s.repo.GetByIdTx(txCtx, tx, budgetId, keep)
+		if err != nil {
+			return errs.Wrap(errs.CodeTransactionLookupFailed, "error reloading merged transaction", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return kept, nil
+}
+
+func (s *transactionService) DismissDuplicate(ctx context.Context, id uuid.UUID) error {
+	budgetId := utils.MustBudgetID(ctx)
+	if _, err := s.getPendingDuplicate(ctx, nil, budgetId, id); err != nil {
+		return err
+	}
+	if err := s.duplicateRepo.UpdateStatus(ctx, nil, budgetId, id, model.DuplicateStatusDismissed); err != nil {
+		return errs.Wrap(errs.CodeDuplicateUpdateFailed, "error dismissing duplicate", err)
+	}
+	return nil
+}
+
+func (s *transactionService) getPendingDuplicate(
+	ctx context.Context,
+	tx pgx.Tx,
+	budgetId uuid.UUID,
+	id uuid.UUID,
+) (*model.TransactionDuplicate, error) {
+	if s.duplicateRepo == nil {
+		return nil, errs.New(errs.CodeInternalError, "duplicate repository is not configured")
+	}
+	duplicate, err := s.duplicateRepo.GetById(ctx, tx, budgetId, id)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, errs.New(errs.CodeDuplicateNotFound, "duplicate not found for id %v", id)
+	}
+	if err != nil {
+		return nil, errs.Wrap(errs.CodeDuplicateLookupFailed, "error getting duplicate", err)
+	}
+	if duplicate.Status != model.DuplicateStatusPending {
+		return nil, errs.New(errs.CodeInvalidArgument, "duplicate %v is already %s", id, duplicate.Status)
+	}
+	return duplicate, nil
+}
+
+func (s *transactionService) getForMerge(
+	ctx context.Context,
+	tx pgx.Tx,
+	budgetId uuid.UUID,
+	id uuid.UUID,
+) (*model.Transaction, error) {
+	txn, err := s.repo.GetByIdTx(ctx, tx, budgetId, id)
+	if errors.Is(err, pgx.ErrNoRows) || (err == nil && txn == nil) {
+		return nil, errs.New(errs.CodeTransactionNotFound, "transaction not found for id %v", id)
+	}
+	if err != nil {
+		return nil, errs.Wrap(errs.CodeTransactionLookupFailed, "error getting transaction", err)
+	}
+	return txn, nil
+}
+
+// mergeDuplicateDetails fills what kept is missing from removed. The amount, date and
+// account of kept stay as they are.
+func mergeDuplicateDetails(kept model.Transaction, removed model.Transaction) model.Transaction {
+	merged := kept
+	if merged.Note == "" {
+		merged.Note = removed.Note
+	}
+	canCategorize := !kept.IsSplit() && !removed.IsSplit() && kept.TransferTransactionID == nil
+	if merged.CategoryID == nil && canCategorize {
+		merged.CategoryID = removed.CategoryID
+	}
+	merged.TagIDs = slices.Clone(kept.TagIDs)
+	for _, tagId := range removed.TagIDs {
+		if !slices.Contains(merged.TagIDs, tagId) {
+			merged.TagIDs = append(merged.TagIDs, tagId)
+		}
+	}
+	return merged
+}
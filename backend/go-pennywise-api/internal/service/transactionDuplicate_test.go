@@ -0,0 +1,128 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	errs "github.com/Rishabh-Kapri/pennywise/backend/shared/errors"
+	"github.com/Rishabh-Kapri/pennywise/backend/shared/model"
+	utils "github.com/Rishabh-Kapri/pennywise/backend/shared/utils"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type mockDuplicateRepo struct {
+	mockBaseRepo
+	mock.Mock
+}
+
+func (m *mockDuplicateRepo) Flag(ctx context.Context, tx pgx.Tx, budgetId uuid.UUID, txnId uuid.UUID) (int64, error) {
+	args := m.Called(ctx, tx, budgetId, txnId)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *mockDuplicateRepo) GetAll(
+	ctx context.Context,
+	budgetId uuid.UUID,
+	status model.DuplicateStatus,
+) ([]model.TransactionDuplicate, error) {
+	args := m.Called(ctx, budgetId, status)
+	if v := args.Get(0); v != nil {
+		return v.([]model.TransactionDuplicate), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *mockDuplicateRepo) GetById(
+	ctx context.Context,
+	tx pgx.Tx,
+	budgetId uuid.UUID,
+	id uuid.UUID,
+) (*model.TransactionDuplicate, error) {
+	args := m.Called(ctx, tx, budgetId, id)
+	if v := args.Get(0); v != nil {
+		return v.(*model.TransactionDuplicate), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *mockDuplicateRepo) UpdateStatus(
+	ctx context.Context,
+	tx pgx.Tx,
+	budgetId uuid.UUID,
+	id uuid.UUID,
+	status model.DuplicateStatus,
+) error {
+	return m.Called(ctx, tx, budgetId, id, status).Error(0)
+}
+
+func TestFlagDuplicates(t *testing.T) {
+	ctx := context.Background()
+	budgetId := uuid.New()
+	txnId := uuid.New()
+	repo := &mockDuplicateRepo{}
+	service := newTestTransactionService(nil, nil, nil, nil, nil, nil, nil)
+	service.duplicateRepo = repo
+
+	repo.On("Flag", ctx, nil, budgetId, txnId).Return(int64(1), nil).Once()
+	require.NoError(t, service.flagDuplicates(ctx, nil, budgetId, &model.Transaction{ID: txnId}))
+
+	// both sides of a transfer are created together
+	counterpartId := uuid.New()
+	require.NoError(t, service.flagDuplicates(ctx, nil, budgetId, &model.Transaction{
+		ID:                    uuid.New(),
+		TransferTransactionID: &counterpartId,
+	}))
+	repo.AssertExpectations(t)
+}
+
+func TestDismissDuplicate(t *testing.T) {
+	budgetId := uuid.New()
+	ctx := utils.WithBudgetID(context.Background(), budgetId)
+	id := uuid.New()
+	repo := &mockDuplicateRepo{}
+	service := newTestTransactionService(nil, nil, nil, nil, nil, nil, nil)
+	service.duplicateRepo = repo
+
+	repo.On("GetById", ctx, nil, budgetId, id).
+		Return(&model.TransactionDuplicate{ID: id, Status: model.DuplicateStatusPending}, nil).Once()
+	repo.On("UpdateStatus", ctx, nil, budgetId, id, model.DuplicateStatusDismissed).Return(nil).Once()
+	require.NoError(t, service.DismissDuplicate(ctx, id))
+
+	repo.On("GetById", ctx, nil, budgetId, id).
+		Return(&model.TransactionDuplicate{ID: id, Status: model.DuplicateStatusMerged}, nil).Once()
+	assert.ErrorContains(t, service.DismissDuplicate(ctx, id), "already MERGED")
+
+	repo.On("GetById", ctx, nil, budgetId, id).Return(nil, pgx.ErrNoRows).Once()
+	assert.True(t, hasErrorCode(service.DismissDuplicate(ctx, id), errs.CodeDuplicateNotFound))
+	repo.AssertExpectations(t)
+}
+
+func TestMergeDuplicateDetails(t *testing.T) {
+	categoryId := uuid.New()
+	tagA, tagB := uuid.New(), uuid.New()
+
+	kept := model.Transaction{Amount: -250, Date: "2024-03-02", TagIDs: []uuid.UUID{tagA}}
+	removed := model.Transaction{
+		Amount:     -250.5,
+		Date:       "2024-03-01",
+		Note:       "dinner",
+		CategoryID: &categoryId,
+		TagIDs:     []uuid.UUID{tagA, tagB},
+	}
+	merged := mergeDuplicateDetails(kept, removed)
+	assert.Equal(t, -250.0, merged.Amount)
+	assert.Equal(t, model.Date("2024-03-02"), merged.Date)
+	assert.Equal(t, "dinner", merged.Note)
+	assert.Equal(t, &categoryId, merged.CategoryID)
+	assert.Equal(t, []uuid.UUID{tagA, tagB}, merged.TagIDs)
+	assert.Equal(t, []uuid.UUID{tagA}, kept.TagIDs)
+
+	// a split keeps its lines instead of taking a category
+	kept.Splits = []model.TransactionSplit{{Amount: -100}, {Amount: -150}}
+	assert.Nil(t, mergeDuplicateDetails(kept, removed).CategoryID)
+}
@@ -0,0 +1,204 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	repository "github.com/Rishabh-Kapri/pennywise/backend/shared/db"
+	errs "github.com/Rishabh-Kapri/pennywise/backend/shared/errors"
+	"github.com/Rishabh-Kapri/pennywise/backend/shared/model"
+	utils "github.com/Rishabh-Kapri/pennywise/backend/shared/utils"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+type TransactionTemplateService interface {
+	GetAll(ctx context.Context) ([]model.TransactionTemplate, error)
+	GetById(ctx context.Context, id uuid.UUID) (*model.TransactionTemplate, error)
+	Create(ctx context.Context, template model.TransactionTemplate) (*model.TransactionTemplate, error)
+	// CreateFromTransaction memorizes the account, payee, category, amount, note and tags of an existing transaction
+	CreateFromTransaction(ctx context.Context, req model.CreateTemplateFromTransactionRequest) (*model.TransactionTemplate, error)
+	Update(ctx context.Context, id uuid.UUID, template model.TransactionTemplate) (*model.TransactionTemplate, error)
+	DeleteById(ctx context.Context, id uuid.UUID) error
+	// Instantiate creates a new transaction from the template and counts the use in the same db transaction
+	Instantiate(ctx context.Context, id uuid.UUID, req model.InstantiateTemplateRequest) ([]model.Transaction, error)
+}
+
+type transactionTemplateService struct {
+	repo               repository.TransactionTemplateRepository
+	transactionRepo    repository.TransactionRepository
+	transactionService TransactionService
+}
+
+func NewTransactionTemplateService(
+	r repository.TransactionTemplateRepository,
+	transactionRepo repository.TransactionRepository,
+	transactionService TransactionService,
+) TransactionTemplateService {
+	return &transactionTemplateService{
+		repo:               r,
+		transactionRepo:    transactionRepo,
+		transactionService: transactionService,
+	}
+}
+
+func templateLookupError(err error) error {
+	if errors.Is(err, pgx.ErrNoRows) {
+		return errs.Wrap(errs.CodeTemplateNotFound, "transaction template not found", err)
+	}
+	return errs.Wrap(errs.CodeTemplateLookupFailed, "error getting transaction template", err)
+}
+
+func templateSaveError(code errs.Code, err error) error {
+	if utils.IsUniqueViolation(err) {
+		return errs.Wrap(errs.CodeTemplateNameTaken, "a transaction template with this name already exists", err)
+	}
+	return errs.Wrap(code, "error saving transaction template", err)
+}
+
+func validateTransactionTemplate(template *model.TransactionTemplate) error {
+	template.Name = strings.TrimSpace(template.Name)
+	if template.Name == "" {
+		return errs.New(errs.CodeInvalidArgument, "name is required")
+	}
+	return nil
+}
+
+func (s *transactionTemplateService) GetAll(ctx context.Context) ([]model.TransactionTemplate, error) {
+	budgetId := utils.MustBudgetID(ctx)
+	templates, err := s.repo.GetAll(ctx, budgetId)
+	if err != nil {
+		return nil, errs.Wrap(errs.CodeTemplateLookupFailed, "error getting transaction templates", err)
+	}
+	return templates, nil
+}
+
+func (s *transactionTemplateService) GetById(ctx context.Context, id uuid.UUID) (*model.TransactionTemplate, error) {
+	budgetId := utils.MustBudgetID(ctx)
+	template, err := s.repo.GetById(ctx, budgetId, id)
+	if err != nil {
+		return nil, templateLookupError(err)
+	}
+	return template, nil
+}
+
+func (s *transactionTemplateService) Create(
+	ctx context.Context,
+	template model.TransactionTemplate,
+) (*model.TransactionTemplate, error) {
+	template.BudgetID = utils.MustBudgetID(ctx)
+	if err := validateTransactionTemplate(&template); err != nil {
+		return nil, err
+	}
+	created, err := s.repo.Create(ctx, template)
+	if err != nil {
+		return nil, templateSaveError(errs.CodeTemplateCreateFailed, err)
+	}
+	return created, nil
+}
+
+func (s *transactionTemplateService) CreateFromTransaction(
+	ctx context.Context,
+	req model.CreateTemplateFromTransactionRequest,
+) (*model.TransactionTemplate, error) {
+	budgetId := utils.MustBudgetID(ctx)
+	txn, err := s.transactionRepo.GetByIdTx(ctx, nil, budgetId, req.TransactionID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, errs.Wrap(errs.CodeTransactionNotFound, "transaction not found", err)
+		}
+		return nil, errs.Wrap(errs.CodeTransactionLookupFailed, "error getting transaction", err)
+	}
+	// a template carries a single category, memorizing a split would silently drop its lines
+	if len(txn.Splits) > 0 {
+		return nil, errs.New(errs.CodeInvalidArgument, "split transactions can't be memorized as a template")
+	}
+
+	return s.Create(ctx, model.TransactionTemplate{
+		Name:       req.Name,
+		AccountID:  txn.AccountID,
+		PayeeID:    txn.PayeeID,
+		CategoryID: txn.CategoryID,
+		Amount:     txn.Amount,
+		Note:       txn.Note,
+		TagIDs:     txn.TagIDs,
+	})
+}
+
+func (s *transactionTemplateService) Update(
+	ctx context.Context,
+	id uuid.UUID,
+	template model.TransactionTemplate,
+) (*model.TransactionTemplate, error) {
+	budgetId := utils.MustBudgetID(ctx)
+	if err := validateTransactionTemplate(&template); err != nil {
+		return nil, err
+	}
+	if err := s.repo.Update(ctx, budgetId, id, template); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, templateLookupError(err)
+		}
+		return nil, templateSaveError(errs.CodeTemplateUpdateFailed, err)
+	}
+	return s.GetById(ctx, id)
+}
+
+func (s *transactionTemplateService) DeleteById(ctx context.Context, id uuid.UUID) error {
+	budgetId := utils.MustBudgetID(ctx)
+	if err := s.repo.DeleteById(ctx, budgetId, id); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return templateLookupError(err)
+		}
+		return errs.Wrap(errs.CodeTemplateDeleteFailed, "error deleting transaction template", err)
+	}
+	return nil
+}
+
+func (s *transactionTemplateService) Instantiate(
+	ctx context.Context,
+	id uuid.UUID,
+	req model.InstantiateTemplateRequest,
+) ([]model.Transaction, error) {
+	budgetId := utils.MustBudgetID(ctx)
+	template, err := s.repo.GetById(ctx, budgetId, id)
+	if err != nil {
+		return nil, templateLookupError(err)
+	}
+
+	txn := model.Transaction{
+		BudgetID:   budgetId,
+		AccountID:  template.AccountID,
+		PayeeID:    template.PayeeID,
+		CategoryID: template.CategoryID,
+		Amount:     template.Amount,
+		Note:       template.Note,
+		TagIDs:     template.TagIDs,
+		Date:       model.Date(time.Now().Format(scheduledDateLayout)),
+	}
+	if req.Date != nil {
+		txn.Date = *req.Date
+	}
+	if req.Amount != nil {
+		txn.Amount = *req.Amount
+	}
+
+	var created []model.Transaction
+	err = withTx(ctx, s.repo.GetDB(), func(tx pgx.Tx) error {
+		var err error
+		created, err = s.transactionService.CreateWithTx(ctx, tx, txn)
+		if err != nil {
+			return err
+		}
+		if err := s.repo.MarkUsed(ctx, tx, budgetId, id); err != nil {
+			return errs.Wrap(errs.CodeTemplateUpdateFailed, "error counting transaction template use", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return created, nil
+}
@@ -0,0 +1,215 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	errs "github.com/Rishabh-Kapri/pennywise/backend/shared/errors"
+	"github.com/Rishabh-Kapri/pennywise/backend/shared/model"
+	utils "github.com/Rishabh-Kapri/pennywise/backend/shared/utils"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type mockTransactionTemplateRepo struct {
+	mockBaseRepo
+	mock.Mock
+}
+
+func (m *mockTransactionTemplateRepo) GetAll(ctx context.Context, budgetId uuid.UUID) ([]model.TransactionTemplate, error) {
+	args := m.Called(ctx, budgetId)
+	if v := args.Get(0); v != nil {
+		return v.([]model.TransactionTemplate), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *mockTransactionTemplateRepo) GetById(
+	ctx context.Context,
+	budgetId uuid.UUID,
+	id uuid.UUID,
+) (*model.TransactionTemplate, error) {
+	args := m.Called(ctx, budgetId, id)
+	if v := args.Get(0); v != nil {
+		return v.(*model.TransactionTemplate), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *mockTransactionTemplateRepo) Create(
+	ctx context.Context,
+	template model.TransactionTemplate,
+) (*model.TransactionTemplate, error) {
+	args := m.Called(ctx, template)
+	if v := args.Get(0); v != nil {
+		return v.(*model.TransactionTemplate), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *mockTransactionTemplateRepo) Update(
+	ctx context.Context,
+	budgetId uuid.UUID,
+	id uuid.UUID,
+	template model.TransactionTemplate,
+) error {
+	return m.Called(ctx, budgetId, id, template).Error(0)
+}
+
+func (m *mockTransactionTemplateRepo) MarkUsed(ctx context.Context, tx pgx.Tx, budgetId uuid.UUID, id uuid.UUID) error {
+	return m.Called(ctx, tx, budgetId, id).Error(0)
+}
+
+func (m *mockTransactionTemplateRepo) DeleteById(ctx context.Context, budgetId uuid.UUID, id uuid.UUID) error {
+	return m.Called(ctx, budgetId, id).Error(0)
+}
+
+func TestTransactionTemplateService_CreateFromTransaction(t *testing.T) {
+	budgetId := uuid.New()
+	ctx := utils.WithBudgetID(context.Background(), budgetId)
+	accountId, payeeId, categoryId, tagId := uuid.New(), uuid.New(), uuid.New(), uuid.New()
+	txnId := uuid.New()
+
+	t.Run("copies_the_transaction", func(t *testing.T) {
+		repo := &mockTransactionTemplateRepo{}
+		txnRepo := &mockTransactionRepo{}
+		service := NewTransactionTemplateService(repo, txnRepo, nil)
+
+		txnRepo.On("GetByIdTx", ctx, nil, budgetId, txnId).Return(&model.Transaction{
+			ID:         txnId,
+			AccountID:  &accountId,
+			PayeeID:    &payeeId,
+			CategoryID: &categoryId,
+			Amount:     -42.5,
+			Note:       "weekly shop",
+			TagIDs:     []uuid.UUID{tagId},
+		}, nil).Once()
+		expected := model.TransactionTemplate{
+			BudgetID:   budgetId,
+			Name:       "Groceries",
+			AccountID:  &accountId,
+			PayeeID:    &payeeId,
+			CategoryID: &categoryId,
+			Amount:     -42.5,
+			Note:       "weekly shop",
+			TagIDs:     []uuid.UUID{tagId},
+		}
+		repo.On("Create", ctx, expected).Return(&expected, nil).Once()
+
+		created, err := service.CreateFromTransaction(ctx, model.CreateTemplateFromTransactionRequest{
+			TransactionID: txnId,
+			Name:          "  Groceries ",
+		})
+		require.NoError(t, err)
+		assert.Equal(t, "Groceries", created.Name)
+		repo.AssertExpectations(t)
+		txnRepo.AssertExpectations(t)
+	})
+
+	t.Run("rejects_split_transactions", func(t *testing.T) {
+		repo := &mockTransactionTemplateRepo{}
+		txnRepo := &mockTransactionRepo{}
+		service := NewTransactionTemplateService(repo, txnRepo, nil)
+		txnRepo.On("GetByIdTx", ctx, nil, budgetId, txnId).Return(&model.Transaction{
+			ID:     txnId,
+			Splits: []model.TransactionSplit{{Amount: -10}, {Amount: -5}},
+		}, nil).Once()
+
+		_, err := service.CreateFromTransaction(ctx, model.CreateTemplateFromTransactionRequest{TransactionID: txnId, Name: "Split"})
+		assert.True(t, hasErrorCode(err, errs.CodeInvalidArgument), err)
+		repo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	})
+
+	t.Run("missing_transaction", func(t *testing.T) {
+		txnRepo := &mockTransactionRepo{}
+		service := NewTransactionTemplateService(&mockTransactionTemplateRepo{}, txnRepo, nil)
+		txnRepo.On("GetByIdTx", ctx, nil, budgetId, txnId).Return(nil, pgx.ErrNoRows).Once()
+
+		_, err := service.CreateFromTransaction(ctx, model.CreateTemplateFromTransactionRequest{TransactionID: txnId, Name: "Gone"})
+		assert.True(t, hasErrorCode(err, errs.CodeTransactionNotFound), err)
+	})
+}
+
+func TestTransactionTemplateService_Create_NameTaken(t *testing.T) {
+	budgetId := uuid.New()
+	ctx := utils.WithBudgetID(context.Background(), budgetId)
+	repo := &mockTransactionTemplateRepo{}
+	service := NewTransactionTemplateService(repo, nil, nil)
+
+	repo.On("Create", ctx, mock.Anything).Return(nil, &pgconn.PgError{Code: "23505"}).Once()
+	_, err := service.Create(ctx, model.TransactionTemplate{Name: "Rent"})
+	assert.True(t, hasErrorCode(err, errs.CodeTemplateNameTaken), err)
+
+	_, err = service.Create(ctx, model.TransactionTemplate{Name: "   "})
+	assert.True(t, hasErrorCode(err, errs.CodeInvalidArgument), err)
+	repo.AssertExpectations(t)
+}
+
+func TestTransactionTemplateService_Instantiate(t *testing.T) {
+	useInlineTx(t)
+
+	budgetId := uuid.New()
+	ctx := utils.WithBudgetID(context.Background(), budgetId)
+	accountId, payeeId, categoryId := uuid.New(), uuid.New(), uuid.New()
+	id := uuid.New()
+	template := &model.TransactionTemplate{
+		ID:         id,
+		BudgetID:   budgetId,
+		Name:       "Rent",
+		AccountID:  &accountId,
+		PayeeID:    &payeeId,
+		CategoryID: &categoryId,
+		Amount:     -1200,
+		Note:       "monthly rent",
+	}
+
+	t.Run("applies_overrides_and_counts_the_use", func(t *testing.T) {
+		repo := &mockTransactionTemplateRepo{}
+		txnService := &mockTxnService{}
+		service := NewTransactionTemplateService(repo, nil, txnService)
+		date := model.Date("2024-04-01")
+		amount := -1250.0
+
+		repo.On("GetById", ctx, budgetId, id).Return(template, nil).Once()
+		txnService.On("CreateWithTx", ctx, nil, mock.MatchedBy(func(txn model.Transaction) bool {
+			return txn.Date == date && txn.Amount == amount && *txn.CategoryID == categoryId && txn.Note == "monthly rent"
+		})).Return([]model.Transaction{{ID: uuid.New(), Date: date, Amount: amount}}, nil).Once()
+		repo.On("MarkUsed", ctx, nil, budgetId, id).Return(nil).Once()
+
+		created, err := service.Instantiate(ctx, id, model.InstantiateTemplateRequest{Date: &date, Amount: &amount})
+		require.NoError(t, err)
+		require.Len(t, created, 1)
+		assert.Equal(t, amount, created[0].Amount)
+		repo.AssertExpectations(t)
+		txnService.AssertExpectations(t)
+	})
+
+	t.Run("failed_create_does_not_count", func(t *testing.T) {
+		repo := &mockTransactionTemplateRepo{}
+		txnService := &mockTxnService{}
+		service := NewTransactionTemplateService(repo, nil, txnService)
+
+		repo.On("GetById", ctx, budgetId, id).Return(template, nil).Once()
+		txnService.On("CreateWithTx", ctx, nil, mock.MatchedBy(func(txn model.Transaction) bool {
+			return txn.Amount == template.Amount && txn.Date.Valid() == nil
+		})).Return(nil, errs.New(errs.CodeInvalidArgument, "payee_id is required")).Once()
+
+		_, err := service.Instantiate(ctx, id, model.InstantiateTemplateRequest{})
+		assert.True(t, hasErrorCode(err, errs.CodeInvalidArgument), err)
+		repo.AssertNotCalled(t, "MarkUsed", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("missing_template", func(t *testing.T) {
+		repo := &mockTransactionTemplateRepo{}
+		service := NewTransactionTemplateService(repo, nil, &mockTxnService{})
+		repo.On("GetById", ctx, budgetId, id).Return(nil, pgx.ErrNoRows).Once()
+
+		_, err := service.Instantiate(ctx, id, model.InstantiateTemplateRequest{})
+		assert.True(t, hasErrorCode(err, errs.CodeTemplateNotFound), err)
+	})
+}
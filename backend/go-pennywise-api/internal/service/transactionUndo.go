@@ -0,0 +1,288 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	errs "github.com/Rishabh-Kapri/pennywise/backend/shared/errors"
+	"github.com/Rishabh-Kapri/pennywise/backend/shared/logger"
+	"github.com/Rishabh-Kapri/pennywise/backend/shared/model"
+	utils "github.com/Rishabh-Kapri/pennywise/backend/shared/utils"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+// maxUndoCount caps how many mutations a single undo or redo can replay
+const maxUndoCount = 50
+
+type journalReplayKey struct{}
+
+// withJournalReplay marks mutations made while replaying the journal so they aren't journaled again
+func withJournalReplay(ctx context.Context) context.Context {
+	return context.WithValue(ctx, journalReplayKey{}, true)
+}
+
+// journaling reports whether mutations made with ctx are recorded in the user's undo history.
+// Mutations without a user, like the ones made by the temporal worker, can't be undone.
+func (s *transactionService) journaling(ctx context.Context) bool {
+	if s.journalRepo == nil {
+		return false
+	}
+	if replay, _ := ctx.Value(journalReplayKey{}).(bool); replay {
+		return false
+	}
+	return utils.RequestMetadataFromContext(ctx).UserID != nil
+}
+
+// recordJournal appends a mutation to the undo history of the current user.
+// A new mutation invalidates everything the user has undone so far.
+func (s *transactionService) recordJournal(
+	ctx context.Context,
+	tx pgx.Tx,
+	action model.AuditAction,
+	id uuid.UUID,
+	before *model.Transaction,
+	after *model.Transaction,
+) error {
+	if !s.journaling(ctx) {
+		return nil
+	}
+	entry := model.JournalEntry{
+		BudgetID:   utils.MustBudgetID(ctx),
+		UserID:     utils.MustUserID(ctx),
+		EntityType: model.AuditEntityTransaction,
+		EntityID:   id,
+		Action:     action,
+	}
+	var err error
+	if before != nil {
+		if entry.Before, err = json.Marshal(before); err != nil {
+			return errs.Wrap(errs.CodeJournalRecordFailed, "error encoding journal snapshot", err)
+		}
+	}
+	if after != nil {
+		if entry.After, err = json.Marshal(after); err != nil {
+			return errs.Wrap(errs.CodeJournalRecordFailed, "error encoding journal snapshot", err)
+		}
+	}
+	if err = s.journalRepo.DeleteUndone(ctx, tx, entry.BudgetID, entry.UserID); err != nil {
+		return errs.Wrap(errs.CodeJournalRecordFailed, "error clearing redo history", err)
+	}
+	if err = s.journalRepo.Create(ctx, tx, entry); err != nil {
+		return errs.Wrap(errs.CodeJournalRecordFailed, "error creating journal entry", err)
+	}
+	return nil
+}
+
+func (s *transactionService) Undo(ctx context.Context, count int) (*model.UndoResponse, error) {
+	return s.replayJournal(ctx, count, false)
+}
+
+func (s *transactionService) Redo(ctx context.Context, count int) (*model.UndoResponse, error) {
+	return s.replayJournal(ctx, count, true)
+}
+
+// replayJournal undoes or redoes the last count entries of the user's journal in a single
+// db transaction. The replayed mutations go through the regular create, update and delete
+// paths so carryovers and transfer counterparts follow along.
+func (s *transactionService) replayJournal(ctx context.Context, count int, redo bool) (*model.UndoResponse, error) {
+	txCtx, txCancel := context.WithTimeout(ctx, 60*time.Second)
+	defer txCancel()
+
+	if s.journalRepo == nil {
+		return nil, errs.New(errs.CodeInternalError, "operation journal is not configured")
+	}
+	if count == 0 {
+		count = 1
+	}
+	if count < 0 || count > maxUndoCount {
+		return nil, errs.New(errs.CodeInvalidArgument, "count must be between 1 and %d", maxUndoCount)
+	}
+	budgetId := utils.MustBudgetID(ctx)
+	userId, err := utils.UserIDFromContext(ctx)
+	if err != nil {
+		return nil, errs.Wrap(errs.CodeInvalidArgument, "undo requires a user", err)
+	}
+	logger.Logger(ctx).Info("replaying operation journal", "count", count, "redo", redo)
+
+	response := &model.UndoResponse{Entries: make([]model.JournalEntry, 0)}
+	replayCtx := withJournalReplay(txCtx)
+	err = withTx(txCtx, s.repo.GetDB(), func(tx pgx.Tx) error {
+		var entries []model.JournalEntry
+		var err error
+		if redo {
+			entries, err = s.journalRepo.GetRedoable(replayCtx, tx, budgetId, userId, count)
+		} else {
+			entries, err = s.journalRepo.GetUndoable(replayCtx, tx, budgetId, userId, count)
+		}
+		if err != nil {
+			return errs.Wrap(errs.CodeJournalLookupFailed, "error getting journal entries", err)
+		}
+		for _, entry := range entries {
+			if err = s.replayJournalEntry(replayCtx, tx, budgetId, entry, redo); err != nil {
+				return err
+			}
+			if err = s.journalRepo.SetUndone(replayCtx, tx, entry.ID, !redo); err != nil {
+				return errs.Wrap(errs.CodeJournalRecordFailed, "error updating journal entry", err)
+			}
+			response.Entries = append(response.Entries, entry)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return response, nil
+}
+
+// replayJournalEntry moves a transaction from one side of a journal entry to the other.
+// Undo expects the after state and restores the before state, redo does the opposite.
+// A missing side means the transaction doesn't exist in that state.
+func (s *transactionService) replayJournalEntry(
+	ctx context.Context,
+	tx pgx.Tx,
+	budgetId uuid.UUID,
+	entry model.JournalEntry,
+	redo bool,
+) error {
+	if entry.EntityType != model.AuditEntityTransaction {
+		return errs.New(errs.CodeInvalidArgument, "journal entries of type %s can't be replayed", entry.EntityType)
+	}
+	expectedRaw, targetRaw := entry.After, entry.Before
+	if redo {
+		expectedRaw, targetRaw = entry.Before, entry.After
+	}
+	expected, err := decodeJournalSnapshot(expectedRaw)
+	if err != nil {
+		return err
+	}
+	target, err := decodeJournalSnapshot(targetRaw)
+	if err != nil {
+		return err
+	}
+
+	if expected == nil {
+		return s.restoreWithTx(ctx, tx, budgetId, entry.EntityID)
+	}
+
+	current, err := s.repo.GetByIdTx(ctx, tx, budgetId, entry.EntityID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return errs.New(errs.CodeUndoConflict, "transaction %v was deleted since", entry.EntityID)
+		}
+		return errs.Wrap(errs.CodeTransactionLookupFailed, "error getting transaction", err)
+	}
+	if current == nil {
+		return errs.New(errs.CodeUndoConflict, "transaction %v was deleted since", entry.EntityID)
+	}
+	if !current.Compare(expected) {
+		return errs.New(errs.CodeUndoConflict, "transaction %v was changed since", entry.EntityID)
+	}
+
+	if target == nil {
+		return s.deleteWithTx(ctx, tx, budgetId, entry.EntityID)
+	}
+	// snapshots leave out empty split lines, undoing a split has to remove them
+	if target.Splits == nil {
+		target.Splits = []model.TransactionSplit{}
+	}
+	if _, err = s.updateWithTx(ctx, tx, budgetId, current, *target); err != nil {
+		return err
+	}
+	// updateWithTx keeps the approval status, so it is replayed on its own
+	updated, err := s.repo.GetByIdTx(ctx, tx, budgetId, entry.EntityID)
+	if err != nil {
+		return errs.Wrap(errs.CodeTransactionLookupFailed, "error reloading transaction", err)
+	}
+	if updated.Status != target.Status {
+		if err = s.updateStatusWithTx(ctx, tx, budgetId, entry.EntityID, target.Status); err != nil {
+			return errs.Wrap(errs.CodeTransactionUpdateFailed, "error updating transaction status", err)
+		}
+	}
+	return nil
+}
+
+func decodeJournalSnapshot(raw json.RawMessage) (*model.Transaction, error) {
+	if len(raw) == 0 || string(raw) == "null" {
+		return nil, nil
+	}
+	var txn model.Transaction
+	if err := json.Unmarshal(raw, &txn); err != nil {
+		return nil, errs.Wrap(errs.CodeJournalLookupFailed, "error decoding journal snapshot", err)
+	}
+	return &txn, nil
+}
+
+func (s *transactionService) Restore(ctx context.Context, id uuid.UUID) error {
+	txCtx, txCancel := context.WithTimeout(ctx, 30*time.Second)
+	defer txCancel()
+
+	budgetId := utils.MustBudgetID(ctx)
+	logger.Logger(ctx).Info("restoring transaction", "id", id)
+
+	return withTx(txCtx, s.repo.GetDB(), func(tx pgx.Tx) error {
+		return s.restoreWithTx(txCtx, tx, budgetId, id)
+	})
+}
+
+// restoreWithTx brings back a soft deleted transaction along with its transfer counterpart
+// and reapplies its carryovers, reversing deleteWithTx
+func (s *transactionService) restoreWithTx(ctx context.Context, tx pgx.Tx, budgetId uuid.UUID, id uuid.UUID) error {
+	if err := s.repo.Restore(ctx, tx, budgetId, id); err != nil {
+		return errs.Wrap(errs.CodeTransactionRestoreFailed, "error restoring transaction", err)
+	}
+	restored, err := s.repo.GetByIdTx(ctx, tx, budgetId, id)
+	if err != nil {
+		return errs.Wrap(errs.CodeTransactionLookupFailed, "error getting restored transaction", err)
+	}
+	// the account and payee have to still exist for the transaction to come back
+	budget, _, _, _, err := s.loadDependencies(ctx, tx, budgetId, *restored)
+	if err != nil {
+		return err
+	}
+
+	paymentCategoryIDs, err := s.loadPaymentCategoryIDs(ctx, tx, budgetId)
+	if err != nil {
+		return err
+	}
+	rules := carryoverRules{
+		inflowCategoryID:   budget.Metadata.InflowCategoryID,
+		paymentCategoryIDs: paymentCategoryIDs,
+	}
+	if err = s.applyCarryoverLines(ctx, tx, budgetId, restored, rules, 1); err != nil {
+		return err
+	}
+
+	if restored.TransferTransactionID != nil {
+		counterpartId := *restored.TransferTransactionID
+		if err = s.repo.Restore(ctx, tx, budgetId, counterpartId); err != nil {
+			return errs.Wrap(errs.CodeTransactionRestoreFailed, "error restoring transfer transaction", err)
+		}
+		counterpart, err := s.loadCounterpartForCarryovers(ctx, tx, budgetId, counterpartId, rules)
+		if err != nil {
+			return err
+		}
+		if counterpart != nil {
+			if err = s.applyCarryoverLines(ctx, tx, budgetId, counterpart, rules, 1); err != nil {
+				return err
+			}
+		}
+		if s.auditService != nil {
+			counterpart, err := s.repo.GetByIdTx(ctx, tx, budgetId, counterpartId)
+			if err != nil {
+				return errs.Wrap(errs.CodeTransactionLookupFailed, "error getting restored transfer transaction", err)
+			}
+			if err = s.recordAudit(ctx, tx, model.AuditActionCreate, counterpartId, nil, counterpart); err != nil {
+				return err
+			}
+		}
+	}
+
+	if err = s.recordAudit(ctx, tx, model.AuditActionCreate, id, nil, restored); err != nil {
+		return err
+	}
+	return s.recordJournal(ctx, tx, model.AuditActionCreate, id, nil, restored)
+}
@@ -0,0 +1,313 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	errs "github.com/Rishabh-Kapri/pennywise/backend/shared/errors"
+	"github.com/Rishabh-Kapri/pennywise/backend/shared/model"
+	utils "github.com/Rishabh-Kapri/pennywise/backend/shared/utils"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type mockJournalRepo struct {
+	mockBaseRepo
+	mock.Mock
+}
+
+func (m *mockJournalRepo) Create(ctx context.Context, tx pgx.Tx, entry model.JournalEntry) error {
+	return m.Called(ctx, tx, entry).Error(0)
+}
+
+func (m *mockJournalRepo) GetUndoable(
+	ctx context.Context,
+	tx pgx.Tx,
+	budgetId uuid.UUID,
+	userId uuid.UUID,
+	limit int,
+) ([]model.JournalEntry, error) {
+	args := m.Called(ctx, tx, budgetId, userId, limit)
+	if v := args.Get(0); v != nil {
+		return v.([]model.JournalEntry), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *mockJournalRepo) GetRedoable(
+	ctx context.Context,
+	tx pgx.Tx,
+	budgetId uuid.UUID,
+	userId uuid.UUID,
+	limit int,
+) ([]model.JournalEntry, error) {
+	args := m.Called(ctx, tx, budgetId, userId, limit)
+	if v := args.Get(0); v != nil {
+		return v.([]model.JournalEntry), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *mockJournalRepo) SetUndone(ctx context.Context, tx pgx.Tx, id uuid.UUID, undone bool) error {
+	return m.Called(ctx, tx, id, undone).Error(0)
+}
+
+func (m *mockJournalRepo) DeleteUndone(ctx context.Context, tx pgx.Tx, budgetId uuid.UUID, userId uuid.UUID) error {
+	return m.Called(ctx, tx, budgetId, userId).Error(0)
+}
+
+func journalSnapshot(t *testing.T, txn model.Transaction) json.RawMessage {
+	t.Helper()
+	raw, err := json.Marshal(txn)
+	require.NoError(t, err)
+	return raw
+}
+
+func TestRecordJournal(t *testing.T) {
+	var mockTx pgx.Tx
+	mockWithTxSuccess(mockTx)
+	defer func() { withTx = utils.WithTx }()
+
+	budgetId := uuid.New()
+	userId := uuid.New()
+	txnId := uuid.New()
+	foundTxn := model.Transaction{ID: txnId, Amount: -20, Note: "groceries"}
+
+	setup := func() (*mockTransactionRepo, *mockJournalRepo, *transactionService) {
+		mockRepo := &mockTransactionRepo{}
+		mockBudget := &mockBudgetRepo{}
+		journal := &mockJournalRepo{}
+		service := newTestTransactionService(mockRepo, mockBudget, nil, nil, nil, nil, nil)
+		service.journalRepo = journal
+		mockRepo.On("GetByIdTx", mock.Anything, mockTx, budgetId, txnId).Return(&foundTxn, nil).Once()
+		mockBudget.On("GetById", mock.Anything, mockTx, budgetId).Return(&model.Budget{}, nil).Once()
+		mockRepo.On("DeleteById", mock.Anything, mockTx, budgetId, txnId).Return(nil).Once()
+		return mockRepo, journal, service
+	}
+
+	t.Run("delete_is_journaled_for_the_user", func(t *testing.T) {
+		_, journal, service := setup()
+		ctx := utils.WithUserID(utils.WithBudgetID(context.Background(), budgetId), userId)
+		journal.On("DeleteUndone", mock.Anything, mockTx, budgetId, userId).Return(nil).Once()
+		journal.On("Create", mock.Anything, mockTx, mock.MatchedBy(func(entry model.JournalEntry) bool {
+			var before model.Transaction
+			return entry.UserID == userId &&
+				entry.BudgetID == budgetId &&
+				entry.EntityID == txnId &&
+				entry.Action == model.AuditActionDelete &&
+				entry.After == nil &&
+				json.Unmarshal(entry.Before, &before) == nil &&
+				before.Compare(&foundTxn)
+		})).Return(nil).Once()
+
+		require.NoError(t, service.DeleteById(ctx, txnId))
+		journal.AssertExpectations(t)
+	})
+
+	t.Run("mutations_without_a_user_are_not_journaled", func(t *testing.T) {
+		_, journal, service := setup()
+		ctx := utils.WithBudgetID(context.Background(), budgetId)
+
+		require.NoError(t, service.DeleteById(ctx, txnId))
+		journal.AssertNotCalled(t, "Create", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("journal_failure_aborts_mutation", func(t *testing.T) {
+		_, journal, service := setup()
+		ctx := utils.WithUserID(utils.WithBudgetID(context.Background(), budgetId), userId)
+		journal.On("DeleteUndone", mock.Anything, mockTx, budgetId, userId).Return(assert.AnError).Once()
+
+		assert.Error(t, service.DeleteById(ctx, txnId))
+	})
+}
+
+func TestUndoRedo(t *testing.T) {
+	var mockTx pgx.Tx
+	mockWithTxSuccess(mockTx)
+	defer func() { withTx = utils.WithTx }()
+
+	budgetId := uuid.New()
+	userId := uuid.New()
+	ctx := utils.WithUserID(utils.WithBudgetID(context.Background(), budgetId), userId)
+
+	txnId := uuid.New()
+	accountId := uuid.New()
+	payeeId := uuid.New()
+	categoryId := uuid.New()
+	txn := model.Transaction{
+		ID:         txnId,
+		BudgetID:   budgetId,
+		AccountID:  &accountId,
+		PayeeID:    &payeeId,
+		CategoryID: &categoryId,
+		Amount:     -25,
+		Date:       "2024-05-10",
+		Status:     model.TransactionStatusApproved,
+		Cleared:    model.ClearedStatusUncleared,
+	}
+
+	type mocks struct {
+		repo          *mockTransactionRepo
+		budget        *mockBudgetRepo
+		account       *mockAccountRepo
+		payee         *mockPayeesRepo
+		monthlyBudget *mockMonthlyBudgetRepo
+		journal       *mockJournalRepo
+	}
+	setup := func() (*mocks, *transactionService) {
+		m := &mocks{
+			repo:          &mockTransactionRepo{},
+			budget:        &mockBudgetRepo{},
+			account:       &mockAccountRepo{},
+			payee:         &mockPayeesRepo{},
+			monthlyBudget: &mockMonthlyBudgetRepo{},
+			journal:       &mockJournalRepo{},
+		}
+		service := newTestTransactionService(m.repo, m.budget, nil, m.account, m.payee, nil, m.monthlyBudget)
+		service.journalRepo = m.journal
+		return m, service
+	}
+
+	t.Run("undo_delete_restores_transaction_counterpart_and_carryover", func(t *testing.T) {
+		m, service := setup()
+		counterpartId := uuid.New()
+		deleted := txn
+		deleted.TransferTransactionID = &counterpartId
+		entry := model.JournalEntry{
+			ID:         uuid.New(),
+			EntityType: model.AuditEntityTransaction,
+			EntityID:   txnId,
+			Action:     model.AuditActionDelete,
+			Before:     journalSnapshot(t, deleted),
+		}
+		m.journal.On("GetUndoable", mock.Anything, mockTx, budgetId, userId, 1).
+			Return([]model.JournalEntry{entry}, nil).Once()
+		m.repo.On("Restore", mock.Anything, mockTx, budgetId, txnId).Return(nil).Once()
+		m.repo.On("GetByIdTx", mock.Anything, mockTx, budgetId, txnId).Return(&deleted, nil).Once()
+		m.budget.On("GetById", mock.Anything, mockTx, budgetId).Return(&model.Budget{}, nil).Once()
+		m.account.On("GetById", mock.Anything, mockTx, budgetId, accountId).Return(&model.Account{}, nil).Once()
+		m.payee.On("GetByIdTx", mock.Anything, mockTx, budgetId, payeeId).Return(&model.Payee{}, nil).Once()
+		m.monthlyBudget.On("GetByCatIdAndMonth", mock.Anything, mockTx, budgetId, categoryId, "2024-05").
+			Return(&model.MonthlyBudget{}, nil).Once()
+		m.monthlyBudget.On("UpdateCarryoverByCatIdAndMonth", mock.Anything, mockTx, budgetId, categoryId, "2024-05", -25.0).
+			Return(nil).Once()
+		m.repo.On("Restore", mock.Anything, mockTx, budgetId, counterpartId).Return(nil).Once()
+		m.journal.On("SetUndone", mock.Anything, mockTx, entry.ID, true).Return(nil).Once()
+
+		response, err := service.Undo(ctx, 0)
+		require.NoError(t, err)
+		require.Len(t, response.Entries, 1)
+		assert.Equal(t, entry.ID, response.Entries[0].ID)
+		m.repo.AssertExpectations(t)
+		m.monthlyBudget.AssertExpectations(t)
+		// replaying the journal doesn't journal again
+		m.journal.AssertNotCalled(t, "Create", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("undo_create_deletes_transaction", func(t *testing.T) {
+		m, service := setup()
+		created := txn
+		created.CategoryID = nil
+		entry := model.JournalEntry{
+			ID:         uuid.New(),
+			EntityType: model.AuditEntityTransaction,
+			EntityID:   txnId,
+			Action:     model.AuditActionCreate,
+			After:      journalSnapshot(t, created),
+		}
+		m.journal.On("GetUndoable", mock.Anything, mockTx, budgetId, userId, 2).
+			Return([]model.JournalEntry{entry}, nil).Once()
+		m.repo.On("GetByIdTx", mock.Anything, mockTx, budgetId, txnId).Return(&created, nil)
+		m.budget.On("GetById", mock.Anything, mockTx, budgetId).Return(&model.Budget{}, nil).Once()
+		m.repo.On("DeleteById", mock.Anything, mockTx, budgetId, txnId).Return(nil).Once()
+		m.journal.On("SetUndone", mock.Anything, mockTx, entry.ID, true).Return(nil).Once()
+
+		response, err := service.Undo(ctx, 2)
+		require.NoError(t, err)
+		assert.Len(t, response.Entries, 1)
+		m.repo.AssertExpectations(t)
+		m.journal.AssertExpectations(t)
+	})
+
+	t.Run("undo_conflicts_when_transaction_changed_since", func(t *testing.T) {
+		m, service := setup()
+		updated := txn
+		updated.Note = "edited"
+		entry := model.JournalEntry{
+			ID:         uuid.New(),
+			EntityType: model.AuditEntityTransaction,
+			EntityID:   txnId,
+			Action:     model.AuditActionUpdate,
+			Before:     journalSnapshot(t, txn),
+			After:      journalSnapshot(t, updated),
+		}
+		changedAgain := updated
+		changedAgain.Note = "edited by someone else"
+		m.journal.On("GetUndoable", mock.Anything, mockTx, budgetId, userId, 1).
+			Return([]model.JournalEntry{entry}, nil).Once()
+		m.repo.On("GetByIdTx", mock.Anything, mockTx, budgetId, txnId).Return(&changedAgain, nil).Once()
+
+		_, err := service.Undo(ctx, 1)
+		var apiErr *errs.Error
+		require.True(t, errors.As(err, &apiErr))
+		assert.Equal(t, errs.CodeUndoConflict, apiErr.Code)
+		m.journal.AssertNotCalled(t, "SetUndone", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("redo_status_change_replays_status", func(t *testing.T) {
+		m, service := setup()
+		rejected := txn
+		rejected.Status = model.TransactionStatusRejected
+		entry := model.JournalEntry{
+			ID:         uuid.New(),
+			EntityType: model.AuditEntityTransaction,
+			EntityID:   txnId,
+			Action:     model.AuditActionUpdate,
+			Before:     journalSnapshot(t, txn),
+			After:      journalSnapshot(t, rejected),
+		}
+		current := txn
+		m.journal.On("GetRedoable", mock.Anything, mockTx, budgetId, userId, 1).
+			Return([]model.JournalEntry{entry}, nil).Once()
+		m.repo.On("GetByIdTx", mock.Anything, mockTx, budgetId, txnId).Return(&current, nil)
+		m.repo.On("UpdateStatus", mock.Anything, mockTx, budgetId, txnId, model.TransactionStatusRejected).
+			Return(nil).Once()
+		m.journal.On("SetUndone", mock.Anything, mockTx, entry.ID, false).Return(nil).Once()
+
+		response, err := service.Redo(ctx, 1)
+		require.NoError(t, err)
+		assert.Len(t, response.Entries, 1)
+		m.repo.AssertExpectations(t)
+		m.repo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("nothing_to_undo", func(t *testing.T) {
+		m, service := setup()
+		m.journal.On("GetUndoable", mock.Anything, mockTx, budgetId, userId, 1).
+			Return([]model.JournalEntry{}, nil).Once()
+
+		response, err := service.Undo(ctx, 1)
+		require.NoError(t, err)
+		assert.Empty(t, response.Entries)
+	})
+
+	t.Run("count_out_of_range", func(t *testing.T) {
+		_, service := setup()
+		_, err := service.Undo(ctx, maxUndoCount+1)
+		assert.Error(t, err)
+		_, err = service.Redo(ctx, -1)
+		assert.Error(t, err)
+	})
+
+	t.Run("requires_a_user", func(t *testing.T) {
+		_, service := setup()
+		_, err := service.Undo(utils.WithBudgetID(context.Background(), budgetId), 1)
+		assert.Error(t, err)
+	})
+}
@@ -152,6 +152,26 @@ func TestCreate(t *testing.T) {
 		assert.Error(t, err)
 	})
 
+	t.Run("closed_account", func(t *testing.T) {
+		mockBudget := &mockBudgetRepo{}
+		mockAccount := &mockAccountRepo{}
+		mockPayee := &mockPayeesRepo{}
+		mockRepo := &mockTransactionRepo{}
+		service := newTestTransactionService(mockRepo, mockBudget, nil, mockAccount, mockPayee, nil, nil)
+
+		mockBudget.On("GetById", mock.Anything, mockTx, budgetId).Return(&model.Budget{}, nil).Once()
+		mockAccount.On("GetById", mock.Anything, mockTx, budgetId, accountId).
+			Return(&model.Account{Type: "checking", Closed: true}, nil).
+			Once()
+		mockPayee.On("GetByIdTx", mock.Anything, mockTx, budgetId, payeeId).Return(&model.Payee{}, nil).Once()
+
+		_, err := service.Create(ctx, validTxn)
+		var appErr *errs.Error
+		require.ErrorAs(t, err, &appErr)
+		assert.Equal(t, errs.CodeAccountClosed, appErr.Code)
+		mockRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything, mock.Anything)
+	})
+
 	t.Run("create_repo_error", func(t *testing.T) {
 		mockBudget := &mockBudgetRepo{}
 		mockAccount := &mockAccountRepo{}
@@ -420,6 +440,9 @@ func TestUpdate(t *testing.T) {
 		mockAccount.On("GetById", mock.Anything, mockTx, budgetId, transferAccountId).
 			Return(&model.Account{ID: transferAccountId, Type: "checking"}, nil).
 			Once()
+		mockRepo.On("GetByIdTx", mock.Anything, mockTx, budgetId, counterpartId).
+			Return(&model.Transaction{ID: counterpartId, Cleared: model.ClearedStatusCleared}, nil).
+			Once()
 
 		mockRepo.On("Update", mock.Anything, mockTx, budgetId, counterpartId, mock.MatchedBy(func(txn model.Transaction) bool {
 			return txn.Status == model.TransactionStatusManual &&
@@ -438,6 +461,49 @@ func TestUpdate(t *testing.T) {
 		mockRepo.AssertExpectations(t)
 	})
 
+	t.Run("reconciled_transfer_counterpart_is_locked", func(t *testing.T) {
+		mockRepo := &mockTransactionRepo{}
+		mockBudget := &mockBudgetRepo{}
+		mockAccount := &mockAccountRepo{}
+		mockPayee := &mockPayeesRepo{}
+		service := newTestTransactionService(mockRepo, mockBudget, nil, mockAccount, mockPayee, nil, nil)
+
+		counterpartId := uuid.New()
+		transferAccountId := uuid.New()
+		transferPayeeId := uuid.New()
+
+		existingTxn := validTxn
+		existingTxn.ID = txnId
+		existingTxn.Amount = 5.0
+		existingTxn.Status = model.TransactionStatusManual
+		existingTxn.TransferTransactionID = &counterpartId
+
+		mockRepo.On("GetByIdTx", mock.Anything, mockTx, budgetId, txnId).Return(&existingTxn, nil).Once()
+		mockBudget.On("GetById", mock.Anything, mockTx, budgetId).Return(&model.Budget{}, nil).Once()
+		mockAccount.On("GetById", mock.Anything, mockTx, budgetId, accountId).
+			Return(&model.Account{ID: accountId, Type: "checking", TransferPayeeID: &transferPayeeId}, nil).
+			Once()
+		mockPayee.On("GetByIdTx", mock.Anything, mockTx, budgetId, payeeId).
+			Return(&model.Payee{TransferAccountID: &transferAccountId}, nil).
+			Once()
+		mockAccount.On("GetById", mock.Anything, mockTx, budgetId, transferAccountId).
+			Return(&model.Account{ID: transferAccountId, Type: "checking"}, nil).
+			Once()
+		mockRepo.On("GetByIdTx", mock.Anything, mockTx, budgetId, counterpartId).
+			Return(&model.Transaction{
+				ID:      counterpartId,
+				Amount:  -existingTxn.Amount,
+				Date:    existingTxn.Date,
+				Cleared: model.ClearedStatusReconciled,
+			}, nil).
+			Once()
+
+		err := service.Update(ctx, txnId, validTxn)
+
+		assert.True(t, hasErrorCode(err, errs.CodeTransactionLocked))
+		mockRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
 	t.Run("unapproved_update_becomes_approved", func(t *testing.T) {
 		mockRepo := &mockTransactionRepo{}
 		mockBudget := &mockBudgetRepo{}
@@ -530,6 +596,9 @@ func TestDeleteById(t *testing.T) {
 		}
 
 		mockRepo.On("GetByIdTx", mock.Anything, mockTx, budgetId, txnId).Return(&foundTxn, nil).Once()
+		mockRepo.On("GetByIdTx", mock.Anything, mockTx, budgetId, transferTxnId).
+			Return(&model.Transaction{ID: transferTxnId}, nil).
+			Once()
 		// applySideEffects isDelete: deletes counterpart transfer transaction
 		mockRepo.On("DeleteById", mock.Anything, mockTx, budgetId, transferTxnId).Return(nil).Once()
 		// Delete main transaction
@@ -551,12 +620,35 @@ func TestDeleteById(t *testing.T) {
 		foundTxn := model.Transaction{ID: txnId, TransferTransactionID: &transferTxnId}
 
 		mockRepo.On("GetByIdTx", mock.Anything, mockTx, budgetId, txnId).Return(&foundTxn, nil).Once()
+		mockRepo.On("GetByIdTx", mock.Anything, mockTx, budgetId, transferTxnId).
+			Return(&model.Transaction{ID: transferTxnId}, nil).
+			Once()
 		mockRepo.On("DeleteById", mock.Anything, mockTx, budgetId, transferTxnId).Return(assert.AnError).Once()
 
 		err := service.DeleteById(ctx, txnId)
 		assert.Error(t, err)
 	})
 
+	t.Run("reconciled_transfer_counterpart_is_locked", func(t *testing.T) {
+		mockRepo := &mockTransactionRepo{}
+		mockBudget := &mockBudgetRepo{}
+		service := newTestTransactionService(mockRepo, mockBudget, nil, nil, nil, nil, nil)
+
+		mockBudget.On("GetById", mock.Anything, mockTx, budgetId).Return(&model.Budget{}, nil).Once()
+
+		transferTxnId := uuid.New()
+		foundTxn := model.Transaction{ID: txnId, TransferTransactionID: &transferTxnId}
+
+		mockRepo.On("GetByIdTx", mock.Anything, mockTx, budgetId, txnId).Return(&foundTxn, nil).Once()
+		mockRepo.On("GetByIdTx", mock.Anything, mockTx, budgetId, transferTxnId).
+			Return(&model.Transaction{ID: transferTxnId, Cleared: model.ClearedStatusReconciled}, nil).
+			Once()
+
+		err := service.DeleteById(ctx, txnId)
+		assert.True(t, hasErrorCode(err, errs.CodeTransactionLocked))
+		mockRepo.AssertNotCalled(t, "DeleteById", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
 	t.Run("repo_deletion_fails", func(t *testing.T) {
 		mockRepo := &mockTransactionRepo{}
 		mockBudget := &mockBudgetRepo{}
@@ -572,4 +664,18 @@ func TestDeleteById(t *testing.T) {
 		err := service.DeleteById(ctx, txnId)
 		assert.Error(t, err)
 	})
+
+	t.Run("reconciled_transaction_is_locked", func(t *testing.T) {
+		mockRepo := &mockTransactionRepo{}
+		service := newTestTransactionService(mockRepo, nil, nil, nil, nil, nil, nil)
+
+		foundTxn := model.Transaction{ID: txnId, Cleared: model.ClearedStatusReconciled}
+		mockRepo.On("GetByIdTx", mock.Anything, mockTx, budgetId, txnId).Return(&foundTxn, nil).Once()
+
+		err := service.DeleteById(ctx, txnId)
+		var appErr *errs.Error
+		require.ErrorAs(t, err, &appErr)
+		assert.Equal(t, errs.CodeTransactionLocked, appErr.Code)
+		mockRepo.AssertNotCalled(t, "DeleteById", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
 }
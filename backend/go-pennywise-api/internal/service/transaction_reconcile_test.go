@@ -125,6 +125,34 @@ func TestReconcileTransfer(t *testing.T) {
 			},
 			expectedError: true,
 		},
+		{
+			name:        "wasTransfer_notTransfer_reconciled_counterpart",
+			wasTransfer: true,
+			isTransfer:  false,
+			setupMocks: func(repo *mockTransactionRepo) {
+				repo.On("GetByIdTx", ctx, mockTx, budgetId, oldTransferTxnId).
+					Return(&model.Transaction{ID: oldTransferTxnId, Cleared: model.ClearedStatusReconciled}, nil).
+					Once()
+			},
+			expectedError: true,
+		},
+		{
+			name:        "wasTransfer_isTransfer_samePayee_reconciled_counterpart",
+			wasTransfer: true,
+			isTransfer:  true,
+			samePayee:   true,
+			setupMocks: func(repo *mockTransactionRepo) {
+				repo.On("GetByIdTx", ctx, mockTx, budgetId, oldTransferTxnId).
+					Return(&model.Transaction{
+						ID:      oldTransferTxnId,
+						Amount:  -50,
+						Date:    "2023-11-11",
+						Cleared: model.ClearedStatusReconciled,
+					}, nil).
+					Once()
+			},
+			expectedError: true,
+		},
 	}
 
 	for _, tt := range tests {
@@ -133,6 +161,9 @@ func TestReconcileTransfer(t *testing.T) {
 			service := newTestTransactionService(mockRepo, nil, nil, nil, nil, nil, nil)
 
 			tt.setupMocks(mockRepo)
+			mockRepo.On("GetByIdTx", ctx, mockTx, budgetId, oldTransferTxnId).
+				Return(&model.Transaction{ID: oldTransferTxnId}, nil).
+				Maybe()
 
 			var oldTxn model.Transaction
 			var newTxn model.Transaction
@@ -159,7 +190,7 @@ func TestReconcileTransfer(t *testing.T) {
 			newTxn.Amount = 100 // for counterpart amount
 			newTxn.Date = "2023-11-11"
 
-			err := service.reconcileTransfer(ctx, mockTx, budgetId, oldTxn, &newTxn, account, payee)
+			err := service.reconcileTransfer(ctx, mockTx, budgetId, oldTxn, &newTxn, account, payee, carryoverRules{})
 
 			if tt.expectedError {
 				assert.Error(t, err)
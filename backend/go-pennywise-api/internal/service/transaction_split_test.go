@@ -0,0 +1,273 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Rishabh-Kapri/pennywise/backend/shared/model"
+	utils "github.com/Rishabh-Kapri/pennywise/backend/shared/utils"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestValidateSplits(t *testing.T) {
+	service := &transactionService{}
+	groceriesID := uuid.New()
+	householdID := uuid.New()
+	inflowCategoryID := uuid.New()
+	transferAccountID := uuid.New()
+
+	tests := []struct {
+		name    string
+		txn     model.Transaction
+		payee   model.Payee
+		wantErr bool
+	}{
+		{
+			name:    "not_split",
+			txn:     model.Transaction{Amount: -100, CategoryID: &groceriesID},
+			wantErr: false,
+		},
+		{
+			name: "lines_add_up",
+			txn: model.Transaction{Amount: -100.30, CategoryID: &groceriesID, Splits: []model.TransactionSplit{
+				{CategoryID: &groceriesID, Amount: -60.10},
+				{CategoryID: &householdID, Amount: -40.20},
+			}},
+			wantErr: false,
+		},
+		{
+			name: "lines_do_not_add_up",
+			txn: model.Transaction{Amount: -100, Splits: []model.TransactionSplit{
+				{CategoryID: &groceriesID, Amount: -60},
+				{CategoryID: &householdID, Amount: -30},
+			}},
+			wantErr: true,
+		},
+		{
+			name: "single_line",
+			txn: model.Transaction{Amount: -100, Splits: []model.TransactionSplit{
+				{CategoryID: &groceriesID, Amount: -100},
+			}},
+			wantErr: true,
+		},
+		{
+			name: "line_without_category",
+			txn: model.Transaction{Amount: -100, Splits: []model.TransactionSplit{
+				{CategoryID: &groceriesID, Amount: -60},
+				{Amount: -40},
+			}},
+			wantErr: true,
+		},
+		{
+			name: "negative_inflow_line",
+			txn: model.Transaction{Amount: -100, Splits: []model.TransactionSplit{
+				{CategoryID: &groceriesID, Amount: -80},
+				{CategoryID: &inflowCategoryID, Amount: -20},
+			}},
+			wantErr: true,
+		},
+		{
+			name: "transfer",
+			txn: model.Transaction{Amount: -100, Splits: []model.TransactionSplit{
+				{CategoryID: &groceriesID, Amount: -60},
+				{CategoryID: &householdID, Amount: -40},
+			}},
+			payee:   model.Payee{TransferAccountID: &transferAccountID},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			txn := tt.txn
+			err := service.validateSplits(&txn, inflowCategoryID, tt.payee)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			if txn.IsSplit() {
+				assert.Nil(t, txn.CategoryID, "parent category should be cleared for split transactions")
+			}
+		})
+	}
+}
+
+func TestGetSplitCarryoverOps(t *testing.T) {
+	groceriesID := uuid.New()
+	householdID := uuid.New()
+	diningID := uuid.New()
+	inflowCategoryID := uuid.New()
+	rules := carryoverRules{inflowCategoryID: inflowCategoryID}
+
+	t.Run("single_to_split", func(t *testing.T) {
+		oldTxn := &model.Transaction{Date: "2024-03-10", Amount: -100, CategoryID: &groceriesID}
+		newTxn := &model.Transaction{Date: "2024-03-10", Amount: -100, Splits: []model.TransactionSplit{
+			{CategoryID: &groceriesID, Amount: -70},
+			{CategoryID: &householdID, Amount: -30},
+		}}
+
+		ops := getSplitCarryoverOps(oldTxn, newTxn, rules)
+		assert.Equal(t, []carryoverOp{
+			{categoryId: groceriesID, monthKey: "2024-03", amountDelta: 30},
+			{categoryId: householdID, monthKey: "2024-03", amountDelta: -30},
+		}, ops)
+	})
+
+	t.Run("unchanged_lines_cancel_out", func(t *testing.T) {
+		splits := []model.TransactionSplit{
+			{CategoryID: &groceriesID, Amount: -70},
+			{CategoryID: &householdID, Amount: -30},
+		}
+		oldTxn := &model.Transaction{Date: "2024-03-10", Amount: -100, Splits: splits}
+		newTxn := &model.Transaction{Date: "2024-03-10", Amount: -100, Splits: []model.TransactionSplit{
+			{CategoryID: &groceriesID, Amount: -70},
+			{CategoryID: &diningID, Amount: -30},
+		}}
+
+		ops := getSplitCarryoverOps(oldTxn, newTxn, rules)
+		assert.Equal(t, []carryoverOp{
+			{categoryId: householdID, monthKey: "2024-03", amountDelta: 30},
+			{categoryId: diningID, monthKey: "2024-03", amountDelta: -30},
+		}, ops)
+	})
+
+	t.Run("month_change_moves_every_line", func(t *testing.T) {
+		splits := []model.TransactionSplit{
+			{CategoryID: &groceriesID, Amount: -70},
+			{CategoryID: &inflowCategoryID, Amount: 20},
+		}
+		oldTxn := &model.Transaction{Date: "2024-03-10", Amount: -50, Splits: splits}
+		newTxn := &model.Transaction{Date: "2024-04-01", Amount: -50, Splits: splits}
+
+		ops := getSplitCarryoverOps(oldTxn, newTxn, rules)
+		assert.Equal(t, []carryoverOp{
+			{categoryId: groceriesID, monthKey: "2024-03", amountDelta: 70},
+			{categoryId: groceriesID, monthKey: "2024-04", amountDelta: -70},
+		}, ops)
+	})
+}
+
+func TestCreateSplitTransaction(t *testing.T) {
+	var mockTx pgx.Tx
+	mockWithTxSuccess(mockTx)
+	defer func() { withTx = utils.WithTx }()
+
+	budgetId := uuid.New()
+	ctx := utils.WithBudgetID(context.Background(), budgetId)
+
+	accountId := uuid.New()
+	payeeId := uuid.New()
+	txnId := uuid.New()
+	groceriesID := uuid.New()
+	householdID := uuid.New()
+	splits := []model.TransactionSplit{
+		{CategoryID: &groceriesID, Amount: -60},
+		{CategoryID: &householdID, Amount: -40},
+	}
+	txn := model.Transaction{
+		AccountID:  &accountId,
+		PayeeID:    &payeeId,
+		CategoryID: &groceriesID,
+		Amount:     -100,
+		Date:       "2024-03-10",
+		Splits:     splits,
+	}
+
+	mockBudget := &mockBudgetRepo{}
+	mockAccount := &mockAccountRepo{}
+	mockPayee := &mockPayeesRepo{}
+	mockRepo := &mockTransactionRepo{}
+	mockMB := &mockMonthlyBudgetRepo{}
+	service := newTestTransactionService(mockRepo, mockBudget, nil, mockAccount, mockPayee, nil, mockMB)
+
+	mockBudget.On("GetById", mock.Anything, mockTx, budgetId).Return(&model.Budget{}, nil).Once()
+	mockAccount.On("GetById", mock.Anything, mockTx, budgetId, accountId).
+		Return(&model.Account{Type: "checking"}, nil).
+		Once()
+	mockPayee.On("GetByIdTx", mock.Anything, mockTx, budgetId, payeeId).Return(&model.Payee{}, nil).Once()
+	mockRepo.On("Create", mock.Anything, mockTx, mock.MatchedBy(func(t model.Transaction) bool {
+		return t.CategoryID == nil
+	})).Return([]model.Transaction{{ID: txnId}}, nil).Once()
+	mockRepo.On("ReplaceSplits", mock.Anything, mockTx, budgetId, txnId, splits).Return(nil).Once()
+	mockMB.On("GetByCatIdAndMonth", mock.Anything, mockTx, budgetId, groceriesID, "2024-03").
+		Return(&model.MonthlyBudget{}, nil).
+		Once()
+	mockMB.On("UpdateCarryoverByCatIdAndMonth", mock.Anything, mockTx, budgetId, groceriesID, "2024-03", -60.0).
+		Return(nil).
+		Once()
+	mockMB.On("GetByCatIdAndMonth", mock.Anything, mockTx, budgetId, householdID, "2024-03").
+		Return(&model.MonthlyBudget{}, nil).
+		Once()
+	mockMB.On("UpdateCarryoverByCatIdAndMonth", mock.Anything, mockTx, budgetId, householdID, "2024-03", -40.0).
+		Return(nil).
+		Once()
+	mockRepo.On("GetByIdTx", mock.Anything, mockTx, budgetId, txnId).
+		Return(&model.Transaction{ID: txnId, Splits: splits}, nil).
+		Once()
+
+	res, err := service.Create(ctx, txn)
+	assert.NoError(t, err)
+	assert.Len(t, res, 1)
+	assert.Len(t, res[0].Splits, 2)
+	mockRepo.AssertExpectations(t)
+	mockMB.AssertExpectations(t)
+}
+
+func TestUpdateWithoutSplitsKeepsSplitLines(t *testing.T) {
+	var mockTx pgx.Tx
+	mockWithTxSuccess(mockTx)
+	defer func() { withTx = utils.WithTx }()
+
+	budgetId := uuid.New()
+	ctx := utils.WithBudgetID(context.Background(), budgetId)
+
+	accountId := uuid.New()
+	payeeId := uuid.New()
+	txnId := uuid.New()
+	groceriesID := uuid.New()
+	householdID := uuid.New()
+	splits := []model.TransactionSplit{
+		{CategoryID: &groceriesID, Amount: -60},
+		{CategoryID: &householdID, Amount: -40},
+	}
+	foundTxn := &model.Transaction{
+		ID:        txnId,
+		BudgetID:  budgetId,
+		AccountID: &accountId,
+		PayeeID:   &payeeId,
+		Amount:    -100,
+		Date:      "2024-03-10",
+		Splits:    splits,
+	}
+	toUpdate := *foundTxn
+	toUpdate.Splits = nil
+	toUpdate.Note = "weekly shop"
+
+	mockBudget := &mockBudgetRepo{}
+	mockAccount := &mockAccountRepo{}
+	mockPayee := &mockPayeesRepo{}
+	mockRepo := &mockTransactionRepo{}
+	mockMB := &mockMonthlyBudgetRepo{}
+	service := newTestTransactionService(mockRepo, mockBudget, nil, mockAccount, mockPayee, nil, mockMB)
+
+	mockRepo.On("GetByIdTx", mock.Anything, mockTx, budgetId, txnId).Return(foundTxn, nil).Once()
+	mockBudget.On("GetById", mock.Anything, mockTx, budgetId).Return(&model.Budget{}, nil).Once()
+	mockAccount.On("GetById", mock.Anything, mockTx, budgetId, accountId).
+		Return(&model.Account{Type: "checking"}, nil).
+		Once()
+	mockPayee.On("GetByIdTx", mock.Anything, mockTx, budgetId, payeeId).Return(&model.Payee{}, nil).Once()
+	mockRepo.On("Update", mock.Anything, mockTx, budgetId, txnId, mock.MatchedBy(func(t model.Transaction) bool {
+		return t.Note == "weekly shop" && t.CategoryID == nil
+	})).Return(nil).Once()
+	mockRepo.On("ReplaceSplits", mock.Anything, mockTx, budgetId, txnId, splits).Return(nil).Once()
+
+	err := service.Update(ctx, txnId, toUpdate)
+	assert.NoError(t, err)
+	mockRepo.AssertExpectations(t)
+	mockMB.AssertExpectations(t)
+}
@@ -52,6 +52,11 @@ func (m *mockTransactionRepo) DeleteById(ctx context.Context, tx pgx.Tx, budgetI
 	return args.Error(0)
 }
 
+func (m *mockTransactionRepo) Restore(ctx context.Context, tx pgx.Tx, budgetId uuid.UUID, id uuid.UUID) error {
+	args := m.Called(ctx, tx, budgetId, id)
+	return args.Error(0)
+}
+
 // GetAll implements repository.TransactionRepository.
 func (m *mockTransactionRepo) GetAll(
 	ctx context.Context,
@@ -133,6 +138,54 @@ func (m *mockTransactionRepo) UpdateStatus(
 	return args.Error(0)
 }
 
+func (m *mockTransactionRepo) MarkReconciled(
+	ctx context.Context,
+	tx pgx.Tx,
+	budgetId uuid.UUID,
+	accountId uuid.UUID,
+) (int64, error) {
+	args := m.Called(ctx, tx, budgetId, accountId)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *mockTransactionRepo) GetForRewriteTx(
+	ctx context.Context,
+	tx pgx.Tx,
+	budgetId uuid.UUID,
+	filter model.TransactionFilter,
+) ([]model.Transaction, error) {
+	args := m.Called(ctx, tx, budgetId, filter)
+	if obj := args.Get(0); obj != nil {
+		return obj.([]model.Transaction), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+// ReplaceSplits implements repository.TransactionRepository.
+func (m *mockTransactionRepo) ReplaceSplits(
+	ctx context.Context,
+	tx pgx.Tx,
+	budgetId uuid.UUID,
+	txnId uuid.UUID,
+	splits []model.TransactionSplit,
+) error {
+	args := m.Called(ctx, tx, budgetId, txnId, splits)
+	return args.Error(0)
+}
+
+// GetTransferCandidates implements repository.TransactionRepository.
+func (m *mockTransactionRepo) GetTransferCandidates(
+	ctx context.Context,
+	budgetId uuid.UUID,
+	windowDays int,
+) ([]model.TransferMatch, error) {
+	args := m.Called(ctx, budgetId, windowDays)
+	if obj := args.Get(0); obj != nil {
+		return obj.([]model.TransferMatch), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
 type mockBudgetRepo struct {
 	mockBaseRepo
 	mock.Mock
@@ -296,6 +349,14 @@ func (m *mockCipherClient) GenerateTransactionEmbedding(
 	return nil, args.Error(1)
 }
 
+func (m *mockCipherClient) Predict(ctx context.Context, req PredictRequest) (*PredictResponse, error) {
+	args := m.Called(ctx, req)
+	if obj := args.Get(0); obj != nil {
+		return obj.(*PredictResponse), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
 // Create implements repository.PredictionRepository.
 func (m *mockPredictionRepo) Create(ctx context.Context, prediction model.Prediction) ([]model.Prediction, error) {
 	panic("unimplemented")
@@ -395,6 +456,57 @@ func (m *mockAccountRepo) UpdateTransferPayee(
 	panic("unimplemented")
 }
 
+func (m *mockAccountRepo) GetBalances(
+	ctx context.Context,
+	tx pgx.Tx,
+	budgetId uuid.UUID,
+	accountId uuid.UUID,
+) (*model.Account, error) {
+	panic("unimplemented")
+}
+
+func (m *mockAccountRepo) UpdateLastReconciled(
+	ctx context.Context,
+	tx pgx.Tx,
+	budgetId uuid.UUID,
+	accountId uuid.UUID,
+) error {
+	panic("unimplemented")
+}
+
+func (m *mockAccountRepo) Update(
+	ctx context.Context,
+	tx pgx.Tx,
+	budgetId uuid.UUID,
+	accountId uuid.UUID,
+	account model.Account,
+) error {
+	panic("unimplemented")
+}
+
+func (m *mockAccountRepo) SetClosed(
+	ctx context.Context,
+	tx pgx.Tx,
+	budgetId uuid.UUID,
+	accountId uuid.UUID,
+	closed bool,
+) error {
+	panic("unimplemented")
+}
+
+func (m *mockAccountRepo) HasTransactions(
+	ctx context.Context,
+	tx pgx.Tx,
+	budgetId uuid.UUID,
+	accountId uuid.UUID,
+) (bool, error) {
+	panic("unimplemented")
+}
+
+func (m *mockAccountRepo) DeleteById(ctx context.Context, tx pgx.Tx, budgetId uuid.UUID, accountId uuid.UUID) error {
+	panic("unimplemented")
+}
+
 type mockPayeesRepo struct {
 	mockBaseRepo
 	mock.Mock
@@ -439,6 +551,16 @@ func (m *mockPayeesRepo) Update(ctx context.Context, budgetId uuid.UUID, id uuid
 	panic("unimplemented")
 }
 
+func (m *mockPayeesRepo) Merge(
+	ctx context.Context,
+	tx pgx.Tx,
+	budgetId uuid.UUID,
+	targetId uuid.UUID,
+	sourceIds []uuid.UUID,
+) (*model.PayeeMergeResult, error) {
+	panic("unimplemented")
+}
+
 type mockCategoryRepo struct {
 	mockBaseRepo
 	mock.Mock
@@ -449,8 +571,55 @@ func (m *mockCategoryRepo) Create(ctx context.Context, tx pgx.Tx, category model
 	panic("unimplemented")
 }
 
+// GetPaymentCategoryIDs implements repository.CategoryRepository.
+func (m *mockCategoryRepo) GetPaymentCategoryIDs(ctx context.Context, tx pgx.Tx, budgetId uuid.UUID) (map[uuid.UUID]uuid.UUID, error) {
+	return nil, nil
+}
+
+// SetPaymentCategoryHidden implements repository.CategoryRepository.
+func (m *mockCategoryRepo) SetPaymentCategoryHidden(
+	ctx context.Context,
+	tx pgx.Tx,
+	budgetId uuid.UUID,
+	accountId uuid.UUID,
+	hidden bool,
+) error {
+	panic("unimplemented")
+}
+
 // DeleteById implements repository.CategoryRepository.
-func (m *mockCategoryRepo) DeleteById(ctx context.Context, budgetId uuid.UUID, id uuid.UUID) error {
+func (m *mockCategoryRepo) DeleteById(ctx context.Context, tx pgx.Tx, budgetId uuid.UUID, id uuid.UUID) error {
+	panic("unimplemented")
+}
+
+// IsInUse implements repository.CategoryRepository.
+func (m *mockCategoryRepo) IsInUse(ctx context.Context, tx pgx.Tx, budgetId uuid.UUID, id uuid.UUID) (bool, error) {
+	panic("unimplemented")
+}
+
+// GetGoal implements repository.CategoryRepository.
+func (m *mockCategoryRepo) GetGoal(ctx context.Context, budgetId uuid.UUID, categoryId uuid.UUID) (*model.CategoryGoal, error) {
+	panic("unimplemented")
+}
+
+// UpsertGoal implements repository.CategoryRepository.
+func (m *mockCategoryRepo) UpsertGoal(ctx context.Context, goal model.CategoryGoal) (*model.CategoryGoal, error) {
+	panic("unimplemented")
+}
+
+// DeleteGoal implements repository.CategoryRepository.
+func (m *mockCategoryRepo) DeleteGoal(ctx context.Context, budgetId uuid.UUID, categoryId uuid.UUID) error {
+	panic("unimplemented")
+}
+
+// Reassign implements repository.CategoryRepository.
+func (m *mockCategoryRepo) Reassign(
+	ctx context.Context,
+	tx pgx.Tx,
+	budgetId uuid.UUID,
+	fromId uuid.UUID,
+	toId uuid.UUID,
+) (*model.CategoryDeleteResult, error) {
 	panic("unimplemented")
 }
 
@@ -472,6 +641,17 @@ func (m *mockCategoryRepo) GetInflowBalance(ctx context.Context, budgetId uuid.U
 	panic("unimplemented")
 }
 
+// GetReadyToAssign implements repository.CategoryRepository.
+func (m *mockCategoryRepo) GetReadyToAssign(
+	ctx context.Context,
+	tx pgx.Tx,
+	budgetId uuid.UUID,
+	month string,
+	categories []model.Category,
+) (float64, error) {
+	panic("unimplemented")
+}
+
 // GetByFilter implements repository.CategoryRepository.
 func (m *mockCategoryRepo) GetByFilter(
 	ctx context.Context,
@@ -579,6 +759,71 @@ func (m *mockMonthlyBudgetRepo) UpdateCarryoverByCatIdAndMonth(
 	return args.Error(0)
 }
 
+// GetSummaries implements repository.MonthlyBudgetRepository.
+func (m *mockMonthlyBudgetRepo) GetSummaries(
+	ctx context.Context,
+	budgetId uuid.UUID,
+	startMonth string,
+	endMonth string,
+) ([]model.MonthlyBudgetSummary, error) {
+	args := m.Called(ctx, budgetId, startMonth, endMonth)
+	if obj := args.Get(0); obj != nil {
+		return obj.([]model.MonthlyBudgetSummary), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *mockMonthlyBudgetRepo) MergeCategory(
+	ctx context.Context,
+	tx pgx.Tx,
+	budgetId uuid.UUID,
+	fromCategoryId uuid.UUID,
+	toCategoryId uuid.UUID,
+) (int64, error) {
+	args := m.Called(ctx, tx, budgetId, fromCategoryId, toCategoryId)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *mockMonthlyBudgetRepo) LockBudget(ctx context.Context, tx pgx.Tx, budgetId uuid.UUID) error {
+	return m.Called(ctx, tx, budgetId).Error(0)
+}
+
+func (m *mockMonthlyBudgetRepo) GetByBudget(
+	ctx context.Context,
+	tx pgx.Tx,
+	budgetId uuid.UUID,
+) ([]model.MonthlyBudget, error) {
+	args := m.Called(ctx, tx, budgetId)
+	if obj := args.Get(0); obj != nil {
+		return obj.([]model.MonthlyBudget), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *mockMonthlyBudgetRepo) GetActivity(
+	ctx context.Context,
+	tx pgx.Tx,
+	budgetId uuid.UUID,
+) ([]model.CategoryMonthActivity, error) {
+	args := m.Called(ctx, tx, budgetId)
+	if obj := args.Get(0); obj != nil {
+		return obj.([]model.CategoryMonthActivity), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *mockMonthlyBudgetRepo) SetCarryover(
+	ctx context.Context,
+	tx pgx.Tx,
+	budgetId uuid.UUID,
+	categoryId uuid.UUID,
+	month string,
+	carryover float64,
+) error {
+	args := m.Called(ctx, tx, budgetId, categoryId, month, carryover)
+	return args.Error(0)
+}
+
 // Mock transaction interface to expose private methods for testing
 type testableTransactionService struct {
 	service transactionService
@@ -713,7 +958,10 @@ func newTestTransactionService(
 		mockAccount,
 		mockPayees,
 		mockCategory,
-		NewMonthlyBudgetService(mockMonthlyBudget),
+		NewMonthlyBudgetService(mockMonthlyBudget, nil),
+		nil,
+		nil,
+		nil,
 	)
 
 	return service.(*transactionService)
@@ -970,7 +1218,7 @@ func TestUpdateWithCipherPredictionMappingChangeMarksCorrectionAndLearns(t *test
 		payeeRuleRepo:        payeeRuleRepo,
 		accountRepo:          accountRepo,
 		payeeRepo:            payeeRepo,
-		mbService:            NewMonthlyBudgetService(monthlyBudgetRepo),
+		mbService:            NewMonthlyBudgetService(monthlyBudgetRepo, nil),
 	}
 	done := make(chan struct{})
 
@@ -1320,7 +1568,7 @@ func TestUpdateCarryovers(t *testing.T) {
 				budgetId,
 				tt.existingTxn,
 				&tt.newTxn,
-				inflowCategoryID,
+				carryoverRules{inflowCategoryID: inflowCategoryID},
 			)
 
 			if tt.expectError {
@@ -1596,6 +1844,14 @@ func (m *mockCipherClientTxn) GenerateTransactionEmbedding(ctx context.Context,
 	return args.Get(0).(*TransactionEmbeddingResponse), args.Error(1)
 }
 
+func (m *mockCipherClientTxn) Predict(ctx context.Context, req PredictRequest) (*PredictResponse, error) {
+	args := m.Called(ctx, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*PredictResponse), args.Error(1)
+}
+
 type mockTxnEmbeddingRepo struct {
 	mock.Mock
 }
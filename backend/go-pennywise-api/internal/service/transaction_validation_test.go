@@ -175,3 +175,84 @@ func TestValidateCategory(t *testing.T) {
 		})
 	}
 }
+
+func TestCheckReconciledLock(t *testing.T) {
+	accountID := uuid.New()
+	otherAccountID := uuid.New()
+	reconciled := model.Transaction{
+		ID:        uuid.New(),
+		AccountID: &accountID,
+		Date:      "2024-03-01",
+		Amount:    -42.5,
+		Note:      "rent",
+		Cleared:   model.ClearedStatusReconciled,
+	}
+
+	tests := []struct {
+		name        string
+		found       model.Transaction
+		update      func(txn *model.Transaction)
+		wantErr     bool
+		wantCleared model.ClearedStatus
+	}{
+		{
+			name:        "keeps_cleared_when_unset",
+			found:       model.Transaction{Cleared: model.ClearedStatusCleared},
+			update:      func(txn *model.Transaction) { txn.Cleared = "" },
+			wantCleared: model.ClearedStatusCleared,
+		},
+		{
+			name:    "cannot_reconcile_directly",
+			found:   model.Transaction{Cleared: model.ClearedStatusCleared},
+			update:  func(txn *model.Transaction) { txn.Cleared = model.ClearedStatusReconciled },
+			wantErr: true,
+		},
+		{
+			name:        "reconciled_note_change_allowed",
+			found:       reconciled,
+			update:      func(txn *model.Transaction) { txn.Cleared = ""; txn.Note = "rent march" },
+			wantCleared: model.ClearedStatusReconciled,
+		},
+		{
+			name:    "reconciled_amount_locked",
+			found:   reconciled,
+			update:  func(txn *model.Transaction) { txn.Amount = -40 },
+			wantErr: true,
+		},
+		{
+			name:    "reconciled_date_locked",
+			found:   reconciled,
+			update:  func(txn *model.Transaction) { txn.Date = "2024-03-02" },
+			wantErr: true,
+		},
+		{
+			name:    "reconciled_account_locked",
+			found:   reconciled,
+			update:  func(txn *model.Transaction) { txn.AccountID = &otherAccountID },
+			wantErr: true,
+		},
+		{
+			name:  "unlocking_allows_amount_change",
+			found: reconciled,
+			update: func(txn *model.Transaction) {
+				txn.Cleared = model.ClearedStatusCleared
+				txn.Amount = -40
+			},
+			wantCleared: model.ClearedStatusCleared,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			toUpdate := tt.found
+			tt.update(&toUpdate)
+			err := checkReconciledLock(&tt.found, &toUpdate)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantCleared, toUpdate.Cleared)
+		})
+	}
+}
@@ -0,0 +1,225 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"math"
+	"time"
+
+	errs "github.com/Rishabh-Kapri/pennywise/backend/shared/errors"
+	"github.com/Rishabh-Kapri/pennywise/backend/shared/logger"
+	"github.com/Rishabh-Kapri/pennywise/backend/shared/model"
+	utils "github.com/Rishabh-Kapri/pennywise/backend/shared/utils"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+func (s *transactionService) FindTransferMatches(ctx context.Context, windowDays int) ([]model.TransferMatch, error) {
+	budgetId := utils.MustBudgetID(ctx)
+	if windowDays == 0 {
+		windowDays = model.DefaultTransferMatchWindowDays
+	}
+	if windowDays < 0 || windowDays > model.MaxTransferMatchWindowDays {
+		return nil, errs.New(
+			errs.CodeInvalidArgument,
+			"windowDays must be between 0 and %d",
+			model.MaxTransferMatchWindowDays,
+		)
+	}
+
+	candidates, err := s.repo.GetTransferCandidates(ctx, budgetId, windowDays)
+	if err != nil {
+		return nil, errs.Wrap(errs.CodeTransactionLookupFailed, "error getting transfer candidates", err)
+	}
+	// candidates come closest first, so each transaction keeps its closest counterpart
+	used := make(map[uuid.UUID]bool)
+	matches := make([]model.TransferMatch, 0)
+	for _, candidate := range candidates {
+		if used[candidate.Outflow.ID] || used[candidate.Inflow.ID] {
+			continue
+		}
+		used[candidate.Outflow.ID] = true
+		used[candidate.Inflow.ID] = true
+		matches = append(matches, candidate)
+	}
+	return matches, nil
+}
+
+// AcceptTransferMatch turns the outflow into a transfer to the inflow's account and the inflow into
+// its counterpart, the same way a transfer created with a transfer payee is linked. The inflow keeps
+// its own date and cleared state since it reflects when the money actually arrived.
+// Accepting a match is not journaled: undoing one side alone would delete the other.
+func (s *transactionService) AcceptTransferMatch(
+	ctx context.Context,
+	outflowId uuid.UUID,
+	inflowId uuid.UUID,
+) (*model.TransferMatch, error) {
+	txCtx, txCancel := context.WithTimeout(ctx, 30*time.Second)
+	defer txCancel()
+
+	budgetId := utils.MustBudgetID(ctx)
+	logger.Logger(ctx).Info("accepting transfer match", "outflowId", outflowId, "inflowId", inflowId)
+
+	var match *model.TransferMatch
+	err := withTx(txCtx, s.repo.GetDB(), func(tx pgx.Tx) error {
+		var err error
+		match, err = s.linkTransferWithTx(txCtx, tx, budgetId, outflowId, inflowId)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return match, nil
+}
+
+func (s *transactionService) linkTransferWithTx(
+	ctx context.Context,
+	tx pgx.Tx,
+	budgetId uuid.UUID,
+	outflowId uuid.UUID,
+	inflowId uuid.UUID,
+) (*model.TransferMatch, error) {
+	outflow, err := s.getForTransferMatch(ctx, tx, budgetId, outflowId)
+	if err != nil {
+		return nil, err
+	}
+	inflow, err := s.getForTransferMatch(ctx, tx, budgetId, inflowId)
+	if err != nil {
+		return nil, err
+	}
+	if outflow.Amount >= 0 {
+		return nil, errs.New(errs.CodeInvalidArgument, "transaction %v is not an outflow", outflowId)
+	}
+	// compare in cents to avoid floating point residue
+	if math.Round(outflow.Amount*100) != -math.Round(inflow.Amount*100) {
+		return nil, errs.New(errs.CodeInvalidArgument, "outflow and inflow amounts don't match")
+	}
+	if *outflow.AccountID == *inflow.AccountID {
+		return nil, errs.New(errs.CodeInvalidArgument, "outflow and inflow are in the same account")
+	}
+
+	budget, err := s.budgetRepo.GetById(ctx, tx, budgetId)
+	if err != nil {
+		return nil, errs.Wrap(errs.CodeBudgetLookupFailed, "error fetching budget", err)
+	}
+	outflowAccount, err := s.accountRepo.GetById(ctx, tx, budgetId, *outflow.AccountID)
+	if err != nil {
+		return nil, errs.Wrap(errs.CodeAccountLookupFailed, "error getting account", err)
+	}
+	inflowAccount, err := s.accountRepo.GetById(ctx, tx, budgetId, *inflow.AccountID)
+	if err != nil {
+		return nil, errs.Wrap(errs.CodeAccountLookupFailed, "error getting transfer account", err)
+	}
+	if outflowAccount.Currency != inflowAccount.Currency {
+		return nil, errs.New(errs.CodeInvalidArgument, "accounts of a transfer must have the same currency")
+	}
+	if outflowAccount.TransferPayeeID == nil || inflowAccount.TransferPayeeID == nil {
+		return nil, errs.New(errs.CodeInvalidArgument, "account has no transfer payee")
+	}
+	// the payee of the outflow is the transfer payee of the inflow's account
+	transferPayee, err := s.payeeRepo.GetByIdTx(ctx, tx, budgetId, *inflowAccount.TransferPayeeID)
+	if err != nil {
+		return nil, errs.Wrap(errs.CodePayeeLookupFailed, "error getting transfer payee", err)
+	}
+
+	paymentCategoryIDs, err := s.loadPaymentCategoryIDs(ctx, tx, budgetId)
+	if err != nil {
+		return nil, err
+	}
+	rules := carryoverRules{
+		inflowCategoryID:   budget.Metadata.InflowCategoryID,
+		paymentCategoryIDs: paymentCategoryIDs,
+	}
+
+	linkedOutflow := *outflow
+	linkedOutflow.PayeeID = &transferPayee.ID
+	linkedOutflow.TransferAccountID = transferPayee.TransferAccountID
+	linkedOutflow.TransferTransactionID = &inflow.ID
+	if isBudgetTransfer(*outflowAccount, inflowAccount) {
+		linkedOutflow.CategoryID = nil
+	}
+
+	linkedInflow := transferCounterpart(budgetId, outflow.ID, linkedOutflow, *outflowAccount, *transferPayee)
+	linkedInflow.ID = inflow.ID
+	linkedInflow.Date = inflow.Date
+	linkedInflow.Amount = inflow.Amount
+	linkedInflow.Cleared = inflow.Cleared
+	linkedInflow.Status = inflow.Status
+	linkedInflow.TagIDs = inflow.TagIDs
+	linkedInflow.OriginalAmount = inflow.OriginalAmount
+	linkedInflow.OriginalCurrency = inflow.OriginalCurrency
+	linkedInflow.FxRate = inflow.FxRate
+	if inflow.Note != "" {
+		linkedInflow.Note = inflow.Note
+	}
+
+	for _, pair := range []struct{ old, linked *model.Transaction }{
+		{outflow, &linkedOutflow},
+		{inflow, &linkedInflow},
+	} {
+		// accepting a match reviews both sides
+		if pair.linked.Status == model.TransactionStatusUnapproved {
+			pair.linked.Status = model.TransactionStatusApproved
+		}
+		if err := s.mbService.UpdateCarryovers(
+			ctx,
+			tx,
+			budgetId,
+			pair.old,
+			pair.linked,
+			rules,
+		); err != nil {
+			return nil, err
+		}
+		if err := s.repo.Update(ctx, tx, budgetId, pair.old.ID, *pair.linked); err != nil {
+			return nil, errs.Wrap(errs.CodeTransferLinkFailed, "error linking transfer transaction", err)
+		}
+		if err := s.recordAudit(ctx, tx, model.AuditActionUpdate, pair.old.ID, pair.old, pair.linked); err != nil {
+			return nil, err
+		}
+	}
+
+	return &model.TransferMatch{
+		Outflow:   linkedOutflow,
+		Inflow:    linkedInflow,
+		DaysApart: daysApart(outflow.Date, inflow.Date),
+	}, nil
+}
+
+// getForTransferMatch loads a transaction that can become one side of a transfer
+func (s *transactionService) getForTransferMatch(
+	ctx context.Context,
+	tx pgx.Tx,
+	budgetId uuid.UUID,
+	id uuid.UUID,
+) (*model.Transaction, error) {
+	txn, err := s.repo.GetByIdTx(ctx, tx, budgetId, id)
+	if errors.Is(err, pgx.ErrNoRows) || (err == nil && txn == nil) {
+		return nil, errs.New(errs.CodeTransactionNotFound, "transaction not found for id %v", id)
+	}
+	if err != nil {
+		return nil, errs.Wrap(errs.CodeTransactionLookupFailed, "error getting transaction", err)
+	}
+	if txn.TransferTransactionID != nil {
+		return nil, errs.New(errs.CodeInvalidArgument, "transaction %v is already a transfer", id)
+	}
+	if txn.IsSplit() {
+		return nil, errs.New(errs.CodeInvalidArgument, "split transaction %v can't be a transfer", id)
+	}
+	if txn.AccountID == nil {
+		return nil, errs.New(errs.CodeInvalidArgument, "transaction %v has no account", id)
+	}
+	return txn, nil
+}
+
+// daysApart returns the number of days between two valid dates
+func daysApart(a model.Date, b model.Date) int {
+	first, _ := time.Parse("2006-01-02", a.String())
+	second, _ := time.Parse("2006-01-02", b.String())
+	days := int(math.Round(first.Sub(second).Hours() / 24))
+	if days < 0 {
+		return -days
+	}
+	return days
+}
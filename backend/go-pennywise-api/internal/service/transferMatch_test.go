@@ -0,0 +1,143 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	errs "github.com/Rishabh-Kapri/pennywise/backend/shared/errors"
+	"github.com/Rishabh-Kapri/pennywise/backend/shared/model"
+	utils "github.com/Rishabh-Kapri/pennywise/backend/shared/utils"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFindTransferMatches(t *testing.T) {
+	budgetId := uuid.New()
+	ctx := utils.WithBudgetID(context.Background(), budgetId)
+	outA, outB, inA, inB := uuid.New(), uuid.New(), uuid.New(), uuid.New()
+	pair := func(out, in uuid.UUID, days int) model.TransferMatch {
+		return model.TransferMatch{
+			Outflow:   model.Transaction{ID: out},
+			Inflow:    model.Transaction{ID: in},
+			DaysApart: days,
+		}
+	}
+
+	repo := &mockTransactionRepo{}
+	// outA is closest to inA, the later outA/inB pair must not reuse it
+	repo.On("GetTransferCandidates", ctx, budgetId, model.DefaultTransferMatchWindowDays).Return([]model.TransferMatch{
+		pair(outA, inA, 0),
+		pair(outA, inB, 1),
+		pair(outB, inA, 1),
+		pair(outB, inB, 2),
+	}, nil).Once()
+	service := newTestTransactionService(repo, nil, nil, nil, nil, nil, nil)
+
+	matches, err := service.FindTransferMatches(ctx, 0)
+	require.NoError(t, err)
+	require.Len(t, matches, 2)
+	assert.Equal(t, []uuid.UUID{outA, inA}, []uuid.UUID{matches[0].Outflow.ID, matches[0].Inflow.ID})
+	assert.Equal(t, []uuid.UUID{outB, inB}, []uuid.UUID{matches[1].Outflow.ID, matches[1].Inflow.ID})
+
+	_, err = service.FindTransferMatches(ctx, model.MaxTransferMatchWindowDays+1)
+	assert.True(t, hasErrorCode(err, errs.CodeInvalidArgument), err)
+	repo.AssertExpectations(t)
+}
+
+func TestAcceptTransferMatch(t *testing.T) {
+	useInlineTx(t)
+
+	budgetId := uuid.New()
+	ctx := utils.WithBudgetID(context.Background(), budgetId)
+	savingsId, cardId := uuid.New(), uuid.New()
+	savingsPayeeId, cardPayeeId := uuid.New(), uuid.New()
+	categoryId := uuid.New()
+	outflowId, inflowId := uuid.New(), uuid.New()
+
+	newMocks := func() (*mockTransactionRepo, *mockAccountRepo, *mockPayeesRepo, *mockMonthlyBudgetRepo, *transactionService) {
+		repo := &mockTransactionRepo{}
+		budgetRepo := &mockBudgetRepo{}
+		accountRepo := &mockAccountRepo{}
+		payeeRepo := &mockPayeesRepo{}
+		mbRepo := &mockMonthlyBudgetRepo{}
+		budgetRepo.On("GetById", mock.Anything, nil, budgetId).Return(&model.Budget{ID: budgetId}, nil).Maybe()
+		accountRepo.On("GetById", mock.Anything, nil, budgetId, savingsId).
+			Return(&model.Account{ID: savingsId, Type: "savings", Currency: "INR", TransferPayeeID: &savingsPayeeId}, nil).Maybe()
+		accountRepo.On("GetById", mock.Anything, nil, budgetId, cardId).
+			Return(&model.Account{ID: cardId, Type: "creditCard", Currency: "INR", TransferPayeeID: &cardPayeeId}, nil).Maybe()
+		payeeRepo.On("GetByIdTx", mock.Anything, nil, budgetId, cardPayeeId).
+			Return(&model.Payee{ID: cardPayeeId, TransferAccountID: &cardId}, nil).Maybe()
+		return repo, accountRepo, payeeRepo, mbRepo,
+			newTestTransactionService(repo, budgetRepo, nil, accountRepo, payeeRepo, nil, mbRepo)
+	}
+
+	t.Run("links_both_sides", func(t *testing.T) {
+		repo, _, _, mbRepo, service := newMocks()
+		repo.On("GetByIdTx", mock.Anything, nil, budgetId, outflowId).Return(&model.Transaction{
+			ID:         outflowId,
+			AccountID:  &savingsId,
+			CategoryID: &categoryId,
+			Amount:     -500,
+			Date:       "2024-01-05",
+			Status:     model.TransactionStatusUnapproved,
+		}, nil).Once()
+		repo.On("GetByIdTx", mock.Anything, nil, budgetId, inflowId).Return(&model.Transaction{
+			ID:        inflowId,
+			AccountID: &cardId,
+			Amount:    500,
+			Date:      "2024-01-06",
+			Note:      "card payment",
+			Cleared:   model.ClearedStatusCleared,
+			Status:    model.TransactionStatusUnapproved,
+		}, nil).Once()
+		// the outflow leaves its category, a budget transfer has none
+		mbRepo.On("GetByCatIdAndMonth", mock.Anything, nil, budgetId, categoryId, mock.Anything).
+			Return(&model.MonthlyBudget{}, nil).Once()
+		mbRepo.On("UpdateCarryoverByCatIdAndMonth", mock.Anything, nil, budgetId, categoryId, mock.Anything, 500.0).
+			Return(nil).Once()
+		repo.On("Update", mock.Anything, nil, budgetId, outflowId, mock.MatchedBy(func(txn model.Transaction) bool {
+			return txn.CategoryID == nil && *txn.PayeeID == cardPayeeId && *txn.TransferAccountID == cardId &&
+				*txn.TransferTransactionID == inflowId && txn.Status == model.TransactionStatusApproved
+		})).Return(nil).Once()
+		repo.On("Update", mock.Anything, nil, budgetId, inflowId, mock.MatchedBy(func(txn model.Transaction) bool {
+			return *txn.PayeeID == savingsPayeeId && *txn.TransferAccountID == savingsId &&
+				*txn.TransferTransactionID == outflowId && txn.Date == "2024-01-06" &&
+				txn.Note == "card payment" && txn.Cleared == model.ClearedStatusCleared
+		})).Return(nil).Once()
+
+		match, err := service.AcceptTransferMatch(ctx, outflowId, inflowId)
+		require.NoError(t, err)
+		assert.Equal(t, 1, match.DaysApart)
+		repo.AssertExpectations(t)
+		mbRepo.AssertExpectations(t)
+	})
+
+	t.Run("rejects_different_amounts", func(t *testing.T) {
+		repo, _, _, _, service := newMocks()
+		repo.On("GetByIdTx", mock.Anything, nil, budgetId, outflowId).
+			Return(&model.Transaction{ID: outflowId, AccountID: &savingsId, Amount: -500}, nil).Once()
+		repo.On("GetByIdTx", mock.Anything, nil, budgetId, inflowId).
+			Return(&model.Transaction{ID: inflowId, AccountID: &cardId, Amount: 499.99}, nil).Once()
+
+		_, err := service.AcceptTransferMatch(ctx, outflowId, inflowId)
+		assert.True(t, hasErrorCode(err, errs.CodeInvalidArgument), err)
+		repo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("rejects_existing_transfer", func(t *testing.T) {
+		repo, _, _, _, service := newMocks()
+		linkedId := uuid.New()
+		repo.On("GetByIdTx", mock.Anything, nil, budgetId, outflowId).Return(&model.Transaction{
+			ID:                    outflowId,
+			AccountID:             &savingsId,
+			Amount:                -500,
+			TransferTransactionID: &linkedId,
+		}, nil).Once()
+
+		_, err := service.AcceptTransferMatch(ctx, outflowId, inflowId)
+		assert.ErrorContains(t, err, "already a transfer")
+	})
+}
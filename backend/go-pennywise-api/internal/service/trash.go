@@ -0,0 +1,152 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/Rishabh-Kapri/pennywise/backend/go-pennywise-api/internal/blobstore"
+	repository "github.com/Rishabh-Kapri/pennywise/backend/shared/db"
+	errs "github.com/Rishabh-Kapri/pennywise/backend/shared/errors"
+	"github.com/Rishabh-Kapri/pennywise/backend/shared/logger"
+	"github.com/Rishabh-Kapri/pennywise/backend/shared/model"
+	utils "github.com/Rishabh-Kapri/pennywise/backend/shared/utils"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+// purgeBatchSize caps how many entities a single purge removes, the rest wait for the next run
+const purgeBatchSize = 500
+
+type TrashService interface {
+	// GetAll lists what was deleted within the retention period, an empty entityType lists every type
+	GetAll(ctx context.Context, entityType model.TrashEntityType) ([]model.TrashItem, error)
+	// Restore brings back a deleted entity. Transactions reapply their carryovers and bring back
+	// their transfer counterpart, payees their rules and accounts their transfer payee.
+	// A category comes back without the transactions and budgets handed to its replacement on delete.
+	Restore(ctx context.Context, entityType model.TrashEntityType, id uuid.UUID) (*model.TrashItem, error)
+	// Purge permanently deletes the entities of all budgets deleted before the retention period
+	// and returns how many were purged. Entities still referenced, like a payee of a live
+	// transaction or schedule, are kept until nothing refers to them anymore.
+	Purge(ctx context.Context, now time.Time) (int, error)
+}
+
+type trashService struct {
+	repo               repository.TrashRepository
+	transactionService TransactionService
+	store              blobstore.BlobStore
+	retention          time.Duration
+}
+
+func NewTrashService(
+	repo repository.TrashRepository,
+	transactionService TransactionService,
+	store blobstore.BlobStore,
+	retentionDays int,
+) TrashService {
+	if retentionDays <= 0 {
+		retentionDays = model.DefaultTrashRetentionDays
+	}
+	return &trashService{
+		repo:               repo,
+		transactionService: transactionService,
+		store:              store,
+		retention:          time.Duration(retentionDays) * 24 * time.Hour,
+	}
+}
+
+func (s *trashService) GetAll(ctx context.Context, entityType model.TrashEntityType) ([]model.TrashItem, error) {
+	budgetId := utils.MustBudgetID(ctx)
+	if entityType != "" && !entityType.Valid() {
+		return nil, errs.New(errs.CodeInvalidArgument, "invalid trash type %q", entityType)
+	}
+	items, err := s.repo.GetAll(ctx, budgetId, entityType, time.Now().Add(-s.retention))
+	if err != nil {
+		return nil, errs.Wrap(errs.CodeTrashLookupFailed, "error getting trash", err)
+	}
+	for i := range items {
+		items[i].PurgeAt = items[i].DeletedAt.Add(s.retention)
+	}
+	return items, nil
+}
+
+func (s *trashService) Restore(
+	ctx context.Context,
+	entityType model.TrashEntityType,
+	id uuid.UUID,
+) (*model.TrashItem, error) {
+	budgetId := utils.MustBudgetID(ctx)
+	if !entityType.Valid() {
+		return nil, errs.New(errs.CodeInvalidArgument, "invalid trash type %q", entityType)
+	}
+	logger.Logger(ctx).Info("restoring from trash", "type", entityType, "id", id)
+
+	item, err := s.repo.GetById(ctx, nil, budgetId, entityType, id)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, errs.New(errs.CodeTrashItemNotFound, "no deleted %s found for id %v", entityType, id)
+	}
+	if err != nil {
+		return nil, errs.Wrap(errs.CodeTrashLookupFailed, "error getting trash item", err)
+	}
+
+	if entityType == model.TrashEntityTransaction {
+		if err = s.transactionService.Restore(ctx, id); err != nil {
+			return nil, err
+		}
+	} else {
+		err = s.repo.Restore(ctx, nil, budgetId, entityType, id)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, errs.New(errs.CodeTrashItemNotFound, "no deleted %s found for id %v", entityType, id)
+		}
+		if err != nil {
+			return nil, errs.Wrap(errs.CodeTrashRestoreFailed, "error restoring "+string(entityType), err)
+		}
+	}
+	item.PurgeAt = item.DeletedAt.Add(s.retention)
+	return item, nil
+}
+
+func (s *trashService) Purge(ctx context.Context, now time.Time) (int, error) {
+	log := logger.Logger(ctx)
+	items, err := s.repo.GetPurgeable(ctx, now.Add(-s.retention), purgeBatchSize)
+	if err != nil {
+		return 0, errs.Wrap(errs.CodeTrashLookupFailed, "error getting purgeable trash", err)
+	}
+
+	purged := 0
+	var purgeErrs []error
+	for _, item := range items {
+		var storageKeys []string
+		// each entity gets its own db transaction so one that is still referenced doesn't hold back the rest
+		err := withTx(ctx, s.repo.GetDB(), func(tx pgx.Tx) error {
+			var err error
+			storageKeys, err = s.repo.Purge(ctx, tx, item)
+			return err
+		})
+		if utils.IsForeignKeyViolation(err) {
+			log.Debug("keeping trashed entity that is still referenced", "type", item.Type, "id", item.ID)
+			continue
+		}
+		if err != nil {
+			purgeErrs = append(purgeErrs, errs.Wrap(errs.CodeTrashPurgeFailed, "error purging "+string(item.Type), err))
+			continue
+		}
+		purged++
+		s.deleteBlobs(ctx, storageKeys)
+	}
+	return purged, errors.Join(purgeErrs...)
+}
+
+// deleteBlobs removes the files of purged attachments. A file left behind only costs storage,
+// so failures are logged instead of failing the purge.
+func (s *trashService) deleteBlobs(ctx context.Context, storageKeys []string) {
+	if s.store == nil {
+		return
+	}
+	for _, key := range storageKeys {
+		if err := s.store.Delete(ctx, key); err != nil && !errors.Is(err, blobstore.ErrNotFound) {
+			logger.Logger(ctx).Warn("failed to delete attachment file", "key", key, "error", err)
+		}
+	}
+}
@@ -0,0 +1,180 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/Rishabh-Kapri/pennywise/backend/go-pennywise-api/internal/blobstore"
+	errs "github.com/Rishabh-Kapri/pennywise/backend/shared/errors"
+	"github.com/Rishabh-Kapri/pennywise/backend/shared/model"
+	utils "github.com/Rishabh-Kapri/pennywise/backend/shared/utils"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type mockTrashRepo struct {
+	mockBaseRepo
+	mock.Mock
+}
+
+func (m *mockTrashRepo) GetAll(
+	ctx context.Context,
+	budgetId uuid.UUID,
+	entityType model.TrashEntityType,
+	since time.Time,
+) ([]model.TrashItem, error) {
+	args := m.Called(ctx, budgetId, entityType, since)
+	if v := args.Get(0); v != nil {
+		return v.([]model.TrashItem), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *mockTrashRepo) GetById(
+	ctx context.Context,
+	tx pgx.Tx,
+	budgetId uuid.UUID,
+	entityType model.TrashEntityType,
+	id uuid.UUID,
+) (*model.TrashItem, error) {
+	args := m.Called(ctx, tx, budgetId, entityType, id)
+	if v := args.Get(0); v != nil {
+		return v.(*model.TrashItem), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *mockTrashRepo) Restore(
+	ctx context.Context,
+	tx pgx.Tx,
+	budgetId uuid.UUID,
+	entityType model.TrashEntityType,
+	id uuid.UUID,
+) error {
+	return m.Called(ctx, tx, budgetId, entityType, id).Error(0)
+}
+
+func (m *mockTrashRepo) GetPurgeable(ctx context.Context, before time.Time, limit int) ([]model.TrashItem, error) {
+	args := m.Called(ctx, before, limit)
+	if v := args.Get(0); v != nil {
+		return v.([]model.TrashItem), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *mockTrashRepo) Purge(ctx context.Context, tx pgx.Tx, item model.TrashItem) ([]string, error) {
+	args := m.Called(ctx, tx, item)
+	if v := args.Get(0); v != nil {
+		return v.([]string), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *mockTxnService) Restore(ctx context.Context, id uuid.UUID) error {
+	return m.Called(ctx, id).Error(0)
+}
+
+func TestTrashService_GetAll(t *testing.T) {
+	budgetId := uuid.New()
+	ctx := utils.WithBudgetID(context.Background(), budgetId)
+	deletedAt := time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC)
+	repo := &mockTrashRepo{}
+	service := NewTrashService(repo, nil, nil, 7)
+
+	repo.On("GetAll", ctx, budgetId, model.TrashEntityPayee, mock.MatchedBy(func(since time.Time) bool {
+		return time.Since(since).Round(time.Hour) == 7*24*time.Hour
+	})).Return([]model.TrashItem{{Type: model.TrashEntityPayee, DeletedAt: deletedAt}}, nil).Once()
+
+	items, err := service.GetAll(ctx, model.TrashEntityPayee)
+	require.NoError(t, err)
+	require.Len(t, items, 1)
+	assert.Equal(t, deletedAt.AddDate(0, 0, 7), items[0].PurgeAt)
+
+	_, err = service.GetAll(ctx, "budget")
+	assert.True(t, hasErrorCode(err, errs.CodeInvalidArgument), err)
+	repo.AssertExpectations(t)
+}
+
+func TestTrashService_Restore(t *testing.T) {
+	budgetId := uuid.New()
+	ctx := utils.WithBudgetID(context.Background(), budgetId)
+	id := uuid.New()
+
+	t.Run("transaction_goes_through_transaction_service", func(t *testing.T) {
+		repo := &mockTrashRepo{}
+		txnService := &mockTxnService{}
+		service := NewTrashService(repo, txnService, nil, 0)
+		repo.On("GetById", ctx, nil, budgetId, model.TrashEntityTransaction, id).
+			Return(&model.TrashItem{Type: model.TrashEntityTransaction, ID: id}, nil).Once()
+		txnService.On("Restore", ctx, id).Return(nil).Once()
+
+		item, err := service.Restore(ctx, model.TrashEntityTransaction, id)
+		require.NoError(t, err)
+		assert.Equal(t, id, item.ID)
+		repo.AssertNotCalled(t, "Restore", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+		txnService.AssertExpectations(t)
+	})
+
+	t.Run("payee", func(t *testing.T) {
+		repo := &mockTrashRepo{}
+		service := NewTrashService(repo, nil, nil, 0)
+		repo.On("GetById", ctx, nil, budgetId, model.TrashEntityPayee, id).
+			Return(&model.TrashItem{Type: model.TrashEntityPayee, ID: id}, nil).Once()
+		repo.On("Restore", ctx, nil, budgetId, model.TrashEntityPayee, id).Return(nil).Once()
+
+		_, err := service.Restore(ctx, model.TrashEntityPayee, id)
+		require.NoError(t, err)
+		repo.AssertExpectations(t)
+	})
+
+	t.Run("not_in_trash", func(t *testing.T) {
+		repo := &mockTrashRepo{}
+		service := NewTrashService(repo, nil, nil, 0)
+		repo.On("GetById", ctx, nil, budgetId, model.TrashEntityCategory, id).Return(nil, pgx.ErrNoRows).Once()
+
+		_, err := service.Restore(ctx, model.TrashEntityCategory, id)
+		assert.True(t, hasErrorCode(err, errs.CodeTrashItemNotFound), err)
+	})
+
+	t.Run("invalid_type", func(t *testing.T) {
+		service := NewTrashService(&mockTrashRepo{}, nil, nil, 0)
+		_, err := service.Restore(ctx, "tag", id)
+		assert.True(t, hasErrorCode(err, errs.CodeInvalidArgument), err)
+	})
+}
+
+func TestTrashService_Purge(t *testing.T) {
+	useInlineTx(t)
+
+	ctx := context.Background()
+	now := time.Date(2024, 3, 31, 3, 0, 0, 0, time.UTC)
+	store, err := blobstore.NewLocalStore(t.TempDir())
+	require.NoError(t, err)
+	require.NoError(t, store.Put(ctx, "budget/txn/attachment", bytes.NewReader([]byte("receipt"))))
+
+	txn := model.TrashItem{Type: model.TrashEntityTransaction, ID: uuid.New()}
+	payee := model.TrashItem{Type: model.TrashEntityPayee, ID: uuid.New()}
+	account := model.TrashItem{Type: model.TrashEntityAccount, ID: uuid.New()}
+
+	repo := &mockTrashRepo{}
+	repo.On("GetPurgeable", ctx, now.AddDate(0, 0, -model.DefaultTrashRetentionDays), purgeBatchSize).
+		Return([]model.TrashItem{txn, payee, account}, nil).Once()
+	repo.On("Purge", ctx, nil, txn).Return([]string{"budget/txn/attachment"}, nil).Once()
+	// a payee still used by a live transaction stays in the trash
+	repo.On("Purge", ctx, nil, payee).Return(nil, &pgconn.PgError{Code: "23503"}).Once()
+	repo.On("Purge", ctx, nil, account).Return(nil, nil).Once()
+
+	purged, err := NewTrashService(repo, nil, store, 0).Purge(ctx, now)
+	require.NoError(t, err)
+	assert.Equal(t, 2, purged)
+	_, err = store.Get(ctx, "budget/txn/attachment")
+	assert.ErrorIs(t, err, blobstore.ErrNotFound)
+	repo.AssertExpectations(t)
+}
@@ -116,6 +116,42 @@ func (f *fakeTransactionService) DeleteById(context.Context, uuid.UUID) error {
 	return nil
 }
 
+func (f *fakeTransactionService) Bulk(context.Context, []model.BulkTransactionOperation) (*model.BulkTransactionResponse, error) {
+	return nil, nil
+}
+
+func (f *fakeTransactionService) Undo(context.Context, int) (*model.UndoResponse, error) {
+	return nil, nil
+}
+
+func (f *fakeTransactionService) Redo(context.Context, int) (*model.UndoResponse, error) {
+	return nil, nil
+}
+
+func (f *fakeTransactionService) FindTransferMatches(context.Context, int) ([]model.TransferMatch, error) {
+	return nil, nil
+}
+
+func (f *fakeTransactionService) AcceptTransferMatch(context.Context, uuid.UUID, uuid.UUID) (*model.TransferMatch, error) {
+	return nil, nil
+}
+
+func (f *fakeTransactionService) GetDuplicates(context.Context, model.DuplicateStatus) ([]model.TransactionDuplicate, error) {
+	return nil, nil
+}
+
+func (f *fakeTransactionService) MergeDuplicate(context.Context, uuid.UUID, *uuid.UUID) (*model.Transaction, error) {
+	return nil, nil
+}
+
+func (f *fakeTransactionService) DismissDuplicate(context.Context, uuid.UUID) error {
+	return nil
+}
+
+func (f *fakeTransactionService) Restore(context.Context, uuid.UUID) error {
+	return nil
+}
+
 type fakePayeeService struct {
 	create func(context.Context, model.Payee) (*model.Payee, error)
 }
@@ -167,6 +203,10 @@ func (f *fakePayeeService) Update(context.Context, uuid.UUID, model.Payee) error
 	return nil
 }
 
+func (f *fakePayeeService) Merge(context.Context, uuid.UUID, model.MergePayeesRequest) (*model.PayeeMergeResult, error) {
+	return nil, nil
+}
+
 func assertErrorCode(t *testing.T, err error, code errs.Code) {
 	t.Helper()
 	var appErr *errs.Error
@@ -83,6 +83,9 @@ func (a *CreateTransactionActivity) CreateTransaction(
 			DedupeHash:  &hash,
 			RawBankText: &p.OriginalRawText,
 			Summary:     &p.Summary,
+			// set when the card was charged in a foreign currency
+			OriginalAmount:   p.OriginalAmount,
+			OriginalCurrency: p.OriginalCurrency,
 		}
 
 		createdTxn, err := a.TransactionService.Create(ctx, txn)
@@ -220,6 +223,9 @@ func (a *CreateTransactionActivity) createTransactions(
 			DedupeHash:  &hash,
 			RawBankText: &p.OriginalRawText,
 			Summary:     &p.Summary,
+			// set when the card was charged in a foreign currency
+			OriginalAmount:   p.OriginalAmount,
+			OriginalCurrency: p.OriginalCurrency,
 		}
 
 		createdTxn, err := a.TransactionService.CreateWithTx(ctx, tx, txn)
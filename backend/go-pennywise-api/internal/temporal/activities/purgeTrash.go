@@ -0,0 +1,33 @@
+package temporal
+
+import (
+	"context"
+	"time"
+
+	"github.com/Rishabh-Kapri/pennywise/backend/go-pennywise-api/internal/service"
+	"github.com/Rishabh-Kapri/pennywise/backend/shared/logger"
+	"github.com/Rishabh-Kapri/pennywise/backend/shared/utils"
+
+	"go.temporal.io/sdk/activity"
+)
+
+type PurgeTrashActivity struct {
+	TrashService service.TrashService
+}
+
+// PurgeTrash permanently deletes what has been in the trash longer than the retention period
+// and returns the number of entities purged
+func (a *PurgeTrashActivity) PurgeTrash(ctx context.Context) (int, error) {
+	ctx = utils.WithServiceName(ctx, "pennywise-api")
+	activityInfo := activity.GetInfo(ctx)
+	log := logger.Logger(ctx).With(
+		"workflow_id", activityInfo.WorkflowExecution.ID,
+		"workflow_run_id", activityInfo.WorkflowExecution.RunID,
+		"activity_id", activityInfo.ActivityID,
+		"activity_type", activityInfo.ActivityType.Name,
+	)
+
+	purged, err := a.TrashService.Purge(ctx, time.Now())
+	log.Info("purged trash", "count", purged)
+	return purged, err
+}
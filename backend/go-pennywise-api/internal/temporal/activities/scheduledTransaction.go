@@ -0,0 +1,55 @@
+package temporal
+
+import (
+	"context"
+	"time"
+
+	"github.com/Rishabh-Kapri/pennywise/backend/go-pennywise-api/internal/service"
+	"github.com/Rishabh-Kapri/pennywise/backend/shared/logger"
+	sharedModel "github.com/Rishabh-Kapri/pennywise/backend/shared/model"
+	"github.com/Rishabh-Kapri/pennywise/backend/shared/utils"
+
+	"github.com/google/uuid"
+	"go.temporal.io/sdk/activity"
+)
+
+type ScheduledTransactionActivity struct {
+	ScheduledTransactionService service.ScheduledTransactionService
+	WebsocketService            service.WebsocketService
+}
+
+// MaterializeScheduledTransactions creates the transactions for all scheduled transactions due today
+// and returns the number of transactions created
+func (a *ScheduledTransactionActivity) MaterializeScheduledTransactions(ctx context.Context) (int, error) {
+	ctx = utils.WithServiceName(ctx, "pennywise-api")
+	activityInfo := activity.GetInfo(ctx)
+	log := logger.Logger(ctx).With(
+		"workflow_id", activityInfo.WorkflowExecution.ID,
+		"workflow_run_id", activityInfo.WorkflowExecution.RunID,
+		"activity_id", activityInfo.ActivityID,
+		"activity_type", activityInfo.ActivityType.Name,
+	)
+
+	created, err := a.ScheduledTransactionService.MaterializeDue(ctx, time.Now())
+
+	// notify for whatever was created, even if some schedules failed
+	byBudget := make(map[uuid.UUID][]sharedModel.Transaction)
+	for _, txn := range created {
+		byBudget[txn.BudgetID] = append(byBudget[txn.BudgetID], txn)
+	}
+	if a.WebsocketService != nil {
+		for budgetId, txns := range byBudget {
+			if notifyErr := a.WebsocketService.SendNotification(
+				ctx,
+				budgetId,
+				"pennywise::transaction::created",
+				txns,
+			); notifyErr != nil {
+				log.Warn("failed to send transaction created websocket notification", "error", notifyErr)
+			}
+		}
+	}
+
+	log.Info("materialized scheduled transactions", "count", len(created))
+	return len(created), err
+}
@@ -3,6 +3,7 @@ package db
 import (
 	"context"
 	"errors"
+	"fmt"
 
 	"github.com/Rishabh-Kapri/pennywise/backend/shared/logger"
 	"github.com/Rishabh-Kapri/pennywise/backend/shared/model"
@@ -21,8 +22,29 @@ type AccountRepository interface {
 	Search(ctx context.Context, budgetId uuid.UUID, query string) ([]model.Account, error)
 	Create(ctx context.Context, tx pgx.Tx, account model.Account) (*model.Account, error)
 	UpdateTransferPayee(ctx context.Context, tx pgx.Tx, accountId uuid.UUID, payeeId uuid.UUID) error
+	GetBalances(ctx context.Context, tx pgx.Tx, budgetId uuid.UUID, accountId uuid.UUID) (*model.Account, error)
+	UpdateLastReconciled(ctx context.Context, tx pgx.Tx, budgetId uuid.UUID, accountId uuid.UUID) error
+	// Update changes the name, type and suffix of an account and renames its transfer payee and payment
+	// category to match. The payment category is deleted when the account stops being a credit card
+	// and brought back when it becomes one again.
+	Update(ctx context.Context, tx pgx.Tx, budgetId uuid.UUID, accountId uuid.UUID, account model.Account) error
+	// SetClosed closes or reopens an account, hiding or showing its transfer payee and payment category with it
+	SetClosed(ctx context.Context, tx pgx.Tx, budgetId uuid.UUID, accountId uuid.UUID, closed bool) error
+	HasTransactions(ctx context.Context, tx pgx.Tx, budgetId uuid.UUID, accountId uuid.UUID) (bool, error)
+	DeleteById(ctx context.Context, tx pgx.Tx, budgetId uuid.UUID, accountId uuid.UUID) error
 }
 
+// accountBalancesJoin sums the working, cleared and uncleared balances of each account.
+// Reconciled transactions count towards the cleared balance.
+const accountBalancesJoin = `LEFT JOIN LATERAL (
+		SELECT
+			COALESCE(SUM(t.amount), 0) AS balance,
+			COALESCE(SUM(t.amount) FILTER (WHERE t.cleared <> 'UNCLEARED'), 0) AS cleared_balance,
+			COALESCE(SUM(t.amount) FILTER (WHERE t.cleared = 'UNCLEARED'), 0) AS uncleared_balance
+		FROM transactions t
+		WHERE t.account_id = accounts.id AND t.deleted = FALSE
+	) balances ON TRUE`
+
 type accountRepo struct {
 	BaseRepository
 }
@@ -40,15 +62,17 @@ func (r *accountRepo) GetAll(ctx context.Context, budgetId uuid.UUID) ([]model.A
 		  accounts.budget_id,
 		  accounts.transfer_payee_id,
 		  accounts.type,
+		  accounts.suffix,
+		  accounts.currency,
 		  accounts.closed,
 		  accounts.created_at,
 		  accounts.updated_at,
-		  COALESCE((
-				SELECT SUM(transactions.amount)
-				FROM transactions
-				WHERE transactions.account_id = accounts.id AND transactions.deleted = FALSE
-		  ), 0) as balance
+		  accounts.last_reconciled_at,
+		  balances.balance,
+		  balances.cleared_balance,
+		  balances.uncleared_balance
 		FROM accounts
+		`+accountBalancesJoin+`
 		WHERE budget_id = $1 AND deleted = FALSE
 		`, budgetId,
 	)
@@ -66,10 +90,15 @@ func (r *accountRepo) GetAll(ctx context.Context, budgetId uuid.UUID) ([]model.A
 			&a.BudgetID,
 			&a.TransferPayeeID,
 			&a.Type,
+			&a.Suffix,
+			&a.Currency,
 			&a.Closed,
 			&a.CreatedAt,
 			&a.UpdatedAt,
+			&a.LastReconciledAt,
 			&a.Balance,
+			&a.ClearedBalance,
+			&a.UnclearedBalance,
 		)
 		if err != nil {
 			errorMsg := errors.New("Error while parsing account rows: ")
@@ -83,7 +112,7 @@ func (r *accountRepo) GetAll(ctx context.Context, budgetId uuid.UUID) ([]model.A
 func (r *accountRepo) GetAllSimplified(ctx context.Context, budgetId uuid.UUID) ([]model.AccountSimplified, error) {
 	rows, err := r.Executor(nil).Query(
 		ctx,
-		`SELECT id, name FROM accounts WHERE budget_id = $1 AND deleted = FALSE AND closed = FALSE`,
+		`SELECT id, name, currency FROM accounts WHERE budget_id = $1 AND deleted = FALSE AND closed = FALSE`,
 		budgetId,
 	)
 	if err != nil {
@@ -94,7 +123,7 @@ func (r *accountRepo) GetAllSimplified(ctx context.Context, budgetId uuid.UUID)
 	accounts := make([]model.AccountSimplified, 0)
 	for rows.Next() {
 		var a model.AccountSimplified
-		if err := rows.Scan(&a.ID, &a.Name); err != nil {
+		if err := rows.Scan(&a.ID, &a.Name, &a.Currency); err != nil {
 			logger.Logger(ctx).Error("error scanning account", "error", err)
 			return nil, err
 		}
@@ -107,7 +136,7 @@ func (r *accountRepo) GetById(ctx context.Context, tx pgx.Tx, budgetId uuid.UUID
 	var a model.Account
 	err := r.Executor(tx).QueryRow(
 		ctx, `
-		  SELECT id, name, budget_id, transfer_payee_id, type, closed, created_at, updated_at 
+		  SELECT id, name, budget_id, transfer_payee_id, type, suffix, currency, closed, created_at, updated_at
 		  FROM accounts 
 		  WHERE id = $1 AND budget_id = $2 AND deleted = FALSE
 		`,
@@ -118,6 +147,8 @@ func (r *accountRepo) GetById(ctx context.Context, tx pgx.Tx, budgetId uuid.UUID
 		&a.BudgetID,
 		&a.TransferPayeeID,
 		&a.Type,
+		&a.Suffix,
+		&a.Currency,
 		&a.Closed,
 		&a.CreatedAt,
 		&a.UpdatedAt,
@@ -133,7 +164,7 @@ func (r *accountRepo) GetBySuffix(ctx context.Context, budgetId uuid.UUID, suffi
 	var a model.Account
 	err := r.Executor(nil).QueryRow(
 		ctx, `
-		  SELECT id, name, budget_id, transfer_payee_id, type, closed, created_at, updated_at 
+		  SELECT id, name, budget_id, transfer_payee_id, type, suffix, currency, closed, created_at, updated_at
 		  FROM accounts 
 		  WHERE budget_id = $1 AND deleted = FALSE AND suffix = $2
 		`,
@@ -144,6 +175,8 @@ func (r *accountRepo) GetBySuffix(ctx context.Context, budgetId uuid.UUID, suffi
 		&a.BudgetID,
 		&a.TransferPayeeID,
 		&a.Type,
+		&a.Suffix,
+		&a.Currency,
 		&a.Closed,
 		&a.CreatedAt,
 		&a.UpdatedAt,
@@ -163,15 +196,17 @@ func (r *accountRepo) Search(ctx context.Context, budgetId uuid.UUID, query stri
 				accounts.budget_id,
 				accounts.transfer_payee_id,
 				accounts.type,
+				accounts.suffix,
+				accounts.currency,
 				accounts.closed,
 				accounts.created_at,
 				accounts.updated_at,
-				COALESCE((
-					SELECT SUM(transactions.amount)
-					FROM transactions
-					WHERE transactions.account_id = accounts.id AND transactions.deleted = FALSE
-				), 0) as balance
+				accounts.last_reconciled_at,
+				balances.balance,
+				balances.cleared_balance,
+				balances.uncleared_balance
 			FROM accounts
+			`+accountBalancesJoin+`
 		  WHERE budget_id = $1 AND deleted = FALSE AND name LIKE $2
 		`,
 		budgetId, "%"+query+"%",
@@ -189,10 +224,15 @@ func (r *accountRepo) Search(ctx context.Context, budgetId uuid.UUID, query stri
 			&a.BudgetID,
 			&a.TransferPayeeID,
 			&a.Type,
+			&a.Suffix,
+			&a.Currency,
 			&a.Closed,
 			&a.CreatedAt,
 			&a.UpdatedAt,
+			&a.LastReconciledAt,
 			&a.Balance,
+			&a.ClearedBalance,
+			&a.UnclearedBalance,
 		)
 		if err != nil {
 			return nil, err
@@ -208,11 +248,13 @@ func (r *accountRepo) Create(ctx context.Context, tx pgx.Tx, account model.Accou
 	err := r.Executor(tx).QueryRow(
 		ctx,
 		`INSERT INTO accounts (
-		  name, type, budget_id, closed, deleted, created_at, updated_at
-		 ) VALUES ($1, $2, $3, FALSE, FALSE, NOW(), NOW()) 
-		 RETURNING id, name, type, budget_id`,
-		account.Name, account.Type, account.BudgetID,
-	).Scan(&createdAcc.ID, &createdAcc.Name, &createdAcc.Type, &createdAcc.BudgetID)
+		  name, type, budget_id, currency, closed, deleted, created_at, updated_at
+		 ) VALUES (
+		  $1, $2, $3, COALESCE(NULLIF($4, ''), (SELECT currency FROM budgets WHERE id = $3)), FALSE, FALSE, NOW(), NOW()
+		 )
+		 RETURNING id, name, type, currency, budget_id`,
+		account.Name, account.Type, account.BudgetID, account.Currency,
+	).Scan(&createdAcc.ID, &createdAcc.Name, &createdAcc.Type, &createdAcc.Currency, &createdAcc.BudgetID)
 	if err != nil {
 		return nil, err
 	}
@@ -227,3 +269,177 @@ func (r *accountRepo) UpdateTransferPayee(ctx context.Context, tx pgx.Tx, accoun
 	)
 	return err
 }
+
+// GetBalances locks the account row and returns it with its balances, so concurrent
+// reconciliations of the same account are serialized
+func (r *accountRepo) GetBalances(
+	ctx context.Context,
+	tx pgx.Tx,
+	budgetId uuid.UUID,
+	accountId uuid.UUID,
+) (*model.Account, error) {
+	var a model.Account
+	err := r.Executor(tx).QueryRow(
+		ctx, `
+		  SELECT
+		    accounts.id,
+		    accounts.name,
+		    accounts.budget_id,
+		    accounts.transfer_payee_id,
+		    accounts.type,
+		    accounts.suffix,
+		    accounts.currency,
+		    accounts.closed,
+		    accounts.created_at,
+		    accounts.updated_at,
+		    accounts.last_reconciled_at,
+		    balances.balance,
+		    balances.cleared_balance,
+		    balances.uncleared_balance
+		  FROM accounts
+		  `+accountBalancesJoin+`
+		  WHERE accounts.id = $1 AND accounts.budget_id = $2 AND accounts.deleted = FALSE
+		  FOR UPDATE OF accounts
+		`,
+		accountId, budgetId,
+	).Scan(
+		&a.ID,
+		&a.Name,
+		&a.BudgetID,
+		&a.TransferPayeeID,
+		&a.Type,
+		&a.Suffix,
+		&a.Currency,
+		&a.Closed,
+		&a.CreatedAt,
+		&a.UpdatedAt,
+		&a.LastReconciledAt,
+		&a.Balance,
+		&a.ClearedBalance,
+		&a.UnclearedBalance,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &a, nil
+}
+
+func (r *accountRepo) UpdateLastReconciled(ctx context.Context, tx pgx.Tx, budgetId uuid.UUID, accountId uuid.UUID) error {
+	cmdTag, err := r.Executor(tx).Exec(
+		ctx,
+		`UPDATE accounts SET last_reconciled_at = NOW(), updated_at = NOW() WHERE id = $1 AND budget_id = $2`,
+		accountId, budgetId,
+	)
+	if err != nil {
+		return err
+	}
+	if cmdTag.RowsAffected() == 0 {
+		return fmt.Errorf("Account not found for id: %v", accountId)
+	}
+	return nil
+}
+
+func (r *accountRepo) Update(
+	ctx context.Context,
+	tx pgx.Tx,
+	budgetId uuid.UUID,
+	accountId uuid.UUID,
+	account model.Account,
+) error {
+	cmdTag, err := r.Executor(tx).Exec(
+		ctx, `
+		WITH updated AS (
+		  UPDATE accounts SET
+		    name = $1,
+		    type = $2,
+		    suffix = $3,
+		    updated_at = NOW()
+		  WHERE id = $4 AND budget_id = $5 AND deleted = FALSE
+		  RETURNING id, transfer_payee_id
+		), renamed AS (
+		  UPDATE payees SET name = 'Transfer : ' || $1, updated_at = NOW()
+		  WHERE id IN (SELECT transfer_payee_id FROM updated)
+		), payment AS (
+		  UPDATE categories SET name = $1, deleted = ($2 <> 'creditCard'), updated_at = NOW()
+		  WHERE account_id IN (SELECT id FROM updated)
+		    AND (deleted = FALSE OR $2 = 'creditCard')
+		)
+		SELECT 1 FROM updated
+		`,
+		account.Name, account.Type, account.Suffix, accountId, budgetId,
+	)
+	if err != nil {
+		return err
+	}
+	if cmdTag.RowsAffected() == 0 {
+		return fmt.Errorf("Account not found for id: %v", accountId)
+	}
+	return nil
+}
+
+func (r *accountRepo) SetClosed(
+	ctx context.Context,
+	tx pgx.Tx,
+	budgetId uuid.UUID,
+	accountId uuid.UUID,
+	closed bool,
+) error {
+	cmdTag, err := r.Executor(tx).Exec(
+		ctx, `
+		WITH updated AS (
+		  UPDATE accounts SET closed = $1, updated_at = NOW()
+		  WHERE id = $2 AND budget_id = $3 AND deleted = FALSE
+		  RETURNING id, transfer_payee_id
+		), payee AS (
+		  UPDATE payees SET hidden = $1, updated_at = NOW()
+		  WHERE id IN (SELECT transfer_payee_id FROM updated)
+		), payment AS (
+		  UPDATE categories SET hidden = $1, updated_at = NOW()
+		  WHERE account_id IN (SELECT id FROM updated) AND deleted = FALSE
+		)
+		SELECT 1 FROM updated
+		`,
+		closed, accountId, budgetId,
+	)
+	if err != nil {
+		return err
+	}
+	if cmdTag.RowsAffected() == 0 {
+		return fmt.Errorf("Account not found for id: %v", accountId)
+	}
+	return nil
+}
+
+func (r *accountRepo) HasTransactions(
+	ctx context.Context,
+	tx pgx.Tx,
+	budgetId uuid.UUID,
+	accountId uuid.UUID,
+) (bool, error) {
+	var exists bool
+	err := r.Executor(tx).QueryRow(
+		ctx,
+		`SELECT EXISTS (
+		  SELECT 1 FROM transactions WHERE account_id = $1 AND budget_id = $2 AND deleted = FALSE
+		)`,
+		accountId, budgetId,
+	).Scan(&exists)
+	return exists, err
+}
+
+// DeleteById soft deletes the account, the accounts_cascade_deleted trigger deletes its transfer payee
+// and payment category
+func (r *accountRepo) DeleteById(ctx context.Context, tx pgx.Tx, budgetId uuid.UUID, accountId uuid.UUID) error {
+	cmdTag, err := r.Executor(tx).Exec(
+		ctx,
+		`UPDATE accounts SET deleted = TRUE, updated_at = NOW() WHERE id = $1 AND budget_id = $2 AND deleted = FALSE`,
+		accountId, budgetId,
+	)
+	if err != nil {
+		return err
+	}
+	if cmdTag.RowsAffected() == 0 {
+		return fmt.Errorf("Account not found for id: %v", accountId)
+	}
+	return nil
+}
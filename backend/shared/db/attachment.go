@@ -0,0 +1,147 @@
+package db
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Rishabh-Kapri/pennywise/backend/shared/model"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type AttachmentRepository interface {
+	BaseRepositoryInterface
+	GetAllByTransaction(ctx context.Context, budgetId uuid.UUID, transactionId uuid.UUID) ([]model.Attachment, error)
+	// GetById returns pgx.ErrNoRows when the attachment does not exist or is deleted
+	GetById(ctx context.Context, budgetId uuid.UUID, transactionId uuid.UUID, id uuid.UUID) (*model.Attachment, error)
+	Create(ctx context.Context, tx pgx.Tx, attachment model.Attachment) (*model.Attachment, error)
+	// DeleteById soft deletes the attachment, the file is kept in the blob store
+	DeleteById(ctx context.Context, budgetId uuid.UUID, transactionId uuid.UUID, id uuid.UUID) error
+}
+
+type attachmentRepo struct {
+	BaseRepository
+}
+
+func NewAttachmentRepository(pool *pgxpool.Pool) AttachmentRepository {
+	return &attachmentRepo{BaseRepository: NewBaseRepository(pool)}
+}
+
+const attachmentColumns = `
+	id,
+	budget_id,
+	transaction_id,
+	file_name,
+	content_type,
+	size_bytes,
+	storage_key,
+	deleted,
+	created_at,
+	updated_at`
+
+func scanAttachment(row pgx.Row) (*model.Attachment, error) {
+	var attachment model.Attachment
+	err := row.Scan(
+		&attachment.ID,
+		&attachment.BudgetID,
+		&attachment.TransactionID,
+		&attachment.FileName,
+		&attachment.ContentType,
+		&attachment.Size,
+		&attachment.StorageKey,
+		&attachment.Deleted,
+		&attachment.CreatedAt,
+		&attachment.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &attachment, nil
+}
+
+func (r *attachmentRepo) GetAllByTransaction(
+	ctx context.Context,
+	budgetId uuid.UUID,
+	transactionId uuid.UUID,
+) ([]model.Attachment, error) {
+	rows, err := r.Executor(nil).Query(
+		ctx,
+		`SELECT `+attachmentColumns+`
+		FROM transaction_attachments
+		WHERE budget_id = $1 AND transaction_id = $2 AND deleted = FALSE
+		ORDER BY created_at ASC`,
+		budgetId, transactionId,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	attachments := make([]model.Attachment, 0)
+	for rows.Next() {
+		attachment, err := scanAttachment(rows)
+		if err != nil {
+			return nil, fmt.Errorf("error while parsing transaction_attachments rows: %w", err)
+		}
+		attachments = append(attachments, *attachment)
+	}
+	return attachments, rows.Err()
+}
+
+func (r *attachmentRepo) GetById(
+	ctx context.Context,
+	budgetId uuid.UUID,
+	transactionId uuid.UUID,
+	id uuid.UUID,
+) (*model.Attachment, error) {
+	return scanAttachment(r.Executor(nil).QueryRow(
+		ctx,
+		`SELECT `+attachmentColumns+`
+		FROM transaction_attachments
+		WHERE budget_id = $1 AND transaction_id = $2 AND id = $3 AND deleted = FALSE`,
+		budgetId, transactionId, id,
+	))
+}
+
+func (r *attachmentRepo) Create(ctx context.Context, tx pgx.Tx, attachment model.Attachment) (*model.Attachment, error) {
+	return scanAttachment(r.Executor(tx).QueryRow(
+		ctx, `
+		INSERT INTO transaction_attachments (
+			id, budget_id, transaction_id, file_name, content_type, size_bytes, storage_key
+		)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING `+attachmentColumns,
+		attachment.ID,
+		attachment.BudgetID,
+		attachment.TransactionID,
+		attachment.FileName,
+		attachment.ContentType,
+		attachment.Size,
+		attachment.StorageKey,
+	))
+}
+
+func (r *attachmentRepo) DeleteById(
+	ctx context.Context,
+	budgetId uuid.UUID,
+	transactionId uuid.UUID,
+	id uuid.UUID,
+) error {
+	cmdTag, err := r.Executor(nil).Exec(
+		ctx,
+		`UPDATE transaction_attachments SET
+		   deleted = TRUE,
+		   updated_at = NOW()
+		WHERE budget_id = $1 AND transaction_id = $2 AND id = $3 AND deleted = FALSE`,
+		budgetId, transactionId, id,
+	)
+	if err != nil {
+		return err
+	}
+	if cmdTag.RowsAffected() == 0 {
+		return fmt.Errorf("Attachment not found for id: %v", id)
+	}
+	return nil
+}
@@ -0,0 +1,120 @@
+package db
+
+import (
+	"context"
+
+	"github.com/Rishabh-Kapri/pennywise/backend/shared/model"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type AuditLogRepository interface {
+	BaseRepositoryInterface
+	Create(ctx context.Context, tx pgx.Tx, entry model.AuditLogEntry) error
+	GetByEntity(
+		ctx context.Context,
+		budgetId uuid.UUID,
+		entityType model.AuditEntityType,
+		entityId uuid.UUID,
+	) ([]model.AuditLogEntry, error)
+}
+
+type auditLogRepo struct {
+	BaseRepository
+}
+
+func NewAuditLogRepository(pool *pgxpool.Pool) AuditLogRepository {
+	return &auditLogRepo{BaseRepository: NewBaseRepository(pool)}
+}
+
+// Create appends an entry to the audit log, entries are never updated or deleted
+func (r *auditLogRepo) Create(ctx context.Context, tx pgx.Tx, entry model.AuditLogEntry) error {
+	if entry.Changes == nil {
+		entry.Changes = []model.AuditChange{}
+	}
+	_, err := r.Executor(tx).Exec(
+		ctx, `
+		INSERT INTO audit_log (
+		  budget_id,
+		  entity_type,
+		  entity_id,
+		  action,
+		  actor_type,
+		  actor_id,
+		  changes,
+		  before,
+		  after,
+		  correlation_id
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)`,
+		entry.BudgetID,
+		entry.EntityType,
+		entry.EntityID,
+		entry.Action,
+		entry.ActorType,
+		entry.ActorID,
+		entry.Changes,
+		entry.Before,
+		entry.After,
+		entry.CorrelationID,
+	)
+	return err
+}
+
+// GetByEntity returns the audit trail of an entity, oldest first
+func (r *auditLogRepo) GetByEntity(
+	ctx context.Context,
+	budgetId uuid.UUID,
+	entityType model.AuditEntityType,
+	entityId uuid.UUID,
+) ([]model.AuditLogEntry, error) {
+	rows, err := r.Executor(nil).Query(
+		ctx, `
+		SELECT
+		  id,
+		  budget_id,
+		  entity_type,
+		  entity_id,
+		  action,
+		  actor_type,
+		  actor_id,
+		  changes,
+		  before,
+		  after,
+		  correlation_id,
+		  created_at
+		FROM audit_log
+		WHERE budget_id = $1 AND entity_type = $2 AND entity_id = $3
+		ORDER BY created_at ASC, id ASC`,
+		budgetId, entityType, entityId,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	entries := make([]model.AuditLogEntry, 0)
+	for rows.Next() {
+		var entry model.AuditLogEntry
+		err := rows.Scan(
+			&entry.ID,
+			&entry.BudgetID,
+			&entry.EntityType,
+			&entry.EntityID,
+			&entry.Action,
+			&entry.ActorType,
+			&entry.ActorID,
+			&entry.Changes,
+			&entry.Before,
+			&entry.After,
+			&entry.CorrelationID,
+			&entry.CreatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, entry)
+	}
+	return entries, rows.Err()
+}
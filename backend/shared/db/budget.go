@@ -31,7 +31,7 @@ func NewBudgetRepository(pool *pgxpool.Pool) BudgetRepository {
 func (r *budgetRepo) GetAll(ctx context.Context, userID uuid.UUID) ([]model.Budget, error) {
 	rows, err := r.Executor(nil).Query(
 		ctx, `
-			SELECT id, user_id, name, is_selected, created_at, updated_at, COALESCE(metadata, '{}')
+			SELECT id, user_id, name, is_selected, currency, created_at, updated_at, COALESCE(metadata, '{}')
 			FROM budgets
 			WHERE user_id = $1 AND deleted = FALSE
 		`, userID,
@@ -46,7 +46,7 @@ func (r *budgetRepo) GetAll(ctx context.Context, userID uuid.UUID) ([]model.Budg
 
 	for rows.Next() {
 		var b model.Budget
-		err := rows.Scan(&b.ID, &b.UserID, &b.Name, &b.IsSelected, &b.CreatedAt, &b.UpdatedAt, &b.Metadata)
+		err := rows.Scan(&b.ID, &b.UserID, &b.Name, &b.IsSelected, &b.Currency, &b.CreatedAt, &b.UpdatedAt, &b.Metadata)
 		if err != nil {
 			return nil, err
 		}
@@ -59,11 +59,20 @@ func (r *budgetRepo) GetById(ctx context.Context, tx pgx.Tx, id uuid.UUID) (*mod
 	var budget model.Budget
 	err := r.Executor(tx).QueryRow(
 		ctx, `
-				SELECT id, user_id, name, is_selected, created_at, updated_at, COALESCE(metadata, '{}')
+				SELECT id, user_id, name, is_selected, currency, created_at, updated_at, COALESCE(metadata, '{}')
 				FROM budgets
 				WHERE id = $1 AND deleted = FALSE
 			`, id,
-	).Scan(&budget.ID, &budget.UserID, &budget.Name, &budget.IsSelected, &budget.CreatedAt, &budget.UpdatedAt, &budget.Metadata)
+	).Scan(
+		&budget.ID,
+		&budget.UserID,
+		&budget.Name,
+		&budget.IsSelected,
+		&budget.Currency,
+		&budget.CreatedAt,
+		&budget.UpdatedAt,
+		&budget.Metadata,
+	)
 	if err != nil {
 		return nil, err
 	}
@@ -76,9 +85,9 @@ func (r *budgetRepo) Create(ctx context.Context, tx pgx.Tx, name string, userID
 		ctx, `
 			INSERT INTO budgets (name, user_id, is_selected, created_at, updated_at) 
 			VALUES ($1, $2, FALSE, NOW(), NOW())
-			RETURNING id, name, is_selected
+			RETURNING id, name, is_selected, currency
 			`, name, userID,
-	).Scan(&createdBudget.ID, &createdBudget.Name, &createdBudget.IsSelected)
+	).Scan(&createdBudget.ID, &createdBudget.Name, &createdBudget.IsSelected, &createdBudget.Currency)
 	if err != nil {
 		return nil, err
 	}
@@ -89,9 +98,14 @@ func (r *budgetRepo) Create(ctx context.Context, tx pgx.Tx, name string, userID
 func (r *budgetRepo) UpdateById(ctx context.Context, tx pgx.Tx, id uuid.UUID, budget model.Budget) error {
 	cmdTag, err := r.Executor(tx).Exec(
 		ctx, `
-			UPDATE budgets SET name = $1, is_selected = $2, metadata = $3, updated_at = NOW()
+			UPDATE budgets SET
+				name = $1,
+				is_selected = $2,
+				metadata = $3,
+				currency = COALESCE(NULLIF($5, ''), currency),
+				updated_at = NOW()
 			WHERE id = $4 AND deleted = FALSE
-			`, budget.Name, budget.IsSelected, budget.Metadata, id,
+			`, budget.Name, budget.IsSelected, budget.Metadata, id, budget.Currency,
 	)
 	if err != nil {
 		return err
@@ -0,0 +1,82 @@
+package db
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Rishabh-Kapri/pennywise/backend/shared/model"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type BudgetMoveRepository interface {
+	BaseRepositoryInterface
+	// GetAll returns the moves of a budget, newest first. An empty month returns every month.
+	GetAll(ctx context.Context, budgetId uuid.UUID, month string) ([]model.BudgetMove, error)
+	Create(ctx context.Context, tx pgx.Tx, move model.BudgetMove) (*model.BudgetMove, error)
+}
+
+type budgetMoveRepo struct {
+	BaseRepository
+}
+
+func NewBudgetMoveRepository(pool *pgxpool.Pool) BudgetMoveRepository {
+	return &budgetMoveRepo{BaseRepository: NewBaseRepository(pool)}
+}
+
+const budgetMoveColumns = `id, budget_id, month, from_category_id, to_category_id, amount, note, created_at`
+
+func scanBudgetMove(row pgx.Row) (*model.BudgetMove, error) {
+	var move model.BudgetMove
+	err := row.Scan(
+		&move.ID,
+		&move.BudgetID,
+		&move.Month,
+		&move.FromCategoryID,
+		&move.ToCategoryID,
+		&move.Amount,
+		&move.Note,
+		&move.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &move, nil
+}
+
+func (r *budgetMoveRepo) GetAll(ctx context.Context, budgetId uuid.UUID, month string) ([]model.BudgetMove, error) {
+	rows, err := r.Executor(nil).Query(
+		ctx,
+		`SELECT `+budgetMoveColumns+`
+		FROM budget_moves
+		WHERE budget_id = $1 AND ($2 = '' OR month = $2)
+		ORDER BY created_at DESC`,
+		budgetId, month,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	moves := make([]model.BudgetMove, 0)
+	for rows.Next() {
+		move, err := scanBudgetMove(rows)
+		if err != nil {
+			return nil, fmt.Errorf("error while parsing budget_moves rows: %w", err)
+		}
+		moves = append(moves, *move)
+	}
+	return moves, rows.Err()
+}
+
+func (r *budgetMoveRepo) Create(ctx context.Context, tx pgx.Tx, move model.BudgetMove) (*model.BudgetMove, error) {
+	return scanBudgetMove(r.Executor(tx).QueryRow(
+		ctx, `
+		INSERT INTO budget_moves (budget_id, month, from_category_id, to_category_id, amount, note)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING `+budgetMoveColumns,
+		move.BudgetID, move.Month, move.FromCategoryID, move.ToCategoryID, move.Amount, move.Note,
+	))
+}
@@ -2,9 +2,9 @@ package db
 
 import (
 	"context"
-	"errors"
 	"fmt"
 	"log"
+	"time"
 
 	"github.com/Rishabh-Kapri/pennywise/backend/shared/logger"
 	"github.com/Rishabh-Kapri/pennywise/backend/shared/model"
@@ -16,17 +16,42 @@ import (
 
 type CategoryRepository interface {
 	BaseRepositoryInterface
+	// GetAll applies the overspending rules to the balances, see model.ApplyOverspending, and evaluates
+	// the goals on them
 	GetAll(ctx context.Context, budgetId uuid.UUID) ([]model.Category, error)
+	// GetAllSimplified leaves out payment categories, nothing can be categorized into them
 	GetAllSimplified(ctx context.Context, budgetId uuid.UUID) ([]model.CategorySimplified, error)
+	// GetInflowBalance returns all the income minus everything budgeted
 	GetInflowBalance(ctx context.Context, budgetId uuid.UUID) (float64, error)
+	// GetReadyToAssign returns what is ready to assign in month: the income up to month minus everything
+	// budgeted, in any month, and the cash overspending of the months before it. categories are the ones
+	// of GetAll, see model.CashOverspentBefore.
+	GetReadyToAssign(ctx context.Context, tx pgx.Tx, budgetId uuid.UUID, month string, categories []model.Category) (float64, error)
 	GetByFilter(ctx context.Context, budgetId uuid.UUID, filter model.CategoryFilter) ([]model.Category, error)
 	Search(ctx context.Context, budgetId uuid.UUID, query string) ([]model.Category, error)
 	GetById(ctx context.Context, budgetId uuid.UUID, id uuid.UUID) (*model.Category, error)
 	GetByIdSimplified(ctx context.Context, budgetId uuid.UUID, id uuid.UUID) (*model.Category, error)
 	GetByIdSimplifiedTx(ctx context.Context, tx pgx.Tx, budgetId uuid.UUID, id uuid.UUID) (*model.Category, error)
 	Create(ctx context.Context, tx pgx.Tx, category model.Category) (*model.Category, error)
-	DeleteById(ctx context.Context, budgetId uuid.UUID, id uuid.UUID) error
+	// GetPaymentCategoryIDs maps every credit card account of the budget to its payment category
+	GetPaymentCategoryIDs(ctx context.Context, tx pgx.Tx, budgetId uuid.UUID) (map[uuid.UUID]uuid.UUID, error)
+	// SetPaymentCategoryHidden hides or shows the payment category of an account, if it has one
+	SetPaymentCategoryHidden(ctx context.Context, tx pgx.Tx, budgetId uuid.UUID, accountId uuid.UUID, hidden bool) error
+	// IsInUse reports whether anything still references the category: live transactions or splits,
+	// budgeted or carried over money, payee rules, embeddings, schedules or templates
+	IsInUse(ctx context.Context, tx pgx.Tx, budgetId uuid.UUID, id uuid.UUID) (bool, error)
+	// Reassign points everything referencing the category at the replacement category.
+	// Monthly budgets are merged separately by the monthly budget repository.
+	Reassign(ctx context.Context, tx pgx.Tx, budgetId uuid.UUID, fromId uuid.UUID, toId uuid.UUID) (*model.CategoryDeleteResult, error)
+	// DeleteById returns pgx.ErrNoRows when the category doesn't exist or is already deleted
+	DeleteById(ctx context.Context, tx pgx.Tx, budgetId uuid.UUID, id uuid.UUID) error
 	Update(ctx context.Context, budgetId uuid.UUID, id uuid.UUID, category model.Category) error
+	// GetGoal returns pgx.ErrNoRows when the category has no goal
+	GetGoal(ctx context.Context, budgetId uuid.UUID, categoryId uuid.UUID) (*model.CategoryGoal, error)
+	// UpsertGoal sets the goal of a category, replacing the existing one
+	UpsertGoal(ctx context.Context, goal model.CategoryGoal) (*model.CategoryGoal, error)
+	// DeleteGoal returns pgx.ErrNoRows when the category has no goal
+	DeleteGoal(ctx context.Context, budgetId uuid.UUID, categoryId uuid.UUID) error
 }
 
 type categoryRepo struct {
@@ -37,45 +62,147 @@ func NewCategoryRepository(pool *pgxpool.Pool) CategoryRepository {
 	return &categoryRepo{BaseRepository: NewBaseRepository(pool)}
 }
 
+// creditActivityQuery selects the activity of the category with the given id column spent on credit card
+// accounts, as a JSON object of month to card account id to amount. Split lines are included.
+func creditActivityQuery(categoryId string) string {
+	return `COALESCE(
+		(
+			SELECT json_object_agg(by_month.month, by_month.cards)
+			FROM (
+				SELECT card_lines.month, json_object_agg(card_lines.account_id, card_lines.sum) AS cards
+				FROM (
+					SELECT LEFT(lines.date, 7) AS month, lines.account_id, SUM(lines.amount) AS sum
+					FROM (
+						SELECT t.date, t.account_id, t.amount
+						FROM transactions t
+						WHERE t.category_id = ` + categoryId + ` AND t.deleted = FALSE
+						UNION ALL
+						SELECT t.date, t.account_id, s.amount
+						FROM transaction_splits s
+						JOIN transactions t ON t.id = s.transaction_id
+						WHERE s.category_id = ` + categoryId + ` AND s.deleted = FALSE AND t.deleted = FALSE
+					) AS lines
+					JOIN accounts a ON a.id = lines.account_id AND a.type = 'creditCard'
+					GROUP BY month, lines.account_id
+				) AS card_lines
+				GROUP BY card_lines.month
+			) AS by_month
+		), '{}'
+	)`
+}
+
+// categoryWithBudgetsQuery selects categories with their budgeted, activity and balance per month and their goal.
+// Activity includes split lines, matching how carryovers are kept.
+func categoryWithBudgetsQuery(where string) string {
+	return `
+		SELECT
+			categories.id,
+			categories.name,
+			categories.budget_id,
+			categories.category_group_id,
+			categories.account_id,
+			categories.hidden,
+			categories.note,
+			categories.is_system,
+			categories.rollover_negative,
+			categories.created_at,
+			categories.updated_at,
+			COALESCE(
+				json_object_agg(monthly_budgets.month, monthly_budgets.budgeted)
+				FILTER (WHERE monthly_budgets.month IS NOT NULL), '{}'
+			) AS budgeted,
+			COALESCE(
+				(
+					SELECT json_object_agg(lines.month, lines.sum)
+					FROM (
+						SELECT LEFT(category_lines.date, 7) AS month, SUM(category_lines.amount) AS sum
+						FROM (
+							SELECT transactions.date, transactions.amount
+							FROM transactions
+							WHERE transactions.category_id = categories.id AND transactions.deleted = FALSE
+							UNION ALL
+							SELECT transactions.date, transaction_splits.amount
+							FROM transaction_splits
+							JOIN transactions ON transactions.id = transaction_splits.transaction_id
+							WHERE transaction_splits.category_id = categories.id
+								AND transaction_splits.deleted = FALSE
+								AND transactions.deleted = FALSE
+						) AS category_lines
+						GROUP BY month
+					) AS lines
+				), '{}'
+			) AS activity,
+			COALESCE(
+				json_object_agg(monthly_budgets.month, monthly_budgets.carryover_balance)
+				FILTER (WHERE monthly_budgets.month IS NOT NULL), '{}'
+			) AS balance,
+			` + creditActivityQuery("categories.id") + ` AS credit_activity,
+			category_goals.id,
+			category_goals.type,
+			category_goals.amount,
+			category_goals.target_month,
+			category_goals.created_at,
+			category_goals.updated_at
+		FROM categories
+		LEFT JOIN monthly_budgets ON categories.id = monthly_budgets.category_id
+		LEFT JOIN category_goals ON categories.id = category_goals.category_id
+		WHERE ` + where + `
+		GROUP BY categories.id, category_goals.id
+	`
+}
+
+// scanCategoryWithBudgets scans a row of categoryWithBudgetsQuery
+func scanCategoryWithBudgets(row pgx.Row) (*model.Category, error) {
+	var c model.Category
+	var goalId *uuid.UUID
+	var goal model.CategoryGoal
+	var goalType *string
+	var goalAmount *float64
+	var goalCreatedAt, goalUpdatedAt *time.Time
+	err := row.Scan(
+		&c.ID,
+		&c.Name,
+		&c.BudgetID,
+		&c.CategoryGroupID,
+		&c.AccountID,
+		&c.Hidden,
+		&c.Note,
+		&c.IsSystem,
+		&c.RolloverNegative,
+		&c.CreatedAt,
+		&c.UpdatedAt,
+		&c.Budgeted,
+		&c.Activity,
+		&c.Balance,
+		&c.CreditActivity,
+		&goalId,
+		&goalType,
+		&goalAmount,
+		&goal.TargetMonth,
+		&goalCreatedAt,
+		&goalUpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if goalId != nil {
+		goal.ID = *goalId
+		goal.BudgetID = c.BudgetID
+		goal.CategoryID = c.ID
+		goal.Type = model.CategoryGoalType(*goalType)
+		goal.Amount = *goalAmount
+		goal.CreatedAt = *goalCreatedAt
+		goal.UpdatedAt = *goalUpdatedAt
+		c.Goal = &goal
+	}
+	return &c, nil
+}
+
 func (r *categoryRepo) GetAll(ctx context.Context, budgetId uuid.UUID) ([]model.Category, error) {
 	rows, err := r.Executor(nil).Query(
-		ctx, `
-			SELECT 
-				categories.id, 
-		    categories.name, 
-				categories.budget_id, 
-				categories.category_group_id, 
-				categories.hidden, 
-				categories.note, 
-				categories.is_system, 
-				categories.created_at, 
-				categories.updated_at,
-		    COALESCE(
-		      json_object_agg(monthly_budgets.month, monthly_budgets.budgeted) 
-		      FILTER (WHERE monthly_budgets.month IS NOT NULL), '{}'
-		    ) AS budgeted,
-		    COALESCE(
-		      (
-		        SELECT json_object_agg(tx.month, tx.sum)
-		        FROM (
-		          SELECT
-		            TO_CHAR(date_trunc('month', transactions.date::date), 'YYYY-MM') AS month,
-		            SUM(transactions.amount) AS sum
-		          FROM transactions
-		          WHERE transactions.category_id = categories.id AND transactions.deleted = FALSE
-		          GROUP BY month
-		        ) AS tx
-		      ), '{}'
-		    ) AS activity,
-		    COALESCE(
-		      json_object_agg(monthly_budgets.month, monthly_budgets.carryover_balance) 
-		      FILTER (WHERE monthly_budgets.month IS NOT NULL), '{}'
-		    ) AS balance
-			FROM categories 
-		  LEFT JOIN monthly_budgets ON categories.id = monthly_budgets.category_id
-			WHERE categories.budget_id = $1 AND categories.deleted = FALSE
-		  GROUP BY categories.id
-		`, budgetId,
+		ctx,
+		categoryWithBudgetsQuery(`categories.budget_id = $1 AND categories.deleted = FALSE`),
+		budgetId,
 	)
 	if err != nil {
 		return nil, err
@@ -84,25 +211,24 @@ func (r *categoryRepo) GetAll(ctx context.Context, budgetId uuid.UUID) ([]model.
 
 	var categories []model.Category
 	for rows.Next() {
-		var c model.Category
-		err := rows.Scan(
-			&c.ID,
-			&c.Name,
-			&c.BudgetID,
-			&c.CategoryGroupID,
-			&c.Hidden,
-			&c.Note,
-			&c.IsSystem,
-			&c.CreatedAt,
-			&c.UpdatedAt,
-			&c.Budgeted,
-			&c.Activity,
-			&c.Balance,
-		)
+		c, err := scanCategoryWithBudgets(rows)
 		if err != nil {
 			return nil, err
 		}
-		categories = append(categories, c)
+		categories = append(categories, *c)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	byPointer := make([]*model.Category, len(categories))
+	for i := range categories {
+		byPointer[i] = &categories[i]
+	}
+	model.ApplyOverspending(byPointer)
+	// goals are funded from what is available, so they are evaluated on the balances after overspending
+	for i := range categories {
+		categories[i].EvaluateGoal()
 	}
 	return categories, nil
 }
@@ -110,7 +236,7 @@ func (r *categoryRepo) GetAll(ctx context.Context, budgetId uuid.UUID) ([]model.
 func (r *categoryRepo) GetAllSimplified(ctx context.Context, budgetId uuid.UUID) ([]model.CategorySimplified, error) {
 	rows, err := r.Executor(nil).Query(
 		ctx,
-		`SELECT id, name FROM categories WHERE budget_id = $1 AND deleted = FALSE AND hidden = FALSE`,
+		`SELECT id, name FROM categories WHERE budget_id = $1 AND deleted = FALSE AND hidden = FALSE AND account_id IS NULL`,
 		budgetId,
 	)
 	if err != nil {
@@ -131,19 +257,40 @@ func (r *categoryRepo) GetAllSimplified(ctx context.Context, budgetId uuid.UUID)
 }
 
 func (r *categoryRepo) GetInflowBalance(ctx context.Context, budgetId uuid.UUID) (float64, error) {
+	return r.inflowMinusBudgeted(ctx, nil, budgetId, "")
+}
+
+func (r *categoryRepo) GetReadyToAssign(
+	ctx context.Context,
+	tx pgx.Tx,
+	budgetId uuid.UUID,
+	month string,
+	categories []model.Category,
+) (float64, error) {
+	balance, err := r.inflowMinusBudgeted(ctx, tx, budgetId, month)
+	if err != nil {
+		return 0, err
+	}
+	return balance - model.CashOverspentBefore(categories, month), nil
+}
+
+// inflowMinusBudgeted sums the income up to month, all of it when month is empty, and takes out
+// everything budgeted. Money assigned to a later month is gone from every month before it.
+func (r *categoryRepo) inflowMinusBudgeted(ctx context.Context, tx pgx.Tx, budgetId uuid.UUID, month string) (float64, error) {
 	var balance float64
 
 	log.Printf("%v", budgetId)
-	err := r.Executor(nil).QueryRow(ctx, `
+	err := r.Executor(tx).QueryRow(ctx, `
 			WITH inflow_cat AS (
 				SELECT id FROM categories
-				WHERE budget_id = $1 AND is_system = TRUE AND deleted = FALSE
+				WHERE budget_id = $1 AND is_system = TRUE AND account_id IS NULL AND deleted = FALSE
 				LIMIT 1
 			),
 			total_txn AS (
 				SELECT COALESCE(SUM(amount), 0) AS transaction_amount
 				FROM transactions
 				WHERE category_id = (SELECT id FROM inflow_cat) AND budget_id = $1 AND deleted = FALSE
+					AND ($2 = '' OR LEFT(date, 7) <= $2)
 			),
 			total_budgeted AS (
 				SELECT COALESCE(SUM(budgeted), 0) AS total_budgeted
@@ -152,15 +299,15 @@ func (r *categoryRepo) GetInflowBalance(ctx context.Context, budgetId uuid.UUID)
 			)
 			SELECT (total_txn.transaction_amount - total_budgeted.total_budgeted)
 			FROM total_txn, total_budgeted
-		`, budgetId).Scan(&balance)
+		`, budgetId, month).Scan(&balance)
 	if err != nil {
 		return 0, err
 	}
-	return balance, err
+	return balance, nil
 }
 
 func (r *categoryRepo) GetByFilter(ctx context.Context, budgetId uuid.UUID, filter model.CategoryFilter) ([]model.Category, error) {
-	sql := `SELECT id, name, budget_id, category_group_id, hidden, note, is_system, created_at, updated_at FROM categories WHERE deleted = FALSE AND budget_id = $1`
+	sql := `SELECT id, name, budget_id, category_group_id, account_id, hidden, note, is_system, rollover_negative, created_at, updated_at FROM categories WHERE deleted = FALSE AND budget_id = $1`
 	args := []any{budgetId}
 	argIndex := 2 // $1 is budget_id
 	if filter.IsSystem != nil {
@@ -185,9 +332,11 @@ func (r *categoryRepo) GetByFilter(ctx context.Context, budgetId uuid.UUID, filt
 			&c.Name,
 			&c.BudgetID,
 			&c.CategoryGroupID,
+			&c.AccountID,
 			&c.Hidden,
 			&c.Note,
 			&c.IsSystem,
+			&c.RolloverNegative,
 			&c.CreatedAt,
 			&c.UpdatedAt,
 		)
@@ -202,43 +351,9 @@ func (r *categoryRepo) GetByFilter(ctx context.Context, budgetId uuid.UUID, filt
 func (r *categoryRepo) Search(ctx context.Context, budgetId uuid.UUID, query string) ([]model.Category, error) {
 	log.Printf("%v %v", budgetId, query)
 	rows, err := r.Executor(nil).Query(
-		ctx, `
-			SELECT 
-				categories.id,
-				categories.name,
-				categories.budget_id,
-				categories.category_group_id,
-				categories.hidden,
-				categories.note,
-				categories.is_system,
-				categories.created_at,
-				categories.updated_at,
-				COALESCE(
-					json_object_agg(monthly_budgets.month, monthly_budgets.budgeted)
-					FILTER (WHERE monthly_budgets.month IS NOT NULL), '{}'
-				) AS budgeted,
-				COALESCE(
-					(
-						SELECT json_object_agg(tx.month, tx.sum)
-						FROM (
-							SELECT
-								TO_CHAR(date_trunc('month', transactions.date::date), 'YYYY-MM') AS month,
-								SUM(transactions.amount) AS sum
-							FROM transactions
-							WHERE transactions.category_id = categories.id AND transactions.deleted = FALSE
-							GROUP BY month
-						) AS tx
-					), '{}'
-				) AS activity,
-				COALESCE(
-					json_object_agg(monthly_budgets.month, monthly_budgets.carryover_balance) 
-					FILTER (WHERE monthly_budgets.month IS NOT NULL), '{}'
-				) AS balance
-			FROM categories 
-		  LEFT JOIN monthly_budgets ON categories.id = monthly_budgets.category_id
-			WHERE categories.budget_id  = $1 AND categories.deleted = FALSE AND categories.name LIKE $2
-		  GROUP BY categories.id
-		`, budgetId, "%"+query+"%",
+		ctx,
+		categoryWithBudgetsQuery(`categories.budget_id = $1 AND categories.deleted = FALSE AND categories.name LIKE $2`),
+		budgetId, "%"+query+"%",
 	)
 	if err != nil {
 		return nil, err
@@ -247,101 +362,35 @@ func (r *categoryRepo) Search(ctx context.Context, budgetId uuid.UUID, query str
 
 	var categories []model.Category
 	for rows.Next() {
-		var c model.Category
-		err := rows.Scan(
-			&c.ID,
-			&c.Name,
-			&c.BudgetID,
-			&c.CategoryGroupID,
-			&c.Hidden,
-			&c.Note,
-			&c.IsSystem,
-			&c.CreatedAt,
-			&c.UpdatedAt,
-			&c.Budgeted,
-			&c.Activity,
-			&c.Balance,
-		)
+		c, err := scanCategoryWithBudgets(rows)
 		if err != nil {
 			return nil, err
 		}
-		categories = append(categories, c)
+		categories = append(categories, *c)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
 	}
 	return categories, nil
 }
 
 func (r *categoryRepo) GetById(ctx context.Context, budgetId uuid.UUID, id uuid.UUID) (*model.Category, error) {
-	row := r.Executor(nil).QueryRow(
-		ctx, `
-			SELECT 
-				categories.id,
-				categories.name,
-				categories.budget_id,
-				categories.category_group_id,
-				categories.hidden,
-				categories.note,
-				categories.is_system,
-				categories.created_at,
-				categories.updated_at,
-				COALESCE(
-					json_object_agg(monthly_budgets.month, monthly_budgets.budgeted)
-					FILTER (WHERE monthly_budgets.month IS NOT NULL), '{}'
-				) AS budgeted,
-				COALESCE(
-					(
-						SELECT json_object_agg(tx.month, tx.sum)
-						FROM (
-							SELECT
-								TO_CHAR(date_trunc('month', transactions.date::date), 'YYYY-MM') AS month,
-								SUM(transactions.amount) AS sum
-							FROM transactions
-							WHERE transactions.category_id = categories.id AND transactions.deleted = FALSE
-							GROUP BY month
-						) AS tx
-					), '{}'
-				) AS activity,
-				COALESCE(
-					json_object_agg(monthly_budgets.month, monthly_budgets.carryover_balance) 
-					FILTER (WHERE monthly_budgets.month IS NOT NULL), '{}'
-				) AS balance
-			FROM categories 
-		  LEFT JOIN monthly_budgets ON categories.id = monthly_budgets.category_id
-			WHERE categories.budget_id = $1 AND categories.deleted = FALSE AND categories.id = $2
-		  GROUP BY categories.id
-		`, budgetId, id,
-	)
-
-	var c model.Category
-	err := row.Scan(
-		&c.ID,
-		&c.Name,
-		&c.BudgetID,
-		&c.CategoryGroupID,
-		&c.Hidden,
-		&c.Note,
-		&c.IsSystem,
-		&c.CreatedAt,
-		&c.UpdatedAt,
-		&c.Budgeted,
-		&c.Activity,
-		&c.Balance,
-	)
-	if err != nil {
-		return nil, err
-	}
-
-	return &c, nil
+	return scanCategoryWithBudgets(r.Executor(nil).QueryRow(
+		ctx,
+		categoryWithBudgetsQuery(`categories.budget_id = $1 AND categories.deleted = FALSE AND categories.id = $2`),
+		budgetId, id,
+	))
 }
 
 func (r *categoryRepo) GetByIdSimplified(ctx context.Context, budgetId uuid.UUID, id uuid.UUID) (*model.Category, error) {
 	var c model.Category
 	err := r.Executor(nil).QueryRow(
 		ctx, `
-		  SELECT id, name, budget_id, category_group_id, hidden, note, is_system, created_at, updated_at
+		  SELECT id, name, budget_id, category_group_id, account_id, hidden, note, is_system, rollover_negative, created_at, updated_at
 		  FROM categories
 		  WHERE id = $1 AND budget_id = $2
 		`, id, budgetId,
-	).Scan(&c.ID, &c.Name, &c.BudgetID, &c.CategoryGroupID, &c.Hidden, &c.Note, &c.IsSystem, &c.CreatedAt, &c.UpdatedAt)
+	).Scan(&c.ID, &c.Name, &c.BudgetID, &c.CategoryGroupID, &c.AccountID, &c.Hidden, &c.Note, &c.IsSystem, &c.RolloverNegative, &c.CreatedAt, &c.UpdatedAt)
 	if err != nil {
 		return nil, err
 	}
@@ -352,11 +401,11 @@ func (r *categoryRepo) GetByIdSimplifiedTx(ctx context.Context, tx pgx.Tx, budge
 	var c model.Category
 	err := tx.QueryRow(
 		ctx, `
-		  SELECT id, name, budget_id, category_group_id, hidden, note, is_system, created_at, updated_at
+		  SELECT id, name, budget_id, category_group_id, account_id, hidden, note, is_system, rollover_negative, created_at, updated_at
 		  FROM categories
 		  WHERE id = $1 AND budget_id = $2
 		`, id, budgetId,
-	).Scan(&c.ID, &c.Name, &c.BudgetID, &c.CategoryGroupID, &c.Hidden, &c.Note, &c.IsSystem, &c.CreatedAt, &c.UpdatedAt)
+	).Scan(&c.ID, &c.Name, &c.BudgetID, &c.CategoryGroupID, &c.AccountID, &c.Hidden, &c.Note, &c.IsSystem, &c.RolloverNegative, &c.CreatedAt, &c.UpdatedAt)
 	if err != nil {
 		return nil, err
 	}
@@ -365,9 +414,9 @@ func (r *categoryRepo) GetByIdSimplifiedTx(ctx context.Context, tx pgx.Tx, budge
 
 func (r *categoryRepo) Create(ctx context.Context, tx pgx.Tx, category model.Category) (*model.Category, error) {
 	sql := `INSERT INTO categories (
-			budget_id, name, category_group_id, note, is_system, hidden, deleted, created_at, updated_at
-		) VALUES ($1, $2, $3, $4, $5, FALSE, FALSE, NOW(), NOW())
-	  RETURNING id, name, category_group_id, is_system`
+			budget_id, name, category_group_id, note, is_system, account_id, rollover_negative, hidden, deleted, created_at, updated_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, FALSE, FALSE, NOW(), NOW())
+	  RETURNING id, name, category_group_id, account_id, is_system, rollover_negative`
 
 	var createdCat model.Category
 
@@ -379,28 +428,197 @@ func (r *categoryRepo) Create(ctx context.Context, tx pgx.Tx, category model.Cat
 		category.CategoryGroupID,
 		category.Note,
 		category.IsSystem,
-	).Scan(&createdCat.ID, &createdCat.Name, &createdCat.CategoryGroupID, &createdCat.IsSystem)
+		category.AccountID,
+		category.RolloverNegative,
+	).Scan(
+		&createdCat.ID,
+		&createdCat.Name,
+		&createdCat.CategoryGroupID,
+		&createdCat.AccountID,
+		&createdCat.IsSystem,
+		&createdCat.RolloverNegative,
+	)
 	if err != nil {
 		return nil, err
 	}
 	return &createdCat, nil
 }
 
-func (r *categoryRepo) DeleteById(ctx context.Context, budgetId uuid.UUID, id uuid.UUID) error {
-	cmdTag, err := r.Executor(nil).Exec(
+func (r *categoryRepo) GetPaymentCategoryIDs(
+	ctx context.Context,
+	tx pgx.Tx,
+	budgetId uuid.UUID,
+) (map[uuid.UUID]uuid.UUID, error) {
+	rows, err := r.Executor(tx).Query(
+		ctx, `
+		SELECT c.account_id, c.id
+		FROM categories c
+		JOIN accounts a ON a.id = c.account_id
+		WHERE c.budget_id = $1 AND c.deleted = FALSE AND a.type = 'creditCard' AND a.deleted = FALSE
+		`,
+		budgetId,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	ids := make(map[uuid.UUID]uuid.UUID)
+	for rows.Next() {
+		var accountId, categoryId uuid.UUID
+		if err := rows.Scan(&accountId, &categoryId); err != nil {
+			return nil, err
+		}
+		ids[accountId] = categoryId
+	}
+	return ids, rows.Err()
+}
+
+func (r *categoryRepo) SetPaymentCategoryHidden(
+	ctx context.Context,
+	tx pgx.Tx,
+	budgetId uuid.UUID,
+	accountId uuid.UUID,
+	hidden bool,
+) error {
+	_, err := r.Executor(tx).Exec(
+		ctx, `
+		UPDATE categories SET hidden = $1, updated_at = NOW()
+		WHERE budget_id = $2 AND account_id = $3 AND deleted = FALSE
+		`,
+		hidden, budgetId, accountId,
+	)
+	return err
+}
+
+func (r *categoryRepo) IsInUse(ctx context.Context, tx pgx.Tx, budgetId uuid.UUID, id uuid.UUID) (bool, error) {
+	var inUse bool
+	err := r.Executor(tx).QueryRow(
+		ctx, `
+		SELECT
+		  EXISTS (SELECT 1 FROM transactions WHERE budget_id = $1 AND category_id = $2 AND deleted = FALSE)
+		  OR EXISTS (SELECT 1 FROM transaction_splits WHERE budget_id = $1 AND category_id = $2 AND deleted = FALSE)
+		  OR EXISTS (
+		    SELECT 1 FROM monthly_budgets
+		    WHERE budget_id = $1 AND category_id = $2 AND (budgeted <> 0 OR carryover_balance <> 0)
+		  )
+		  OR EXISTS (SELECT 1 FROM payee_rules WHERE budget_id = $1 AND category_id = $2 AND deleted = FALSE)
+		  OR EXISTS (SELECT 1 FROM transaction_embeddings WHERE budget_id = $1 AND category_id = $2)
+		  OR EXISTS (SELECT 1 FROM scheduled_transactions WHERE budget_id = $1 AND category_id = $2 AND deleted = FALSE)
+		  OR EXISTS (SELECT 1 FROM transaction_templates WHERE budget_id = $1 AND category_id = $2 AND deleted = FALSE)
+		`, budgetId, id,
+	).Scan(&inUse)
+	return inUse, err
+}
+
+func (r *categoryRepo) Reassign(
+	ctx context.Context,
+	tx pgx.Tx,
+	budgetId uuid.UUID,
+	fromId uuid.UUID,
+	toId uuid.UUID,
+) (*model.CategoryDeleteResult, error) {
+	executor := r.Executor(tx)
+	result := &model.CategoryDeleteResult{ReplacementID: &toId}
+
+	// deleted transactions are moved too so that restoring one from the trash doesn't point at a deleted category
+	cmdTag, err := executor.Exec(
+		ctx,
+		`UPDATE transactions SET category_id = $3, updated_at = NOW() WHERE budget_id = $1 AND category_id = $2`,
+		budgetId, fromId, toId,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("error moving transactions: %w", err)
+	}
+	result.Transactions = cmdTag.RowsAffected()
+
+	cmdTag, err = executor.Exec(
+		ctx,
+		`UPDATE transaction_splits SET category_id = $3, updated_at = NOW() WHERE budget_id = $1 AND category_id = $2`,
+		budgetId, fromId, toId,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("error moving transaction splits: %w", err)
+	}
+	result.Splits = cmdTag.RowsAffected()
+
+	cmdTag, err = executor.Exec(
+		ctx,
+		`UPDATE transaction_embeddings SET category_id = $3, updated_at = NOW() WHERE budget_id = $1 AND category_id = $2`,
+		budgetId, fromId, toId,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("error moving transaction embeddings: %w", err)
+	}
+	result.TransactionEmbeddings = cmdTag.RowsAffected()
+
+	cmdTag, err = executor.Exec(
+		ctx,
+		`UPDATE payee_rules SET category_id = $3, updated_at = NOW() WHERE budget_id = $1 AND category_id = $2`,
+		budgetId, fromId, toId,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("error moving payee rules: %w", err)
+	}
+	result.PayeeRules = cmdTag.RowsAffected()
+
+	_, err = executor.Exec(
+		ctx, `
+		UPDATE cipher_predictions SET
+		  predicted_category_id = CASE WHEN predicted_category_id = $2 THEN $3 ELSE predicted_category_id END,
+		  actual_category_id = CASE WHEN actual_category_id = $2 THEN $3 ELSE actual_category_id END,
+		  updated_at = NOW()
+		WHERE budget_id = $1 AND (predicted_category_id = $2 OR actual_category_id = $2)
+		`,
+		budgetId, fromId, toId,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("error moving predictions: %w", err)
+	}
+
+	for _, table := range []string{"scheduled_transactions", "transaction_templates"} {
+		_, err = executor.Exec(
+			ctx,
+			`UPDATE `+table+` SET category_id = $3, updated_at = NOW() WHERE budget_id = $1 AND category_id = $2`,
+			budgetId, fromId, toId,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("error moving %s: %w", table, err)
+		}
+	}
+
+	// loan metadata is keyed by account, not budget
+	_, err = executor.Exec(
+		ctx, `
+		UPDATE loan_metadata SET category_id = $3, updated_at = NOW()
+		WHERE category_id = $2 AND account_id IN (SELECT id FROM accounts WHERE budget_id = $1)
+		`,
+		budgetId, fromId, toId,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("error moving loan metadata: %w", err)
+	}
+	return result, nil
+}
+
+func (r *categoryRepo) DeleteById(ctx context.Context, tx pgx.Tx, budgetId uuid.UUID, id uuid.UUID) error {
+	cmdTag, err := r.Executor(tx).Exec(
 		ctx,
 		`UPDATE categories SET 
-	    deleted = TRUE
+	    deleted = TRUE,
 		  updated_at = NOW()
-		WHERE id = $1 AND budget_id = $2`,
+		WHERE id = $1 AND budget_id = $2 AND deleted = FALSE`,
 		id, budgetId,
 	)
+	if err != nil {
+		return err
+	}
 
 	if cmdTag.RowsAffected() == 0 {
-		return errors.New("Category not found")
+		return pgx.ErrNoRows
 	}
 
-	return err
+	return nil
 }
 
 func (r *categoryRepo) Update(ctx context.Context, budgetId uuid.UUID, id uuid.UUID, category model.Category) error {
@@ -412,13 +630,15 @@ func (r *categoryRepo) Update(ctx context.Context, budgetId uuid.UUID, id uuid.U
 			  note = $3,
 			  hidden = $4,
 				is_system = $5,
+				rollover_negative = $6,
 				updated_at = NOW()
-		WHERE budget_id = $6 AND id = $7`,
+		WHERE budget_id = $7 AND id = $8`,
 		category.Name,
 		category.CategoryGroupID,
 		category.Note,
 		category.Hidden,
 		category.IsSystem,
+		category.RolloverNegative,
 		budgetId,
 		id,
 	)
@@ -426,6 +646,64 @@ func (r *categoryRepo) Update(ctx context.Context, budgetId uuid.UUID, id uuid.U
 	return err
 }
 
+const categoryGoalColumns = `id, budget_id, category_id, type, amount, target_month, created_at, updated_at`
+
+func scanCategoryGoal(row pgx.Row) (*model.CategoryGoal, error) {
+	var goal model.CategoryGoal
+	err := row.Scan(
+		&goal.ID,
+		&goal.BudgetID,
+		&goal.CategoryID,
+		&goal.Type,
+		&goal.Amount,
+		&goal.TargetMonth,
+		&goal.CreatedAt,
+		&goal.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &goal, nil
+}
+
+func (r *categoryRepo) GetGoal(ctx context.Context, budgetId uuid.UUID, categoryId uuid.UUID) (*model.CategoryGoal, error) {
+	return scanCategoryGoal(r.Executor(nil).QueryRow(
+		ctx,
+		`SELECT `+categoryGoalColumns+` FROM category_goals WHERE budget_id = $1 AND category_id = $2`,
+		budgetId, categoryId,
+	))
+}
+
+func (r *categoryRepo) UpsertGoal(ctx context.Context, goal model.CategoryGoal) (*model.CategoryGoal, error) {
+	return scanCategoryGoal(r.Executor(nil).QueryRow(
+		ctx, `
+		INSERT INTO category_goals (budget_id, category_id, type, amount, target_month)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (category_id) DO UPDATE SET
+		  type = EXCLUDED.type,
+		  amount = EXCLUDED.amount,
+		  target_month = EXCLUDED.target_month,
+		  updated_at = NOW()
+		RETURNING `+categoryGoalColumns,
+		goal.BudgetID, goal.CategoryID, goal.Type, goal.Amount, goal.TargetMonth,
+	))
+}
+
+func (r *categoryRepo) DeleteGoal(ctx context.Context, budgetId uuid.UUID, categoryId uuid.UUID) error {
+	cmdTag, err := r.Executor(nil).Exec(
+		ctx,
+		`DELETE FROM category_goals WHERE budget_id = $1 AND category_id = $2`,
+		budgetId, categoryId,
+	)
+	if err != nil {
+		return err
+	}
+	if cmdTag.RowsAffected() == 0 {
+		return pgx.ErrNoRows
+	}
+	return nil
+}
+
 // Charit Bhatt
 // 405 Philip Blvd Apt 302
 // Lawrenceville, GA
@@ -21,6 +21,19 @@ type CategoryGroupRepository interface {
 	DeleteById(ctx context.Context, budgetId uuid.UUID, id uuid.UUID) error
 }
 
+// activityLinesQuery selects the live transaction and split lines counting towards the activity of
+// their category
+const activityLinesQuery = `
+	SELECT t.category_id, t.date, t.amount
+	FROM transactions t
+	WHERE t.category_id IS NOT NULL AND t.deleted = FALSE
+	UNION ALL
+	SELECT s.category_id, t.date, s.amount
+	FROM transaction_splits s
+	JOIN transactions t ON t.id = s.transaction_id
+	WHERE s.deleted = FALSE AND t.deleted = FALSE
+`
+
 type categoryGroupRepo struct {
 	BaseRepository
 }
@@ -49,7 +62,7 @@ func (r *categoryGroupRepo) GetAll(ctx context.Context, budgetId uuid.UUID) ([]m
 							SELECT mb.month, SUM(mb.budgeted) AS sum_budgeted
 							FROM monthly_budgets mb
 							JOIN categories c ON c.id = mb.category_id
-							WHERE c.category_group_id = cg.id AND c.deleted = FALSE AND c.hidden = FALSE AND c.is_system = FALSE
+							WHERE c.category_group_id = cg.id AND c.deleted = FALSE AND c.hidden = FALSE AND (c.is_system = FALSE OR c.account_id IS NOT NULL)
 							GROUP BY mb.month
 						) t
 					),
@@ -62,11 +75,11 @@ func (r *categoryGroupRepo) GetAll(ctx context.Context, budgetId uuid.UUID) ([]m
 						SELECT json_object_agg(month, sum_activity)
 						FROM (
 							SELECT
-								TO_CHAR(date_trunc('month', t.date::date), 'YYYY-MM') AS month,
-								SUM(t.amount) AS sum_activity
-							FROM transactions t
-							JOIN categories c ON c.id = t.category_id
-							WHERE c.category_group_id = cg.id AND c.deleted = FALSE AND c.hidden = FALSE AND c.is_system = FALSE
+								LEFT(l.date, 7) AS month,
+								SUM(l.amount) AS sum_activity
+							FROM (`+activityLinesQuery+`) l
+							JOIN categories c ON c.id = l.category_id
+							WHERE c.category_group_id = cg.id AND c.deleted = FALSE AND c.hidden = FALSE AND (c.is_system = FALSE OR c.account_id IS NOT NULL)
 							GROUP BY month
 						) a
 					),
@@ -81,7 +94,7 @@ func (r *categoryGroupRepo) GetAll(ctx context.Context, budgetId uuid.UUID) ([]m
 							SELECT mb2.month, SUM(mb2.carryover_balance) AS sum_balance
 							FROM monthly_budgets mb2
 							JOIN categories c ON c.id = mb2.category_id
-							WHERE c.category_group_id = cg.id AND c.deleted = FALSE AND c.hidden = FALSE AND c.is_system = FALSE
+							WHERE c.category_group_id = cg.id AND c.deleted = FALSE AND c.hidden = FALSE AND (c.is_system = FALSE OR c.account_id IS NOT NULL)
 							GROUP BY mb2.month
 						) b
 					),
@@ -100,9 +113,11 @@ func (r *categoryGroupRepo) GetAll(ctx context.Context, budgetId uuid.UUID) ([]m
 							'name', c.name,
 							'budgetId', c.budget_id,
 							'categoryGroupId', c.category_group_id,
+							'accountId', c.account_id,
 							'note', c.note,
 							'hidden', c.hidden,
 							'isSystem', c.is_system,
+							'rolloverNegative', c.rollover_negative,
 							'createdAt', c.created_at,
 							'updatedAt', c.updated_at,
 							'budgeted', COALESCE(
@@ -115,10 +130,10 @@ func (r *categoryGroupRepo) GetAll(ctx context.Context, budgetId uuid.UUID) ([]m
 						(SELECT json_object_agg(tx.month, tx.sum)
 							FROM (
 								SELECT
-									TO_CHAR(date_trunc('month', t.date::date), 'YYYY-MM') AS month,
-									SUM(t.amount) AS sum
-								FROM transactions t
-								WHERE t.category_id = c.id AND t.deleted = FALSE
+									LEFT(l.date, 7) AS month,
+									SUM(l.amount) AS sum
+								FROM (`+activityLinesQuery+`) l
+								WHERE l.category_id = c.id
 								GROUP BY month
 							) tx
 						), '{}'
@@ -128,11 +143,12 @@ func (r *categoryGroupRepo) GetAll(ctx context.Context, budgetId uuid.UUID) ([]m
 							FROM monthly_budgets mb2
 							WHERE mb2.category_id = c.id
 						), '{}'
-					)
+					),
+					'creditActivity', `+creditActivityQuery("c.id")+`
 						)::jsonb AS category_json,
 						c.id
 				FROM categories c
-				WHERE c.category_group_id = cg.id AND c.deleted = FALSE AND c.hidden = FALSE AND c.is_system = FALSE
+				WHERE c.category_group_id = cg.id AND c.deleted = FALSE AND c.hidden = FALSE AND (c.is_system = FALSE OR c.account_id IS NOT NULL)
 			) category_json ON TRUE
 			WHERE cg.budget_id = $1 AND cg.deleted = FALSE
 			GROUP BY cg.id
@@ -169,10 +185,10 @@ func (r *categoryGroupRepo) GetAll(ctx context.Context, budgetId uuid.UUID) ([]m
 						SELECT json_object_agg(month, sum_activity)
 						FROM (
 							SELECT
-								TO_CHAR(date_trunc('month', t.date::date), 'YYYY-MM') AS month,
-								SUM(t.amount) AS sum_activity
-							FROM transactions t
-							JOIN categories c ON c.id = t.category_id
+								LEFT(l.date, 7) AS month,
+								SUM(l.amount) AS sum_activity
+							FROM (`+activityLinesQuery+`) l
+							JOIN categories c ON c.id = l.category_id
 							WHERE c.budget_id = $1 AND c.hidden = TRUE AND c.deleted = FALSE
 							GROUP BY month
 						) a
@@ -206,9 +222,11 @@ func (r *categoryGroupRepo) GetAll(ctx context.Context, budgetId uuid.UUID) ([]m
 								'name', c.name,
 								'budgetId', c.budget_id,
 								'categoryGroupId', c.category_group_id,
+								'accountId', c.account_id,
 								'note', c.note,
 								'hidden', c.hidden,
 								'isSystem', c.is_system,
+								'rolloverNegative', c.rollover_negative,
 								'createdAt', c.created_at,
 								'updatedAt', c.updated_at,
 								'budgeted', COALESCE(
@@ -221,10 +239,10 @@ func (r *categoryGroupRepo) GetAll(ctx context.Context, budgetId uuid.UUID) ([]m
 									(SELECT json_object_agg(tx.month, tx.sum)
 										FROM (
 											SELECT
-												TO_CHAR(date_trunc('month', t.date::date), 'YYYY-MM') AS month,
-												SUM(t.amount) AS sum
-											FROM transactions t
-											WHERE t.budget_id = $1 AND t.category_id = c.id AND t.deleted = FALSE
+												LEFT(l.date, 7) AS month,
+												SUM(l.amount) AS sum
+											FROM (`+activityLinesQuery+`) l
+											WHERE l.category_id = c.id
 											GROUP BY month
 										) tx
 									), '{}'
@@ -234,7 +252,8 @@ func (r *categoryGroupRepo) GetAll(ctx context.Context, budgetId uuid.UUID) ([]m
 										FROM monthly_budgets mb2
 										WHERE mb2.budget_id = $1 AND mb2.category_id = c.id
 									), '{}'
-								)
+								),
+								'creditActivity', `+creditActivityQuery("c.id")+`
 								)::jsonb AS category_json
 							FROM categories c
 							WHERE c.budget_id = $1 AND c.hidden = TRUE AND c.deleted = FALSE
@@ -279,9 +298,39 @@ func (r *categoryGroupRepo) GetAll(ctx context.Context, budgetId uuid.UUID) ([]m
 		}
 		groups = append(groups, g)
 	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	applyOverspending(groups)
 	return groups, nil
 }
 
+// applyOverspending applies the overspending rules to the categories, see model.ApplyOverspending, and sums
+// the balance of the groups again
+func applyOverspending(groups []model.CategoryGroup) {
+	var categories []*model.Category
+	for i := range groups {
+		for j := range groups[i].Categories {
+			categories = append(categories, &groups[i].Categories[j])
+		}
+	}
+	model.ApplyOverspending(categories)
+
+	for i := range groups {
+		if len(groups[i].Categories) == 0 {
+			continue
+		}
+		balance := make(map[string]float32)
+		for _, c := range groups[i].Categories {
+			for month, amount := range c.Balance {
+				balance[month] += amount
+			}
+		}
+		groups[i].Balance = balance
+	}
+}
+
 func (r *categoryGroupRepo) Create(ctx context.Context, tx pgx.Tx, categoryGroup model.CategoryGroup) (*model.CategoryGroup, error) {
 	sql := `INSERT INTO category_groups (name, budget_id, hidden, is_system, deleted, created_at, updated_at)
 		VALUES ($1, $2, $3, $4, FALSE, NOW(), NOW())
@@ -0,0 +1,84 @@
+package db
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+func (ts *testSuite) createReadyToAssignTables(t *testing.T) {
+	ts.createTableIfNotExists(t)
+	_, err := ts.dbpool.Exec(ts.ctx, `
+		CREATE TABLE IF NOT EXISTS categories (
+			id UUID,
+			budget_id UUID,
+			account_id UUID,
+			is_system BOOLEAN,
+			deleted BOOLEAN
+		)`)
+	require.NoError(t, err)
+	_, err = ts.dbpool.Exec(ts.ctx, `
+		CREATE TABLE IF NOT EXISTS transactions (
+			id UUID,
+			budget_id UUID,
+			category_id UUID,
+			date TEXT,
+			amount NUMERIC(12, 2),
+			deleted BOOLEAN
+		)`)
+	require.NoError(t, err)
+}
+
+func TestGetReadyToAssign(t *testing.T) {
+	ts := setupTestSuite(t)
+	defer ts.tearDown()
+	defer ts.cleanupTestData()
+	defer func() {
+		_, _ = ts.dbpool.Exec(ts.ctx, `DELETE FROM transactions WHERE budget_id = $1`, ts.budgetID)
+		_, _ = ts.dbpool.Exec(ts.ctx, `DELETE FROM categories WHERE budget_id = $1`, ts.budgetID)
+	}()
+	ts.createReadyToAssignTables(t)
+
+	inflowID := uuid.New()
+	_, err := ts.dbpool.Exec(ts.ctx, `
+		INSERT INTO categories (id, budget_id, account_id, is_system, deleted) VALUES ($1, $2, NULL, TRUE, FALSE)
+	`, inflowID, ts.budgetID)
+	require.NoError(t, err)
+	_, err = ts.dbpool.Exec(ts.ctx, `
+		INSERT INTO transactions (id, budget_id, category_id, date, amount, deleted) VALUES
+			($1, $3, $4, '2025-01-05', 1000, FALSE),
+			($2, $3, $4, '2025-03-05', 500, FALSE)
+	`, uuid.New(), uuid.New(), ts.budgetID, inflowID)
+	require.NoError(t, err)
+	// all of January's income is assigned in February
+	_, err = ts.dbpool.Exec(ts.ctx, `
+		INSERT INTO monthly_budgets (id, budget_id, category_id, month, budgeted, carryover_balance, created_at, updated_at)
+		VALUES ($1, $2, $3, '2025-02', 1000, 0, NOW(), NOW())
+	`, uuid.New(), ts.budgetID, ts.catID)
+	require.NoError(t, err)
+
+	repo := NewCategoryRepository(ts.dbpool)
+	tests := []struct {
+		name  string
+		month string
+		want  float64
+	}{
+		{"EarlierMonthAfterLaterMonthIsFunded", "2025-01", 0},
+		{"FundedMonth", "2025-02", 0},
+		{"LaterIncome", "2025-03", 500},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			readyToAssign, err := repo.GetReadyToAssign(ts.ctx, nil, ts.budgetID, tt.month, nil)
+			require.NoError(t, err)
+			require.Equal(t, tt.want, readyToAssign)
+		})
+	}
+
+	t.Run("InflowBalanceCountsAllIncome", func(t *testing.T) {
+		balance, err := repo.GetInflowBalance(ts.ctx, ts.budgetID)
+		require.NoError(t, err)
+		require.Equal(t, 500.0, balance)
+	})
+}
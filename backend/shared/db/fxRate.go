@@ -0,0 +1,159 @@
+package db
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Rishabh-Kapri/pennywise/backend/shared/model"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type FxRateRepository interface {
+	BaseRepositoryInterface
+	GetAll(ctx context.Context, budgetId uuid.UUID, filter model.FxRateFilter) ([]model.FxRate, error)
+	// GetRate returns the latest rate on or before date to convert from into to.
+	// A rate stored the other way around is inverted. pgx.ErrNoRows is returned when there is none.
+	GetRate(ctx context.Context, budgetId uuid.UUID, from string, to string, date string) (*model.FxRate, error)
+	// Upsert creates the rate of a currency pair for a date or replaces the existing one
+	Upsert(ctx context.Context, tx pgx.Tx, rate model.FxRate) (*model.FxRate, error)
+	DeleteById(ctx context.Context, budgetId uuid.UUID, id uuid.UUID) error
+}
+
+type fxRateRepo struct {
+	BaseRepository
+}
+
+func NewFxRateRepository(pool *pgxpool.Pool) FxRateRepository {
+	return &fxRateRepo{BaseRepository: NewBaseRepository(pool)}
+}
+
+const fxRateColumns = `
+	id,
+	budget_id,
+	date,
+	base_currency,
+	quote_currency,
+	rate,
+	created_at,
+	updated_at`
+
+func scanFxRate(row pgx.Row) (*model.FxRate, error) {
+	var rate model.FxRate
+	err := row.Scan(
+		&rate.ID,
+		&rate.BudgetID,
+		&rate.Date,
+		&rate.BaseCurrency,
+		&rate.QuoteCurrency,
+		&rate.Rate,
+		&rate.CreatedAt,
+		&rate.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &rate, nil
+}
+
+func (r *fxRateRepo) GetAll(
+	ctx context.Context,
+	budgetId uuid.UUID,
+	filter model.FxRateFilter,
+) ([]model.FxRate, error) {
+	query := sq.StatementBuilder.PlaceholderFormat(sq.Dollar).
+		Select(fxRateColumns).
+		From("fx_rates").
+		Where(sq.Eq{"budget_id": budgetId}).
+		OrderBy("date DESC", "base_currency ASC", "quote_currency ASC")
+	if filter.BaseCurrency != nil {
+		query = query.Where(sq.Eq{"base_currency": *filter.BaseCurrency})
+	}
+	if filter.QuoteCurrency != nil {
+		query = query.Where(sq.Eq{"quote_currency": *filter.QuoteCurrency})
+	}
+	if filter.StartDate != nil {
+		query = query.Where(sq.GtOrEq{"date": *filter.StartDate})
+	}
+	if filter.EndDate != nil {
+		query = query.Where(sq.LtOrEq{"date": *filter.EndDate})
+	}
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return nil, err
+	}
+	rows, err := r.Executor(nil).Query(ctx, sql, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	rates := make([]model.FxRate, 0)
+	for rows.Next() {
+		rate, err := scanFxRate(rows)
+		if err != nil {
+			return nil, fmt.Errorf("error while parsing fx_rates rows: %w", err)
+		}
+		rates = append(rates, *rate)
+	}
+	return rates, rows.Err()
+}
+
+func (r *fxRateRepo) GetRate(
+	ctx context.Context,
+	budgetId uuid.UUID,
+	from string,
+	to string,
+	date string,
+) (*model.FxRate, error) {
+	rate, err := scanFxRate(r.Executor(nil).QueryRow(
+		ctx,
+		`SELECT `+fxRateColumns+`
+		FROM fx_rates
+		WHERE budget_id = $1 AND date <= $4
+		  AND ((base_currency = $2 AND quote_currency = $3) OR (base_currency = $3 AND quote_currency = $2))
+		ORDER BY date DESC, (base_currency = $2) DESC
+		LIMIT 1`,
+		budgetId, from, to, date,
+	))
+	if err != nil {
+		return nil, err
+	}
+	if rate.BaseCurrency != from {
+		rate.BaseCurrency, rate.QuoteCurrency = rate.QuoteCurrency, rate.BaseCurrency
+		rate.Rate = 1 / rate.Rate
+	}
+	return rate, nil
+}
+
+func (r *fxRateRepo) Upsert(ctx context.Context, tx pgx.Tx, rate model.FxRate) (*model.FxRate, error) {
+	return scanFxRate(r.Executor(tx).QueryRow(
+		ctx, `
+		INSERT INTO fx_rates (budget_id, date, base_currency, quote_currency, rate)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (budget_id, base_currency, quote_currency, date) DO UPDATE SET
+			rate = EXCLUDED.rate,
+			updated_at = NOW()
+		RETURNING `+fxRateColumns,
+		rate.BudgetID, rate.Date, rate.BaseCurrency, rate.QuoteCurrency, rate.Rate,
+	))
+}
+
+func (r *fxRateRepo) DeleteById(ctx context.Context, budgetId uuid.UUID, id uuid.UUID) error {
+	cmdTag, err := r.Executor(nil).Exec(
+		ctx,
+		`DELETE FROM fx_rates WHERE budget_id = $1 AND id = $2`,
+		budgetId, id,
+	)
+	if err != nil {
+		return err
+	}
+	if cmdTag.RowsAffected() == 0 {
+		return fmt.Errorf("FX rate not found for id: %v", id)
+	}
+	return nil
+}
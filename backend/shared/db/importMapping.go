@@ -0,0 +1,153 @@
+package db
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Rishabh-Kapri/pennywise/backend/shared/model"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type ImportMappingRepository interface {
+	BaseRepositoryInterface
+	GetAll(ctx context.Context, budgetId uuid.UUID) ([]model.ImportMapping, error)
+	GetByAccountId(ctx context.Context, budgetId uuid.UUID, accountId uuid.UUID) (*model.ImportMapping, error)
+	// Upsert creates the mapping of an account or replaces the existing one
+	Upsert(ctx context.Context, mapping model.ImportMapping) (*model.ImportMapping, error)
+	DeleteByAccountId(ctx context.Context, budgetId uuid.UUID, accountId uuid.UUID) error
+}
+
+type importMappingRepo struct {
+	BaseRepository
+}
+
+func NewImportMappingRepository(pool *pgxpool.Pool) ImportMappingRepository {
+	return &importMappingRepo{BaseRepository: NewBaseRepository(pool)}
+}
+
+const importMappingColumns = `
+	id,
+	budget_id,
+	account_id,
+	delimiter,
+	skip_rows,
+	date_column,
+	date_format,
+	amount_column,
+	inflow_column,
+	outflow_column,
+	payee_column,
+	memo_column,
+	invert_amount,
+	created_at,
+	updated_at`
+
+func scanImportMapping(row pgx.Row) (*model.ImportMapping, error) {
+	var mapping model.ImportMapping
+	err := row.Scan(
+		&mapping.ID,
+		&mapping.BudgetID,
+		&mapping.AccountID,
+		&mapping.Delimiter,
+		&mapping.SkipRows,
+		&mapping.DateColumn,
+		&mapping.DateFormat,
+		&mapping.AmountColumn,
+		&mapping.InflowColumn,
+		&mapping.OutflowColumn,
+		&mapping.PayeeColumn,
+		&mapping.MemoColumn,
+		&mapping.InvertAmount,
+		&mapping.CreatedAt,
+		&mapping.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &mapping, nil
+}
+
+func (r *importMappingRepo) GetAll(ctx context.Context, budgetId uuid.UUID) ([]model.ImportMapping, error) {
+	rows, err := r.Executor(nil).Query(
+		ctx,
+		`SELECT `+importMappingColumns+`
+		FROM import_mappings
+		WHERE budget_id = $1 AND deleted = FALSE
+		ORDER BY created_at ASC`,
+		budgetId,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var mappings []model.ImportMapping
+	for rows.Next() {
+		mapping, err := scanImportMapping(rows)
+		if err != nil {
+			return nil, fmt.Errorf("error while parsing import_mappings rows: %w", err)
+		}
+		mappings = append(mappings, *mapping)
+	}
+	return mappings, rows.Err()
+}
+
+func (r *importMappingRepo) GetByAccountId(
+	ctx context.Context,
+	budgetId uuid.UUID,
+	accountId uuid.UUID,
+) (*model.ImportMapping, error) {
+	return scanImportMapping(r.Executor(nil).QueryRow(
+		ctx,
+		`SELECT `+importMappingColumns+`
+		FROM import_mappings
+		WHERE budget_id = $1 AND account_id = $2 AND deleted = FALSE`,
+		budgetId, accountId,
+	))
+}
+
+func (r *importMappingRepo) Upsert(ctx context.Context, mapping model.ImportMapping) (*model.ImportMapping, error) {
+	return scanImportMapping(r.Executor(nil).QueryRow(
+		ctx, `
+		INSERT INTO import_mappings (
+			budget_id, account_id, delimiter, skip_rows, date_column, date_format,
+			amount_column, inflow_column, outflow_column, payee_column, memo_column, invert_amount
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
+		ON CONFLICT (budget_id, account_id) WHERE deleted = FALSE DO UPDATE SET
+			delimiter = EXCLUDED.delimiter,
+			skip_rows = EXCLUDED.skip_rows,
+			date_column = EXCLUDED.date_column,
+			date_format = EXCLUDED.date_format,
+			amount_column = EXCLUDED.amount_column,
+			inflow_column = EXCLUDED.inflow_column,
+			outflow_column = EXCLUDED.outflow_column,
+			payee_column = EXCLUDED.payee_column,
+			memo_column = EXCLUDED.memo_column,
+			invert_amount = EXCLUDED.invert_amount,
+			updated_at = NOW()
+		RETURNING `+importMappingColumns,
+		mapping.BudgetID, mapping.AccountID, mapping.Delimiter, mapping.SkipRows, mapping.DateColumn,
+		mapping.DateFormat, mapping.AmountColumn, mapping.InflowColumn, mapping.OutflowColumn,
+		mapping.PayeeColumn, mapping.MemoColumn, mapping.InvertAmount,
+	))
+}
+
+func (r *importMappingRepo) DeleteByAccountId(ctx context.Context, budgetId uuid.UUID, accountId uuid.UUID) error {
+	cmdTag, err := r.Executor(nil).Exec(
+		ctx, `
+		UPDATE import_mappings
+		SET deleted = TRUE, updated_at = NOW()
+		WHERE budget_id = $1 AND account_id = $2 AND deleted = FALSE
+		`, budgetId, accountId,
+	)
+	if err != nil {
+		return err
+	}
+	if cmdTag.RowsAffected() == 0 {
+		return fmt.Errorf("Import mapping not found for account id: %v", accountId)
+	}
+	return nil
+}
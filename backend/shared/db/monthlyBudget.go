@@ -25,6 +25,23 @@ type MonthlyBudgetRepository interface {
 	// updates the carryover for current and further months
 	// amount should be the value the carryvover needs to be updated with
 	UpdateCarryoverByCatIdAndMonth(ctx context.Context, tx pgx.Tx, budgetId uuid.UUID, categoryId uuid.UUID, month string, amount float64) error
+	// GetSummaries returns the monthly budget rows between startMonth and endMonth (YYYY-MM, empty for no bound)
+	// along with each category's activity for the month
+	GetSummaries(ctx context.Context, budgetId uuid.UUID, startMonth string, endMonth string) ([]model.MonthlyBudgetSummary, error)
+	// MergeCategory folds the monthly budgets of fromCategoryId into toCategoryId and deletes them,
+	// returning the number of months moved
+	MergeCategory(ctx context.Context, tx pgx.Tx, budgetId uuid.UUID, fromCategoryId uuid.UUID, toCategoryId uuid.UUID) (int64, error)
+	// LockBudget locks the budget row within tx, so changes that check Ready to Assign before assigning
+	// money run one at a time
+	LockBudget(ctx context.Context, tx pgx.Tx, budgetId uuid.UUID) error
+	// GetByBudget returns every monthly budget of the budget, locked for update within tx
+	GetByBudget(ctx context.Context, tx pgx.Tx, budgetId uuid.UUID) ([]model.MonthlyBudget, error)
+	// GetActivity sums what the transactions of the budget add to the carryover balances per category
+	// and month, the same way the carryovers are kept when transactions are saved
+	GetActivity(ctx context.Context, tx pgx.Tx, budgetId uuid.UUID) ([]model.CategoryMonthActivity, error)
+	// SetCarryover overwrites the carryover balance of a single month, creating the monthly budget when
+	// it doesn't exist. Other months are left alone.
+	SetCarryover(ctx context.Context, tx pgx.Tx, budgetId uuid.UUID, categoryId uuid.UUID, month string, carryover float64) error
 }
 
 type monthlyBudgetRepo struct {
@@ -201,3 +218,262 @@ func (r *monthlyBudgetRepo) UpdateCarryoverByCatIdAndMonth(
 	}
 	return nil
 }
+
+func (r *monthlyBudgetRepo) GetSummaries(
+	ctx context.Context,
+	budgetId uuid.UUID,
+	startMonth string,
+	endMonth string,
+) ([]model.MonthlyBudgetSummary, error) {
+	rows, err := r.Executor(nil).Query(
+		ctx, `
+		SELECT
+			monthly_budgets.month,
+			categories.id,
+			categories.name,
+			category_groups.name,
+			monthly_budgets.budgeted,
+			COALESCE(activity.total, 0),
+			monthly_budgets.carryover_balance
+		FROM monthly_budgets
+		JOIN categories ON categories.id = monthly_budgets.category_id
+		JOIN category_groups ON category_groups.id = categories.category_group_id
+		LEFT JOIN LATERAL (
+			SELECT SUM(lines.amount) AS total
+			FROM (
+				SELECT transactions.amount
+				FROM transactions
+				WHERE transactions.category_id = categories.id
+					AND transactions.deleted = FALSE
+					AND LEFT(transactions.date, 7) = monthly_budgets.month
+				UNION ALL
+				SELECT transaction_splits.amount
+				FROM transaction_splits
+				JOIN transactions ON transactions.id = transaction_splits.transaction_id
+				WHERE transaction_splits.category_id = categories.id
+					AND transaction_splits.deleted = FALSE
+					AND transactions.deleted = FALSE
+					AND LEFT(transactions.date, 7) = monthly_budgets.month
+			) AS lines
+		) AS activity ON TRUE
+		WHERE monthly_budgets.budget_id = $1
+			AND categories.deleted = FALSE
+			AND ($2 = '' OR monthly_budgets.month >= $2)
+			AND ($3 = '' OR monthly_budgets.month <= $3)
+		ORDER BY monthly_budgets.month ASC, category_groups.name ASC, categories.name ASC
+		`, budgetId, startMonth, endMonth,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var summaries []model.MonthlyBudgetSummary
+	for rows.Next() {
+		var summary model.MonthlyBudgetSummary
+		err := rows.Scan(
+			&summary.Month,
+			&summary.CategoryID,
+			&summary.CategoryName,
+			&summary.CategoryGroupName,
+			&summary.Budgeted,
+			&summary.Activity,
+			&summary.Available,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("error while parsing monthly_budgets rows: %w", err)
+		}
+		summaries = append(summaries, summary)
+	}
+	return summaries, rows.Err()
+}
+
+// budgeted amounts are summed per month. carryover_balance is a running balance, so a month only one of the
+// categories has still picks up the other's latest balance before it.
+func (r *monthlyBudgetRepo) MergeCategory(
+	ctx context.Context,
+	tx pgx.Tx,
+	budgetId uuid.UUID,
+	fromCategoryId uuid.UUID,
+	toCategoryId uuid.UUID,
+) (int64, error) {
+	var moved int64
+	err := r.Executor(tx).QueryRow(
+		ctx, `
+		WITH months AS (
+		  SELECT DISTINCT month FROM monthly_budgets
+		  WHERE budget_id = $1 AND category_id IN ($2, $3)
+		), merged AS (
+		  SELECT
+		    months.month,
+		    (
+		      SELECT COALESCE(SUM(budgeted), 0) FROM monthly_budgets
+		      WHERE budget_id = $1 AND category_id IN ($2, $3) AND month = months.month
+		    ) AS budgeted,
+		    COALESCE((
+		      SELECT carryover_balance FROM monthly_budgets
+		      WHERE budget_id = $1 AND category_id = $2 AND month <= months.month
+		      ORDER BY month DESC LIMIT 1
+		    ), 0) + COALESCE((
+		      SELECT carryover_balance FROM monthly_budgets
+		      WHERE budget_id = $1 AND category_id = $3 AND month <= months.month
+		      ORDER BY month DESC LIMIT 1
+		    ), 0) AS carryover_balance
+		  FROM months
+		), updated AS (
+		  UPDATE monthly_budgets SET
+		    budgeted = merged.budgeted,
+		    carryover_balance = merged.carryover_balance,
+		    updated_at = NOW()
+		  FROM merged
+		  WHERE monthly_budgets.budget_id = $1 AND monthly_budgets.category_id = $3
+		    AND monthly_budgets.month = merged.month
+		), inserted AS (
+		  INSERT INTO monthly_budgets (
+		    budget_id, category_id, month, budgeted, carryover_balance, created_at, updated_at
+		  )
+		  SELECT $1, $3, merged.month, merged.budgeted, merged.carryover_balance, NOW(), NOW()
+		  FROM merged
+		  WHERE NOT EXISTS (
+		    SELECT 1 FROM monthly_budgets
+		    WHERE budget_id = $1 AND category_id = $3 AND month = merged.month
+		  )
+		), deleted AS (
+		  DELETE FROM monthly_budgets
+		  WHERE budget_id = $1 AND category_id = $2
+		  RETURNING id
+		)
+		SELECT COUNT(*) FROM deleted
+		`, budgetId, fromCategoryId, toCategoryId,
+	).Scan(&moved)
+	return moved, err
+}
+
+func (r *monthlyBudgetRepo) LockBudget(ctx context.Context, tx pgx.Tx, budgetId uuid.UUID) error {
+	var id uuid.UUID
+	return r.Executor(tx).QueryRow(
+		ctx,
+		`SELECT id FROM budgets WHERE id = $1 FOR UPDATE`,
+		budgetId,
+	).Scan(&id)
+}
+
+func (r *monthlyBudgetRepo) GetByBudget(ctx context.Context, tx pgx.Tx, budgetId uuid.UUID) ([]model.MonthlyBudget, error) {
+	rows, err := r.Executor(tx).Query(
+		ctx, `
+		SELECT id, budget_id, category_id, month, budgeted, carryover_balance
+		FROM monthly_budgets
+		WHERE budget_id = $1
+		ORDER BY category_id, month
+		FOR UPDATE
+		`, budgetId,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var monthlyBudgets []model.MonthlyBudget
+	for rows.Next() {
+		var mb model.MonthlyBudget
+		err := rows.Scan(&mb.ID, &mb.BudgetID, &mb.CategoryID, &mb.Month, &mb.Budgeted, &mb.CarryoverBalance)
+		if err != nil {
+			return nil, fmt.Errorf("error while parsing monthly_budgets rows: %w", err)
+		}
+		monthlyBudgets = append(monthlyBudgets, mb)
+	}
+	return monthlyBudgets, rows.Err()
+}
+
+// Split lines count for their own category and the inflow category is left out. On a credit card the
+// spending categories' lines move into the card's payment category, and so does a payment into the card,
+// see carryoverLines in the api service.
+func (r *monthlyBudgetRepo) GetActivity(
+	ctx context.Context,
+	tx pgx.Tx,
+	budgetId uuid.UUID,
+) ([]model.CategoryMonthActivity, error) {
+	rows, err := r.Executor(tx).Query(
+		ctx, `
+		WITH live_transactions AS (
+		  SELECT t.*, EXISTS (
+		    SELECT 1 FROM transaction_splits s WHERE s.transaction_id = t.id AND s.deleted = FALSE
+		  ) AS is_split
+		  FROM transactions t
+		  WHERE t.budget_id = $1 AND t.deleted = FALSE
+		), category_lines AS (
+		  SELECT t.account_id, LEFT(t.date, 7) AS month, t.category_id, t.amount
+		  FROM live_transactions t
+		  WHERE NOT t.is_split AND t.category_id IS NOT NULL
+		  UNION ALL
+		  SELECT t.account_id, LEFT(t.date, 7) AS month, s.category_id, s.amount
+		  FROM transaction_splits s
+		  JOIN live_transactions t ON t.id = s.transaction_id
+		  WHERE s.deleted = FALSE AND s.category_id IS NOT NULL
+		), budget_lines AS (
+		  SELECT * FROM category_lines
+		  WHERE category_id IS DISTINCT FROM (
+		    SELECT (metadata->>'inflowCategoryId')::uuid FROM budgets WHERE id = $1
+		  )
+		), payment_categories AS (
+		  SELECT c.account_id, c.id
+		  FROM categories c
+		  JOIN accounts a ON a.id = c.account_id
+		  WHERE c.budget_id = $1 AND c.deleted = FALSE AND a.type = 'creditCard' AND a.deleted = FALSE
+		), lines AS (
+		  SELECT category_id, month, amount FROM budget_lines
+		  UNION ALL
+		  SELECT p.id, l.month, -l.amount
+		  FROM budget_lines l
+		  JOIN payment_categories p ON p.account_id = l.account_id
+		  UNION ALL
+		  SELECT p.id, LEFT(t.date, 7), -t.amount
+		  FROM live_transactions t
+		  JOIN payment_categories p ON p.account_id = t.account_id
+		  WHERE NOT t.is_split AND t.category_id IS NULL AND t.transfer_account_id IS NOT NULL AND t.amount > 0
+		)
+		SELECT category_id, month, SUM(amount)
+		FROM lines
+		GROUP BY category_id, month
+		ORDER BY category_id, month
+		`, budgetId,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var activity []model.CategoryMonthActivity
+	for rows.Next() {
+		var a model.CategoryMonthActivity
+		if err := rows.Scan(&a.CategoryID, &a.Month, &a.Activity); err != nil {
+			return nil, fmt.Errorf("error while parsing activity rows: %w", err)
+		}
+		activity = append(activity, a)
+	}
+	return activity, rows.Err()
+}
+
+func (r *monthlyBudgetRepo) SetCarryover(
+	ctx context.Context,
+	tx pgx.Tx,
+	budgetId uuid.UUID,
+	categoryId uuid.UUID,
+	month string,
+	carryover float64,
+) error {
+	_, err := r.Executor(tx).Exec(
+		ctx, `
+		INSERT INTO monthly_budgets (
+			budget_id, category_id, budgeted, month, carryover_balance, created_at, updated_at
+		) VALUES ($1, $2, 0, $3, $4, NOW(), NOW())
+		ON CONFLICT (budget_id, category_id, month) DO UPDATE SET
+			carryover_balance = EXCLUDED.carryover_balance,
+			updated_at = NOW()
+		`, budgetId, categoryId, month, carryover,
+	)
+	if err != nil {
+		return fmt.Errorf("error setting carryover for categoryId %v and month %v: %w", categoryId, month, err)
+	}
+	return nil
+}
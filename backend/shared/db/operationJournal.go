@@ -0,0 +1,162 @@
+package db
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Rishabh-Kapri/pennywise/backend/shared/model"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type OperationJournalRepository interface {
+	BaseRepositoryInterface
+	Create(ctx context.Context, tx pgx.Tx, entry model.JournalEntry) error
+	// GetUndoable returns the latest entries that haven't been undone, newest first
+	GetUndoable(ctx context.Context, tx pgx.Tx, budgetId uuid.UUID, userId uuid.UUID, limit int) ([]model.JournalEntry, error)
+	// GetRedoable returns the entries that were undone, in the order they were originally made
+	GetRedoable(ctx context.Context, tx pgx.Tx, budgetId uuid.UUID, userId uuid.UUID, limit int) ([]model.JournalEntry, error)
+	SetUndone(ctx context.Context, tx pgx.Tx, id uuid.UUID, undone bool) error
+	// DeleteUndone drops the redo history of a user, a new mutation makes it stale
+	DeleteUndone(ctx context.Context, tx pgx.Tx, budgetId uuid.UUID, userId uuid.UUID) error
+}
+
+type operationJournalRepo struct {
+	BaseRepository
+}
+
+func NewOperationJournalRepository(pool *pgxpool.Pool) OperationJournalRepository {
+	return &operationJournalRepo{BaseRepository: NewBaseRepository(pool)}
+}
+
+const operationJournalColumns = `
+	id,
+	budget_id,
+	user_id,
+	entity_type,
+	entity_id,
+	action,
+	before,
+	after,
+	undone_at,
+	created_at`
+
+func (r *operationJournalRepo) Create(ctx context.Context, tx pgx.Tx, entry model.JournalEntry) error {
+	_, err := r.Executor(tx).Exec(
+		ctx, `
+		INSERT INTO operation_journal (
+		  budget_id,
+		  user_id,
+		  entity_type,
+		  entity_id,
+		  action,
+		  before,
+		  after
+		) VALUES ($1, $2, $3, $4, $5, $6, $7)`,
+		entry.BudgetID,
+		entry.UserID,
+		entry.EntityType,
+		entry.EntityID,
+		entry.Action,
+		entry.Before,
+		entry.After,
+	)
+	return err
+}
+
+func (r *operationJournalRepo) GetUndoable(
+	ctx context.Context,
+	tx pgx.Tx,
+	budgetId uuid.UUID,
+	userId uuid.UUID,
+	limit int,
+) ([]model.JournalEntry, error) {
+	return r.query(
+		ctx, tx, `
+		SELECT`+operationJournalColumns+`
+		FROM operation_journal
+		WHERE budget_id = $1 AND user_id = $2 AND undone_at IS NULL
+		ORDER BY seq DESC
+		LIMIT $3
+		FOR UPDATE`,
+		budgetId, userId, limit,
+	)
+}
+
+func (r *operationJournalRepo) GetRedoable(
+	ctx context.Context,
+	tx pgx.Tx,
+	budgetId uuid.UUID,
+	userId uuid.UUID,
+	limit int,
+) ([]model.JournalEntry, error) {
+	return r.query(
+		ctx, tx, `
+		SELECT`+operationJournalColumns+`
+		FROM operation_journal
+		WHERE budget_id = $1 AND user_id = $2 AND undone_at IS NOT NULL
+		ORDER BY seq ASC
+		LIMIT $3
+		FOR UPDATE`,
+		budgetId, userId, limit,
+	)
+}
+
+func (r *operationJournalRepo) query(ctx context.Context, tx pgx.Tx, sql string, args ...any) ([]model.JournalEntry, error) {
+	rows, err := r.Executor(tx).Query(ctx, sql, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	entries := make([]model.JournalEntry, 0)
+	for rows.Next() {
+		var entry model.JournalEntry
+		err := rows.Scan(
+			&entry.ID,
+			&entry.BudgetID,
+			&entry.UserID,
+			&entry.EntityType,
+			&entry.EntityID,
+			&entry.Action,
+			&entry.Before,
+			&entry.After,
+			&entry.UndoneAt,
+			&entry.CreatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, entry)
+	}
+	return entries, rows.Err()
+}
+
+func (r *operationJournalRepo) SetUndone(ctx context.Context, tx pgx.Tx, id uuid.UUID, undone bool) error {
+	cmdTag, err := r.Executor(tx).Exec(
+		ctx, `
+		UPDATE operation_journal
+		SET undone_at = CASE WHEN $1 THEN NOW() ELSE NULL END
+		WHERE id = $2`,
+		undone, id,
+	)
+	if err != nil {
+		return err
+	}
+	if cmdTag.RowsAffected() == 0 {
+		return fmt.Errorf("Journal entry not found for id: %v", id)
+	}
+	return nil
+}
+
+func (r *operationJournalRepo) DeleteUndone(ctx context.Context, tx pgx.Tx, budgetId uuid.UUID, userId uuid.UUID) error {
+	_, err := r.Executor(tx).Exec(
+		ctx, `
+		DELETE FROM operation_journal
+		WHERE budget_id = $1 AND user_id = $2 AND undone_at IS NOT NULL`,
+		budgetId, userId,
+	)
+	return err
+}
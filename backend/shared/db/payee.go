@@ -3,6 +3,7 @@ package db
 import (
 	"context"
 	"errors"
+	"fmt"
 	"log"
 
 	"github.com/Rishabh-Kapri/pennywise/backend/shared/model"
@@ -21,6 +22,9 @@ type PayeesRepository interface {
 	Create(ctx context.Context, tx pgx.Tx, payee model.Payee) (*model.Payee, error)
 	DeleteById(ctx context.Context, budgetId uuid.UUID, id uuid.UUID) error
 	Update(ctx context.Context, budgetId uuid.UUID, id uuid.UUID, payee model.Payee) error
+	// Merge repoints everything that references the source payees to the target payee and
+	// soft deletes the sources. The counts of the result are filled in, the payees are not.
+	Merge(ctx context.Context, tx pgx.Tx, budgetId uuid.UUID, targetId uuid.UUID, sourceIds []uuid.UUID) (*model.PayeeMergeResult, error)
 }
 
 type payeeRepo struct {
@@ -35,7 +39,7 @@ func (r *payeeRepo) GetAll(ctx context.Context, budgetId uuid.UUID) ([]model.Pay
 	rows, err := r.Executor(nil).Query(
 		ctx, `
 		SELECT id, name, budget_id, transfer_account_id, created_at, updated_at
-		FROM payees WHERE budget_id = $1 AND deleted = FALSE`,
+		FROM payees WHERE budget_id = $1 AND deleted = FALSE AND hidden = FALSE`,
 		budgetId,
 	)
 	if err != nil {
@@ -85,7 +89,7 @@ func (r *payeeRepo) GetById(ctx context.Context, budgetId, id uuid.UUID) (*model
 	var payee model.Payee
 	err := r.Executor(nil).QueryRow(
 		ctx, `
-		  SELECT id, name, budget_id, transfer_account_id
+		  SELECT id, name, budget_id, transfer_account_id, hidden
 		  FROM payees
 		  WHERE id = $1 AND budget_id = $2 AND deleted = FALSE
 		`, id, budgetId,
@@ -94,6 +98,7 @@ func (r *payeeRepo) GetById(ctx context.Context, budgetId, id uuid.UUID) (*model
 		&payee.Name,
 		&payee.BudgetID,
 		&payee.TransferAccountID,
+		&payee.Hidden,
 	)
 	if err != nil {
 		return nil, err
@@ -105,7 +110,7 @@ func (r *payeeRepo) GetByIdTx(ctx context.Context, tx pgx.Tx, budgetId, id uuid.
 	var payee model.Payee
 	err := tx.QueryRow(
 		ctx, `
-		  SELECT id, name, budget_id, transfer_account_id
+		  SELECT id, name, budget_id, transfer_account_id, hidden
 		  FROM payees
 		  WHERE id = $1 AND budget_id = $2 AND deleted = FALSE
 		`, id, budgetId,
@@ -114,6 +119,7 @@ func (r *payeeRepo) GetByIdTx(ctx context.Context, tx pgx.Tx, budgetId, id uuid.
 		&payee.Name,
 		&payee.BudgetID,
 		&payee.TransferAccountID,
+		&payee.Hidden,
 	)
 	if err != nil {
 		return nil, err
@@ -171,3 +177,117 @@ func (r *payeeRepo) Update(ctx context.Context, budgetId uuid.UUID, id uuid.UUID
 
 	return err
 }
+
+func (r *payeeRepo) Merge(
+	ctx context.Context,
+	tx pgx.Tx,
+	budgetId uuid.UUID,
+	targetId uuid.UUID,
+	sourceIds []uuid.UUID,
+) (*model.PayeeMergeResult, error) {
+	executor := r.Executor(tx)
+	result := &model.PayeeMergeResult{MergedPayeeIDs: sourceIds}
+
+	// deleted transactions are moved too so that restoring one from the trash doesn't revive a merged payee
+	cmdTag, err := executor.Exec(
+		ctx,
+		`UPDATE transactions SET payee_id = $2, updated_at = NOW() WHERE budget_id = $1 AND payee_id = ANY($3)`,
+		budgetId, targetId, sourceIds,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("error moving transactions: %w", err)
+	}
+	result.Transactions = cmdTag.RowsAffected()
+
+	// match strings are unique per budget, so the rules move over without conflicts
+	cmdTag, err = executor.Exec(
+		ctx,
+		`UPDATE payee_rules SET payee_id = $2, updated_at = NOW()
+		WHERE budget_id = $1 AND payee_id = ANY($3) AND deleted = FALSE`,
+		budgetId, targetId, sourceIds,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("error moving payee rules: %w", err)
+	}
+	result.Rules = cmdTag.RowsAffected()
+
+	cmdTag, err = executor.Exec(
+		ctx,
+		`UPDATE transaction_embeddings SET payee_id = $2, updated_at = NOW() WHERE budget_id = $1 AND payee_id = ANY($3)`,
+		budgetId, targetId, sourceIds,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("error moving transaction embeddings: %w", err)
+	}
+	result.TransactionEmbeddings = cmdTag.RowsAffected()
+
+	// a payee has a single entity embedding. The oldest source embedding is kept for a target
+	// without one, the rest are dropped.
+	cmdTag, err = executor.Exec(
+		ctx, `
+		WITH keep AS (
+		  SELECT id FROM entity_embeddings
+		  WHERE budget_id = $1 AND entity_type = 'payee' AND entity_id = ANY($3)
+		    AND NOT EXISTS (
+		      SELECT 1 FROM entity_embeddings
+		      WHERE budget_id = $1 AND entity_type = 'payee' AND entity_id = $2
+		    )
+		  ORDER BY created_at
+		  LIMIT 1
+		), moved AS (
+		  UPDATE entity_embeddings SET entity_id = $2, updated_at = NOW()
+		  WHERE id IN (SELECT id FROM keep)
+		  RETURNING id
+		), dropped AS (
+		  DELETE FROM entity_embeddings
+		  WHERE budget_id = $1 AND entity_type = 'payee' AND entity_id = ANY($3)
+		    AND id NOT IN (SELECT id FROM keep)
+		)
+		SELECT 1 FROM moved
+		`,
+		budgetId, targetId, sourceIds,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("error moving entity embeddings: %w", err)
+	}
+	result.EntityEmbeddings = cmdTag.RowsAffected()
+
+	cmdTag, err = executor.Exec(
+		ctx, `
+		UPDATE cipher_predictions SET
+		  predicted_payee_id = CASE WHEN predicted_payee_id = ANY($3) THEN $2 ELSE predicted_payee_id END,
+		  actual_payee_id = CASE WHEN actual_payee_id = ANY($3) THEN $2 ELSE actual_payee_id END,
+		  updated_at = NOW()
+		WHERE budget_id = $1 AND (predicted_payee_id = ANY($3) OR actual_payee_id = ANY($3))
+		`,
+		budgetId, targetId, sourceIds,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("error moving predictions: %w", err)
+	}
+	result.Predictions = cmdTag.RowsAffected()
+
+	for _, table := range []string{"scheduled_transactions", "transaction_templates"} {
+		_, err = executor.Exec(
+			ctx,
+			`UPDATE `+table+` SET payee_id = $2, updated_at = NOW() WHERE budget_id = $1 AND payee_id = ANY($3)`,
+			budgetId, targetId, sourceIds,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("error moving %s: %w", table, err)
+		}
+	}
+
+	cmdTag, err = executor.Exec(
+		ctx,
+		`UPDATE payees SET deleted = TRUE, updated_at = NOW() WHERE budget_id = $1 AND id = ANY($2) AND deleted = FALSE`,
+		budgetId, sourceIds,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("error deleting merged payees: %w", err)
+	}
+	if cmdTag.RowsAffected() != int64(len(sourceIds)) {
+		return nil, fmt.Errorf("expected to delete %d merged payees, deleted %d", len(sourceIds), cmdTag.RowsAffected())
+	}
+	return result, nil
+}
@@ -0,0 +1,233 @@
+package db
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Rishabh-Kapri/pennywise/backend/shared/model"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type ScheduledTransactionRepository interface {
+	BaseRepositoryInterface
+	GetAll(ctx context.Context, budgetId uuid.UUID) ([]model.ScheduledTransaction, error)
+	GetById(ctx context.Context, budgetId uuid.UUID, id uuid.UUID) (*model.ScheduledTransaction, error)
+	// GetDue returns the scheduled transactions of all budgets whose next occurrence is on or before asOf
+	GetDue(ctx context.Context, asOf string) ([]model.ScheduledTransaction, error)
+	Create(ctx context.Context, st model.ScheduledTransaction) (*model.ScheduledTransaction, error)
+	Update(ctx context.Context, budgetId uuid.UUID, id uuid.UUID, st model.ScheduledTransaction) error
+	UpdateNextDate(ctx context.Context, tx pgx.Tx, budgetId uuid.UUID, id uuid.UUID, nextDate *model.Date) error
+	DeleteById(ctx context.Context, budgetId uuid.UUID, id uuid.UUID) error
+}
+
+type scheduledTransactionRepo struct {
+	BaseRepository
+}
+
+func NewScheduledTransactionRepository(pool *pgxpool.Pool) ScheduledTransactionRepository {
+	return &scheduledTransactionRepo{BaseRepository: NewBaseRepository(pool)}
+}
+
+const scheduledTransactionColumns = `
+	id,
+	budget_id,
+	account_id,
+	payee_id,
+	category_id,
+	amount,
+	note,
+	tag_ids,
+	frequency,
+	interval_count,
+	day_of_month,
+	start_date,
+	end_date,
+	next_date,
+	created_at,
+	updated_at`
+
+func scanScheduledTransaction(row pgx.Row) (*model.ScheduledTransaction, error) {
+	var st model.ScheduledTransaction
+	err := row.Scan(
+		&st.ID,
+		&st.BudgetID,
+		&st.AccountID,
+		&st.PayeeID,
+		&st.CategoryID,
+		&st.Amount,
+		&st.Note,
+		&st.TagIDs,
+		&st.Frequency,
+		&st.Interval,
+		&st.DayOfMonth,
+		&st.StartDate,
+		&st.EndDate,
+		&st.NextDate,
+		&st.CreatedAt,
+		&st.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &st, nil
+}
+
+func (r *scheduledTransactionRepo) query(
+	ctx context.Context,
+	sql string,
+	args ...any,
+) ([]model.ScheduledTransaction, error) {
+	rows, err := r.Executor(nil).Query(ctx, sql, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var scheduled []model.ScheduledTransaction
+	for rows.Next() {
+		st, err := scanScheduledTransaction(rows)
+		if err != nil {
+			return nil, fmt.Errorf("error while parsing scheduled_transactions rows: %w", err)
+		}
+		scheduled = append(scheduled, *st)
+	}
+	return scheduled, rows.Err()
+}
+
+func (r *scheduledTransactionRepo) GetAll(ctx context.Context, budgetId uuid.UUID) ([]model.ScheduledTransaction, error) {
+	return r.query(
+		ctx,
+		`SELECT `+scheduledTransactionColumns+`
+		FROM scheduled_transactions
+		WHERE budget_id = $1 AND deleted = FALSE
+		ORDER BY next_date ASC NULLS LAST, created_at ASC`,
+		budgetId,
+	)
+}
+
+func (r *scheduledTransactionRepo) GetById(
+	ctx context.Context,
+	budgetId uuid.UUID,
+	id uuid.UUID,
+) (*model.ScheduledTransaction, error) {
+	return scanScheduledTransaction(r.Executor(nil).QueryRow(
+		ctx,
+		`SELECT `+scheduledTransactionColumns+`
+		FROM scheduled_transactions
+		WHERE budget_id = $1 AND id = $2 AND deleted = FALSE`,
+		budgetId, id,
+	))
+}
+
+func (r *scheduledTransactionRepo) GetDue(ctx context.Context, asOf string) ([]model.ScheduledTransaction, error) {
+	return r.query(
+		ctx,
+		`SELECT `+scheduledTransactionColumns+`
+		FROM scheduled_transactions
+		WHERE deleted = FALSE AND next_date IS NOT NULL AND next_date <= $1
+		ORDER BY budget_id, next_date ASC`,
+		asOf,
+	)
+}
+
+func (r *scheduledTransactionRepo) Create(
+	ctx context.Context,
+	st model.ScheduledTransaction,
+) (*model.ScheduledTransaction, error) {
+	if st.TagIDs == nil {
+		st.TagIDs = []uuid.UUID{}
+	}
+	return scanScheduledTransaction(r.Executor(nil).QueryRow(
+		ctx, `
+		INSERT INTO scheduled_transactions (
+			budget_id, account_id, payee_id, category_id, amount, note, tag_ids,
+			frequency, interval_count, day_of_month, start_date, end_date, next_date
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)
+		RETURNING `+scheduledTransactionColumns,
+		st.BudgetID, st.AccountID, st.PayeeID, st.CategoryID, st.Amount, st.Note, st.TagIDs,
+		st.Frequency, st.Interval, st.DayOfMonth, st.StartDate, st.EndDate, st.NextDate,
+	))
+}
+
+func (r *scheduledTransactionRepo) Update(
+	ctx context.Context,
+	budgetId uuid.UUID,
+	id uuid.UUID,
+	st model.ScheduledTransaction,
+) error {
+	if st.TagIDs == nil {
+		st.TagIDs = []uuid.UUID{}
+	}
+	cmdTag, err := r.Executor(nil).Exec(
+		ctx, `
+		UPDATE scheduled_transactions SET
+			account_id = $1,
+			payee_id = $2,
+			category_id = $3,
+			amount = $4,
+			note = $5,
+			tag_ids = $6,
+			frequency = $7,
+			interval_count = $8,
+			day_of_month = $9,
+			start_date = $10,
+			end_date = $11,
+			next_date = $12,
+			updated_at = NOW()
+		WHERE budget_id = $13 AND id = $14 AND deleted = FALSE
+		`,
+		st.AccountID, st.PayeeID, st.CategoryID, st.Amount, st.Note, st.TagIDs,
+		st.Frequency, st.Interval, st.DayOfMonth, st.StartDate, st.EndDate, st.NextDate,
+		budgetId, id,
+	)
+	if err != nil {
+		return err
+	}
+	if cmdTag.RowsAffected() == 0 {
+		return fmt.Errorf("Scheduled transaction not found for id: %v", id)
+	}
+	return nil
+}
+
+func (r *scheduledTransactionRepo) UpdateNextDate(
+	ctx context.Context,
+	tx pgx.Tx,
+	budgetId uuid.UUID,
+	id uuid.UUID,
+	nextDate *model.Date,
+) error {
+	cmdTag, err := r.Executor(tx).Exec(
+		ctx, `
+		UPDATE scheduled_transactions
+		SET next_date = $1, updated_at = NOW()
+		WHERE budget_id = $2 AND id = $3
+		`, nextDate, budgetId, id,
+	)
+	if err != nil {
+		return err
+	}
+	if cmdTag.RowsAffected() == 0 {
+		return fmt.Errorf("Scheduled transaction not found for id: %v", id)
+	}
+	return nil
+}
+
+func (r *scheduledTransactionRepo) DeleteById(ctx context.Context, budgetId uuid.UUID, id uuid.UUID) error {
+	cmdTag, err := r.Executor(nil).Exec(
+		ctx, `
+		UPDATE scheduled_transactions
+		SET deleted = TRUE, updated_at = NOW()
+		WHERE budget_id = $1 AND id = $2 AND deleted = FALSE
+		`, budgetId, id,
+	)
+	if err != nil {
+		return err
+	}
+	if cmdTag.RowsAffected() == 0 {
+		return fmt.Errorf("No active scheduled transaction found with the given id and budgetId")
+	}
+	return nil
+}
@@ -2,6 +2,7 @@ package db
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"time"
 
@@ -21,6 +22,15 @@ type TransactionRepository interface {
 	GetAll(ctx context.Context, budgetId uuid.UUID, filter *model.TransactionFilter) ([]model.Transaction, error)
 	GetById(ctx context.Context, budgetId uuid.UUID, id uuid.UUID) (*model.Transaction, error)
 	GetByIdTx(ctx context.Context, tx pgx.Tx, budgetId uuid.UUID, id uuid.UUID) (*model.Transaction, error)
+	// GetForRewriteTx locks and returns the live transactions a bulk rewrite is about to change, matching
+	// every filter set on accounts, payees, cleared status and categories. A category matches the
+	// transaction or any of its split lines.
+	GetForRewriteTx(
+		ctx context.Context,
+		tx pgx.Tx,
+		budgetId uuid.UUID,
+		filter model.TransactionFilter,
+	) ([]model.Transaction, error)
 	GetAllNormalized(
 		ctx context.Context,
 		budgetId uuid.UUID,
@@ -28,10 +38,49 @@ type TransactionRepository interface {
 	) (model.PaginatedResponse[model.Transaction], error)
 	Update(ctx context.Context, tx pgx.Tx, budgetId uuid.UUID, id uuid.UUID, txn model.Transaction) error
 	UpdateStatus(ctx context.Context, tx pgx.Tx, budgetId uuid.UUID, id uuid.UUID, status model.TransactionStatus) error
+	MarkReconciled(ctx context.Context, tx pgx.Tx, budgetId uuid.UUID, accountId uuid.UUID) (int64, error)
+	// Create returns no transaction when one with the same dedupe hash already exists
 	Create(ctx context.Context, tx pgx.Tx, txn model.Transaction) ([]model.Transaction, error)
 	DeleteById(ctx context.Context, tx pgx.Tx, budgetId uuid.UUID, id uuid.UUID) error
+	Restore(ctx context.Context, tx pgx.Tx, budgetId uuid.UUID, id uuid.UUID) error
+	ReplaceSplits(
+		ctx context.Context,
+		tx pgx.Tx,
+		budgetId uuid.UUID,
+		txnId uuid.UUID,
+		splits []model.TransactionSplit,
+	) error
+	// GetTransferCandidates returns every outflow and inflow pair of equal amount across two accounts
+	// of the same currency that are at most windowDays apart, closest first. Transfers, split and
+	// rejected transactions are left out. A transaction can show up in more than one pair.
+	GetTransferCandidates(ctx context.Context, budgetId uuid.UUID, windowDays int) ([]model.TransferMatch, error)
 }
 
+// transactionSplitsColumn selects the split lines of a transaction as a json array
+const transactionSplitsColumn = `COALESCE((
+		SELECT json_agg(json_build_object(
+			'id', ts.id,
+			'transactionId', ts.transaction_id,
+			'categoryId', ts.category_id,
+			'categoryName', sc.name,
+			'amount', ts.amount,
+			'note', ts.note
+		) ORDER BY ts.position)
+		FROM transaction_splits ts
+		LEFT JOIN categories sc ON ts.category_id = sc.id
+		WHERE ts.transaction_id = transactions.id AND ts.deleted = FALSE
+	), '[]'::json) AS splits`
+
+// transactionSearchRank scores a search match, the first placeholder is the tsquery text and the
+// second the trigram text. Both take the raw search query.
+const transactionSearchRank = `(
+		ts_rank(transactions.search_vector, websearch_to_tsquery('simple', ?)) +
+		word_similarity(?, transactions.search_text)
+	)::float8`
+
+// transactionSearchHeadlineOptions highlights the matched words of a search snippet
+const transactionSearchHeadlineOptions = "StartSel=<mark>, StopSel=</mark>, MaxWords=20, MinWords=5, MaxFragments=2"
+
 type transactionRepo struct {
 	BaseRepository
 }
@@ -55,8 +104,12 @@ func (r *transactionRepo) GetAll(
 			note,
 			amount,
 			status,
+			cleared,
 			raw_bank_text,
 			summary,
+			original_amount,
+			original_currency,
+			fx_rate,
 			transfer_account_id,
 			transfer_transaction_id,
 			tag_ids,
@@ -94,8 +147,12 @@ func (r *transactionRepo) GetAll(
 			&txn.Note,
 			&txn.Amount,
 			&txn.Status,
+			&txn.Cleared,
 			&txn.RawBankText,
 			&txn.Summary,
+			&txn.OriginalAmount,
+			&txn.OriginalCurrency,
+			&txn.FxRate,
 			&txn.TransferAccountID,
 			&txn.TransferTransactionID,
 			&txn.TagIDs,
@@ -124,8 +181,12 @@ func (r *transactionRepo) GetById(ctx context.Context, budgetId uuid.UUID, id uu
 				transactions.note,
 				transactions.amount,
 				transactions.status,
+				transactions.cleared,
 		    transactions.raw_bank_text,
 				transactions.summary,
+				transactions.original_amount,
+				transactions.original_currency,
+				transactions.fx_rate,
 				transactions.transfer_account_id,
 				transactions.transfer_transaction_id,
 				transactions.tag_ids,
@@ -133,7 +194,8 @@ func (r *transactionRepo) GetById(ctx context.Context, budgetId uuid.UUID, id uu
 				transactions.updated_at,
 				accounts.name AS account_name,
 				payees.name AS payee_name,
-				categories.name AS category_name
+				categories.name AS category_name,
+				`+transactionSplitsColumn+`
 		  FROM transactions
 		  LEFT JOIN accounts    ON transactions.account_id = accounts.id
 		  LEFT JOIN payees     ON transactions.payee_id = payees.id
@@ -150,8 +212,12 @@ func (r *transactionRepo) GetById(ctx context.Context, budgetId uuid.UUID, id uu
 		&txn.Note,
 		&txn.Amount,
 		&txn.Status,
+		&txn.Cleared,
 		&txn.RawBankText,
 		&txn.Summary,
+		&txn.OriginalAmount,
+		&txn.OriginalCurrency,
+		&txn.FxRate,
 		&txn.TransferAccountID,
 		&txn.TransferTransactionID,
 		&txn.TagIDs,
@@ -160,6 +226,7 @@ func (r *transactionRepo) GetById(ctx context.Context, budgetId uuid.UUID, id uu
 		&txn.AccountName,
 		&txn.PayeeName,
 		&txn.CategoryName,
+		&txn.Splits,
 	)
 	if err != nil {
 		return nil, err
@@ -186,8 +253,12 @@ func (r *transactionRepo) GetByIdTx(
 				transactions.note,
 				transactions.amount,
 				transactions.status,
+				transactions.cleared,
 				transactions.raw_bank_text,
 				transactions.summary,
+				transactions.original_amount,
+				transactions.original_currency,
+				transactions.fx_rate,
 				transactions.transfer_account_id,
 				transactions.transfer_transaction_id,
 				transactions.tag_ids,
@@ -195,7 +266,8 @@ func (r *transactionRepo) GetByIdTx(
 				transactions.updated_at,
 				accounts.name AS account_name,
 				payees.name AS payee_name,
-				categories.name AS category_name
+				categories.name AS category_name,
+				`+transactionSplitsColumn+`
 		  FROM transactions
 		  LEFT JOIN accounts    ON transactions.account_id = accounts.id
 		  LEFT JOIN payees     ON transactions.payee_id = payees.id
@@ -212,8 +284,12 @@ func (r *transactionRepo) GetByIdTx(
 		&txn.Note,
 		&txn.Amount,
 		&txn.Status,
+		&txn.Cleared,
 		&txn.RawBankText,
 		&txn.Summary,
+		&txn.OriginalAmount,
+		&txn.OriginalCurrency,
+		&txn.FxRate,
 		&txn.TransferAccountID,
 		&txn.TransferTransactionID,
 		&txn.TagIDs,
@@ -222,6 +298,7 @@ func (r *transactionRepo) GetByIdTx(
 		&txn.AccountName,
 		&txn.PayeeName,
 		&txn.CategoryName,
+		&txn.Splits,
 	)
 	if err != nil {
 		return nil, err
@@ -229,6 +306,109 @@ func (r *transactionRepo) GetByIdTx(
 	return &txn, nil
 }
 
+func (r *transactionRepo) GetForRewriteTx(
+	ctx context.Context,
+	tx pgx.Tx,
+	budgetId uuid.UUID,
+	filter model.TransactionFilter,
+) ([]model.Transaction, error) {
+	sql := `
+		SELECT
+			transactions.id,
+			transactions.budget_id,
+			transactions.date,
+			transactions.payee_id,
+			transactions.category_id,
+			transactions.account_id,
+			transactions.note,
+			transactions.amount,
+			transactions.status,
+			transactions.cleared,
+			transactions.raw_bank_text,
+			transactions.summary,
+			transactions.original_amount,
+			transactions.original_currency,
+			transactions.fx_rate,
+			transactions.transfer_account_id,
+			transactions.transfer_transaction_id,
+			transactions.tag_ids,
+			transactions.created_at,
+			transactions.updated_at,
+			` + transactionSplitsColumn + `
+		FROM transactions
+		WHERE transactions.budget_id = $1 AND transactions.deleted = FALSE`
+	args := []any{budgetId}
+	if len(filter.AccountIDs) > 0 {
+		args = append(args, filter.AccountIDs)
+		sql += fmt.Sprintf(" AND transactions.account_id = ANY($%d)", len(args))
+	}
+	if len(filter.PayeeIDs) > 0 {
+		args = append(args, filter.PayeeIDs)
+		sql += fmt.Sprintf(" AND transactions.payee_id = ANY($%d)", len(args))
+	}
+	if len(filter.Cleared) > 0 {
+		cleared := make([]string, 0, len(filter.Cleared))
+		for _, status := range filter.Cleared {
+			cleared = append(cleared, string(status))
+		}
+		args = append(args, cleared)
+		sql += fmt.Sprintf(" AND transactions.cleared::text = ANY($%d)", len(args))
+	}
+	if len(filter.CategoryIDs) > 0 {
+		args = append(args, filter.CategoryIDs)
+		sql += fmt.Sprintf(` AND (
+			transactions.category_id = ANY($%[1]d) OR EXISTS (
+				SELECT 1 FROM transaction_splits
+				WHERE transaction_splits.transaction_id = transactions.id
+					AND transaction_splits.category_id = ANY($%[1]d)
+					AND transaction_splits.deleted = FALSE
+			)
+		)`, len(args))
+	}
+	sql += "\nORDER BY transactions.id\nFOR UPDATE OF transactions"
+
+	rows, err := r.Executor(tx).Query(ctx, sql, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	txns := []model.Transaction{}
+	for rows.Next() {
+		var txn model.Transaction
+		if err := rows.Scan(
+			&txn.ID,
+			&txn.BudgetID,
+			&txn.Date,
+			&txn.PayeeID,
+			&txn.CategoryID,
+			&txn.AccountID,
+			&txn.Note,
+			&txn.Amount,
+			&txn.Status,
+			&txn.Cleared,
+			&txn.RawBankText,
+			&txn.Summary,
+			&txn.OriginalAmount,
+			&txn.OriginalCurrency,
+			&txn.FxRate,
+			&txn.TransferAccountID,
+			&txn.TransferTransactionID,
+			&txn.TagIDs,
+			&txn.CreatedAt,
+			&txn.UpdatedAt,
+			&txn.Splits,
+		); err != nil {
+			return nil, err
+		}
+		txns = append(txns, txn)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return txns, nil
+}
+
 func (r *transactionRepo) GetAllNormalized(
 	ctx context.Context,
 	budgetId uuid.UUID,
@@ -250,10 +430,13 @@ func (r *transactionRepo) GetAllNormalized(
 
 	isFirstPage := filter == nil || filter.CursorString == ""
 	pointsNext := false
+	search := filter != nil && filter.Query != nil
+	byRelevance := search && filter.SortOrder == model.SortOrderRelevance
 
 	var cursorDate model.Date
 	var cursorUpdatedAt time.Time
 	var cursorID uuid.UUID
+	var cursorRank float64
 
 	logger.Logger(ctx).Info("filter", "filter", filter)
 
@@ -283,6 +466,13 @@ func (r *transactionRepo) GetAllNormalized(
 		if err != nil {
 			return model.PaginatedResponse[model.Transaction]{}, err
 		}
+
+		if byRelevance {
+			if decodedCursor.Rank == nil {
+				return model.PaginatedResponse[model.Transaction]{}, errs.New(errs.CodeInvalidArgument, "invalid cursor")
+			}
+			cursorRank = *decodedCursor.Rank
+		}
 	}
 
 	query := sq.StatementBuilder.PlaceholderFormat(sq.Dollar).
@@ -297,8 +487,12 @@ func (r *transactionRepo) GetAllNormalized(
 			"transactions.amount",
 			"transactions.dedupe_hash",
 			"transactions.status",
+			"transactions.cleared",
 			"transactions.raw_bank_text",
 			"transactions.summary",
+			"transactions.original_amount",
+			"transactions.original_currency",
+			"transactions.fx_rate",
 			"transactions.transfer_account_id",
 			"transactions.transfer_transaction_id",
 			"transactions.tag_ids",
@@ -310,6 +504,7 @@ func (r *transactionRepo) GetAllNormalized(
 			"CASE WHEN transactions.amount >= 0 THEN transactions.amount ELSE 0 END AS inflow",
 			"CASE WHEN transactions.amount < 0 THEN ABS(transactions.amount) ELSE 0 END AS outflow",
 			balanceExpr,
+			transactionSplitsColumn,
 		).
 		From("transactions").
 		LeftJoin("accounts ON transactions.account_id = accounts.id").
@@ -318,6 +513,16 @@ func (r *transactionRepo) GetAllNormalized(
 		Where(sq.Eq{"transactions.budget_id": budgetId}).
 		Where(sq.Eq{"transactions.deleted": false})
 
+	if search {
+		query = query.
+			Column(sq.Expr(transactionSearchRank+" AS search_rank", *filter.Query, *filter.Query)).
+			Column(sq.Expr(
+				"ts_headline('simple', transactions.search_text, websearch_to_tsquery('simple', ?), ?) AS search_snippet",
+				*filter.Query,
+				transactionSearchHeadlineOptions,
+			))
+	}
+
 	countQuery := sq.StatementBuilder.PlaceholderFormat(sq.Dollar).
 		Select("COUNT(*)").
 		From("transactions").
@@ -337,17 +542,38 @@ func (r *transactionRepo) GetAllNormalized(
 		return model.PaginatedResponse[model.Transaction]{}, err
 	}
 
-	queryOrder := filter.SortOrder
+	// relevance pages like a descending sort with the rank as the leading cursor key
+	sortOrder := filter.SortOrder
+	if byRelevance {
+		sortOrder = "DESC"
+	}
+	queryOrder := sortOrder
 	if !isFirstPage && !pointsNext {
-		queryOrder = utils.ReverseSortOrder(filter.SortOrder)
+		queryOrder = utils.ReverseSortOrder(sortOrder)
 	}
 
-	if !isFirstPage {
+	if !isFirstPage && byRelevance {
+		query = query.Where(
+			sq.Expr(
+				fmt.Sprintf(
+					"(%s, transactions.date, transactions.updated_at, transactions.id) %s (?, ?, ?, ?)",
+					transactionSearchRank,
+					utils.CursorOperator(sortOrder, pointsNext),
+				),
+				*filter.Query,
+				*filter.Query,
+				cursorRank,
+				cursorDate,
+				cursorUpdatedAt,
+				cursorID,
+			),
+		)
+	} else if !isFirstPage {
 		query = query.Where(
 			sq.Expr(
 				fmt.Sprintf(
 					"(transactions.date, transactions.updated_at, transactions.id) %s (?, ?, ?)",
-					utils.CursorOperator(filter.SortOrder, pointsNext),
+					utils.CursorOperator(sortOrder, pointsNext),
 				),
 				cursorDate,
 				cursorUpdatedAt,
@@ -355,10 +581,19 @@ func (r *transactionRepo) GetAllNormalized(
 			),
 		)
 	}
-	query = query.OrderBy(
-		"transactions.date "+queryOrder,
-		"transactions.updated_at "+queryOrder,
-	)
+	if byRelevance {
+		query = query.OrderBy(
+			"search_rank "+queryOrder,
+			"transactions.date "+queryOrder,
+			"transactions.updated_at "+queryOrder,
+			"transactions.id "+queryOrder,
+		)
+	} else {
+		query = query.OrderBy(
+			"transactions.date "+queryOrder,
+			"transactions.updated_at "+queryOrder,
+		)
+	}
 
 	query = query.Limit(uint64(limit + 1))
 
@@ -377,7 +612,7 @@ func (r *transactionRepo) GetAllNormalized(
 	for rows.Next() {
 		var txn model.Transaction
 		var status *model.TransactionStatus
-		err := rows.Scan(
+		dest := []any{
 			&txn.ID,
 			&txn.BudgetID,
 			&txn.Date,
@@ -388,8 +623,12 @@ func (r *transactionRepo) GetAllNormalized(
 			&txn.Amount,
 			&txn.DedupeHash,
 			&status,
+			&txn.Cleared,
 			&txn.RawBankText,
 			&txn.Summary,
+			&txn.OriginalAmount,
+			&txn.OriginalCurrency,
+			&txn.FxRate,
 			&txn.TransferAccountID,
 			&txn.TransferTransactionID,
 			&txn.TagIDs,
@@ -401,7 +640,12 @@ func (r *transactionRepo) GetAllNormalized(
 			&txn.Inflow,
 			&txn.Outflow,
 			&txn.Balance,
-		)
+			&txn.Splits,
+		}
+		if search {
+			dest = append(dest, &txn.SearchRank, &txn.SearchSnippet)
+		}
+		err := rows.Scan(dest...)
 		if err != nil {
 			return model.PaginatedResponse[model.Transaction]{}, err
 		}
@@ -441,13 +685,27 @@ func applyTransactionFilters(query sq.SelectBuilder, filter *model.TransactionFi
 	}
 
 	if len(filter.CategoryIDs) > 0 {
-		query = query.Where(sq.Eq{"transactions.category_id": filter.CategoryIDs})
+		// split transactions match on any of their lines
+		query = query.Where(sq.Or{
+			sq.Eq{"transactions.category_id": filter.CategoryIDs},
+			sq.Expr(
+				`EXISTS (
+					SELECT 1 FROM transaction_splits ts
+					WHERE ts.transaction_id = transactions.id AND ts.deleted = FALSE AND ts.category_id = ANY(?)
+				)`,
+				filter.CategoryIDs,
+			),
+		})
 	}
 
 	if len(filter.PayeeIDs) > 0 {
 		query = query.Where(sq.Eq{"transactions.payee_id": filter.PayeeIDs})
 	}
 
+	if len(filter.Cleared) > 0 {
+		query = query.Where(sq.Eq{"transactions.cleared": filter.Cleared})
+	}
+
 	if filter.StartDate != nil {
 		query = query.Where(sq.GtOrEq{"transactions.date": *filter.StartDate})
 	}
@@ -460,6 +718,15 @@ func applyTransactionFilters(query sq.SelectBuilder, filter *model.TransactionFi
 		query = query.Where(sq.Expr("transactions.note ILIKE ?", "%"+*filter.Note+"%"))
 	}
 
+	if filter.Query != nil {
+		// full-text matches whole words, the trigram match catches partial words and typos
+		query = query.Where(sq.Expr(
+			"(transactions.search_vector @@ websearch_to_tsquery('simple', ?) OR ? <% transactions.search_text)",
+			*filter.Query,
+			*filter.Query,
+		))
+	}
+
 	return query
 }
 
@@ -469,6 +736,7 @@ func transactionCursor(txn model.Transaction, pointsNext bool) model.Cursor {
 		Date:       txn.Date.String(),
 		UpdatedAt:  txn.UpdatedAt,
 		PointsNext: pointsNext,
+		Rank:       txn.SearchRank,
 	}
 }
 
@@ -490,9 +758,17 @@ func (r *transactionRepo) Create(ctx context.Context, tx pgx.Tx, txn model.Trans
 			summary,
 		  transfer_account_id,
 		  transfer_transaction_id,
-		  tag_ids
-		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14)
-		RETURNING id, amount, budget_id, status, summary`,
+		  tag_ids,
+		  cleared,
+		  original_amount,
+		  original_currency,
+		  fx_rate
+		) VALUES (
+		  $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14,
+		  COALESCE($15::cleared_status, 'UNCLEARED'), $16, $17, $18
+		)
+		ON CONFLICT (budget_id, dedupe_hash) WHERE dedupe_hash IS NOT NULL AND deleted = FALSE DO NOTHING
+		RETURNING id, amount, budget_id, status, cleared, summary`,
 		txn.BudgetID,
 		txn.Date,
 		txn.PayeeID,
@@ -507,7 +783,21 @@ func (r *transactionRepo) Create(ctx context.Context, tx pgx.Tx, txn model.Trans
 		txn.TransferAccountID,
 		txn.TransferTransactionID,
 		txn.TagIDs,
-	).Scan(&createdTxn.ID, &createdTxn.Amount, &createdTxn.BudgetID, &createdTxn.Status, &createdTxn.Summary)
+		clearedParam(txn.Cleared),
+		txn.OriginalAmount,
+		txn.OriginalCurrency,
+		txn.FxRate,
+	).Scan(
+		&createdTxn.ID,
+		&createdTxn.Amount,
+		&createdTxn.BudgetID,
+		&createdTxn.Status,
+		&createdTxn.Cleared,
+		&createdTxn.Summary,
+	)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return []model.Transaction{}, nil
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -535,8 +825,12 @@ func (r *transactionRepo) Update(
 				transfer_transaction_id = $8,
 				tag_ids = $9,
 				status = $10,
+				cleared = COALESCE($11::cleared_status, cleared),
+				original_amount = $14,
+				original_currency = $15,
+				fx_rate = $16,
 				updated_at = NOW()
-		  WHERE budget_id = $11 AND id = $12
+		  WHERE budget_id = $12 AND id = $13
 		`, txn.Date,
 		txn.PayeeID,
 		txn.CategoryID,
@@ -547,8 +841,12 @@ func (r *transactionRepo) Update(
 		txn.TransferTransactionID,
 		txn.TagIDs,
 		txn.Status,
+		clearedParam(txn.Cleared),
 		budgetId,
 		id,
+		txn.OriginalAmount,
+		txn.OriginalCurrency,
+		txn.FxRate,
 	)
 	if err != nil {
 		return err
@@ -581,6 +879,34 @@ func (r *transactionRepo) UpdateStatus(
 	return nil
 }
 
+// MarkReconciled locks all cleared transactions of an account by moving them to reconciled
+func (r *transactionRepo) MarkReconciled(
+	ctx context.Context,
+	tx pgx.Tx,
+	budgetId uuid.UUID,
+	accountId uuid.UUID,
+) (int64, error) {
+	cmdTag, err := r.Executor(tx).Exec(
+		ctx, `
+			UPDATE transactions
+			SET cleared = 'RECONCILED', updated_at = NOW()
+			WHERE budget_id = $1 AND account_id = $2 AND cleared = 'CLEARED' AND deleted = FALSE
+		`, budgetId, accountId,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return cmdTag.RowsAffected(), nil
+}
+
+// clearedParam maps an unset cleared status to NULL so the column default or current value is kept
+func clearedParam(cleared model.ClearedStatus) *model.ClearedStatus {
+	if cleared == "" {
+		return nil
+	}
+	return &cleared
+}
+
 func (r *transactionRepo) DeleteById(ctx context.Context, tx pgx.Tx, budgetId uuid.UUID, id uuid.UUID) error {
 	cmdTag, err := r.Executor(tx).Exec(
 		ctx, `
@@ -598,3 +924,127 @@ func (r *transactionRepo) DeleteById(ctx context.Context, tx pgx.Tx, budgetId uu
 
 	return nil
 }
+
+// Restore brings back a soft deleted transaction, its split lines are left untouched on delete
+func (r *transactionRepo) Restore(ctx context.Context, tx pgx.Tx, budgetId uuid.UUID, id uuid.UUID) error {
+	cmdTag, err := r.Executor(tx).Exec(
+		ctx, `
+			UPDATE transactions
+			SET deleted = FALSE, updated_at = NOW()
+			WHERE budget_id = $1 AND id = $2 AND deleted = TRUE
+			`, budgetId, id,
+	)
+	if err != nil {
+		return err
+	}
+	if cmdTag.RowsAffected() == 0 {
+		return fmt.Errorf("No deleted transaction found for id: %v", id)
+	}
+	return nil
+}
+
+// ReplaceSplits soft deletes the existing split lines of a transaction and inserts the given ones
+func (r *transactionRepo) ReplaceSplits(
+	ctx context.Context,
+	tx pgx.Tx,
+	budgetId uuid.UUID,
+	txnId uuid.UUID,
+	splits []model.TransactionSplit,
+) error {
+	_, err := r.Executor(tx).Exec(
+		ctx, `
+			UPDATE transaction_splits
+			SET deleted = TRUE, updated_at = NOW()
+			WHERE budget_id = $1 AND transaction_id = $2 AND deleted = FALSE
+		`, budgetId, txnId,
+	)
+	if err != nil {
+		return err
+	}
+
+	for i, split := range splits {
+		_, err = r.Executor(tx).Exec(
+			ctx, `
+				INSERT INTO transaction_splits (budget_id, transaction_id, category_id, amount, note, position)
+				VALUES ($1, $2, $3, $4, $5, $6)
+			`, budgetId, txnId, split.CategoryID, split.Amount, split.Note, i,
+		)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *transactionRepo) GetTransferCandidates(
+	ctx context.Context,
+	budgetId uuid.UUID,
+	windowDays int,
+) ([]model.TransferMatch, error) {
+	rows, err := r.Executor(nil).Query(
+		ctx, `
+		SELECT
+			o.id, o.date, o.account_id, oa.name, o.payee_id, op.name, o.category_id, o.amount, o.note, o.status, o.cleared,
+			i.id, i.date, i.account_id, ia.name, i.payee_id, ip.name, i.category_id, i.amount, i.note, i.status, i.cleared,
+			ABS(o.date::date - i.date::date) AS days_apart
+		FROM transactions o
+		JOIN accounts oa ON oa.id = o.account_id AND oa.deleted = FALSE
+		JOIN transactions i
+			ON i.budget_id = o.budget_id AND i.account_id <> o.account_id AND i.amount = -o.amount
+		JOIN accounts ia ON ia.id = i.account_id AND ia.deleted = FALSE AND ia.currency = oa.currency
+		LEFT JOIN payees op ON op.id = o.payee_id
+		LEFT JOIN payees ip ON ip.id = i.payee_id
+		WHERE o.budget_id = $1 AND o.amount < 0
+			AND o.deleted = FALSE AND i.deleted = FALSE
+			AND o.transfer_transaction_id IS NULL AND i.transfer_transaction_id IS NULL
+			AND o.status <> 'REJECTED' AND i.status <> 'REJECTED'
+			AND ABS(o.date::date - i.date::date) <= $2
+			AND NOT EXISTS (
+				SELECT 1 FROM transaction_splits ts
+				WHERE ts.transaction_id IN (o.id, i.id) AND ts.deleted = FALSE
+			)
+		ORDER BY days_apart ASC, o.date DESC, o.id, i.id`,
+		budgetId, windowDays,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	matches := make([]model.TransferMatch, 0)
+	for rows.Next() {
+		match := model.TransferMatch{}
+		err := rows.Scan(
+			&match.Outflow.ID,
+			&match.Outflow.Date,
+			&match.Outflow.AccountID,
+			&match.Outflow.AccountName,
+			&match.Outflow.PayeeID,
+			&match.Outflow.PayeeName,
+			&match.Outflow.CategoryID,
+			&match.Outflow.Amount,
+			&match.Outflow.Note,
+			&match.Outflow.Status,
+			&match.Outflow.Cleared,
+			&match.Inflow.ID,
+			&match.Inflow.Date,
+			&match.Inflow.AccountID,
+			&match.Inflow.AccountName,
+			&match.Inflow.PayeeID,
+			&match.Inflow.PayeeName,
+			&match.Inflow.CategoryID,
+			&match.Inflow.Amount,
+			&match.Inflow.Note,
+			&match.Inflow.Status,
+			&match.Inflow.Cleared,
+			&match.DaysApart,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("error while parsing transfer candidate rows: %w", err)
+		}
+		match.Outflow.BudgetID = budgetId
+		match.Inflow.BudgetID = budgetId
+		matches = append(matches, match)
+	}
+	return matches, rows.Err()
+}
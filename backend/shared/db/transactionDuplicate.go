@@ -0,0 +1,206 @@
+package db
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Rishabh-Kapri/pennywise/backend/shared/model"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type TransactionDuplicateRepository interface {
+	BaseRepositoryInterface
+	// Flag records the suspected duplicates of a transaction among the older transactions of
+	// its account, see model.DuplicateWindowDays. Pairs flagged before, including dismissed
+	// ones, are left as they are. It returns the number of new flags.
+	Flag(ctx context.Context, tx pgx.Tx, budgetId uuid.UUID, txnId uuid.UUID) (int64, error)
+	// GetAll returns the flags of a status along with both transactions, newest first.
+	// Flags of deleted transactions are left out.
+	GetAll(ctx context.Context, budgetId uuid.UUID, status model.DuplicateStatus) ([]model.TransactionDuplicate, error)
+	// GetById returns the flag without its transactions, pgx.ErrNoRows when it doesn't exist
+	GetById(ctx context.Context, tx pgx.Tx, budgetId uuid.UUID, id uuid.UUID) (*model.TransactionDuplicate, error)
+	UpdateStatus(ctx context.Context, tx pgx.Tx, budgetId uuid.UUID, id uuid.UUID, status model.DuplicateStatus) error
+}
+
+type transactionDuplicateRepo struct {
+	BaseRepository
+}
+
+func NewTransactionDuplicateRepository(pool *pgxpool.Pool) TransactionDuplicateRepository {
+	return &transactionDuplicateRepo{BaseRepository: NewBaseRepository(pool)}
+}
+
+func (r *transactionDuplicateRepo) Flag(
+	ctx context.Context,
+	tx pgx.Tx,
+	budgetId uuid.UUID,
+	txnId uuid.UUID,
+) (int64, error) {
+	cmdTag, err := r.Executor(tx).Exec(
+		ctx, `
+		INSERT INTO transaction_duplicates (budget_id, transaction_id, duplicate_of_id, score)
+		SELECT
+			n.budget_id,
+			n.id,
+			o.id,
+			CASE WHEN o.payee_id = n.payee_id THEN 1 ELSE similarity(o.search_text, n.search_text) END
+		FROM transactions n
+		JOIN transactions o
+			ON o.budget_id = n.budget_id AND o.account_id = n.account_id AND o.id <> n.id AND o.deleted = FALSE
+		WHERE n.budget_id = $1 AND n.id = $2
+			AND SIGN(o.amount) = SIGN(n.amount)
+			AND ABS(o.amount - n.amount) <= GREATEST($3, ABS(n.amount) * $4)
+			AND ABS(o.date::date - n.date::date) <= $5
+			AND (o.payee_id = n.payee_id OR similarity(o.search_text, n.search_text) >= $6)
+			AND o.transfer_transaction_id IS DISTINCT FROM n.id
+		ON CONFLICT (budget_id, transaction_id, duplicate_of_id) DO NOTHING`,
+		budgetId,
+		txnId,
+		model.DuplicateAmountTolerance,
+		model.DuplicateAmountToleranceRatio,
+		model.DuplicateWindowDays,
+		model.DuplicateMinSimilarity,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return cmdTag.RowsAffected(), nil
+}
+
+func (r *transactionDuplicateRepo) GetAll(
+	ctx context.Context,
+	budgetId uuid.UUID,
+	status model.DuplicateStatus,
+) ([]model.TransactionDuplicate, error) {
+	rows, err := r.Executor(nil).Query(
+		ctx, `
+		SELECT
+			d.id, d.budget_id, d.transaction_id, d.duplicate_of_id, d.score, d.status, d.created_at, d.updated_at,
+			n.date, n.account_id, na.name, n.payee_id, np.name, n.category_id, nc.name,
+			n.amount, n.note, n.raw_bank_text, n.status, n.cleared,
+			o.date, o.account_id, oa.name, o.payee_id, op.name, o.category_id, oc.name,
+			o.amount, o.note, o.raw_bank_text, o.status, o.cleared
+		FROM transaction_duplicates d
+		JOIN transactions n ON n.id = d.transaction_id AND n.deleted = FALSE
+		JOIN transactions o ON o.id = d.duplicate_of_id AND o.deleted = FALSE
+		LEFT JOIN accounts na ON na.id = n.account_id
+		LEFT JOIN payees np ON np.id = n.payee_id
+		LEFT JOIN categories nc ON nc.id = n.category_id
+		LEFT JOIN accounts oa ON oa.id = o.account_id
+		LEFT JOIN payees op ON op.id = o.payee_id
+		LEFT JOIN categories oc ON oc.id = o.category_id
+		WHERE d.budget_id = $1 AND d.status = $2
+		ORDER BY d.created_at DESC, d.score DESC`,
+		budgetId, status,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	duplicates := make([]model.TransactionDuplicate, 0)
+	for rows.Next() {
+		var duplicate model.TransactionDuplicate
+		txn := model.Transaction{BudgetID: budgetId}
+		original := model.Transaction{BudgetID: budgetId}
+		err := rows.Scan(
+			&duplicate.ID,
+			&duplicate.BudgetID,
+			&duplicate.TransactionID,
+			&duplicate.DuplicateOfID,
+			&duplicate.Score,
+			&duplicate.Status,
+			&duplicate.CreatedAt,
+			&duplicate.UpdatedAt,
+			&txn.Date,
+			&txn.AccountID,
+			&txn.AccountName,
+			&txn.PayeeID,
+			&txn.PayeeName,
+			&txn.CategoryID,
+			&txn.CategoryName,
+			&txn.Amount,
+			&txn.Note,
+			&txn.RawBankText,
+			&txn.Status,
+			&txn.Cleared,
+			&original.Date,
+			&original.AccountID,
+			&original.AccountName,
+			&original.PayeeID,
+			&original.PayeeName,
+			&original.CategoryID,
+			&original.CategoryName,
+			&original.Amount,
+			&original.Note,
+			&original.RawBankText,
+			&original.Status,
+			&original.Cleared,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("error while parsing transaction_duplicates rows: %w", err)
+		}
+		txn.ID = duplicate.TransactionID
+		original.ID = duplicate.DuplicateOfID
+		duplicate.Transaction = &txn
+		duplicate.DuplicateOf = &original
+		duplicates = append(duplicates, duplicate)
+	}
+	return duplicates, rows.Err()
+}
+
+func (r *transactionDuplicateRepo) GetById(
+	ctx context.Context,
+	tx pgx.Tx,
+	budgetId uuid.UUID,
+	id uuid.UUID,
+) (*model.TransactionDuplicate, error) {
+	var duplicate model.TransactionDuplicate
+	err := r.Executor(tx).QueryRow(
+		ctx, `
+		SELECT id, budget_id, transaction_id, duplicate_of_id, score, status, created_at, updated_at
+		FROM transaction_duplicates
+		WHERE budget_id = $1 AND id = $2`,
+		budgetId, id,
+	).Scan(
+		&duplicate.ID,
+		&duplicate.BudgetID,
+		&duplicate.TransactionID,
+		&duplicate.DuplicateOfID,
+		&duplicate.Score,
+		&duplicate.Status,
+		&duplicate.CreatedAt,
+		&duplicate.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &duplicate, nil
+}
+
+func (r *transactionDuplicateRepo) UpdateStatus(
+	ctx context.Context,
+	tx pgx.Tx,
+	budgetId uuid.UUID,
+	id uuid.UUID,
+	status model.DuplicateStatus,
+) error {
+	cmdTag, err := r.Executor(tx).Exec(
+		ctx,
+		`UPDATE transaction_duplicates SET
+		   status = $1,
+		   updated_at = NOW()
+		WHERE budget_id = $2 AND id = $3`,
+		status, budgetId, id,
+	)
+	if err != nil {
+		return err
+	}
+	if cmdTag.RowsAffected() == 0 {
+		return fmt.Errorf("Transaction duplicate not found for id: %v", id)
+	}
+	return nil
+}
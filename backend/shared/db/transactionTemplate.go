@@ -0,0 +1,197 @@
+package db
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Rishabh-Kapri/pennywise/backend/shared/model"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type TransactionTemplateRepository interface {
+	BaseRepositoryInterface
+	// GetAll returns the templates of a budget, most used first
+	GetAll(ctx context.Context, budgetId uuid.UUID) ([]model.TransactionTemplate, error)
+	// GetById returns pgx.ErrNoRows when the template doesn't exist or is deleted
+	GetById(ctx context.Context, budgetId uuid.UUID, id uuid.UUID) (*model.TransactionTemplate, error)
+	Create(ctx context.Context, template model.TransactionTemplate) (*model.TransactionTemplate, error)
+	Update(ctx context.Context, budgetId uuid.UUID, id uuid.UUID, template model.TransactionTemplate) error
+	// MarkUsed counts a transaction created from the template
+	MarkUsed(ctx context.Context, tx pgx.Tx, budgetId uuid.UUID, id uuid.UUID) error
+	DeleteById(ctx context.Context, budgetId uuid.UUID, id uuid.UUID) error
+}
+
+type transactionTemplateRepo struct {
+	BaseRepository
+}
+
+func NewTransactionTemplateRepository(pool *pgxpool.Pool) TransactionTemplateRepository {
+	return &transactionTemplateRepo{BaseRepository: NewBaseRepository(pool)}
+}
+
+const transactionTemplateColumns = `
+	id,
+	budget_id,
+	name,
+	account_id,
+	payee_id,
+	category_id,
+	amount,
+	note,
+	tag_ids,
+	use_count,
+	last_used_at,
+	created_at,
+	updated_at`
+
+func scanTransactionTemplate(row pgx.Row) (*model.TransactionTemplate, error) {
+	var template model.TransactionTemplate
+	err := row.Scan(
+		&template.ID,
+		&template.BudgetID,
+		&template.Name,
+		&template.AccountID,
+		&template.PayeeID,
+		&template.CategoryID,
+		&template.Amount,
+		&template.Note,
+		&template.TagIDs,
+		&template.UseCount,
+		&template.LastUsedAt,
+		&template.CreatedAt,
+		&template.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &template, nil
+}
+
+func (r *transactionTemplateRepo) GetAll(ctx context.Context, budgetId uuid.UUID) ([]model.TransactionTemplate, error) {
+	rows, err := r.Executor(nil).Query(
+		ctx,
+		`SELECT `+transactionTemplateColumns+`
+		FROM transaction_templates
+		WHERE budget_id = $1 AND deleted = FALSE
+		ORDER BY use_count DESC, last_used_at DESC NULLS LAST, name ASC`,
+		budgetId,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	templates := make([]model.TransactionTemplate, 0)
+	for rows.Next() {
+		template, err := scanTransactionTemplate(rows)
+		if err != nil {
+			return nil, fmt.Errorf("error while parsing transaction_templates rows: %w", err)
+		}
+		templates = append(templates, *template)
+	}
+	return templates, rows.Err()
+}
+
+func (r *transactionTemplateRepo) GetById(
+	ctx context.Context,
+	budgetId uuid.UUID,
+	id uuid.UUID,
+) (*model.TransactionTemplate, error) {
+	return scanTransactionTemplate(r.Executor(nil).QueryRow(
+		ctx,
+		`SELECT `+transactionTemplateColumns+`
+		FROM transaction_templates
+		WHERE budget_id = $1 AND id = $2 AND deleted = FALSE`,
+		budgetId, id,
+	))
+}
+
+func (r *transactionTemplateRepo) Create(
+	ctx context.Context,
+	template model.TransactionTemplate,
+) (*model.TransactionTemplate, error) {
+	if template.TagIDs == nil {
+		template.TagIDs = []uuid.UUID{}
+	}
+	return scanTransactionTemplate(r.Executor(nil).QueryRow(
+		ctx, `
+		INSERT INTO transaction_templates (
+			budget_id, name, account_id, payee_id, category_id, amount, note, tag_ids
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		RETURNING `+transactionTemplateColumns,
+		template.BudgetID, template.Name, template.AccountID, template.PayeeID, template.CategoryID,
+		template.Amount, template.Note, template.TagIDs,
+	))
+}
+
+func (r *transactionTemplateRepo) Update(
+	ctx context.Context,
+	budgetId uuid.UUID,
+	id uuid.UUID,
+	template model.TransactionTemplate,
+) error {
+	if template.TagIDs == nil {
+		template.TagIDs = []uuid.UUID{}
+	}
+	cmdTag, err := r.Executor(nil).Exec(
+		ctx, `
+		UPDATE transaction_templates SET
+			name = $1,
+			account_id = $2,
+			payee_id = $3,
+			category_id = $4,
+			amount = $5,
+			note = $6,
+			tag_ids = $7,
+			updated_at = NOW()
+		WHERE budget_id = $8 AND id = $9 AND deleted = FALSE
+		`,
+		template.Name, template.AccountID, template.PayeeID, template.CategoryID,
+		template.Amount, template.Note, template.TagIDs,
+		budgetId, id,
+	)
+	if err != nil {
+		return err
+	}
+	if cmdTag.RowsAffected() == 0 {
+		return pgx.ErrNoRows
+	}
+	return nil
+}
+
+func (r *transactionTemplateRepo) MarkUsed(ctx context.Context, tx pgx.Tx, budgetId uuid.UUID, id uuid.UUID) error {
+	cmdTag, err := r.Executor(tx).Exec(
+		ctx, `
+		UPDATE transaction_templates
+		SET use_count = use_count + 1, last_used_at = NOW()
+		WHERE budget_id = $1 AND id = $2 AND deleted = FALSE
+		`, budgetId, id,
+	)
+	if err != nil {
+		return err
+	}
+	if cmdTag.RowsAffected() == 0 {
+		return pgx.ErrNoRows
+	}
+	return nil
+}
+
+func (r *transactionTemplateRepo) DeleteById(ctx context.Context, budgetId uuid.UUID, id uuid.UUID) error {
+	cmdTag, err := r.Executor(nil).Exec(
+		ctx, `
+		UPDATE transaction_templates
+		SET deleted = TRUE, updated_at = NOW()
+		WHERE budget_id = $1 AND id = $2 AND deleted = FALSE
+		`, budgetId, id,
+	)
+	if err != nil {
+		return err
+	}
+	if cmdTag.RowsAffected() == 0 {
+		return pgx.ErrNoRows
+	}
+	return nil
+}
@@ -0,0 +1,288 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/Rishabh-Kapri/pennywise/backend/shared/model"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type TrashRepository interface {
+	BaseRepositoryInterface
+	// GetAll returns the entities of a budget deleted since the given time, newest first.
+	// An empty entityType lists every type.
+	GetAll(ctx context.Context, budgetId uuid.UUID, entityType model.TrashEntityType, since time.Time) ([]model.TrashItem, error)
+	// GetById returns pgx.ErrNoRows when the entity doesn't exist or isn't deleted
+	GetById(ctx context.Context, tx pgx.Tx, budgetId uuid.UUID, entityType model.TrashEntityType, id uuid.UUID) (*model.TrashItem, error)
+	// Restore brings back a deleted payee, category or account. The rules of a payee and the
+	// transfer payee of an account deleted along with it come back too.
+	// Transactions are restored by the transaction service to reapply their carryovers.
+	Restore(ctx context.Context, tx pgx.Tx, budgetId uuid.UUID, entityType model.TrashEntityType, id uuid.UUID) error
+	// GetPurgeable returns the entities of all budgets deleted before the given time that nothing
+	// refers to anymore. Transactions come first since they hold on to the other types.
+	GetPurgeable(ctx context.Context, before time.Time, limit int) ([]model.TrashItem, error)
+	// Purge permanently deletes a trashed entity and returns the storage keys of the attachments
+	// deleted with it. Entities that are still referenced fail with a foreign key violation.
+	Purge(ctx context.Context, tx pgx.Tx, item model.TrashItem) ([]string, error)
+}
+
+type trashRepo struct {
+	BaseRepository
+}
+
+func NewTrashRepository(pool *pgxpool.Pool) TrashRepository {
+	return &trashRepo{BaseRepository: NewBaseRepository(pool)}
+}
+
+// trashItemsSQL lists every deleted entity. A deleted transfer is listed once, by its outflow,
+// and transfer payees are left to their account.
+const trashItemsSQL = `
+	SELECT 'transaction' AS type, t.id, t.budget_id, COALESCE(p.name, t.note, '') AS name,
+		t.amount, t.date, COALESCE(t.deleted_at, t.updated_at) AS deleted_at, 0 AS purge_order
+	FROM transactions t
+	LEFT JOIN payees p ON p.id = t.payee_id
+	WHERE t.deleted = TRUE
+		AND NOT (t.amount > 0 AND EXISTS (
+			SELECT 1 FROM transactions c WHERE c.id = t.transfer_transaction_id AND c.deleted = TRUE
+		))
+	UNION ALL
+	SELECT 'payee', id, budget_id, name, NULL, NULL, COALESCE(deleted_at, updated_at), 1
+	FROM payees
+	WHERE deleted = TRUE AND transfer_account_id IS NULL
+	UNION ALL
+	SELECT 'category', id, budget_id, name, NULL, NULL, COALESCE(deleted_at, updated_at), 1
+	FROM categories
+	WHERE deleted = TRUE
+	UNION ALL
+	SELECT 'account', id, budget_id, name, NULL, NULL, COALESCE(deleted_at, updated_at), 2
+	FROM accounts
+	WHERE deleted = TRUE`
+
+// purgeBlockedSQL matches the trashed entities that are still referenced. Foreign keys reject
+// most of them, but left in a purge batch they would fill it run after run. Live scheduled
+// transactions would instead be deleted along with their payee or account, and splits, schedules
+// and templates would quietly lose their category.
+const purgeBlockedSQL = `
+	(trash.type = 'payee' AND (
+		EXISTS (SELECT 1 FROM transactions WHERE payee_id = trash.id)
+		OR EXISTS (SELECT 1 FROM scheduled_transactions WHERE payee_id = trash.id AND deleted = FALSE)
+		OR EXISTS (SELECT 1 FROM cipher_predictions WHERE trash.id IN (predicted_payee_id, actual_payee_id))
+	))
+	OR (trash.type = 'category' AND (
+		EXISTS (SELECT 1 FROM transactions WHERE category_id = trash.id)
+		OR EXISTS (SELECT 1 FROM transaction_splits WHERE category_id = trash.id)
+		OR EXISTS (SELECT 1 FROM scheduled_transactions WHERE category_id = trash.id AND deleted = FALSE)
+		OR EXISTS (SELECT 1 FROM transaction_templates WHERE category_id = trash.id AND deleted = FALSE)
+		OR EXISTS (SELECT 1 FROM payee_rules WHERE category_id = trash.id)
+		OR EXISTS (SELECT 1 FROM loan_metadata WHERE category_id = trash.id)
+		OR EXISTS (SELECT 1 FROM cipher_predictions WHERE trash.id IN (predicted_category_id, actual_category_id))
+	))
+	OR (trash.type = 'account' AND (
+		EXISTS (SELECT 1 FROM transactions WHERE trash.id IN (account_id, transfer_account_id))
+		OR EXISTS (SELECT 1 FROM scheduled_transactions WHERE account_id = trash.id AND deleted = FALSE)
+		OR EXISTS (SELECT 1 FROM categories WHERE account_id = trash.id)
+		OR EXISTS (
+			SELECT 1 FROM accounts a
+			WHERE a.id = trash.id AND (
+				EXISTS (SELECT 1 FROM transactions WHERE payee_id = a.transfer_payee_id)
+				OR EXISTS (
+					SELECT 1 FROM scheduled_transactions
+					WHERE payee_id = a.transfer_payee_id AND deleted = FALSE
+				)
+			)
+		)
+	))`
+
+func (r *trashRepo) GetAll(
+	ctx context.Context,
+	budgetId uuid.UUID,
+	entityType model.TrashEntityType,
+	since time.Time,
+) ([]model.TrashItem, error) {
+	rows, err := r.Executor(nil).Query(
+		ctx, `
+		SELECT type, id, budget_id, name, amount, date, deleted_at
+		FROM (`+trashItemsSQL+`) trash
+		WHERE budget_id = $1 AND ($2 = '' OR type = $2) AND deleted_at >= $3
+		ORDER BY deleted_at DESC, type, id`,
+		budgetId, entityType, since,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return scanTrashItems(rows)
+}
+
+func (r *trashRepo) GetById(
+	ctx context.Context,
+	tx pgx.Tx,
+	budgetId uuid.UUID,
+	entityType model.TrashEntityType,
+	id uuid.UUID,
+) (*model.TrashItem, error) {
+	var item model.TrashItem
+	err := r.Executor(tx).QueryRow(
+		ctx, `
+		SELECT type, id, budget_id, name, amount, date, deleted_at
+		FROM (`+trashItemsSQL+`) trash
+		WHERE budget_id = $1 AND type = $2 AND id = $3`,
+		budgetId, entityType, id,
+	).Scan(&item.Type, &item.ID, &item.BudgetID, &item.Name, &item.Amount, &item.Date, &item.DeletedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &item, nil
+}
+
+func (r *trashRepo) Restore(
+	ctx context.Context,
+	tx pgx.Tx,
+	budgetId uuid.UUID,
+	entityType model.TrashEntityType,
+	id uuid.UUID,
+) error {
+	var table string
+	switch entityType {
+	case model.TrashEntityPayee:
+		table = "payees"
+	case model.TrashEntityCategory:
+		table = "categories"
+	case model.TrashEntityAccount:
+		table = "accounts"
+	default:
+		return fmt.Errorf("%s can't be restored by the trash repository", entityType)
+	}
+	cmdTag, err := r.Executor(tx).Exec(
+		ctx,
+		`UPDATE `+table+` SET
+		   deleted = FALSE,
+		   updated_at = NOW()
+		WHERE budget_id = $1 AND id = $2 AND deleted = TRUE`,
+		budgetId, id,
+	)
+	if err != nil {
+		return err
+	}
+	if cmdTag.RowsAffected() == 0 {
+		return pgx.ErrNoRows
+	}
+	return nil
+}
+
+func (r *trashRepo) GetPurgeable(ctx context.Context, before time.Time, limit int) ([]model.TrashItem, error) {
+	rows, err := r.Executor(nil).Query(
+		ctx, `
+		SELECT type, id, budget_id, name, amount, date, deleted_at
+		FROM (`+trashItemsSQL+`) trash
+		WHERE deleted_at < $1 AND NOT (`+purgeBlockedSQL+`)
+		ORDER BY purge_order, deleted_at
+		LIMIT $2`,
+		before, limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return scanTrashItems(rows)
+}
+
+func (r *trashRepo) Purge(ctx context.Context, tx pgx.Tx, item model.TrashItem) ([]string, error) {
+	executor := r.Executor(tx)
+	switch item.Type {
+	case model.TrashEntityTransaction:
+		// both sides of a deleted transfer go together
+		const purgedIds = `
+			SELECT id FROM transactions
+			WHERE budget_id = $1 AND deleted = TRUE AND (id = $2 OR transfer_transaction_id = $2)`
+		if _, err := executor.Exec(
+			ctx,
+			`DELETE FROM predictions WHERE transaction_id IN (`+purgedIds+`)`,
+			item.BudgetID, item.ID,
+		); err != nil {
+			return nil, err
+		}
+		rows, err := executor.Query(
+			ctx,
+			`DELETE FROM transaction_attachments WHERE transaction_id IN (`+purgedIds+`) RETURNING storage_key`,
+			item.BudgetID, item.ID,
+		)
+		if err != nil {
+			return nil, err
+		}
+		storageKeys, err := pgx.CollectRows(rows, pgx.RowTo[string])
+		if err != nil {
+			return nil, err
+		}
+		if _, err = executor.Exec(
+			ctx,
+			`DELETE FROM transactions WHERE budget_id = $1 AND deleted = TRUE AND (id = $2 OR transfer_transaction_id = $2)`,
+			item.BudgetID, item.ID,
+		); err != nil {
+			return nil, err
+		}
+		return storageKeys, nil
+	case model.TrashEntityPayee:
+		_, err := executor.Exec(
+			ctx,
+			`DELETE FROM payees WHERE budget_id = $1 AND id = $2 AND deleted = TRUE`,
+			item.BudgetID, item.ID,
+		)
+		return nil, err
+	case model.TrashEntityCategory:
+		if _, err := executor.Exec(
+			ctx,
+			`DELETE FROM monthly_budgets WHERE budget_id = $1 AND category_id = $2`,
+			item.BudgetID, item.ID,
+		); err != nil {
+			return nil, err
+		}
+		_, err := executor.Exec(
+			ctx,
+			`DELETE FROM categories WHERE budget_id = $1 AND id = $2 AND deleted = TRUE`,
+			item.BudgetID, item.ID,
+		)
+		return nil, err
+	case model.TrashEntityAccount:
+		if _, err := executor.Exec(ctx, `DELETE FROM loan_metadata WHERE account_id = $1`, item.ID); err != nil {
+			return nil, err
+		}
+		// the account and its transfer payee reference each other, so they go in one statement
+		_, err := executor.Exec(
+			ctx, `
+			WITH purged AS (
+				DELETE FROM accounts WHERE budget_id = $1 AND id = $2 AND deleted = TRUE
+				RETURNING transfer_payee_id
+			)
+			DELETE FROM payees WHERE id IN (SELECT transfer_payee_id FROM purged)`,
+			item.BudgetID, item.ID,
+		)
+		return nil, err
+	}
+	return nil, fmt.Errorf("unknown trash entity type %q", item.Type)
+}
+
+func scanTrashItems(rows pgx.Rows) ([]model.TrashItem, error) {
+	defer rows.Close()
+
+	items := make([]model.TrashItem, 0)
+	for rows.Next() {
+		var item model.TrashItem
+		if err := rows.Scan(
+			&item.Type,
+			&item.ID,
+			&item.BudgetID,
+			&item.Name,
+			&item.Amount,
+			&item.Date,
+			&item.DeletedAt,
+		); err != nil {
+			return nil, fmt.Errorf("error while parsing trash rows: %w", err)
+		}
+		items = append(items, item)
+	}
+	return items, rows.Err()
+}
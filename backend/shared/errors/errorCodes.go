@@ -33,28 +33,124 @@ const (
 
 // Transaction/Transfer/Prediction error codes
 const (
-	CodeTransactionCreateFailed Code = "TRANSACTION_CREATE_FAILED"
-	CodeTransactionNotCreated   Code = "TRANSACTION_NOT_CREATED"
-	CodeTransactionUpdateFailed Code = "TRANSACTION_UPDATE_FAILED"
-	CodeTransactionLookupFailed Code = "TRANSACTION_LOOKUP_FAILED"
-	CodeTransactionDeleteFailed Code = "TRANSACTION_DELETE_FAILED"
-	CodeTransferCreateFailed    Code = "TRANSFER_CREATE_FAILED"
-	CodeTransferNotCreated      Code = "TRANSFER_NOT_CREATED"
-	CodeTransferLinkFailed      Code = "TRANSFER_LINK_FAILED"
-	CodeBudgetLookupFailed      Code = "BUDGET_LOOKUP_FAILED"
-	CodePredictionLookupFailed  Code = "PREDICTION_LOOKUP_FAILED"
-	CodePredictionCreateFailed  Code = "PREDICTION_CREATE_FAILED"
-	CodePredictionUpdateFailed  Code = "PREDICTION_UPDATE_FAILED"
-	CodePredictionDeleteFailed  Code = "PREDICTION_DELETE_FAILED"
+	CodeTransactionCreateFailed  Code = "TRANSACTION_CREATE_FAILED"
+	CodeTransactionNotCreated    Code = "TRANSACTION_NOT_CREATED"
+	CodeTransactionUpdateFailed  Code = "TRANSACTION_UPDATE_FAILED"
+	CodeTransactionLookupFailed  Code = "TRANSACTION_LOOKUP_FAILED"
+	CodeTransactionNotFound      Code = "TRANSACTION_NOT_FOUND"
+	CodeTransactionDeleteFailed  Code = "TRANSACTION_DELETE_FAILED"
+	CodeTransactionLocked        Code = "TRANSACTION_LOCKED"
+	CodeTransactionRestoreFailed Code = "TRANSACTION_RESTORE_FAILED"
+	CodeTransferCreateFailed     Code = "TRANSFER_CREATE_FAILED"
+	CodeTransferNotCreated       Code = "TRANSFER_NOT_CREATED"
+	CodeTransferLinkFailed       Code = "TRANSFER_LINK_FAILED"
+	CodeBudgetLookupFailed       Code = "BUDGET_LOOKUP_FAILED"
+	CodePredictionLookupFailed   Code = "PREDICTION_LOOKUP_FAILED"
+	CodePredictionCreateFailed   Code = "PREDICTION_CREATE_FAILED"
+	CodePredictionUpdateFailed   Code = "PREDICTION_UPDATE_FAILED"
+	CodePredictionDeleteFailed   Code = "PREDICTION_DELETE_FAILED"
+)
+
+// Scheduled transaction error codes
+const (
+	CodeScheduledTransactionLookupFailed Code = "SCHEDULED_TRANSACTION_LOOKUP_FAILED"
+	CodeScheduledTransactionCreateFailed Code = "SCHEDULED_TRANSACTION_CREATE_FAILED"
+	CodeScheduledTransactionUpdateFailed Code = "SCHEDULED_TRANSACTION_UPDATE_FAILED"
+	CodeScheduledTransactionDeleteFailed Code = "SCHEDULED_TRANSACTION_DELETE_FAILED"
+)
+
+// Transaction template error codes
+const (
+	CodeTemplateLookupFailed Code = "TEMPLATE_LOOKUP_FAILED"
+	CodeTemplateCreateFailed Code = "TEMPLATE_CREATE_FAILED"
+	CodeTemplateUpdateFailed Code = "TEMPLATE_UPDATE_FAILED"
+	CodeTemplateDeleteFailed Code = "TEMPLATE_DELETE_FAILED"
+	CodeTemplateNotFound     Code = "TEMPLATE_NOT_FOUND"
+	CodeTemplateNameTaken    Code = "TEMPLATE_NAME_TAKEN"
+)
+
+// Import error codes
+const (
+	CodeImportParseFailed         Code = "IMPORT_PARSE_FAILED"
+	CodeImportMappingLookupFailed Code = "IMPORT_MAPPING_LOOKUP_FAILED"
+	CodeImportMappingSaveFailed   Code = "IMPORT_MAPPING_SAVE_FAILED"
+	CodeImportMappingDeleteFailed Code = "IMPORT_MAPPING_DELETE_FAILED"
+)
+
+// Audit error codes
+const (
+	CodeAuditRecordFailed Code = "AUDIT_RECORD_FAILED"
+	CodeAuditLookupFailed Code = "AUDIT_LOOKUP_FAILED"
+)
+
+// Undo error codes
+const (
+	CodeJournalRecordFailed Code = "JOURNAL_RECORD_FAILED"
+	CodeJournalLookupFailed Code = "JOURNAL_LOOKUP_FAILED"
+	CodeUndoConflict        Code = "UNDO_CONFLICT"
+)
+
+// FX rate error codes
+const (
+	CodeFxRateLookupFailed Code = "FX_RATE_LOOKUP_FAILED"
+	CodeFxRateSaveFailed   Code = "FX_RATE_SAVE_FAILED"
+	CodeFxRateNotFound     Code = "FX_RATE_NOT_FOUND"
+)
+
+// Duplicate error codes
+const (
+	CodeDuplicateLookupFailed Code = "DUPLICATE_LOOKUP_FAILED"
+	CodeDuplicateUpdateFailed Code = "DUPLICATE_UPDATE_FAILED"
+	CodeDuplicateNotFound     Code = "DUPLICATE_NOT_FOUND"
+)
+
+// Attachment error codes
+const (
+	CodeAttachmentLookupFailed Code = "ATTACHMENT_LOOKUP_FAILED"
+	CodeAttachmentSaveFailed   Code = "ATTACHMENT_SAVE_FAILED"
+	CodeAttachmentDeleteFailed Code = "ATTACHMENT_DELETE_FAILED"
+	CodeAttachmentNotFound     Code = "ATTACHMENT_NOT_FOUND"
+	CodeAttachmentTooLarge     Code = "ATTACHMENT_TOO_LARGE"
+	CodeAttachmentUnsupported  Code = "ATTACHMENT_UNSUPPORTED_TYPE"
+)
+
+// Trash error codes
+const (
+	CodeTrashLookupFailed  Code = "TRASH_LOOKUP_FAILED"
+	CodeTrashItemNotFound  Code = "TRASH_ITEM_NOT_FOUND"
+	CodeTrashRestoreFailed Code = "TRASH_RESTORE_FAILED"
+	CodeTrashPurgeFailed   Code = "TRASH_PURGE_FAILED"
+)
+
+// Idempotency error codes
+const (
+	CodeIdempotencyKeyReused  Code = "IDEMPOTENCY_KEY_REUSED"
+	CodeIdempotencyInProgress Code = "IDEMPOTENCY_IN_PROGRESS"
 )
 
 // Payee/Account/Category error codes
 const (
-	CodePayeeLookupFailed    Code = "PAYEE_LOOKUP_FAILED"
-	CodePayeeCreateFailed    Code = "PAYEE_CREATE_FAILED"
-	CodeAccountLookupFailed  Code = "ACCOUNT_LOOKUP_FAILED"
-	CodeAccountCreateFailed  Code = "ACCOUNT_CREATE_FAILED"
-	CodeCategoryLookupFailed Code = "CATEGORY_LOOKUP_FAILED"
+	CodePayeeLookupFailed      Code = "PAYEE_LOOKUP_FAILED"
+	CodePayeeCreateFailed      Code = "PAYEE_CREATE_FAILED"
+	CodePayeeNotFound          Code = "PAYEE_NOT_FOUND"
+	CodePayeeMergeFailed       Code = "PAYEE_MERGE_FAILED"
+	CodeAccountLookupFailed    Code = "ACCOUNT_LOOKUP_FAILED"
+	CodeAccountCreateFailed    Code = "ACCOUNT_CREATE_FAILED"
+	CodeAccountReconcileFailed Code = "ACCOUNT_RECONCILE_FAILED"
+	CodeAccountUpdateFailed    Code = "ACCOUNT_UPDATE_FAILED"
+	CodeAccountCloseFailed     Code = "ACCOUNT_CLOSE_FAILED"
+	CodeAccountDeleteFailed    Code = "ACCOUNT_DELETE_FAILED"
+	CodeAccountNotFound        Code = "ACCOUNT_NOT_FOUND"
+	CodeAccountClosed          Code = "ACCOUNT_CLOSED"
+	CodeAccountHasBalance      Code = "ACCOUNT_HAS_BALANCE"
+	CodeAccountHasTransactions Code = "ACCOUNT_HAS_TRANSACTIONS"
+	CodeCategoryLookupFailed   Code = "CATEGORY_LOOKUP_FAILED"
+	CodeCategoryCreateFailed   Code = "CATEGORY_CREATE_FAILED"
+	CodeCategoryNotFound       Code = "CATEGORY_NOT_FOUND"
+	CodeCategoryInUse          Code = "CATEGORY_IN_USE"
+	CodeCategoryDeleteFailed   Code = "CATEGORY_DELETE_FAILED"
+	CodeCategoryGoalNotFound   Code = "CATEGORY_GOAL_NOT_FOUND"
+	CodeCategoryGoalFailed     Code = "CATEGORY_GOAL_FAILED"
 )
 
 // Monthly budget error codes
@@ -62,6 +158,8 @@ const (
 	CodeMonthlyBudgetLookupFailed Code = "MONTHLY_BUDGET_LOOKUP_FAILED"
 	CodeMonthlyBudgetCreateFailed Code = "MONTHLY_BUDGET_CREATE_FAILED"
 	CodeMonthlyBudgetUpdateFailed Code = "MONTHLY_BUDGET_UPDATE_FAILED"
+	CodeMonthlyBudgetOverAssigned Code = "MONTHLY_BUDGET_OVER_ASSIGNED"
+	CodeMonthlyBudgetMoveFailed   Code = "MONTHLY_BUDGET_MOVE_FAILED"
 )
 
 // Agent
@@ -12,14 +12,59 @@ type Account struct {
 	BudgetID        uuid.UUID  `json:"budgetId"`
 	TransferPayeeID *uuid.UUID `json:"transferPayeeId,omitempty"`
 	Type            string     `json:"type"`
-	Balance         float64    `json:"balance,omitempty"`
-	Closed          bool       `json:"closed"`
-	Deleted         bool       `json:"deleted"`
-	CreatedAt       time.Time  `json:"createdAt"`
-	UpdatedAt       time.Time  `json:"updatedAt"`
+	// Suffix is the last digits of the account number, used to match bank emails to the account
+	Suffix   *string `json:"suffix,omitempty"`
+	Currency string  `json:"currency"`
+	Balance  float64 `json:"balance,omitempty"`
+	// ClearedBalance sums cleared and reconciled transactions, UnclearedBalance the rest
+	ClearedBalance   float64    `json:"clearedBalance"`
+	UnclearedBalance float64    `json:"unclearedBalance"`
+	LastReconciledAt *time.Time `json:"lastReconciledAt,omitempty"`
+	Closed           bool       `json:"closed"`
+	Deleted          bool       `json:"deleted"`
+	CreatedAt        time.Time  `json:"createdAt"`
+	UpdatedAt        time.Time  `json:"updatedAt"`
+}
+
+// ReconcileRequest is the statement balance to reconcile an account against
+type ReconcileRequest struct {
+	StatementBalance float64 `json:"statementBalance"`
+	// Date of the adjustment transaction, defaults to today
+	Date Date `json:"date"`
+}
+
+// ReconcileResult is the outcome of an account reconciliation. Adjustment is set
+// when the cleared balance didn't match the statement balance.
+type ReconcileResult struct {
+	Account          *Account     `json:"account"`
+	StatementBalance float64      `json:"statementBalance"`
+	ClearedBalance   float64      `json:"clearedBalance"`
+	Reconciled       int64        `json:"reconciled"`
+	Adjustment       *Transaction `json:"adjustment,omitempty"`
+}
+
+// UpdateAccountRequest changes the given fields of an account, renaming an account also renames its transfer payee
+type UpdateAccountRequest struct {
+	Name   *string `json:"name"`
+	Type   *string `json:"type"`
+	Suffix *string `json:"suffix"`
+}
+
+// CloseAccountRequest closes an account. An account with a balance can only be closed
+// by moving the balance to TransferAccountID with a final transfer on Date, which defaults to today.
+type CloseAccountRequest struct {
+	TransferAccountID *uuid.UUID `json:"transferAccountId"`
+	Date              Date       `json:"date"`
+}
+
+// CloseAccountResult is the closed account and the final transfer, if the account had a balance
+type CloseAccountResult struct {
+	Account  *Account     `json:"account"`
+	Transfer *Transaction `json:"transfer,omitempty"`
 }
 
 type AccountSimplified struct {
-	ID   uuid.UUID `json:"id"`
-	Name string    `json:"name"`
+	ID       uuid.UUID `json:"id"`
+	Name     string    `json:"name"`
+	Currency string    `json:"currency"`
 }
@@ -0,0 +1,36 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// MaxAttachmentSize caps the size of a single uploaded file
+const MaxAttachmentSize = 10 << 20
+
+// AllowedAttachmentTypes lists the content types accepted for attachments. The type
+// is sniffed from the file contents, the one sent by the client is not trusted.
+var AllowedAttachmentTypes = map[string]bool{
+	"application/pdf": true,
+	"image/jpeg":      true,
+	"image/png":       true,
+	"image/gif":       true,
+	"image/webp":      true,
+	"text/plain":      true,
+}
+
+// Attachment is a file such as a receipt or an invoice linked to a transaction.
+// The contents live in the blob store under StorageKey.
+type Attachment struct {
+	ID            uuid.UUID `json:"id"`
+	BudgetID      uuid.UUID `json:"budgetId"`
+	TransactionID uuid.UUID `json:"transactionId"`
+	FileName      string    `json:"fileName"`
+	ContentType   string    `json:"contentType"`
+	Size          int64     `json:"size"`
+	StorageKey    string    `json:"-"`
+	Deleted       bool      `json:"deleted"`
+	CreatedAt     time.Time `json:"createdAt"`
+	UpdatedAt     time.Time `json:"updatedAt"`
+}
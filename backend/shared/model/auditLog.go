@@ -0,0 +1,54 @@
+package model
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type AuditEntityType string
+
+const (
+	AuditEntityTransaction AuditEntityType = "TRANSACTION"
+	// monthly budget entries are keyed by category, the month is part of the snapshot
+	AuditEntityMonthlyBudget AuditEntityType = "MONTHLY_BUDGET"
+)
+
+type AuditAction string
+
+const (
+	AuditActionCreate AuditAction = "CREATE"
+	AuditActionUpdate AuditAction = "UPDATE"
+	AuditActionDelete AuditAction = "DELETE"
+)
+
+type AuditActorType string
+
+const (
+	AuditActorUser    AuditActorType = "USER"
+	AuditActorAPIKey  AuditActorType = "API_KEY"
+	AuditActorService AuditActorType = "SERVICE"
+)
+
+// AuditChange is a single field that differs between the before and after snapshots
+type AuditChange struct {
+	Field  string `json:"field"`
+	Before any    `json:"before"`
+	After  any    `json:"after"`
+}
+
+type AuditLogEntry struct {
+	ID            uuid.UUID       `json:"id"`
+	BudgetID      uuid.UUID       `json:"budgetId"`
+	EntityType    AuditEntityType `json:"entityType"`
+	EntityID      uuid.UUID       `json:"entityId"`
+	Action        AuditAction     `json:"action"`
+	ActorType     AuditActorType  `json:"actorType"`
+	ActorID       string          `json:"actorId"`
+	Changes       []AuditChange   `json:"changes"`
+	Before        json.RawMessage `json:"before,omitempty"`
+	After         json.RawMessage `json:"after,omitempty"`
+	CorrelationID *string         `json:"correlationId,omitempty"`
+	CreatedAt     time.Time       `json:"createdAt"`
+}
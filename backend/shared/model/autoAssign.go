@@ -0,0 +1,46 @@
+package model
+
+import "github.com/google/uuid"
+
+type AutoAssignStrategy string
+
+const (
+	// AutoAssignBudgetedLastMonth budgets what was budgeted the month before
+	AutoAssignBudgetedLastMonth AutoAssignStrategy = "BUDGETED_LAST_MONTH"
+	// AutoAssignAverageSpent budgets the average spent over the Months before
+	AutoAssignAverageSpent AutoAssignStrategy = "AVERAGE_SPENT"
+	// AutoAssignUnderfundedGoals tops up categories whose goal is underfunded for the month
+	AutoAssignUnderfundedGoals AutoAssignStrategy = "UNDERFUNDED_GOALS"
+	// AutoAssignReset sets budgeted back to zero
+	AutoAssignReset AutoAssignStrategy = "RESET"
+)
+
+// DefaultAutoAssignMonths is how many months AVERAGE_SPENT looks back when Months isn't set
+const DefaultAutoAssignMonths = 3
+
+// AutoAssignRequest picks a strategy for the month. CategoryIDs limits the categories touched,
+// all visible categories are used otherwise. Preview computes the proposals without saving them.
+type AutoAssignRequest struct {
+	Strategy    AutoAssignStrategy `json:"strategy" binding:"required"`
+	Months      int                `json:"months,omitempty"`
+	CategoryIDs []uuid.UUID        `json:"categoryIds,omitempty"`
+	Preview     bool               `json:"preview"`
+}
+
+// AutoAssignProposal is the budgeted amount proposed for a category, Current is what is budgeted now
+type AutoAssignProposal struct {
+	CategoryID   uuid.UUID `json:"categoryId"`
+	CategoryName string    `json:"categoryName"`
+	Current      float64   `json:"current"`
+	Proposed     float64   `json:"proposed"`
+}
+
+// AutoAssignResult lists the proposals and the ready to assign balance before and after applying them
+type AutoAssignResult struct {
+	Month              string               `json:"month"`
+	Strategy           AutoAssignStrategy   `json:"strategy"`
+	Applied            bool                 `json:"applied"`
+	Proposals          []AutoAssignProposal `json:"proposals"`
+	ReadyToAssign      float64              `json:"readyToAssign"`
+	ReadyToAssignAfter float64              `json:"readyToAssignAfter"`
+}
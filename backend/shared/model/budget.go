@@ -17,6 +17,7 @@ type Budget struct {
 	UserID     uuid.UUID      `json:"userId"`
 	Name       string         `json:"name"`
 	IsSelected bool           `json:"isSelected"`
+	Currency   string         `json:"currency"`
 	CreatedAt  time.Time      `json:"createdAt"`
 	UpdatedAt  time.Time      `json:"updatedAt"`
 	Metadata   BudgetMetadata `json:"metadata"`
@@ -33,5 +34,6 @@ type BudgetTemplateGroup struct {
 
 type CreateBudgetRequest struct {
 	Name           string                `json:"name"`
+	Currency       string                `json:"currency"`
 	TemplateGroups []BudgetTemplateGroup `json:"templateGroups"`
 }
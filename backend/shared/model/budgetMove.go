@@ -0,0 +1,29 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// MoveMoneyRequest moves Amount of the month's budgeted money from one category to another.
+// A nil category stands for Ready to Assign.
+type MoveMoneyRequest struct {
+	Month          string     `json:"month" binding:"required"`
+	FromCategoryID *uuid.UUID `json:"fromCategoryId,omitempty"`
+	ToCategoryID   *uuid.UUID `json:"toCategoryId,omitempty"`
+	Amount         float64    `json:"amount" binding:"required"`
+	Note           string     `json:"note"`
+}
+
+// BudgetMove records a move of budgeted money, a nil category is Ready to Assign
+type BudgetMove struct {
+	ID             uuid.UUID  `json:"id"`
+	BudgetID       uuid.UUID  `json:"budgetId"`
+	Month          string     `json:"month"`
+	FromCategoryID *uuid.UUID `json:"fromCategoryId,omitempty"`
+	ToCategoryID   *uuid.UUID `json:"toCategoryId,omitempty"`
+	Amount         float64    `json:"amount"`
+	Note           string     `json:"note"`
+	CreatedAt      time.Time  `json:"createdAt"`
+}
@@ -0,0 +1,113 @@
+package model
+
+import (
+	"math"
+	"sort"
+
+	"github.com/google/uuid"
+)
+
+// CategoryMonthActivity is what the transactions of a month add to the carryover balance of a category
+type CategoryMonthActivity struct {
+	CategoryID uuid.UUID
+	Month      string
+	Activity   float64
+}
+
+// CarryoverDiscrepancy is a category month whose stored carryover balance doesn't match the one rebuilt
+// from transactions and budgeted amounts
+type CarryoverDiscrepancy struct {
+	CategoryID uuid.UUID `json:"categoryId"`
+	Month      string    `json:"month"`
+	// Stored is nil when the month has no monthly budget although its transactions change the balance,
+	// Difference is then what the month is missing on top of the balance carried over
+	Stored     *float64 `json:"stored"`
+	Expected   float64  `json:"expected"`
+	Difference float64  `json:"difference"`
+}
+
+// BudgetRebuildResult reports the carryover balances of a budget that were found wrong, and whether they
+// were repaired
+type BudgetRebuildResult struct {
+	BudgetID uuid.UUID `json:"budgetId"`
+	// Checked counts the category months rebuilt
+	Checked       int                    `json:"checked"`
+	Discrepancies []CarryoverDiscrepancy `json:"discrepancies"`
+	Repaired      bool                   `json:"repaired"`
+}
+
+// RebuildCarryovers recomputes the running carryover balance of every category month from the budgeted
+// amounts of the stored monthly budgets and the activity. It returns how many category months it checked
+// and the ones with a wrong stored balance, ordered by category and month. A month without a monthly
+// budget is only reported when its activity changes the balance, otherwise the previous month's balance
+// carries over just as well.
+func RebuildCarryovers(stored []MonthlyBudget, activity []CategoryMonthActivity) (int, []CarryoverDiscrepancy) {
+	type categoryMonth struct {
+		categoryId uuid.UUID
+		month      string
+	}
+	rows := make(map[categoryMonth]MonthlyBudget, len(stored))
+	amounts := make(map[uuid.UUID]map[string]float64)
+	add := func(categoryId uuid.UUID, month string, amount float64) {
+		if amounts[categoryId] == nil {
+			amounts[categoryId] = make(map[string]float64)
+		}
+		amounts[categoryId][month] += amount
+	}
+	for _, mb := range stored {
+		rows[categoryMonth{mb.CategoryID, mb.Month}] = mb
+		add(mb.CategoryID, mb.Month, mb.Budgeted)
+	}
+	for _, a := range activity {
+		add(a.CategoryID, a.Month, a.Activity)
+	}
+
+	categoryIds := make([]uuid.UUID, 0, len(amounts))
+	for categoryId := range amounts {
+		categoryIds = append(categoryIds, categoryId)
+	}
+	sort.Slice(categoryIds, func(i, j int) bool { return categoryIds[i].String() < categoryIds[j].String() })
+
+	checked := 0
+	discrepancies := []CarryoverDiscrepancy{}
+	for _, categoryId := range categoryIds {
+		months := make([]string, 0, len(amounts[categoryId]))
+		for month := range amounts[categoryId] {
+			months = append(months, month)
+		}
+		sort.Strings(months)
+
+		running := 0.0
+		for _, month := range months {
+			checked++
+			amount := amounts[categoryId][month]
+			running += amount
+			expected := roundCents(running)
+			row, ok := rows[categoryMonth{categoryId, month}]
+			if !ok {
+				if math.Round(amount*100) == 0 {
+					continue
+				}
+				discrepancies = append(discrepancies, CarryoverDiscrepancy{
+					CategoryID: categoryId,
+					Month:      month,
+					Expected:   expected,
+					Difference: roundCents(amount),
+				})
+				continue
+			}
+			if math.Round(row.CarryoverBalance*100) == math.Round(expected*100) {
+				continue
+			}
+			storedBalance := row.CarryoverBalance
+			discrepancies = append(discrepancies, CarryoverDiscrepancy{
+				CategoryID: categoryId,
+				Month:      month,
+				Stored:     &storedBalance,
+				Expected:   expected,
+				Difference: roundCents(expected - storedBalance),
+			})
+		}
+	}
+	return checked, discrepancies
+}
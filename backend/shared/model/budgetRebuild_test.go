@@ -0,0 +1,56 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRebuildCarryovers(t *testing.T) {
+	t.Parallel()
+
+	groceries, rent := uuid.New(), uuid.New()
+	stored := []MonthlyBudget{
+		{CategoryID: groceries, Month: "2025-01", Budgeted: 100, CarryoverBalance: 40},
+		// should be 40 + 100 - 80
+		{CategoryID: groceries, Month: "2025-02", Budgeted: 100, CarryoverBalance: 100},
+		{CategoryID: rent, Month: "2025-01", Budgeted: 900, CarryoverBalance: 0},
+	}
+	activity := []CategoryMonthActivity{
+		{CategoryID: groceries, Month: "2025-01", Activity: -60},
+		{CategoryID: groceries, Month: "2025-02", Activity: -80},
+		// nets to nothing, carrying February over is fine
+		{CategoryID: groceries, Month: "2025-03", Activity: 0},
+		{CategoryID: rent, Month: "2025-01", Activity: -900},
+		// no monthly budget for a month that changes the balance
+		{CategoryID: rent, Month: "2025-02", Activity: -25.5},
+	}
+
+	checked, discrepancies := RebuildCarryovers(stored, activity)
+	require.Equal(t, 5, checked)
+
+	byCategory := make(map[uuid.UUID][]CarryoverDiscrepancy)
+	for _, d := range discrepancies {
+		byCategory[d.CategoryID] = append(byCategory[d.CategoryID], d)
+	}
+	storedBalance := 100.0
+	require.Equal(t, []CarryoverDiscrepancy{
+		{CategoryID: groceries, Month: "2025-02", Stored: &storedBalance, Expected: 60, Difference: -40},
+	}, byCategory[groceries])
+	require.Equal(t, []CarryoverDiscrepancy{
+		{CategoryID: rent, Month: "2025-02", Expected: -25.5, Difference: -25.5},
+	}, byCategory[rent])
+}
+
+func TestRebuildCarryoversWhenConsistent(t *testing.T) {
+	t.Parallel()
+
+	groceries := uuid.New()
+	checked, discrepancies := RebuildCarryovers(
+		[]MonthlyBudget{{CategoryID: groceries, Month: "2025-01", Budgeted: 50.1, CarryoverBalance: 30.1}},
+		[]CategoryMonthActivity{{CategoryID: groceries, Month: "2025-01", Activity: -20}},
+	)
+	require.Equal(t, 1, checked)
+	require.Empty(t, discrepancies)
+}
@@ -11,15 +11,34 @@ type Category struct {
 	Name            string             `json:"name"`
 	BudgetID        uuid.UUID          `json:"budgetId"`
 	CategoryGroupID uuid.UUID          `json:"categoryGroupId"`
+	AccountID       *uuid.UUID         `json:"accountId,omitempty"` // set on the payment category of a credit card
 	Budgeted        map[string]float32 `json:"budgeted,omitempty"`
 	Activity        map[string]float32 `json:"activity,omitempty"`
 	Balance         map[string]float32 `json:"balance,omitempty"`
-	Note            string             `json:"note"`
-	Hidden          bool               `json:"hidden"`
-	IsSystem        bool               `json:"isSystem"`
-	Deleted         bool               `json:"deleted"`
-	CreatedAt       time.Time          `json:"createdAt"`
-	UpdatedAt       time.Time          `json:"updatedAt"`
+	Goal            *CategoryGoal      `json:"goal,omitempty"`
+	Underfunded     map[string]float32 `json:"underfunded,omitempty"`
+	GoalProgress    map[string]float32 `json:"goalProgress,omitempty"`
+	// CreditActivity is the part of Activity spent on credit cards, per month and card
+	CreditActivity map[string]map[uuid.UUID]float32 `json:"creditActivity,omitempty"`
+	// CreditOverspent is the overspending covered by credit cards, i.e. new card debt, per month
+	CreditOverspent map[string]float32 `json:"creditOverspent,omitempty"`
+	// CashOverspent is the rest of the overspending per month, taken out of the next month's Ready to Assign
+	CashOverspent map[string]float32 `json:"cashOverspent,omitempty"`
+	// RolloverNegative carries a negative balance into the next month instead of starting it at zero
+	RolloverNegative bool      `json:"rolloverNegative"`
+	Note             string    `json:"note"`
+	Hidden           bool      `json:"hidden"`
+	IsSystem         bool      `json:"isSystem"`
+	Deleted          bool      `json:"deleted"`
+	CreatedAt        time.Time `json:"createdAt"`
+	UpdatedAt        time.Time `json:"updatedAt"`
+}
+
+// CategoryUpdate is the body of a category update. RolloverNegative is a pointer so an update leaving
+// it out keeps the stored setting.
+type CategoryUpdate struct {
+	Category
+	RolloverNegative *bool `json:"rolloverNegative"`
 }
 
 type CategorySimplified struct {
@@ -27,6 +46,16 @@ type CategorySimplified struct {
 	Name string    `json:"name"`
 }
 
+// CategoryDeleteResult counts what was moved to the replacement when a category was deleted
+type CategoryDeleteResult struct {
+	ReplacementID         *uuid.UUID `json:"replacementId,omitempty"`
+	Transactions          int64      `json:"transactions"`
+	Splits                int64      `json:"splits"`
+	MonthlyBudgets        int64      `json:"monthlyBudgets"`
+	TransactionEmbeddings int64      `json:"transactionEmbeddings"`
+	PayeeRules            int64      `json:"payeeRules"`
+}
+
 type CategoryFilter struct {
 	ID              *uuid.UUID
 	Name            *string
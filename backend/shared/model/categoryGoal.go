@@ -0,0 +1,100 @@
+package model
+
+import (
+	"math"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const goalMonthLayout = "2006-01"
+
+type CategoryGoalType string
+
+const (
+	// CategoryGoalMonthlyFunding asks for Amount to be budgeted every month
+	CategoryGoalMonthlyFunding CategoryGoalType = "MONTHLY_FUNDING"
+	// CategoryGoalTargetBalance saves up to a balance of Amount, spread evenly over the months
+	// left until TargetMonth, or all at once without one
+	CategoryGoalTargetBalance CategoryGoalType = "TARGET_BALANCE"
+	// CategoryGoalNeededForSpending asks for Amount to be available for spending every month,
+	// money carried over from the previous month counts towards it
+	CategoryGoalNeededForSpending CategoryGoalType = "NEEDED_FOR_SPENDING"
+)
+
+type CategoryGoal struct {
+	ID          uuid.UUID        `json:"id"`
+	BudgetID    uuid.UUID        `json:"budgetId"`
+	CategoryID  uuid.UUID        `json:"categoryId"`
+	Type        CategoryGoalType `json:"type"`
+	Amount      float64          `json:"amount"`
+	TargetMonth *string          `json:"targetMonth,omitempty"`
+	CreatedAt   time.Time        `json:"createdAt"`
+	UpdatedAt   time.Time        `json:"updatedAt"`
+}
+
+// Evaluate returns how much more has to be budgeted in month for the goal to be on track and how far
+// along the goal is, between 0 and 1. available is the money the category had for the month before
+// any spending, i.e. the previous month's balance plus budgeted.
+func (g *CategoryGoal) Evaluate(month string, budgeted float64, available float64) (underfunded float64, progress float64) {
+	if g.Amount <= 0 {
+		return 0, 1
+	}
+
+	var needed, funded float64
+	switch g.Type {
+	case CategoryGoalMonthlyFunding:
+		needed, funded = g.Amount, budgeted
+	case CategoryGoalNeededForSpending:
+		needed, funded = g.Amount, available
+	case CategoryGoalTargetBalance:
+		// what is still missing at the start of the month is split over the months left
+		carriedOver := available - budgeted
+		needed = math.Max(g.Amount-carriedOver, 0) / float64(g.monthsLeft(month))
+		return roundCents(math.Max(needed-budgeted, 0)), clampProgress(available / g.Amount)
+	default:
+		return 0, 0
+	}
+	return roundCents(math.Max(needed-funded, 0)), clampProgress(funded / needed)
+}
+
+func clampProgress(progress float64) float64 {
+	return math.Min(math.Max(progress, 0), 1)
+}
+
+// monthsLeft counts month and the months after it up to the target month, at least one
+func (g *CategoryGoal) monthsLeft(month string) int {
+	if g.TargetMonth == nil {
+		return 1
+	}
+	from, err := time.Parse(goalMonthLayout, month)
+	if err != nil {
+		return 1
+	}
+	to, err := time.Parse(goalMonthLayout, *g.TargetMonth)
+	if err != nil {
+		return 1
+	}
+	months := (to.Year()-from.Year())*12 + int(to.Month()-from.Month()) + 1
+	return max(months, 1)
+}
+
+func roundCents(amount float64) float64 {
+	return math.Round(amount*100) / 100
+}
+
+// EvaluateGoal fills Underfunded and GoalProgress for every month the category has budget data for
+func (c *Category) EvaluateGoal() {
+	if c.Goal == nil {
+		return
+	}
+	c.Underfunded = make(map[string]float32, len(c.Balance))
+	c.GoalProgress = make(map[string]float32, len(c.Balance))
+	for month, balance := range c.Balance {
+		// the balance already has the month's spending taken out
+		available := float64(balance) - float64(c.Activity[month])
+		underfunded, progress := c.Goal.Evaluate(month, float64(c.Budgeted[month]), available)
+		c.Underfunded[month] = float32(underfunded)
+		c.GoalProgress[month] = float32(progress)
+	}
+}
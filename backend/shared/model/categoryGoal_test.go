@@ -0,0 +1,84 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCategoryGoalEvaluate(t *testing.T) {
+	t.Parallel()
+
+	targetMonth := "2025-12"
+	tests := []struct {
+		name        string
+		goal        CategoryGoal
+		month       string
+		budgeted    float64
+		available   float64
+		underfunded float64
+		progress    float64
+	}{
+		{
+			name:        "monthly_funding_counts_only_budgeted",
+			goal:        CategoryGoal{Type: CategoryGoalMonthlyFunding, Amount: 100},
+			month:       "2025-01",
+			budgeted:    60,
+			available:   500,
+			underfunded: 40,
+			progress:    0.6,
+		},
+		{
+			name:        "needed_for_spending_counts_carryover",
+			goal:        CategoryGoal{Type: CategoryGoalNeededForSpending, Amount: 300},
+			month:       "2025-01",
+			budgeted:    50,
+			available:   250,
+			underfunded: 50,
+			progress:    250.0 / 300,
+		},
+		{
+			name:        "target_balance_spreads_over_months_left",
+			goal:        CategoryGoal{Type: CategoryGoalTargetBalance, Amount: 1200, TargetMonth: &targetMonth},
+			month:       "2025-01",
+			underfunded: 100,
+		},
+		{
+			name:        "target_balance_past_target_month_asks_for_the_rest",
+			goal:        CategoryGoal{Type: CategoryGoalTargetBalance, Amount: 1200, TargetMonth: &targetMonth},
+			month:       "2026-02",
+			available:   1000,
+			underfunded: 200,
+			progress:    1000.0 / 1200,
+		},
+		{
+			name:      "target_balance_reached",
+			goal:      CategoryGoal{Type: CategoryGoalTargetBalance, Amount: 1200},
+			month:     "2025-01",
+			available: 1300,
+			progress:  1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			underfunded, progress := tt.goal.Evaluate(tt.month, tt.budgeted, tt.available)
+			require.Equal(t, tt.underfunded, underfunded)
+			require.InDelta(t, tt.progress, progress, 0.0001)
+		})
+	}
+}
+
+func TestCategoryEvaluateGoal(t *testing.T) {
+	t.Parallel()
+
+	category := Category{
+		Budgeted: map[string]float32{"2025-01": 100},
+		Activity: map[string]float32{"2025-01": -60},
+		Balance:  map[string]float32{"2025-01": 40},
+		Goal:     &CategoryGoal{Type: CategoryGoalNeededForSpending, Amount: 120},
+	}
+	category.EvaluateGoal()
+
+	require.Equal(t, map[string]float32{"2025-01": 20}, category.Underfunded)
+	require.InDelta(t, 100.0/120, category.GoalProgress["2025-01"], 0.0001)
+}
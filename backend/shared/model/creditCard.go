@@ -0,0 +1,20 @@
+package model
+
+// IsPaymentCategory reports whether the category is the payment category of a credit card account
+func (c *Category) IsPaymentCategory() bool {
+	return c.AccountID != nil
+}
+
+// carriedBalance returns the balance of month, or the latest earlier balance when month has none
+func carriedBalance(balance map[string]float32, month string) float32 {
+	if value, ok := balance[month]; ok {
+		return value
+	}
+	latest := ""
+	for key := range balance {
+		if key < month && key > latest {
+			latest = key
+		}
+	}
+	return balance[latest]
+}
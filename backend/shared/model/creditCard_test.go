@@ -0,0 +1,45 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+func TestApplyOverspending(t *testing.T) {
+	t.Parallel()
+
+	visa, amex := uuid.New(), uuid.New()
+	visaPayment := &Category{
+		AccountID: &visa,
+		IsSystem:  true,
+		Balance:   map[string]float32{"2025-01": 150},
+	}
+	amexPayment := &Category{AccountID: &amex, IsSystem: true}
+	// 50 budgeted, 150 spent on the visa and 50 on the amex, rolled over until 100 more is budgeted
+	groceries := &Category{
+		RolloverNegative: true,
+		Balance:          map[string]float32{"2025-01": -150, "2025-02": -50},
+		CreditActivity:   map[string]map[uuid.UUID]float32{"2025-01": {visa: -150, amex: -50}},
+	}
+	// overspent with cash only, nothing to do with the cards
+	rent := &Category{Balance: map[string]float32{"2025-01": -20}}
+
+	ApplyOverspending([]*Category{visaPayment, amexPayment, groceries, rent})
+
+	require.Equal(t, map[string]float32{"2025-01": 150, "2025-02": 50}, groceries.CreditOverspent)
+	require.Nil(t, rent.CreditOverspent)
+	require.Equal(t, map[string]float32{"2025-01": 20}, rent.CashOverspent)
+	require.Equal(t, map[string]float32{"2025-01": 37.5, "2025-02": 112.5}, visaPayment.Balance)
+	require.Equal(t, map[string]float32{"2025-01": 112.5, "2025-02": 37.5}, visaPayment.CreditOverspent)
+	require.Equal(t, map[string]float32{"2025-01": -37.5, "2025-02": -12.5}, amexPayment.Balance)
+}
+
+func TestApplyOverspendingWithoutCards(t *testing.T) {
+	t.Parallel()
+
+	groceries := &Category{Balance: map[string]float32{"2025-01": -150}}
+	ApplyOverspending([]*Category{groceries})
+	require.Nil(t, groceries.CreditOverspent)
+}
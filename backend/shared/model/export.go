@@ -0,0 +1,72 @@
+package model
+
+import (
+	"strings"
+
+	errs "github.com/Rishabh-Kapri/pennywise/backend/shared/errors"
+)
+
+type ExportFormat string
+
+const (
+	ExportFormatCSV ExportFormat = "CSV"
+	ExportFormatOFX ExportFormat = "OFX"
+	// ExportFormatYNAB is the nYNAB register/budget CSV layout, which YNAB4 imports as well
+	ExportFormatYNAB ExportFormat = "YNAB"
+)
+
+type ExportType string
+
+const (
+	ExportTypeTransactions ExportType = "TRANSACTIONS"
+	ExportTypeBudgets      ExportType = "BUDGETS"
+)
+
+// ExportRequest describes a ledger export. Transactions are filtered by Filter while
+// monthly budgets only use its start and end dates, truncated to months.
+// Transaction amounts are converted into Currency when it is set.
+type ExportRequest struct {
+	Type     ExportType
+	Format   ExportFormat
+	Filter   TransactionFilter
+	Currency string
+}
+
+// Normalize upper cases the type and format and defaults to a CSV transactions export
+func (r *ExportRequest) Normalize() {
+	r.Type = ExportType(strings.ToUpper(string(r.Type)))
+	r.Format = ExportFormat(strings.ToUpper(string(r.Format)))
+	if r.Type == "" {
+		r.Type = ExportTypeTransactions
+	}
+	if r.Format == "" {
+		r.Format = ExportFormatCSV
+	}
+	r.Currency = strings.ToUpper(strings.TrimSpace(r.Currency))
+}
+
+func (r ExportRequest) Valid() error {
+	switch r.Type {
+	case ExportTypeTransactions, ExportTypeBudgets:
+	default:
+		return errs.New(errs.CodeInvalidArgument, "unsupported export type %q", r.Type)
+	}
+	switch r.Format {
+	case ExportFormatCSV, ExportFormatYNAB:
+	case ExportFormatOFX:
+		if r.Type != ExportTypeTransactions {
+			return errs.New(errs.CodeInvalidArgument, "ofx exports only support transactions")
+		}
+	default:
+		return errs.New(errs.CodeInvalidArgument, "unsupported export format %q", r.Format)
+	}
+	if r.Currency != "" {
+		if r.Type != ExportTypeTransactions {
+			return errs.New(errs.CodeInvalidArgument, "only transaction exports can be converted")
+		}
+		if _, err := NormalizeCurrency(r.Currency); err != nil {
+			return err
+		}
+	}
+	return nil
+}
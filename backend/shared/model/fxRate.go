@@ -0,0 +1,77 @@
+package model
+
+import (
+	"regexp"
+	"strings"
+	"time"
+
+	errs "github.com/Rishabh-Kapri/pennywise/backend/shared/errors"
+
+	"github.com/google/uuid"
+)
+
+// DefaultCurrency is used for budgets created without a currency
+const DefaultCurrency = "INR"
+
+var currencyCodeRegex = regexp.MustCompile(`^[A-Z]{3}$`)
+
+// NormalizeCurrency upper cases an ISO 4217 currency code and checks its shape
+func NormalizeCurrency(currency string) (string, error) {
+	currency = strings.ToUpper(strings.TrimSpace(currency))
+	if !currencyCodeRegex.MatchString(currency) {
+		return "", errs.New(errs.CodeInvalidArgument, "invalid currency code %q", currency)
+	}
+	return currency, nil
+}
+
+// FxRate says 1 unit of BaseCurrency was worth Rate units of QuoteCurrency on Date
+type FxRate struct {
+	ID            uuid.UUID `json:"id"`
+	BudgetID      uuid.UUID `json:"budgetId"`
+	Date          Date      `json:"date"`
+	BaseCurrency  string    `json:"baseCurrency"`
+	QuoteCurrency string    `json:"quoteCurrency"`
+	Rate          float64   `json:"rate"`
+	CreatedAt     time.Time `json:"createdAt"`
+	UpdatedAt     time.Time `json:"updatedAt"`
+}
+
+// Normalize upper cases the currencies and validates the rate
+func (r *FxRate) Normalize() error {
+	if err := r.Date.Valid(); err != nil {
+		return err
+	}
+	var err error
+	if r.BaseCurrency, err = NormalizeCurrency(r.BaseCurrency); err != nil {
+		return err
+	}
+	if r.QuoteCurrency, err = NormalizeCurrency(r.QuoteCurrency); err != nil {
+		return err
+	}
+	if r.BaseCurrency == r.QuoteCurrency {
+		return errs.New(errs.CodeInvalidArgument, "base and quote currency must differ")
+	}
+	if r.Rate <= 0 {
+		return errs.New(errs.CodeInvalidArgument, "rate must be positive")
+	}
+	return nil
+}
+
+type FxRateFilter struct {
+	BaseCurrency  *string
+	QuoteCurrency *string
+	StartDate     *string
+	EndDate       *string
+}
+
+type FxRateImportRowResult struct {
+	Line  int    `json:"line"`
+	Error string `json:"error,omitempty"`
+}
+
+// FxRateImportResult reports how many rates a CSV import saved. Rows that fail
+// validation are skipped and listed in Failed.
+type FxRateImportResult struct {
+	Saved  int                     `json:"saved"`
+	Failed []FxRateImportRowResult `json:"failed"`
+}
@@ -0,0 +1,74 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type ImportFormat string
+
+const (
+	ImportFormatCSV ImportFormat = "CSV"
+	ImportFormatOFX ImportFormat = "OFX"
+	ImportFormatQIF ImportFormat = "QIF"
+)
+
+type ImportRowStatus string
+
+const (
+	ImportRowCreated ImportRowStatus = "CREATED"
+	// ImportRowSkipped marks rows whose dedupe hash already exists, i.e. rows imported before
+	ImportRowSkipped ImportRowStatus = "SKIPPED"
+	ImportRowFailed  ImportRowStatus = "FAILED"
+)
+
+// ImportMapping describes how the columns of an account's CSV export map to transaction fields.
+// Columns are matched by header name, case insensitive. Either AmountColumn or at least one of
+// InflowColumn/OutflowColumn is required. DateFormat uses YYYY, YY, MM, MMM and DD tokens, e.g. DD/MM/YYYY.
+type ImportMapping struct {
+	ID            uuid.UUID `json:"id"`
+	BudgetID      uuid.UUID `json:"budgetId"`
+	AccountID     uuid.UUID `json:"accountId"`
+	Delimiter     string    `json:"delimiter"`
+	SkipRows      int       `json:"skipRows"`
+	DateColumn    string    `json:"dateColumn"`
+	DateFormat    string    `json:"dateFormat"`
+	AmountColumn  *string   `json:"amountColumn,omitempty"`
+	InflowColumn  *string   `json:"inflowColumn,omitempty"`
+	OutflowColumn *string   `json:"outflowColumn,omitempty"`
+	PayeeColumn   *string   `json:"payeeColumn,omitempty"`
+	MemoColumn    *string   `json:"memoColumn,omitempty"`
+	// InvertAmount flips the sign of amounts, for exports where charges are positive
+	InvertAmount bool      `json:"invertAmount"`
+	Deleted      bool      `json:"deleted"`
+	CreatedAt    time.Time `json:"createdAt"`
+	UpdatedAt    time.Time `json:"updatedAt"`
+}
+
+// ImportRequest holds the options of a single statement import.
+// Mapping overrides the saved CSV mapping of the account and is saved when SaveMapping is set.
+type ImportRequest struct {
+	AccountID   uuid.UUID      `json:"accountId"`
+	Format      ImportFormat   `json:"format"`
+	Predict     bool           `json:"predict"`
+	Mapping     *ImportMapping `json:"mapping,omitempty"`
+	SaveMapping bool           `json:"saveMapping"`
+}
+
+type ImportRowResult struct {
+	Line          int             `json:"line"`
+	Date          string          `json:"date,omitempty"`
+	Amount        float64         `json:"amount"`
+	Payee         string          `json:"payee,omitempty"`
+	Status        ImportRowStatus `json:"status"`
+	TransactionID *uuid.UUID      `json:"transactionId,omitempty"`
+	Error         string          `json:"error,omitempty"`
+}
+
+type ImportResult struct {
+	Created int               `json:"created"`
+	Skipped int               `json:"skipped"`
+	Failed  int               `json:"failed"`
+	Rows    []ImportRowResult `json:"rows"`
+}
@@ -1,6 +1,7 @@
 package model
 
 import (
+	"sort"
 	"time"
 
 	"github.com/google/uuid"
@@ -16,3 +17,77 @@ type MonthlyBudget struct {
 	CreatedAt        time.Time `json:"createdAt"`
 	UpdatedAt        time.Time `json:"updatedAt"`
 }
+
+// MonthlyBudgetSummary is a monthly budget row with its category names and the month's activity
+type MonthlyBudgetSummary struct {
+	Month             string    `json:"month"`
+	CategoryID        uuid.UUID `json:"categoryId"`
+	CategoryName      string    `json:"categoryName"`
+	CategoryGroupName string    `json:"categoryGroupName"`
+	Budgeted          float64   `json:"budgeted"`
+	Activity          float64   `json:"activity"`
+	Available         float64   `json:"available"`
+}
+
+// OverspentCategory is a category left with a negative balance at the end of a month
+type OverspentCategory struct {
+	CategoryID       uuid.UUID `json:"categoryId"`
+	CategoryName     string    `json:"categoryName"`
+	CategoryGroupID  uuid.UUID `json:"categoryGroupId"`
+	Available        float64   `json:"available"`
+	CashOverspent    float64   `json:"cashOverspent"`
+	CreditOverspent  float64   `json:"creditOverspent"`
+	RolloverNegative bool      `json:"rolloverNegative"`
+}
+
+// BudgetMonth sums up a month of the budget and lists its overspent categories
+type BudgetMonth struct {
+	Month         string  `json:"month"`
+	ReadyToAssign float64 `json:"readyToAssign"`
+	Budgeted      float64 `json:"budgeted"`
+	Activity      float64 `json:"activity"`
+	Available     float64 `json:"available"`
+	// CashOverspent comes out of Ready to Assign of the next month, CreditOverspent became card debt
+	CashOverspent   float64             `json:"cashOverspent"`
+	CreditOverspent float64             `json:"creditOverspent"`
+	Overspent       []OverspentCategory `json:"overspent"`
+}
+
+// SummarizeMonth sums up the month over categories with the overspending rules applied, see
+// ApplyOverspending. Ready to Assign is left to the caller.
+func SummarizeMonth(categories []Category, month string) *BudgetMonth {
+	summary := &BudgetMonth{Month: month, Overspent: []OverspentCategory{}}
+	for _, c := range categories {
+		if c.IsSystem && !c.IsPaymentCategory() {
+			continue
+		}
+		summary.Budgeted += float64(c.Budgeted[month])
+		summary.Activity += float64(c.Activity[month])
+		available := float64(c.AvailableIn(month))
+		summary.Available += available
+		if available >= 0 || c.IsPaymentCategory() {
+			continue
+		}
+		overspent := OverspentCategory{
+			CategoryID:       c.ID,
+			CategoryName:     c.Name,
+			CategoryGroupID:  c.CategoryGroupID,
+			Available:        available,
+			CashOverspent:    float64(c.CashOverspent[month]),
+			CreditOverspent:  float64(c.CreditOverspent[month]),
+			RolloverNegative: c.RolloverNegative,
+		}
+		summary.CashOverspent += overspent.CashOverspent
+		summary.CreditOverspent += overspent.CreditOverspent
+		summary.Overspent = append(summary.Overspent, overspent)
+	}
+	summary.Budgeted = roundCents(summary.Budgeted)
+	summary.Activity = roundCents(summary.Activity)
+	summary.Available = roundCents(summary.Available)
+	summary.CashOverspent = roundCents(summary.CashOverspent)
+	summary.CreditOverspent = roundCents(summary.CreditOverspent)
+	sort.Slice(summary.Overspent, func(i, j int) bool {
+		return summary.Overspent[i].Available < summary.Overspent[j].Available
+	})
+	return summary
+}
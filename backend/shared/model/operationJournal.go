@@ -0,0 +1,32 @@
+package model
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// JournalEntry is a reversible mutation in a user's undo history. Before and After hold the
+// full entity so the mutation can be replayed in either direction.
+type JournalEntry struct {
+	ID         uuid.UUID       `json:"id"`
+	BudgetID   uuid.UUID       `json:"budgetId"`
+	UserID     uuid.UUID       `json:"userId"`
+	EntityType AuditEntityType `json:"entityType"`
+	EntityID   uuid.UUID       `json:"entityId"`
+	Action     AuditAction     `json:"action"`
+	Before     json.RawMessage `json:"before,omitempty"`
+	After      json.RawMessage `json:"after,omitempty"`
+	UndoneAt   *time.Time      `json:"undoneAt,omitempty"`
+	CreatedAt  time.Time       `json:"createdAt"`
+}
+
+type UndoRequest struct {
+	// Count is the number of mutations to revert or reapply, defaults to 1
+	Count int `json:"count"`
+}
+
+type UndoResponse struct {
+	Entries []JournalEntry `json:"entries"`
+}
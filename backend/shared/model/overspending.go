@@ -0,0 +1,186 @@
+package model
+
+import (
+	"sort"
+
+	"github.com/google/uuid"
+)
+
+// cardDebt is an amount of credit card debt per card account and month
+type cardDebt map[uuid.UUID]map[string]float64
+
+func (d cardDebt) add(accountId uuid.UUID, month string, amount float64) {
+	if d[accountId] == nil {
+		d[accountId] = make(map[string]float64)
+	}
+	d[accountId][month] += amount
+}
+
+// ApplyOverspending applies the month end overspending rules to the balances of the categories.
+//
+// Balances are stored as a running total of everything budgeted and spent in the category. A category
+// that ends a month overspent starts the next month at zero instead, unless it rolls its negative balance
+// over, and its overspending is split in two:
+//   - credit overspending, what it spent on credit cards beyond what it had available, is debt the cards
+//     can't be paid from. It is taken out of the payment categories of the cards, split by how much was
+//     spent on each, for good.
+//   - cash overspending, the rest, comes out of Ready to Assign of the next month, see CashOverspentBefore.
+//
+// A category rolling its negative balance over stays overspent until the money is budgeted, its credit
+// overspending only comes out of the payment categories for as long as it lasts.
+func ApplyOverspending(categories []*Category) {
+	paymentByAccount := make(map[uuid.UUID]*Category)
+	monthSet := make(map[string]bool)
+	for _, c := range categories {
+		if c.IsPaymentCategory() {
+			paymentByAccount[*c.AccountID] = c
+		}
+		for month := range c.Balance {
+			monthSet[month] = true
+		}
+		for month := range c.CreditActivity {
+			monthSet[month] = true
+		}
+	}
+	months := make([]string, 0, len(monthSet))
+	for month := range monthSet {
+		months = append(months, month)
+	}
+	sort.Strings(months)
+
+	// outstanding is the credit overspending of the categories per month, settled is what became debt
+	// for good at the start of a month
+	outstanding, settled := make(cardDebt), make(cardDebt)
+	for _, c := range categories {
+		if !c.IsSystem {
+			c.applyOverspending(months, paymentByAccount, outstanding, settled)
+		}
+	}
+
+	for accountId, payment := range paymentByAccount {
+		if len(outstanding[accountId]) == 0 && len(settled[accountId]) == 0 {
+			continue
+		}
+		balance := make(map[string]float32, len(months))
+		payment.CreditOverspent = make(map[string]float32)
+		debt := 0.0
+		for _, month := range months {
+			debt += settled[accountId][month]
+			current := outstanding[accountId][month]
+			if _, ok := payment.Balance[month]; ok || len(balance) > 0 || debt+current != 0 {
+				balance[month] = float32(roundCents(float64(carriedBalance(payment.Balance, month)) - debt - current))
+			}
+			if current != 0 {
+				payment.CreditOverspent[month] = float32(roundCents(current))
+			}
+		}
+		payment.Balance = balance
+	}
+}
+
+// applyOverspending replaces the running total balance of the category with what it has available per
+// month and sets its cash and credit overspending, see ApplyOverspending
+func (c *Category) applyOverspending(
+	months []string,
+	paymentByAccount map[uuid.UUID]*Category,
+	outstanding cardDebt,
+	settled cardDebt,
+) {
+	c.CashOverspent, c.CreditOverspent = nil, nil
+	balance := make(map[string]float32, len(months))
+	// spent is the credit card spending per card the category didn't have the money for yet
+	spent := make(map[uuid.UUID]float64)
+	var lastDebt map[uuid.UUID]float64
+	var available, previousTotal float64
+	started := false
+	for _, month := range months {
+		_, hasBalance := c.Balance[month]
+		if !started && !hasBalance && len(c.CreditActivity[month]) == 0 {
+			continue
+		}
+		started = true
+
+		if available < 0 && !c.RolloverNegative {
+			for accountId, amount := range lastDebt {
+				settled.add(accountId, month, amount)
+			}
+			available = 0
+		}
+		if available >= 0 {
+			clear(spent)
+		}
+		total := float64(carriedBalance(c.Balance, month))
+		available = roundCents(available + total - previousTotal)
+		previousTotal = total
+		for accountId, activity := range c.CreditActivity[month] {
+			spent[accountId] -= float64(activity)
+		}
+		balance[month] = float32(available)
+
+		lastDebt = nil
+		if available >= 0 {
+			continue
+		}
+		totalSpent := 0.0
+		for _, amount := range spent {
+			if amount > 0 {
+				totalSpent += amount
+			}
+		}
+		creditOverspent := roundCents(min(-available, totalSpent))
+		if creditOverspent > 0 {
+			if c.CreditOverspent == nil {
+				c.CreditOverspent = make(map[string]float32)
+			}
+			c.CreditOverspent[month] = float32(creditOverspent)
+			lastDebt = make(map[uuid.UUID]float64)
+			for accountId, amount := range spent {
+				if amount <= 0 || paymentByAccount[accountId] == nil {
+					continue
+				}
+				share := creditOverspent * amount / totalSpent
+				lastDebt[accountId] = share
+				outstanding.add(accountId, month, share)
+			}
+		}
+		if cashOverspent := roundCents(-available - max(creditOverspent, 0)); cashOverspent > 0 {
+			if c.CashOverspent == nil {
+				c.CashOverspent = make(map[string]float32)
+			}
+			c.CashOverspent[month] = float32(cashOverspent)
+		}
+	}
+	if started {
+		c.Balance = balance
+	}
+}
+
+// AvailableIn returns what the category has available in month once ApplyOverspending ran. A month past
+// its last balance starts at zero when the category was overspent and doesn't roll negatives over.
+func (c *Category) AvailableIn(month string) float32 {
+	if available, ok := c.Balance[month]; ok {
+		return available
+	}
+	available := carriedBalance(c.Balance, month)
+	if available < 0 && !c.IsSystem && !c.RolloverNegative {
+		return 0
+	}
+	return available
+}
+
+// CashOverspentBefore sums the cash overspending of the months before month that comes out of Ready to
+// Assign. Overspending of categories rolling their negative balance over never does.
+func CashOverspentBefore(categories []Category, month string) float64 {
+	total := 0.0
+	for _, c := range categories {
+		if c.IsSystem || c.RolloverNegative {
+			continue
+		}
+		for overspentMonth, amount := range c.CashOverspent {
+			if overspentMonth < month {
+				total += float64(amount)
+			}
+		}
+	}
+	return roundCents(total)
+}
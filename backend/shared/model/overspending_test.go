@@ -0,0 +1,102 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+// overspentCategories budgets 50 for groceries in January and spends 100 in cash and 30 on the visa,
+// then budgets 100 in February
+func overspentCategories() (visaPayment, groceries *Category) {
+	visa := uuid.New()
+	visaPayment = &Category{
+		Name:      "Visa",
+		AccountID: &visa,
+		IsSystem:  true,
+		Balance:   map[string]float32{"2025-01": 30},
+	}
+	groceries = &Category{
+		ID:             uuid.New(),
+		Name:           "Groceries",
+		Budgeted:       map[string]float32{"2025-01": 50, "2025-02": 100},
+		Activity:       map[string]float32{"2025-01": -130},
+		Balance:        map[string]float32{"2025-01": -80, "2025-02": 20},
+		CreditActivity: map[string]map[uuid.UUID]float32{"2025-01": {visa: -30}},
+	}
+	return visaPayment, groceries
+}
+
+func TestApplyOverspendingStartsNextMonthAtZero(t *testing.T) {
+	t.Parallel()
+
+	visaPayment, groceries := overspentCategories()
+	ApplyOverspending([]*Category{visaPayment, groceries})
+
+	require.Equal(t, map[string]float32{"2025-01": -80, "2025-02": 100}, groceries.Balance)
+	require.Equal(t, map[string]float32{"2025-01": 30}, groceries.CreditOverspent)
+	require.Equal(t, map[string]float32{"2025-01": 50}, groceries.CashOverspent)
+	// the card was never funded for the 30 overspent on it, that stays card debt
+	require.Equal(t, map[string]float32{"2025-01": 0, "2025-02": 0}, visaPayment.Balance)
+	require.Equal(t, map[string]float32{"2025-01": 30}, visaPayment.CreditOverspent)
+
+	require.Equal(t, float32(100), groceries.AvailableIn("2025-05"))
+	require.Equal(t, 0.0, CashOverspentBefore([]Category{*groceries}, "2025-01"))
+	require.Equal(t, 50.0, CashOverspentBefore([]Category{*groceries}, "2025-02"))
+}
+
+func TestApplyOverspendingRollsNegativeBalanceOver(t *testing.T) {
+	t.Parallel()
+
+	visaPayment, groceries := overspentCategories()
+	groceries.RolloverNegative = true
+	ApplyOverspending([]*Category{visaPayment, groceries})
+
+	require.Equal(t, map[string]float32{"2025-01": -80, "2025-02": 20}, groceries.Balance)
+	// covered in February, the card gets its money back
+	require.Equal(t, map[string]float32{"2025-01": 0, "2025-02": 30}, visaPayment.Balance)
+	require.Equal(t, 0.0, CashOverspentBefore([]Category{*groceries}, "2025-02"))
+}
+
+func TestAvailableInAfterLastBalance(t *testing.T) {
+	t.Parallel()
+
+	rent := &Category{Balance: map[string]float32{"2025-01": -20}}
+	ApplyOverspending([]*Category{rent})
+	require.Equal(t, float32(-20), rent.AvailableIn("2025-01"))
+	require.Equal(t, float32(0), rent.AvailableIn("2025-02"))
+
+	rent.RolloverNegative = true
+	require.Equal(t, float32(-20), rent.AvailableIn("2025-02"))
+}
+
+func TestSummarizeMonth(t *testing.T) {
+	t.Parallel()
+
+	visaPayment, groceries := overspentCategories()
+	inflow := &Category{Name: "Inflow: Ready to Assign", IsSystem: true, Balance: map[string]float32{"2025-01": 500}}
+	ApplyOverspending([]*Category{visaPayment, groceries, inflow})
+	categories := []Category{*visaPayment, *groceries, *inflow}
+
+	january := SummarizeMonth(categories, "2025-01")
+	require.Equal(t, &BudgetMonth{
+		Month:           "2025-01",
+		Budgeted:        50,
+		Activity:        -130,
+		Available:       -80,
+		CashOverspent:   50,
+		CreditOverspent: 30,
+		Overspent: []OverspentCategory{{
+			CategoryID:      groceries.ID,
+			CategoryName:    "Groceries",
+			Available:       -80,
+			CashOverspent:   50,
+			CreditOverspent: 30,
+		}},
+	}, january)
+
+	february := SummarizeMonth(categories, "2025-02")
+	require.Equal(t, 100.0, february.Available)
+	require.Empty(t, february.Overspent)
+}
@@ -11,9 +11,27 @@ type Payee struct {
 	Name              string     `json:"name"`
 	BudgetID          uuid.UUID  `json:"budgetId"`
 	TransferAccountID *uuid.UUID `json:"transferAccountId,omitempty"`
-	Deleted           bool       `json:"deleted"`
-	CreatedAt         time.Time  `json:"createdAt"`
-	UpdatedAt         time.Time  `json:"updatedAt"`
+	// Hidden payees, like the transfer payee of a closed account, are left out of payee lists
+	Hidden    bool      `json:"hidden"`
+	Deleted   bool      `json:"deleted"`
+	CreatedAt time.Time `json:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+// MergePayeesRequest lists the payees to fold into the target payee
+type MergePayeesRequest struct {
+	SourceIDs []uuid.UUID `json:"sourceIds" binding:"required,min=1"`
+}
+
+// PayeeMergeResult counts what was moved from the source payees to the target payee
+type PayeeMergeResult struct {
+	Payee                 *Payee      `json:"payee"`
+	MergedPayeeIDs        []uuid.UUID `json:"mergedPayeeIds"`
+	Transactions          int64       `json:"transactions"`
+	Rules                 int64       `json:"rules"`
+	TransactionEmbeddings int64       `json:"transactionEmbeddings"`
+	EntityEmbeddings      int64       `json:"entityEmbeddings"`
+	Predictions           int64       `json:"predictions"`
 }
 
 type PayeeRule struct {
@@ -0,0 +1,41 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type ScheduledFrequency string
+
+const (
+	// ScheduledFrequencyMonthlyOnDay repeats every Interval months on DayOfMonth,
+	// clamped to the last day for shorter months
+	ScheduledFrequencyMonthlyOnDay ScheduledFrequency = "MONTHLY_ON_DAY"
+	// ScheduledFrequencyEveryNWeeks repeats every Interval weeks from StartDate
+	ScheduledFrequencyEveryNWeeks ScheduledFrequency = "EVERY_N_WEEKS"
+	// ScheduledFrequencyLastBusinessDay repeats every Interval months on the last weekday of the month
+	ScheduledFrequencyLastBusinessDay ScheduledFrequency = "LAST_BUSINESS_DAY"
+)
+
+// ScheduledTransaction is a recurring transaction template. Due occurrences are
+// materialised as UNAPPROVED transactions and NextDate moves to the following occurrence.
+type ScheduledTransaction struct {
+	ID         uuid.UUID          `json:"id"`
+	BudgetID   uuid.UUID          `json:"budgetId"`
+	AccountID  *uuid.UUID         `json:"accountId,omitempty"`
+	PayeeID    *uuid.UUID         `json:"payeeId,omitempty"`
+	CategoryID *uuid.UUID         `json:"categoryId,omitempty"`
+	Amount     float64            `json:"amount"`
+	Note       string             `json:"note"`
+	TagIDs     []uuid.UUID        `json:"tagIds"`
+	Frequency  ScheduledFrequency `json:"frequency"`
+	Interval   int                `json:"interval"`
+	DayOfMonth *int               `json:"dayOfMonth,omitempty"`
+	StartDate  Date               `json:"startDate"`
+	EndDate    *Date              `json:"endDate,omitempty"`
+	NextDate   *Date              `json:"nextDate,omitempty"`
+	Deleted    bool               `json:"deleted"`
+	CreatedAt  time.Time          `json:"createdAt"`
+	UpdatedAt  time.Time          `json:"updatedAt"`
+}
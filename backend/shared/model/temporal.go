@@ -7,13 +7,15 @@ import (
 )
 
 const (
-	PennywiseTaskQueue                   = "pennywise-tasks"
-	GmailActivitiesTaskQueue             = "gmail-activities"
-	CipherActivitiesTaskQueue            = "cipher-activities"
-	PennywiseActivitiesTaskQueue         = "pennywise-activities"
-	EmailToTransactionWorkflowName       = "EmailToTransactionWorkflow"
-	ParsedEmailToTransactionWorkflowName = "ParsedEmailToTransactionWorkflow"
-	RefreshGmailWatchWorkflowName        = "RefreshGmailWatchWorkflow"
+	PennywiseTaskQueue                           = "pennywise-tasks"
+	GmailActivitiesTaskQueue                     = "gmail-activities"
+	CipherActivitiesTaskQueue                    = "cipher-activities"
+	PennywiseActivitiesTaskQueue                 = "pennywise-activities"
+	EmailToTransactionWorkflowName               = "EmailToTransactionWorkflow"
+	ParsedEmailToTransactionWorkflowName         = "ParsedEmailToTransactionWorkflow"
+	RefreshGmailWatchWorkflowName                = "RefreshGmailWatchWorkflow"
+	MaterializeScheduledTransactionsWorkflowName = "MaterializeScheduledTransactionsWorkflow"
+	PurgeTrashWorkflowName                       = "PurgeTrashWorkflow"
 
 	RetryEmailParseSignal = "retry-email-parse"
 	// RetryPredictSignal is sent to a waiting workflow to trigger a manual retry
@@ -48,6 +50,10 @@ type ParsedEmail struct {
 	Account           string  `json:"account"`
 	Payee             string  `json:"payee"`
 	Category          string  `json:"category"`
+
+	// OriginalAmount and OriginalCurrency are only set for foreign currency charges
+	OriginalAmount   *float64 `json:"originalAmount,omitempty"`
+	OriginalCurrency *string  `json:"originalCurrency,omitempty"`
 }
 
 // ExtractedEmail is the structured output from Phase 1 LLM extraction.
@@ -59,6 +65,11 @@ type ExtractedEmailResponse struct {
 	Date        string  `json:"date"`
 	AccountCard string  `json:"account_card"`
 	Reasoning   string  `json:"reasoning"`
+
+	// OriginalAmount and OriginalCurrency hold the foreign amount of a forex charge,
+	// Amount is the equivalent in the card currency
+	OriginalAmount   *float64 `json:"original_amount"`
+	OriginalCurrency *string  `json:"original_currency"`
 }
 
 // ParsedEmailsInput is the result of FetchAndParseEmails (go-gmail)
@@ -108,6 +119,9 @@ type CipherPredictionResult struct {
 	Source          PredictionSource `json:"source"` // pgvector | rule | llm
 	Reasoning       string           `json:"reasoning,omitempty"`
 	Metadata        map[string]any   `json:"metadata,omitempty"`
+
+	OriginalAmount   *float64 `json:"originalAmount,omitempty"`
+	OriginalCurrency *string  `json:"originalCurrency,omitempty"`
 }
 
 type PredictionResultInput struct {
@@ -2,6 +2,7 @@ package model
 
 import (
 	"fmt"
+	"math"
 	"time"
 
 	errs "github.com/Rishabh-Kapri/pennywise/backend/shared/errors"
@@ -20,6 +21,24 @@ const (
 	TransactionStatusRejected   TransactionStatus = "REJECTED"
 )
 
+// ClearedStatus tracks whether a transaction has shown up on the bank statement.
+// Reconciled transactions are locked, their amount, date and account can't change.
+type ClearedStatus string
+
+const (
+	ClearedStatusUncleared  ClearedStatus = "UNCLEARED"
+	ClearedStatusCleared    ClearedStatus = "CLEARED"
+	ClearedStatusReconciled ClearedStatus = "RECONCILED"
+)
+
+func (c ClearedStatus) Valid() error {
+	switch c {
+	case ClearedStatusUncleared, ClearedStatusCleared, ClearedStatusReconciled:
+		return nil
+	}
+	return errs.New(errs.CodeInvalidArgument, "invalid cleared status %q", c)
+}
+
 // check if date is valid and is in the format YYYY-MM-DD
 func (d Date) Valid() error {
 	if d == "" {
@@ -37,30 +56,90 @@ func (d Date) String() string {
 }
 
 type Transaction struct {
-	ID                    uuid.UUID         `json:"id"`
-	BudgetID              uuid.UUID         `json:"budgetId"`
-	Date                  Date              `json:"date"`
-	PayeeID               *uuid.UUID        `json:"payeeId,omitempty"`
-	CategoryID            *uuid.UUID        `json:"categoryId,omitempty"`
-	AccountID             *uuid.UUID        `json:"accountId,omitempty"`
-	AccountName           *string           `json:"accountName,omitempty"`
-	PayeeName             *string           `json:"payeeName,omitempty"`
-	CategoryName          *string           `json:"categoryName,omitempty"`
-	Note                  string            `json:"note"`
-	Amount                float64           `json:"amount"`
-	Inflow                float64           `json:"inflow"`
-	Outflow               float64           `json:"outflow"`
-	Balance               float64           `json:"balance"`
-	DedupeHash            *string           `json:"dedupeHash,omitempty"`
-	Status                TransactionStatus `json:"status"`
-	RawBankText           *string           `json:"rawBankText,omitempty"`
-	Summary               *string           `json:"summary,omitempty"`
-	TransferAccountID     *uuid.UUID        `json:"transferAccountId,omitempty"`
-	TransferTransactionID *uuid.UUID        `json:"transferTransactionId,omitempty"`
-	TagIDs                []uuid.UUID       `json:"tagIds"`
-	Deleted               bool              `json:"deleted"`
-	CreatedAt             time.Time         `json:"createdAt"`
-	UpdatedAt             time.Time         `json:"updatedAt"`
+	ID                    uuid.UUID          `json:"id"`
+	BudgetID              uuid.UUID          `json:"budgetId"`
+	Date                  Date               `json:"date"`
+	PayeeID               *uuid.UUID         `json:"payeeId,omitempty"`
+	CategoryID            *uuid.UUID         `json:"categoryId,omitempty"`
+	AccountID             *uuid.UUID         `json:"accountId,omitempty"`
+	AccountName           *string            `json:"accountName,omitempty"`
+	PayeeName             *string            `json:"payeeName,omitempty"`
+	CategoryName          *string            `json:"categoryName,omitempty"`
+	Note                  string             `json:"note"`
+	Amount                float64            `json:"amount"`
+	Inflow                float64            `json:"inflow"`
+	Outflow               float64            `json:"outflow"`
+	Balance               float64            `json:"balance"`
+	DedupeHash            *string            `json:"dedupeHash,omitempty"`
+	Status                TransactionStatus  `json:"status"`
+	Cleared               ClearedStatus      `json:"cleared"`
+	RawBankText           *string            `json:"rawBankText,omitempty"`
+	Summary               *string            `json:"summary,omitempty"`
+	OriginalAmount        *float64           `json:"originalAmount,omitempty"`   // amount charged in OriginalCurrency
+	OriginalCurrency      *string            `json:"originalCurrency,omitempty"` // set for foreign currency charges
+	FxRate                *float64           `json:"fxRate,omitempty"`           // account currency units per original unit
+	TransferAccountID     *uuid.UUID         `json:"transferAccountId,omitempty"`
+	TransferTransactionID *uuid.UUID         `json:"transferTransactionId,omitempty"`
+	TagIDs                []uuid.UUID        `json:"tagIds"`
+	Splits                []TransactionSplit `json:"splits,omitempty"`
+	Deleted               bool               `json:"deleted"`
+	CreatedAt             time.Time          `json:"createdAt"`
+	UpdatedAt             time.Time          `json:"updatedAt"`
+	// SearchRank and SearchSnippet are only set when listing with a search query
+	SearchRank    *float64 `json:"searchRank,omitempty"`
+	SearchSnippet *string  `json:"searchSnippet,omitempty"`
+}
+
+// TransactionSplit is a single line of a split transaction. The parent
+// transaction carries no category; each line carries its own category and
+// amount, and the lines must sum to the parent amount.
+type TransactionSplit struct {
+	ID            uuid.UUID  `json:"id"`
+	TransactionID uuid.UUID  `json:"transactionId"`
+	CategoryID    *uuid.UUID `json:"categoryId,omitempty"`
+	CategoryName  *string    `json:"categoryName,omitempty"`
+	Amount        float64    `json:"amount"`
+	Note          string     `json:"note"`
+}
+
+// IsSplit returns true if the transaction has split lines
+func (t *Transaction) IsSplit() bool {
+	return len(t.Splits) > 0
+}
+
+// SplitsTotal returns the sum of all split line amounts
+func (t *Transaction) SplitsTotal() float64 {
+	total := 0.0
+	for _, split := range t.Splits {
+		total += split.Amount
+	}
+	return total
+}
+
+// ApplyOriginalAmount normalizes the original currency of a foreign currency charge and
+// derives the fx rate from the amount booked in the account currency. The original amount
+// takes the sign of the amount, since extracted amounts don't always carry one.
+func (t *Transaction) ApplyOriginalAmount() error {
+	if t.OriginalAmount == nil && t.OriginalCurrency == nil {
+		t.FxRate = nil
+		return nil
+	}
+	if t.OriginalAmount == nil || t.OriginalCurrency == nil {
+		return errs.New(errs.CodeInvalidArgument, "original amount and currency must be set together")
+	}
+	currency, err := NormalizeCurrency(*t.OriginalCurrency)
+	if err != nil {
+		return err
+	}
+	if *t.OriginalAmount == 0 {
+		return errs.New(errs.CodeInvalidArgument, "original amount can't be zero")
+	}
+	originalAmount := math.Copysign(math.Abs(*t.OriginalAmount), t.Amount)
+	rate := math.Round(math.Abs(t.Amount/originalAmount)*1e6) / 1e6
+	t.OriginalAmount = &originalAmount
+	t.OriginalCurrency = &currency
+	t.FxRate = &rate
+	return nil
 }
 
 type TransactionStatusReq struct {
@@ -71,9 +150,11 @@ type TransactionFilter struct {
 	AccountIDs   []uuid.UUID
 	CategoryIDs  []uuid.UUID
 	PayeeIDs     []uuid.UUID
+	Cleared      []ClearedStatus
 	StartDate    *string
 	EndDate      *string
 	Note         *string
+	Query        *string // full-text search over note, raw bank text, summary, payee and category names
 	SortOrder    string
 	Limit        uint64
 	GroupBy      *string
@@ -139,6 +220,13 @@ func (t *Transaction) Compare(other *Transaction) bool {
 	if t.Status != other.Status {
 		return false
 	}
+	if t.Cleared != other.Cleared {
+		return false
+	}
+	if ptrToFloat64String(t.OriginalAmount) != ptrToFloat64String(other.OriginalAmount) ||
+		ptrToString(t.OriginalCurrency) != ptrToString(other.OriginalCurrency) {
+		return false
+	}
 	// handle tagIds
 	if len(t.TagIDs) != len(other.TagIDs) {
 		return false
@@ -151,5 +239,18 @@ func (t *Transaction) Compare(other *Transaction) bool {
 		}
 	}
 
+	if len(t.Splits) != len(other.Splits) {
+		return false
+	}
+	for i, split := range t.Splits {
+		otherSplit := other.Splits[i]
+		if ptrToUUIDString(split.CategoryID) != ptrToUUIDString(otherSplit.CategoryID) {
+			return false
+		}
+		if split.Amount != otherSplit.Amount || split.Note != otherSplit.Note {
+			return false
+		}
+	}
+
 	return true
 }
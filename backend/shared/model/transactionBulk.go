@@ -0,0 +1,53 @@
+package model
+
+import "github.com/google/uuid"
+
+type BulkTransactionAction string
+
+const (
+	BulkActionApprove      BulkTransactionAction = "APPROVE"
+	BulkActionReject       BulkTransactionAction = "REJECT"
+	BulkActionRecategorize BulkTransactionAction = "RECATEGORIZE"
+	BulkActionSetPayee     BulkTransactionAction = "SET_PAYEE"
+	BulkActionAddTags      BulkTransactionAction = "ADD_TAGS"
+	BulkActionRemoveTags   BulkTransactionAction = "REMOVE_TAGS"
+	BulkActionDelete       BulkTransactionAction = "DELETE"
+)
+
+type BulkResultStatus string
+
+const (
+	BulkResultApplied    BulkResultStatus = "APPLIED"
+	BulkResultFailed     BulkResultStatus = "FAILED"
+	BulkResultRolledBack BulkResultStatus = "ROLLED_BACK"
+	BulkResultNotApplied BulkResultStatus = "NOT_APPLIED"
+)
+
+// BulkTransactionOperation is a single operation of a bulk request.
+// CategoryID is used by RECATEGORIZE, PayeeID by SET_PAYEE and TagIDs by ADD_TAGS/REMOVE_TAGS.
+type BulkTransactionOperation struct {
+	TransactionID uuid.UUID             `json:"transactionId"`
+	Action        BulkTransactionAction `json:"action"`
+	CategoryID    *uuid.UUID            `json:"categoryId,omitempty"`
+	PayeeID       *uuid.UUID            `json:"payeeId,omitempty"`
+	TagIDs        []uuid.UUID           `json:"tagIds,omitempty"`
+}
+
+type BulkTransactionRequest struct {
+	Operations []BulkTransactionOperation `json:"operations"`
+}
+
+type BulkTransactionResult struct {
+	Index         int                   `json:"index"`
+	TransactionID uuid.UUID             `json:"transactionId"`
+	Action        BulkTransactionAction `json:"action"`
+	Status        BulkResultStatus      `json:"status"`
+	Error         string                `json:"error,omitempty"`
+}
+
+// BulkTransactionResponse holds the per operation results. Operations are applied
+// atomically, so either every result is APPLIED or none of them took effect.
+type BulkTransactionResponse struct {
+	Applied bool                    `json:"applied"`
+	Results []BulkTransactionResult `json:"results"`
+}
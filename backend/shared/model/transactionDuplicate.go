@@ -0,0 +1,56 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Suspected duplicates are in the same account, of the same sign and within the amount
+// tolerance and date window of each other. They also need the same payee or similar text.
+const (
+	// DuplicateAmountTolerance is the absolute amount difference allowed, larger amounts
+	// allow DuplicateAmountToleranceRatio of the amount instead
+	DuplicateAmountTolerance      = 1.0
+	DuplicateAmountToleranceRatio = 0.01
+	DuplicateWindowDays           = 3
+	// DuplicateMinSimilarity is the trigram similarity of the payee, note and bank text needed
+	// when the payees differ
+	DuplicateMinSimilarity = 0.4
+)
+
+type DuplicateStatus string
+
+const (
+	DuplicateStatusPending   DuplicateStatus = "PENDING"
+	DuplicateStatusMerged    DuplicateStatus = "MERGED"
+	DuplicateStatusDismissed DuplicateStatus = "DISMISSED"
+)
+
+func (s DuplicateStatus) Valid() bool {
+	switch s {
+	case DuplicateStatusPending, DuplicateStatusMerged, DuplicateStatusDismissed:
+		return true
+	}
+	return false
+}
+
+// TransactionDuplicate flags Transaction as a suspected duplicate of the older DuplicateOf.
+// Score is 1 for the same payee, otherwise the text similarity of the two.
+type TransactionDuplicate struct {
+	ID            uuid.UUID       `json:"id"`
+	BudgetID      uuid.UUID       `json:"budgetId"`
+	TransactionID uuid.UUID       `json:"transactionId"`
+	DuplicateOfID uuid.UUID       `json:"duplicateOfId"`
+	Score         float64         `json:"score"`
+	Status        DuplicateStatus `json:"status"`
+	Transaction   *Transaction    `json:"transaction,omitempty"`
+	DuplicateOf   *Transaction    `json:"duplicateOf,omitempty"`
+	CreatedAt     time.Time       `json:"createdAt"`
+	UpdatedAt     time.Time       `json:"updatedAt"`
+}
+
+// MergeDuplicateRequest picks the transaction to keep, the older one is kept when unset
+type MergeDuplicateRequest struct {
+	KeepID *uuid.UUID `json:"keepId,omitempty"`
+}
@@ -0,0 +1,37 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// TransactionTemplate is a memorized payee, category, account, amount and tags combination
+// that new transactions can be created from. UseCount counts the transactions created from it.
+type TransactionTemplate struct {
+	ID         uuid.UUID   `json:"id"`
+	BudgetID   uuid.UUID   `json:"budgetId"`
+	Name       string      `json:"name"`
+	AccountID  *uuid.UUID  `json:"accountId,omitempty"`
+	PayeeID    *uuid.UUID  `json:"payeeId,omitempty"`
+	CategoryID *uuid.UUID  `json:"categoryId,omitempty"`
+	Amount     float64     `json:"amount"`
+	Note       string      `json:"note"`
+	TagIDs     []uuid.UUID `json:"tagIds"`
+	UseCount   int         `json:"useCount"`
+	LastUsedAt *time.Time  `json:"lastUsedAt,omitempty"`
+	CreatedAt  time.Time   `json:"createdAt"`
+	UpdatedAt  time.Time   `json:"updatedAt"`
+}
+
+type CreateTemplateFromTransactionRequest struct {
+	TransactionID uuid.UUID `json:"transactionId" binding:"required"`
+	Name          string    `json:"name" binding:"required"`
+}
+
+// InstantiateTemplateRequest overrides the template for the new transaction,
+// the date defaults to today and the amount to the template's amount
+type InstantiateTemplateRequest struct {
+	Date   *Date    `json:"date"`
+	Amount *float64 `json:"amount"`
+}
@@ -0,0 +1,22 @@
+package model
+
+import "github.com/google/uuid"
+
+const (
+	// DefaultTransferMatchWindowDays is how many days apart the two sides of a transfer may be
+	DefaultTransferMatchWindowDays = 3
+	MaxTransferMatchWindowDays     = 14
+)
+
+// TransferMatch pairs an outflow with an inflow of the same amount in another account
+// of the budget. Both are unlinked transactions that likely are the two sides of one transfer.
+type TransferMatch struct {
+	Outflow   Transaction `json:"outflow"`
+	Inflow    Transaction `json:"inflow"`
+	DaysApart int         `json:"daysApart"`
+}
+
+type AcceptTransferMatchRequest struct {
+	OutflowID uuid.UUID `json:"outflowId"`
+	InflowID  uuid.UUID `json:"inflowId"`
+}
@@ -0,0 +1,40 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// DefaultTrashRetentionDays is how long deleted entities stay restorable before they are purged
+const DefaultTrashRetentionDays = 30
+
+type TrashEntityType string
+
+const (
+	TrashEntityTransaction TrashEntityType = "transaction"
+	TrashEntityPayee       TrashEntityType = "payee"
+	TrashEntityCategory    TrashEntityType = "category"
+	TrashEntityAccount     TrashEntityType = "account"
+)
+
+func (t TrashEntityType) Valid() bool {
+	switch t {
+	case TrashEntityTransaction, TrashEntityPayee, TrashEntityCategory, TrashEntityAccount:
+		return true
+	}
+	return false
+}
+
+// TrashItem is a soft deleted entity that can still be restored. Name is the payee of a
+// transaction, which also carries its amount and date.
+type TrashItem struct {
+	Type      TrashEntityType `json:"type"`
+	ID        uuid.UUID       `json:"id"`
+	BudgetID  uuid.UUID       `json:"budgetId"`
+	Name      string          `json:"name"`
+	Amount    *float64        `json:"amount,omitempty"`
+	Date      *Date           `json:"date,omitempty"`
+	DeletedAt time.Time       `json:"deletedAt"`
+	PurgeAt   time.Time       `json:"purgeAt"`
+}
@@ -12,11 +12,16 @@ type Pagination struct {
 	NextCursor string `json:"nextCursor,omitempty"`
 }
 
+// SortOrderRelevance orders search results by rank, best match first
+const SortOrderRelevance = "RELEVANCE"
+
 type Cursor struct {
 	Date       string
 	UpdatedAt  time.Time
 	ID         uuid.UUID
 	PointsNext bool
+	// Rank is only set when paging through results sorted by relevance
+	Rank *float64 `json:",omitempty"`
 }
 
 type PaginatedResponse[T any] struct {
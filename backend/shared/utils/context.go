@@ -30,6 +30,9 @@ const (
 	HeaderBudgetID        = "X-Budget-ID"
 	HeaderUserID          = "X-User-ID"
 	HeaderAPIKey          = "X-API-Key"
+
+	HeaderIdempotencyKey     = "Idempotency-Key"
+	HeaderIdempotentReplayed = "Idempotent-Replayed"
 )
 
 type RequestMetadata struct {
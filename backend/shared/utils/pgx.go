@@ -2,11 +2,20 @@ package utils
 
 import (
 	"context"
+	"errors"
 
 	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
+const (
+	// pgUniqueViolation is the postgres error code for unique constraint violations
+	pgUniqueViolation = "23505"
+	// pgForeignKeyViolation is the postgres error code for foreign key constraint violations
+	pgForeignKeyViolation = "23503"
+)
+
 // Helper method to execute a function within a transaction. It will commit if the function returns nil error, otherwise it will rollback.
 // This is useful to avoid repeating the same transaction handling code in multiple places. Just pass the function that contains the logic that needs to be executed within the transaction.
 // This also ensures that the transaction is properly rolled back in case of any error, preventing potential data inconsistencies.
@@ -39,3 +48,15 @@ func WithTx(ctx context.Context, pool *pgxpool.Pool, fn func(tx pgx.Tx) error) e
 	}
 	return tx.Commit(ctx)
 }
+
+// IsUniqueViolation reports whether err, or any error it wraps, is a postgres unique constraint violation
+func IsUniqueViolation(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == pgUniqueViolation
+}
+
+// IsForeignKeyViolation reports whether err, or any error it wraps, is a postgres foreign key violation
+func IsForeignKeyViolation(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == pgForeignKeyViolation
+}
@@ -61,6 +61,12 @@ func main() {
 	w.RegisterWorkflowWithOptions(workflow.RefreshGmailWatchWorkflow, sdkworkflow.RegisterOptions{
 		Name: sharedModel.RefreshGmailWatchWorkflowName,
 	})
+	w.RegisterWorkflowWithOptions(workflow.MaterializeScheduledTransactionsWorkflow, sdkworkflow.RegisterOptions{
+		Name: sharedModel.MaterializeScheduledTransactionsWorkflowName,
+	})
+	w.RegisterWorkflowWithOptions(workflow.PurgeTrashWorkflow, sdkworkflow.RegisterOptions{
+		Name: sharedModel.PurgeTrashWorkflowName,
+	})
 
 	// 4. Start listening (blocks until interrupted)
 	err = w.Run(worker.InterruptCh())
@@ -0,0 +1,36 @@
+package workflow
+
+import (
+	"time"
+
+	sharedModel "github.com/Rishabh-Kapri/pennywise/backend/shared/model"
+
+	"go.temporal.io/sdk/temporal"
+	"go.temporal.io/sdk/workflow"
+)
+
+func PurgeTrashWorkflow(ctx workflow.Context) error {
+	workflowInfo := workflow.GetInfo(ctx)
+	logFields := []interface{}{
+		"workflow_id", workflowInfo.WorkflowExecution.ID,
+		"workflow_run_id", workflowInfo.WorkflowExecution.RunID,
+	}
+	workflow.GetLogger(ctx).Info("starting purge trash workflow", logFields...)
+
+	pennywiseCtx := workflow.WithActivityOptions(ctx, workflow.ActivityOptions{
+		TaskQueue:           sharedModel.PennywiseActivitiesTaskQueue,
+		StartToCloseTimeout: 10 * time.Minute,
+		RetryPolicy: &temporal.RetryPolicy{
+			InitialInterval: time.Minute,
+			MaximumAttempts: 3,
+		},
+	})
+
+	var purged int
+	if err := workflow.ExecuteActivity(pennywiseCtx, "PurgeTrash").Get(pennywiseCtx, &purged); err != nil {
+		return err
+	}
+
+	workflow.GetLogger(ctx).Info("purge trash workflow completed", append(logFields, "count", purged)...)
+	return nil
+}